@@ -0,0 +1,273 @@
+package abi
+
+import (
+	"bytes"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/game"
+)
+
+func mustHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := eth.DecodeHex(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		t.Fatalf("Error decoding hex: %v", err)
+	}
+	return b
+}
+
+func TestSelectorsAndTopics(t *testing.T) {
+	cases := map[string]string{
+		ClaimData.Signature():   "0xc6f0308c",
+		GetGameUUID.Signature(): "0x96cd9720",
+		Create.Signature():      "0x82ecf2f6",
+		MustParseMethod("balanceOf(address) returns (uint256)").Signature(): "0x70a08231",
+	}
+	for sig, want := range cases {
+		m := MustParseMethod(sig)
+		if got := eth.EncodeHex(m.Selector()); got != want {
+			t.Errorf("selector of %s: expected %s, got %s", sig, want, got)
+		}
+	}
+
+	if got := Resolved.Topic().String(); got != "0x5e186f09b9c93491f14e277eea7faa5de6a2d4bda75a79af7a3684fbfb42da60" {
+		t.Errorf("unexpected Resolved topic %s", got)
+	}
+	if got := DisputeGameCreated.Topic().String(); got != "0x5b565efe82411da98814f356d0e7bcb8f0219b8d970307c5afb4a6903a8b2e35" {
+		t.Errorf("unexpected DisputeGameCreated topic %s", got)
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	// the same call written the different ways the gate files write it
+	sigs := []string{
+		"claimData(uint256) returns (uint32,address,address,uint128,bytes32,uint128,uint128)",
+		"claimData(uint256) returns(uint32,address,address,uint128,bytes32,uint128,uint128)",
+		"claimData(uint256 idx) view returns (uint32,address,address,uint128,bytes32,uint128,uint128)",
+		"function claimData(uint256) external view returns (uint32 parentIndex, address counteredBy, address claimant, uint128 bond, bytes32 claim, uint128 position, uint128 clock)",
+	}
+	for _, sig := range sigs {
+		m, err := ParseMethod(sig)
+		if err != nil {
+			t.Fatalf("Error parsing %q: %v", sig, err)
+		}
+		if m.Signature() != "claimData(uint256)" || len(m.Outputs) != 7 || m.Outputs[3].Type.String() != "uint128" {
+			t.Errorf("unexpected method %+v from %q", m, sig)
+		}
+	}
+
+	tuple := MustParseMethod("f((uint256,bytes)[] calldata items, bool flag)")
+	if tuple.Signature() != "f((uint256,bytes)[],bool)" || tuple.Inputs[0].Name != "items" {
+		t.Errorf("unexpected tuple method %+v", tuple)
+	}
+
+	for _, sig := range []string{"f(uint7)", "f(bytes33)", "f(uint256", "f(uint256) returns", "f(uint256) foo", "(uint256)"} {
+		if _, err := ParseMethod(sig); err == nil {
+			t.Errorf("expected %q to fail parsing", sig)
+		}
+	}
+}
+
+func TestEncodeSolidityExample(t *testing.T) {
+	// the example from the Solidity ABI specification, mixing static, dynamic and array arguments
+	m := MustParseMethod("f(uint256,uint32[],bytes10,bytes)")
+	calldata, err := m.Pack(0x123, []uint32{0x456, 0x789}, []byte("1234567890"), []byte("Hello, world!"))
+	if err != nil {
+		t.Fatalf("Error encoding: %v", err)
+	}
+	want := mustHex(t, `0x8be65246
+		0000000000000000000000000000000000000000000000000000000000000123
+		0000000000000000000000000000000000000000000000000000000000000080
+		3132333435363738393000000000000000000000000000000000000000000000
+		00000000000000000000000000000000000000000000000000000000000000e0
+		0000000000000000000000000000000000000000000000000000000000000002
+		0000000000000000000000000000000000000000000000000000000000000456
+		0000000000000000000000000000000000000000000000000000000000000789
+		000000000000000000000000000000000000000000000000000000000000000d
+		48656c6c6f2c20776f726c642100000000000000000000000000000000000000`)
+	if !bytes.Equal(calldata, want) {
+		t.Fatalf("expected %x, got %x", want, calldata)
+	}
+
+	values, err := m.UnpackInputs(calldata)
+	if err != nil {
+		t.Fatalf("Error decoding: %v", err)
+	}
+	expected := []any{big.NewInt(0x123), []any{big.NewInt(0x456), big.NewInt(0x789)}, []byte("1234567890"), []byte("Hello, world!")}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+}
+
+func TestIntegerRanges(t *testing.T) {
+	uint128 := MustParseType("uint128")
+	max128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	if _, err := Encode([]Type{uint128}, []any{max128}); err != nil {
+		t.Errorf("Error encoding max uint128: %v", err)
+	}
+	if _, err := Encode([]Type{uint128}, []any{new(big.Int).Add(max128, big.NewInt(1))}); err == nil {
+		t.Errorf("expected overflow encoding 2^128 as uint128")
+	}
+	if _, err := Encode([]Type{uint128}, []any{-1}); err == nil {
+		t.Errorf("expected negative value to fail encoding as uint128")
+	}
+
+	// signed integers are two's complement
+	int8 := MustParseType("int8")
+	encoded, err := Encode([]Type{int8}, []any{-128})
+	if err != nil {
+		t.Fatalf("Error encoding int8: %v", err)
+	}
+	if encoded[0] != 0xff || encoded[31] != 0x80 {
+		t.Errorf("unexpected int8 encoding %x", encoded)
+	}
+	decoded, err := Decode([]Type{int8}, encoded)
+	if err != nil || decoded[0].(*big.Int).Int64() != -128 {
+		t.Errorf("unexpected int8 decoding %v (%v)", decoded, err)
+	}
+	if _, err := Encode([]Type{int8}, []any{128}); err == nil {
+		t.Errorf("expected overflow encoding 128 as int8")
+	}
+
+	// return data with bits set above the declared size is rejected
+	dirty := make([]byte, 32)
+	dirty[15] = 1
+	if _, err := Decode([]Type{uint128}, dirty); err == nil {
+		t.Errorf("expected dirty uint128 to fail decoding")
+	}
+}
+
+func TestDecodeShortData(t *testing.T) {
+	types := []Type{MustParseType("bytes")}
+	encoded, err := Encode(types, []any{[]byte("Hello, world!")})
+	if err != nil {
+		t.Fatalf("Error encoding: %v", err)
+	}
+	// the offset, length and 13 bytes of content are needed, the trailing padding is not
+	for _, size := range []int{0, 16, 32, 48, 64, 76} {
+		if _, err := Decode(types, encoded[:size]); err == nil {
+			t.Errorf("expected truncated data of %d bytes to fail decoding", size)
+		}
+	}
+}
+
+func TestClaimData(t *testing.T) {
+	// a counter to the root claim of a game created at 1700000000, made 1000 seconds later
+	g := game.NewGame(game.Config{MaxClockDuration: 302400, ClockExtension: 10800, SplitDepth: 30, MaxGameDepth: 73}, 1700000000)
+	idx, err := g.Move(0, true, 1700001000)
+	if err != nil {
+		t.Fatalf("Error making move: %v", err)
+	}
+	claimant := eth.MustAddress("0x00000000000000000000000000000000000000CC")
+	claim := NewClaim(g.Claims[idx], claimant, big.NewInt(87594000000000000), eth.MustHash("0x1234"))
+	claim.CounteredBy = eth.MustAddress("0x00000000000000000000000000000000000000DD")
+
+	data, err := claim.Encode()
+	if err != nil {
+		t.Fatalf("Error encoding claim: %v", err)
+	}
+	if len(data) != 7*32 {
+		t.Fatalf("expected 7 words of return data, got %d bytes", len(data))
+	}
+
+	decoded, err := DecodeClaim(data)
+	if err != nil {
+		t.Fatalf("Error decoding claim: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, claim) {
+		t.Errorf("expected %+v, got %+v", claim, decoded)
+	}
+	gameClaim, err := decoded.GameClaim()
+	if err != nil {
+		t.Fatalf("Error converting claim: %v", err)
+	}
+	if gameClaim.ParentIndex != 0 || !gameClaim.Position.Equal(g.Claims[idx].Position) || gameClaim.Clock != g.Claims[idx].Clock {
+		t.Errorf("expected %+v, got %+v", g.Claims[idx], gameClaim)
+	}
+
+	// claimData(1) calldata
+	calldata, err := ClaimData.Pack(1)
+	if err != nil {
+		t.Fatalf("Error encoding call: %v", err)
+	}
+	if eth.EncodeHex(calldata) != "0xc6f0308c"+strings.Repeat("0", 63)+"1" {
+		t.Errorf("unexpected calldata %x", calldata)
+	}
+}
+
+func TestGameUUID(t *testing.T) {
+	rootClaim := eth.MustHash("0xabcd")
+	extraData := new(big.Int).SetUint64(21000000).FillBytes(make([]byte, 32))
+
+	uuid, err := GameUUID(0, rootClaim, extraData)
+	if err != nil {
+		t.Fatalf("Error computing uuid: %v", err)
+	}
+	// abi.encode(uint32, bytes32, bytes) puts the bytes after a three word head
+	preimage := mustHex(t, `0x
+		0000000000000000000000000000000000000000000000000000000000000000
+		000000000000000000000000000000000000000000000000000000000000abcd
+		0000000000000000000000000000000000000000000000000000000000000060
+		0000000000000000000000000000000000000000000000000000000000000020
+		0000000000000000000000000000000000000000000000000000000001406f40`)
+	if uuid != eth.Keccak256(preimage) {
+		t.Errorf("unexpected uuid %s", uuid)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	factory := eth.MustAddress("0x43edB88C4B80fDD2AdFF2412A7BebF9dF42cB40e")
+	proxy := eth.MustAddress("0x00000000000000000000000000000000000000BB")
+	rootClaim := eth.MustHash("0xabcd")
+
+	// DisputeGameCreated stores every parameter in the topics
+	log, err := DisputeGameCreated.Log(factory, proxy, uint32(0), rootClaim)
+	if err != nil {
+		t.Fatalf("Error encoding log: %v", err)
+	}
+	if len(log.Topics) != 4 || len(log.Data) != 0 || log.Topics[1] != eth.BytesToHash(proxy.Bytes()) || log.Topics[3] != rootClaim {
+		t.Errorf("unexpected log %+v", log)
+	}
+	values, err := DisputeGameCreated.Decode(log)
+	if err != nil {
+		t.Fatalf("Error decoding log: %v", err)
+	}
+	if values[0] != proxy || values[1].(*big.Int).Sign() != 0 || !bytes.Equal(values[2].([]byte), rootClaim.Bytes()) {
+		t.Errorf("unexpected values %v", values)
+	}
+
+	// ReceiveETH stores the amount in the data
+	log, err = ReceiveETH.Log(proxy, big.NewInt(1e18))
+	if err != nil {
+		t.Fatalf("Error encoding log: %v", err)
+	}
+	if len(log.Topics) != 1 || new(big.Int).SetBytes(log.Data).Cmp(big.NewInt(1e18)) != 0 {
+		t.Errorf("unexpected log %+v", log)
+	}
+
+	// a log for one event does not decode as another
+	if _, err := Resolved.Decode(log); err == nil {
+		t.Errorf("expected ReceiveETH log to fail decoding as Resolved")
+	}
+
+	// indexed dynamic parameters are hashed
+	e := MustParseEvent("Named(string indexed name, bytes data)")
+	topics, data, err := e.Encode("base", []byte{1, 2})
+	if err != nil {
+		t.Fatalf("Error encoding event: %v", err)
+	}
+	if topics[1] != eth.Keccak256([]byte("base")) {
+		t.Errorf("unexpected topic %s", topics[1])
+	}
+	values, err = e.Decode(eth.Log{Topics: topics, Data: data})
+	if err != nil {
+		t.Fatalf("Error decoding event: %v", err)
+	}
+	if values[0] != eth.Keccak256([]byte("base")) || !bytes.Equal(values[1].([]byte), []byte{1, 2}) {
+		t.Errorf("unexpected values %v", values)
+	}
+}
@@ -0,0 +1,327 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+var (
+	errShortData = errors.New("abi: data too short")
+
+	two256    = new(big.Int).Lsh(big.NewInt(1), 256)
+	maxOffset = big.NewInt(1 << 32)
+)
+
+// Encode ABI encodes values as a tuple of types, as used for call arguments, return data and event data.
+func Encode(types []Type, values []any) ([]byte, error) {
+	if len(types) != len(values) {
+		return nil, fmt.Errorf("abi: expected %d values, got %d", len(types), len(values))
+	}
+	return encodeTuple(types, values)
+}
+
+// Decode ABI decodes data as a tuple of types.
+func Decode(types []Type, data []byte) ([]any, error) {
+	return decodeTuple(types, data)
+}
+
+func encodeTuple(types []Type, values []any) ([]byte, error) {
+	headSize := 0
+	for _, t := range types {
+		headSize += t.headSize()
+	}
+	var head, tail []byte
+	for i, t := range types {
+		encoded, err := encodeValue(t, values[i])
+		if err != nil {
+			return nil, err
+		}
+		if t.Dynamic() {
+			head = append(head, encodeUint(big.NewInt(int64(headSize+len(tail))))...)
+			tail = append(tail, encoded...)
+		} else {
+			head = append(head, encoded...)
+		}
+	}
+	return append(head, tail...), nil
+}
+
+func encodeValue(t Type, v any) ([]byte, error) {
+	switch t.Kind {
+	case UintKind, IntKind:
+		n, err := toBig(v)
+		if err != nil {
+			return nil, err
+		}
+		if !fits(t, n) {
+			return nil, fmt.Errorf("abi: %s out of range for %s", n, t)
+		}
+		if n.Sign() < 0 {
+			n = new(big.Int).Add(n, two256)
+		}
+		return encodeUint(n), nil
+	case BoolKind:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("abi: cannot encode %T as bool", v)
+		}
+		if b {
+			return encodeUint(big.NewInt(1)), nil
+		}
+		return make([]byte, 32), nil
+	case AddressKind:
+		var addr eth.Address
+		switch a := v.(type) {
+		case eth.Address:
+			addr = a
+		case string:
+			parsed, err := eth.ParseAddress(a)
+			if err != nil {
+				return nil, err
+			}
+			addr = parsed
+		default:
+			return nil, fmt.Errorf("abi: cannot encode %T as address", v)
+		}
+		return leftPad(addr.Bytes()), nil
+	case FixedBytesKind:
+		b, err := toBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != t.Size {
+			return nil, fmt.Errorf("abi: expected %d bytes for %s, got %d", t.Size, t, len(b))
+		}
+		return rightPad(b), nil
+	case BytesKind, StringKind:
+		var b []byte
+		if s, ok := v.(string); ok && t.Kind == StringKind {
+			b = []byte(s)
+		} else {
+			var err error
+			if b, err = toBytes(v); err != nil {
+				return nil, err
+			}
+		}
+		return append(encodeUint(big.NewInt(int64(len(b)))), rightPad(b)...), nil
+	case SliceKind:
+		elems, err := toSlice(v)
+		if err != nil {
+			return nil, err
+		}
+		types := make([]Type, len(elems))
+		for i := range types {
+			types[i] = *t.Elem
+		}
+		encoded, err := encodeTuple(types, elems)
+		if err != nil {
+			return nil, err
+		}
+		return append(encodeUint(big.NewInt(int64(len(elems)))), encoded...), nil
+	case TupleKind:
+		elems, err := toSlice(v)
+		if err != nil {
+			return nil, err
+		}
+		if len(elems) != len(t.Components) {
+			return nil, fmt.Errorf("abi: expected %d values for %s, got %d", len(t.Components), t, len(elems))
+		}
+		return encodeTuple(t.Components, elems)
+	}
+	return nil, fmt.Errorf("abi: cannot encode %s", t)
+}
+
+func decodeTuple(types []Type, data []byte) ([]any, error) {
+	values := make([]any, len(types))
+	pos := 0
+	for i, t := range types {
+		if len(data) < pos+t.headSize() {
+			return nil, errShortData
+		}
+		if t.Dynamic() {
+			offset, err := readOffset(data[pos:])
+			if err != nil {
+				return nil, err
+			}
+			if offset > len(data) {
+				return nil, errShortData
+			}
+			if values[i], err = decodeValue(t, data[offset:]); err != nil {
+				return nil, err
+			}
+		} else {
+			var err error
+			if values[i], err = decodeValue(t, data[pos:]); err != nil {
+				return nil, err
+			}
+		}
+		pos += t.headSize()
+	}
+	return values, nil
+}
+
+func decodeValue(t Type, data []byte) (any, error) {
+	if t.Kind == TupleKind {
+		return decodeTuple(t.Components, data)
+	}
+	if len(data) < 32 {
+		return nil, errShortData
+	}
+	word := data[:32]
+	switch t.Kind {
+	case UintKind, IntKind:
+		n := new(big.Int).SetBytes(word)
+		if t.Kind == IntKind && word[0]&0x80 != 0 {
+			n.Sub(n, two256)
+		}
+		if !fits(t, n) {
+			return nil, fmt.Errorf("abi: value out of range for %s", t)
+		}
+		return n, nil
+	case BoolKind:
+		n := new(big.Int).SetBytes(word)
+		if n.BitLen() > 1 {
+			return nil, fmt.Errorf("abi: invalid bool %s", n)
+		}
+		return n.Sign() == 1, nil
+	case AddressKind:
+		if !isZero(word[:12]) {
+			return nil, fmt.Errorf("abi: dirty address padding %s", eth.EncodeHex(word))
+		}
+		return eth.BytesToAddress(word[12:]), nil
+	case FixedBytesKind:
+		if !isZero(word[t.Size:]) {
+			return nil, fmt.Errorf("abi: dirty %s padding %s", t, eth.EncodeHex(word))
+		}
+		return append([]byte(nil), word[:t.Size]...), nil
+	case BytesKind, StringKind:
+		size, err := readOffset(word)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < 32+size {
+			return nil, errShortData
+		}
+		b := append([]byte(nil), data[32:32+size]...)
+		if t.Kind == StringKind {
+			return string(b), nil
+		}
+		return b, nil
+	case SliceKind:
+		size, err := readOffset(word)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) < 32+size*t.Elem.headSize() {
+			return nil, errShortData
+		}
+		types := make([]Type, size)
+		for i := range types {
+			types[i] = *t.Elem
+		}
+		return decodeTuple(types, data[32:])
+	}
+	return nil, fmt.Errorf("abi: cannot decode %s", t)
+}
+
+// fits reports whether n is in the range of the integer type t.
+func fits(t Type, n *big.Int) bool {
+	if t.Kind == UintKind {
+		return n.Sign() >= 0 && n.BitLen() <= t.Size
+	}
+	if n.Sign() >= 0 {
+		return n.BitLen() < t.Size
+	}
+	// the most negative value -2^(size-1) has a bit length of size-1 after negation
+	abs := new(big.Int).Neg(n)
+	abs.Sub(abs, big.NewInt(1))
+	return abs.BitLen() < t.Size
+}
+
+func readOffset(word []byte) (int, error) {
+	n := new(big.Int).SetBytes(word[:32])
+	if n.Cmp(maxOffset) >= 0 {
+		return 0, fmt.Errorf("abi: offset %s too large", n)
+	}
+	return int(n.Int64()), nil
+}
+
+func encodeUint(n *big.Int) []byte {
+	return n.FillBytes(make([]byte, 32))
+}
+
+func leftPad(b []byte) []byte {
+	padded := make([]byte, 32)
+	copy(padded[32-len(b):], b)
+	return padded
+}
+
+func rightPad(b []byte) []byte {
+	padded := make([]byte, (len(b)+31)/32*32)
+	copy(padded, b)
+	return padded
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func toBig(v any) (*big.Int, error) {
+	switch n := v.(type) {
+	case *big.Int:
+		if n == nil {
+			return nil, errors.New("abi: cannot encode nil integer")
+		}
+		return n, nil
+	case *eth.Big:
+		return n.Int(), nil
+	case eth.Uint64:
+		return new(big.Int).SetUint64(uint64(n)), nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(rv.Uint()), nil
+	}
+	return nil, fmt.Errorf("abi: cannot encode %T as integer", v)
+}
+
+func toBytes(v any) ([]byte, error) {
+	switch b := v.(type) {
+	case []byte:
+		return b, nil
+	case eth.Bytes:
+		return b, nil
+	case eth.Hash:
+		return b.Bytes(), nil
+	case string:
+		return eth.DecodeHex(b)
+	}
+	return nil, fmt.Errorf("abi: cannot encode %T as bytes", v)
+}
+
+func toSlice(v any) ([]any, error) {
+	if values, ok := v.([]any); ok {
+		return values, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("abi: cannot encode %T as a list", v)
+	}
+	values := make([]any, rv.Len())
+	for i := range values {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values, nil
+}
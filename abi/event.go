@@ -0,0 +1,156 @@
+package abi
+
+import (
+	"fmt"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// Event is a contract event.
+type Event struct {
+	Name   string
+	Inputs []Argument
+}
+
+// ParseEvent parses an event signature such as `Move(uint256 indexed parentIndex, bytes32 indexed claim,
+// address indexed claimant)`.
+func ParseEvent(sig string) (Event, error) {
+	name, inputs, outputs, err := parseSignature(sig)
+	if err != nil {
+		return Event{}, err
+	}
+	if outputs != nil {
+		return Event{}, fmt.Errorf("abi: event %q has return values", sig)
+	}
+	indexed := 0
+	for _, arg := range inputs {
+		if !arg.Indexed {
+			continue
+		}
+		if arg.Type.Kind == SliceKind || arg.Type.Kind == TupleKind {
+			return Event{}, fmt.Errorf("abi: indexed %s parameters are not supported in %q", arg.Type, sig)
+		}
+		indexed++
+	}
+	if indexed > 3 {
+		return Event{}, fmt.Errorf("abi: event %q has more than 3 indexed parameters", sig)
+	}
+	return Event{Name: name, Inputs: inputs}, nil
+}
+
+// MustParseEvent is like ParseEvent but panics on error. It is intended for package level declarations.
+func MustParseEvent(sig string) Event {
+	e, err := ParseEvent(sig)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Signature returns the canonical signature the topic is derived from, e.g. `Resolved(uint8)`.
+func (e Event) Signature() string {
+	return e.Name + "(" + joinTypes(argumentTypes(e.Inputs)) + ")"
+}
+
+// Topic returns the first topic of logs emitted for the event.
+func (e Event) Topic() eth.Hash {
+	return eth.Keccak256([]byte(e.Signature()))
+}
+
+func (e Event) split() (indexed, data []Argument) {
+	for _, arg := range e.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		} else {
+			data = append(data, arg)
+		}
+	}
+	return indexed, data
+}
+
+// Decode returns the event parameters of log in declaration order. Indexed parameters of dynamic type are only
+// stored as their hash, so they are returned as an eth.Hash. Indexed arrays and tuples are not supported.
+func (e Event) Decode(log eth.Log) ([]any, error) {
+	indexed, data := e.split()
+	if len(log.Topics) != len(indexed)+1 || log.Topics[0] != e.Topic() {
+		return nil, fmt.Errorf("abi: log is not a %s event", e.Signature())
+	}
+	dataValues, err := Decode(argumentTypes(data), log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", e.Name, err)
+	}
+
+	values := make([]any, 0, len(e.Inputs))
+	topics := log.Topics[1:]
+	for _, arg := range e.Inputs {
+		if !arg.Indexed {
+			values = append(values, dataValues[0])
+			dataValues = dataValues[1:]
+			continue
+		}
+		topic := topics[0]
+		topics = topics[1:]
+		if arg.Type.Dynamic() {
+			values = append(values, topic)
+			continue
+		}
+		decoded, err := decodeValue(arg.Type, topic.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%s: topic %s: %w", e.Name, arg.Name, err)
+		}
+		values = append(values, decoded)
+	}
+	return values, nil
+}
+
+// Encode builds the topics and data of a log emitting the event with values in declaration order, e.g. to mock
+// the result of `eth_getLogs`. Indexed parameters of dynamic type are hashed.
+func (e Event) Encode(values ...any) ([]eth.Hash, []byte, error) {
+	if len(values) != len(e.Inputs) {
+		return nil, nil, fmt.Errorf("abi: expected %d values for %s, got %d", len(e.Inputs), e.Name, len(values))
+	}
+	topics := []eth.Hash{e.Topic()}
+	var dataTypes []Type
+	var dataValues []any
+	for i, arg := range e.Inputs {
+		if !arg.Indexed {
+			dataTypes = append(dataTypes, arg.Type)
+			dataValues = append(dataValues, values[i])
+			continue
+		}
+		if arg.Type.Dynamic() {
+			// only the contents of bytes and strings are hashed, without the length prefix and padding
+			var contents []byte
+			if s, ok := values[i].(string); ok && arg.Type.Kind == StringKind {
+				contents = []byte(s)
+			} else {
+				b, err := toBytes(values[i])
+				if err != nil {
+					return nil, nil, fmt.Errorf("%s: topic %s: %w", e.Name, arg.Name, err)
+				}
+				contents = b
+			}
+			topics = append(topics, eth.Keccak256(contents))
+			continue
+		}
+		encoded, err := encodeValue(arg.Type, values[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: topic %s: %w", e.Name, arg.Name, err)
+		}
+		topics = append(topics, eth.BytesToHash(encoded))
+	}
+	data, err := Encode(dataTypes, dataValues)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", e.Name, err)
+	}
+	return topics, data, nil
+}
+
+// Log returns a log emitting the event from address, with the remaining fields left for the caller to fill in.
+func (e Event) Log(address eth.Address, values ...any) (eth.Log, error) {
+	topics, data, err := e.Encode(values...)
+	if err != nil {
+		return eth.Log{}, err
+	}
+	return eth.Log{Address: address, Topics: topics, Data: data}, nil
+}
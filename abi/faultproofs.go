@@ -0,0 +1,97 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/game"
+)
+
+// Fault proof contract methods and events, using the signatures from the gate files.
+var (
+	ClaimData       = MustParseMethod("claimData(uint256) returns (uint32 parentIndex, address counteredBy, address claimant, uint128 bond, bytes32 claim, uint128 position, uint128 clock)")
+	ClaimDataLen    = MustParseMethod("claimDataLen() returns (uint256 len_)")
+	Withdrawals     = MustParseMethod("withdrawals(address game, address recipient) returns (uint256 amount, uint256 timestamp)")
+	GetGameUUID     = MustParseMethod("getGameUUID(uint32 _gameType, bytes32 _rootClaim, bytes _extraData) returns (bytes32 uuid_)")
+	Create          = MustParseMethod("create(uint32 _gameType, bytes32 _rootClaim, bytes _extraData) payable returns (address proxy_)")
+	GetRequiredBond = MustParseMethod("getRequiredBond(uint128 _position) returns (uint256 requiredBond_)")
+
+	Move               = MustParseEvent("Move(uint256 indexed parentIndex, bytes32 indexed claim, address indexed claimant)")
+	Resolved           = MustParseEvent("Resolved(uint8 indexed status)")
+	DisputeGameCreated = MustParseEvent("DisputeGameCreated(address indexed disputeProxy, uint32 indexed gameType, bytes32 indexed rootClaim)")
+	ReceiveETH         = MustParseEvent("ReceiveETH(uint256 amount)")
+)
+
+// Claim is the decoded result of `claimData(i)`.
+type Claim struct {
+	ParentIndex uint32
+	CounteredBy eth.Address
+	Claimant    eth.Address
+	Bond        *big.Int
+	// Value is the claimed output root or trace commitment.
+	Value    eth.Hash
+	Position *big.Int
+	Clock    *big.Int
+}
+
+// DecodeClaim decodes the return data of `claimData(i)`.
+func DecodeClaim(data []byte) (Claim, error) {
+	values, err := ClaimData.UnpackOutputs(data)
+	if err != nil {
+		return Claim{}, err
+	}
+	return Claim{
+		ParentIndex: uint32(values[0].(*big.Int).Uint64()),
+		CounteredBy: values[1].(eth.Address),
+		Claimant:    values[2].(eth.Address),
+		Bond:        values[3].(*big.Int),
+		Value:       eth.BytesToHash(values[4].([]byte)),
+		Position:    values[5].(*big.Int),
+		Clock:       values[6].(*big.Int),
+	}, nil
+}
+
+// Values returns the claim as `claimData(i)` return values, in the shape of a gate mock.
+func (c Claim) Values() []any {
+	return []any{c.ParentIndex, c.CounteredBy, c.Claimant, c.Bond, c.Value, c.Position, c.Clock}
+}
+
+// Encode returns the claim as `claimData(i)` return data.
+func (c Claim) Encode() ([]byte, error) {
+	return ClaimData.PackOutputs(c.Values()...)
+}
+
+// GameClaim converts the claim to the subset used by the clock and bond rules.
+func (c Claim) GameClaim() (game.Claim, error) {
+	position, err := game.PositionFromGIndex(c.Position)
+	if err != nil {
+		return game.Claim{}, err
+	}
+	clock, err := game.DecodeClock(c.Clock)
+	if err != nil {
+		return game.Claim{}, err
+	}
+	return game.Claim{ParentIndex: c.ParentIndex, Position: position, Clock: clock}, nil
+}
+
+// NewClaim builds a claim from the clock and bond rules view, e.g. to encode mock `claimData(i)` results.
+func NewClaim(claim game.Claim, claimant eth.Address, bond *big.Int, value eth.Hash) Claim {
+	return Claim{
+		ParentIndex: claim.ParentIndex,
+		Claimant:    claimant,
+		Bond:        bond,
+		Value:       value,
+		Position:    claim.Position.GIndex(),
+		Clock:       claim.Clock.Raw(),
+	}
+}
+
+// GameUUID returns the UUID the DisputeGameFactory assigns to a game, matching `getGameUUID`.
+func GameUUID(gameType uint32, rootClaim eth.Hash, extraData []byte) (eth.Hash, error) {
+	encoded, err := Encode(argumentTypes(GetGameUUID.Inputs), []any{gameType, rootClaim, extraData})
+	if err != nil {
+		return eth.Hash{}, fmt.Errorf("encoding game uuid: %w", err)
+	}
+	return eth.Keccak256(encoded), nil
+}
@@ -0,0 +1,84 @@
+package abi
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// Method is a contract function.
+type Method struct {
+	Name    string
+	Inputs  []Argument
+	Outputs []Argument
+}
+
+// ParseMethod parses a function signature such as `withdrawals(address game, address recipient) returns
+// (uint256 amount, uint256 timestamp)`.
+func ParseMethod(sig string) (Method, error) {
+	name, inputs, outputs, err := parseSignature(sig)
+	if err != nil {
+		return Method{}, err
+	}
+	for _, arg := range inputs {
+		if arg.Indexed {
+			return Method{}, fmt.Errorf("abi: indexed parameter in method %q", sig)
+		}
+	}
+	return Method{Name: name, Inputs: inputs, Outputs: outputs}, nil
+}
+
+// MustParseMethod is like ParseMethod but panics on error. It is intended for package level declarations.
+func MustParseMethod(sig string) Method {
+	m, err := ParseMethod(sig)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Signature returns the canonical signature the selector is derived from, e.g. `claimData(uint256)`.
+func (m Method) Signature() string {
+	return m.Name + "(" + joinTypes(argumentTypes(m.Inputs)) + ")"
+}
+
+// Selector returns the first four bytes of the hash of the signature.
+func (m Method) Selector() []byte {
+	return eth.Keccak256([]byte(m.Signature())).Bytes()[:4]
+}
+
+// Pack returns the calldata calling the method with args.
+func (m Method) Pack(args ...any) ([]byte, error) {
+	encoded, err := Encode(argumentTypes(m.Inputs), args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.Name, err)
+	}
+	return append(m.Selector(), encoded...), nil
+}
+
+// UnpackInputs decodes the arguments from calldata, which must start with the method selector.
+func (m Method) UnpackInputs(calldata []byte) ([]any, error) {
+	if len(calldata) < 4 || !bytes.Equal(calldata[:4], m.Selector()) {
+		return nil, fmt.Errorf("abi: calldata is not a call to %s", m.Signature())
+	}
+	return Decode(argumentTypes(m.Inputs), calldata[4:])
+}
+
+// PackOutputs encodes return values, e.g. to mock the result of an `eth_call`.
+func (m Method) PackOutputs(values ...any) ([]byte, error) {
+	encoded, err := Encode(argumentTypes(m.Outputs), values)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.Name, err)
+	}
+	return encoded, nil
+}
+
+// UnpackOutputs decodes the return data of a call.
+func (m Method) UnpackOutputs(data []byte) ([]any, error) {
+	values, err := Decode(argumentTypes(m.Outputs), data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", m.Name, err)
+	}
+	return values, nil
+}
@@ -0,0 +1,139 @@
+package abi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Argument is a named parameter of a method or event.
+type Argument struct {
+	Name string
+	Type Type
+	// Indexed marks event parameters that are stored in the log topics rather than the data.
+	Indexed bool
+}
+
+// parseSignature splits a human readable signature into its name, parameters and, after the `returns`
+// keyword, its return values. State mutability and visibility keywords are ignored.
+func parseSignature(sig string) (string, []Argument, []Argument, error) {
+	sig = strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(sig), "function "), "event "))
+	open := strings.Index(sig, "(")
+	if open <= 0 {
+		return "", nil, nil, fmt.Errorf("abi: invalid signature %q", sig)
+	}
+	name := strings.TrimSpace(sig[:open])
+	inputs, rest, err := parseArguments(sig[open:])
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("abi: invalid signature %q: %w", sig, err)
+	}
+
+	var outputs []Argument
+	rest = strings.TrimSpace(rest)
+	for rest != "" {
+		if strings.HasPrefix(rest, "returns") {
+			outputs, rest, err = parseArguments(strings.TrimSpace(strings.TrimPrefix(rest, "returns")))
+			if err != nil {
+				return "", nil, nil, fmt.Errorf("abi: invalid signature %q: %w", sig, err)
+			}
+			if strings.TrimSpace(rest) != "" {
+				return "", nil, nil, fmt.Errorf("abi: unexpected %q after returns in %q", rest, sig)
+			}
+			break
+		}
+		word := strings.Fields(rest)[0]
+		switch word {
+		case "view", "pure", "payable", "nonpayable", "external", "public", "anonymous":
+		default:
+			return "", nil, nil, fmt.Errorf("abi: unexpected %q in signature %q", word, sig)
+		}
+		rest = strings.TrimSpace(rest[len(word):])
+	}
+	return name, inputs, outputs, nil
+}
+
+// parseArguments parses a parenthesized argument list at the start of s and returns the remainder.
+func parseArguments(s string) ([]Argument, string, error) {
+	if !strings.HasPrefix(s, "(") {
+		return nil, "", fmt.Errorf("expected argument list at %q", s)
+	}
+	depth := 0
+	end := -1
+	for i, r := range s {
+		if r == '(' {
+			depth++
+		} else if r == ')' {
+			depth--
+			if depth == 0 {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return nil, "", fmt.Errorf("unterminated argument list %q", s)
+	}
+
+	params, err := splitParams(s[1:end])
+	if err != nil {
+		return nil, "", err
+	}
+	args := make([]Argument, len(params))
+	for i, param := range params {
+		if args[i], err = parseArgument(param); err != nil {
+			return nil, "", err
+		}
+	}
+	return args, s[end+1:], nil
+}
+
+// parseArgument parses `type [indexed] [location] [name]`, where the type may be a parenthesized tuple.
+func parseArgument(param string) (Argument, error) {
+	var typ string
+	var words []string
+	if strings.HasPrefix(param, "(") {
+		_, rest, err := parseArguments(param)
+		if err != nil {
+			return Argument{}, err
+		}
+		fields := strings.Fields(rest)
+		typ = param[:len(param)-len(rest)]
+		if len(fields) > 0 && strings.HasPrefix(fields[0], "[]") {
+			typ += fields[0]
+			fields = fields[1:]
+		}
+		words = fields
+	} else {
+		fields := strings.Fields(param)
+		if len(fields) == 0 {
+			return Argument{}, fmt.Errorf("empty parameter")
+		}
+		typ, words = fields[0], fields[1:]
+	}
+
+	t, err := ParseType(typ)
+	if err != nil {
+		return Argument{}, err
+	}
+	arg := Argument{Type: t}
+	for _, word := range words {
+		switch word {
+		case "indexed":
+			arg.Indexed = true
+		case "memory", "calldata", "storage", "payable":
+		default:
+			if arg.Name != "" {
+				return Argument{}, fmt.Errorf("unexpected %q in parameter %q", word, param)
+			}
+			arg.Name = word
+		}
+	}
+	return arg, nil
+}
+
+func argumentTypes(args []Argument) []Type {
+	types := make([]Type, len(args))
+	for i, arg := range args {
+		types[i] = arg.Type
+	}
+	return types
+}
@@ -0,0 +1,202 @@
+// Package abi encodes and decodes Solidity ABI data for the fault proof contract calls and events the monitors
+// use. Methods and events are described with the same human readable signatures the gate files use, e.g.
+// `claimData(uint256) returns (uint32,address,address,uint128,bytes32,uint128,uint128)`.
+//
+// Decoded values use *big.Int for every integer, bool, eth.Address, []byte for fixed and dynamic bytes, string,
+// and []any for arrays and tuples. Encoding accepts the same values as well as native Go integers, eth.Hash,
+// eth.Bytes and 0x prefixed hex strings for addresses and bytes.
+package abi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind is the category of an ABI type.
+type Kind int
+
+const (
+	UintKind Kind = iota
+	IntKind
+	BoolKind
+	AddressKind
+	FixedBytesKind
+	BytesKind
+	StringKind
+	SliceKind
+	TupleKind
+)
+
+// Type is a parsed ABI type.
+type Type struct {
+	Kind Kind
+	// Size is the bit size of integers and the byte size of fixed bytes.
+	Size int
+	// Elem is the element type of a slice.
+	Elem *Type
+	// Components are the member types of a tuple.
+	Components []Type
+}
+
+// ParseType parses a canonical or tuple type such as `uint128`, `bytes`, `address[]` or `(uint256,bool)`.
+func ParseType(s string) (Type, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasSuffix(s, "[]") {
+		elem, err := ParseType(s[:len(s)-2])
+		if err != nil {
+			return Type{}, err
+		}
+		return Type{Kind: SliceKind, Elem: &elem}, nil
+	}
+	if strings.HasPrefix(s, "(") {
+		if !strings.HasSuffix(s, ")") {
+			return Type{}, fmt.Errorf("unterminated tuple type %q", s)
+		}
+		params, err := splitParams(s[1 : len(s)-1])
+		if err != nil {
+			return Type{}, err
+		}
+		components := make([]Type, len(params))
+		for i, param := range params {
+			if components[i], err = ParseType(param); err != nil {
+				return Type{}, err
+			}
+		}
+		return Type{Kind: TupleKind, Components: components}, nil
+	}
+
+	switch {
+	case s == "bool":
+		return Type{Kind: BoolKind}, nil
+	case s == "address":
+		return Type{Kind: AddressKind}, nil
+	case s == "bytes":
+		return Type{Kind: BytesKind}, nil
+	case s == "string":
+		return Type{Kind: StringKind}, nil
+	case strings.HasPrefix(s, "uint"):
+		size, err := parseSize(s, "uint", 256, 8)
+		return Type{Kind: UintKind, Size: size}, err
+	case strings.HasPrefix(s, "int"):
+		size, err := parseSize(s, "int", 256, 8)
+		return Type{Kind: IntKind, Size: size}, err
+	case strings.HasPrefix(s, "bytes"):
+		size, err := parseSize(s, "bytes", 32, 1)
+		if err == nil && s == "bytes" {
+			err = fmt.Errorf("invalid type %q", s)
+		}
+		return Type{Kind: FixedBytesKind, Size: size}, err
+	}
+	return Type{}, fmt.Errorf("unsupported type %q", s)
+}
+
+// MustParseType is like ParseType but panics on error. It is intended for package level declarations.
+func MustParseType(s string) Type {
+	t, err := ParseType(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func parseSize(s, prefix string, max, step int) (int, error) {
+	digits := strings.TrimPrefix(s, prefix)
+	if digits == "" {
+		return max, nil
+	}
+	size, err := strconv.Atoi(digits)
+	if err != nil || size <= 0 || size > max || size%step != 0 {
+		return 0, fmt.Errorf("invalid type %q", s)
+	}
+	return size, nil
+}
+
+// String returns the canonical form of the type used in signatures.
+func (t Type) String() string {
+	switch t.Kind {
+	case UintKind:
+		return "uint" + strconv.Itoa(t.Size)
+	case IntKind:
+		return "int" + strconv.Itoa(t.Size)
+	case BoolKind:
+		return "bool"
+	case AddressKind:
+		return "address"
+	case FixedBytesKind:
+		return "bytes" + strconv.Itoa(t.Size)
+	case BytesKind:
+		return "bytes"
+	case StringKind:
+		return "string"
+	case SliceKind:
+		return t.Elem.String() + "[]"
+	case TupleKind:
+		return "(" + joinTypes(t.Components) + ")"
+	}
+	return "unknown"
+}
+
+// Dynamic reports whether the type is encoded out of line with an offset in the head.
+func (t Type) Dynamic() bool {
+	switch t.Kind {
+	case BytesKind, StringKind, SliceKind:
+		return true
+	case TupleKind:
+		for _, c := range t.Components {
+			if c.Dynamic() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// headSize is the number of bytes the type occupies in the head of an enclosing tuple.
+func (t Type) headSize() int {
+	if t.Kind == TupleKind && !t.Dynamic() {
+		size := 0
+		for _, c := range t.Components {
+			size += c.headSize()
+		}
+		return size
+	}
+	return 32
+}
+
+func joinTypes(types []Type) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = t.String()
+	}
+	return strings.Join(names, ",")
+}
+
+// splitParams splits a comma separated parameter list at the top level, leaving nested tuples intact.
+func splitParams(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var params []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses in %q", s)
+			}
+		case ',':
+			if depth == 0 {
+				params = append(params, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses in %q", s)
+	}
+	return append(params, strings.TrimSpace(s[start:])), nil
+}
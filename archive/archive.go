@@ -0,0 +1,255 @@
+// Package archive stores the JSON-RPC responses read while evaluating monitors over a block range, so that the
+// evaluation can be replayed offline. An archive is a directory holding a manifest and the responses as
+// content-addressed objects:
+//
+//	manifest.json
+//	objects/<sha256 of the response>.json
+//
+// The manifest maps every request, keyed by method and canonical params, to the object holding its response. The
+// same response read by many requests, such as an unchanged claim read at every block, is only stored once.
+package archive
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+// Version is the version of the archive format written by this package.
+const Version = 1
+
+const (
+	manifestFile = "manifest.json"
+	objectsDir   = "objects"
+)
+
+// ErrNotRecorded is the message of the JSON-RPC error returned for requests that are not in the archive.
+var ErrNotRecorded = errors.New("request not recorded in archive")
+
+// Monitor is a monitor deployment evaluated over the archive, as it would be configured in Hexagate.
+type Monitor struct {
+	Name   string         `json:"name"`
+	Params map[string]any `json:"params"`
+}
+
+// Manifest describes the contents of an archive.
+type Manifest struct {
+	Version     int    `json:"version"`
+	Description string `json:"description,omitempty"`
+	// ChainID is the chain the monitors are deployed on.
+	ChainID   uint64        `json:"chainId"`
+	FromBlock uint64        `json:"fromBlock"`
+	ToBlock   uint64        `json:"toBlock"`
+	Games     []eth.Address `json:"games,omitempty"`
+	Monitors  []Monitor     `json:"monitors,omitempty"`
+	// Responses maps the key of each request made to the chain to the ID of the object holding its response.
+	Responses map[string]string `json:"responses"`
+	// L2Responses holds the responses of the L2 chains whose outputs are proposed, keyed by chain ID.
+	L2Responses map[uint64]map[string]string `json:"l2Responses,omitempty"`
+}
+
+// responses returns the responses recorded for the L2 chain with the given ID, or for the chain the monitors are
+// deployed on if it is zero.
+func (m *Manifest) responses(l2ChainID uint64) map[string]string {
+	if l2ChainID == 0 {
+		return m.Responses
+	}
+	return m.L2Responses[l2ChainID]
+}
+
+// object is a recorded JSON-RPC response, without the ID of the request it answered.
+type object struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *rpc.Error      `json:"error,omitempty"`
+}
+
+// Key returns the key a request is recorded under: its method followed by its params in canonical form, so that
+// requests differing only in the case of hex strings or the order of object fields share a key.
+func Key(method string, params json.RawMessage) (string, error) {
+	var decoded any
+	if len(params) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(params))
+		dec.UseNumber()
+		if err := dec.Decode(&decoded); err != nil {
+			return "", fmt.Errorf("decoding params of %s: %w", method, err)
+		}
+	}
+	if decoded == nil {
+		decoded = []any{}
+	}
+	canonical, err := json.Marshal(canonicalize(decoded))
+	if err != nil {
+		return "", err
+	}
+	return method + " " + string(canonical), nil
+}
+
+func canonicalize(v any) any {
+	switch v := v.(type) {
+	case string:
+		return strings.ToLower(v)
+	case []any:
+		for i := range v {
+			v[i] = canonicalize(v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = canonicalize(v[k])
+		}
+	}
+	return v
+}
+
+// Archive is an archive opened for replay.
+type Archive struct {
+	dir      string
+	manifest Manifest
+
+	mu      sync.Mutex
+	objects map[string]json.RawMessage
+}
+
+// Open opens the archive in dir.
+func Open(dir string) (*Archive, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("reading archive manifest: %w", err)
+	}
+	a := &Archive{dir: dir, objects: make(map[string]json.RawMessage)}
+	if err := json.Unmarshal(data, &a.manifest); err != nil {
+		return nil, fmt.Errorf("decoding archive manifest: %w", err)
+	}
+	if a.manifest.Version != Version {
+		return nil, fmt.Errorf("unsupported archive version %d", a.manifest.Version)
+	}
+	return a, nil
+}
+
+// Manifest returns the manifest of the archive.
+func (a *Archive) Manifest() Manifest {
+	return a.manifest
+}
+
+// Client returns a JSON-RPC client answering from the responses recorded for the L2 chain with the given ID, or
+// for the chain the monitors are deployed on if it is zero. Requests that were not recorded fail.
+func (a *Archive) Client(l2ChainID uint64) *rpc.Client {
+	client := rpc.NewClient(fmt.Sprintf("archive://%s/%d", a.dir, l2ChainID))
+	client.SetHTTPClient(&http.Client{Transport: &transport{archive: a, l2ChainID: l2ChainID}})
+	return client
+}
+
+// Response returns the recorded response of a request.
+func (a *Archive) Response(l2ChainID uint64, method string, params json.RawMessage) (json.RawMessage, *rpc.Error, error) {
+	key, err := Key(method, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	id, ok := a.manifest.responses(l2ChainID)[key]
+	if !ok {
+		return nil, &rpc.Error{Code: -32000, Message: fmt.Sprintf("%s: %s", ErrNotRecorded, key)}, nil
+	}
+	data, err := a.object(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	var obj object
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, nil, fmt.Errorf("decoding object %s: %w", id, err)
+	}
+	return obj.Result, obj.Error, nil
+}
+
+// object reads an object, checking it against its ID.
+func (a *Archive) object(id string) (json.RawMessage, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if data, ok := a.objects[id]; ok {
+		return data, nil
+	}
+	data, err := os.ReadFile(filepath.Join(a.dir, objectsDir, id+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("reading object: %w", err)
+	}
+	if objectID(data) != id {
+		return nil, fmt.Errorf("object %s is corrupted", id)
+	}
+	a.objects[id] = data
+	return data, nil
+}
+
+func objectID(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// transport answers JSON-RPC requests, including batches, from an archive.
+type transport struct {
+	archive   *Archive
+	l2ChainID uint64
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var result any
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var reqs []rpc.Request
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			return nil, err
+		}
+		resps := make([]rpc.Response, len(reqs))
+		for i := range reqs {
+			if resps[i], err = t.respond(&reqs[i]); err != nil {
+				return nil, err
+			}
+		}
+		result = resps
+	} else {
+		var r rpc.Request
+		if err := json.Unmarshal(body, &r); err != nil {
+			return nil, err
+		}
+		if result, err = t.respond(&r); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(data)),
+		Request:    req,
+	}, nil
+}
+
+func (t *transport) respond(req *rpc.Request) (rpc.Response, error) {
+	result, rpcErr, err := t.archive.Response(t.l2ChainID, req.Method, req.Params)
+	if err != nil {
+		return rpc.Response{}, err
+	}
+	if rpcErr == nil && len(result) == 0 {
+		result = json.RawMessage("null")
+	}
+	return rpc.Response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr}, nil
+}
@@ -0,0 +1,109 @@
+package archive_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/archive"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+func TestReplay(t *testing.T) {
+	dir := t.TempDir()
+	game := eth.MustAddress("0x00000000000000000000000000000000000000bB")
+	w, err := archive.Create(dir, archive.Manifest{ChainID: 8453, FromBlock: 100, ToBlock: 100, Games: []eth.Address{game}})
+	if err != nil {
+		t.Fatalf("Error creating archive: %v", err)
+	}
+	// the same output recorded for two calls is stored once
+	output := json.RawMessage(`"0x0000000000000000000000000000000000000000000000000000000000000001"`)
+	records := []struct {
+		params string
+		result json.RawMessage
+		err    *rpc.Error
+	}{
+		{params: `[{"to":"0x00000000000000000000000000000000000000bb","data":"0x01"},"0x64"]`, result: output},
+		{params: `[{"to":"0x00000000000000000000000000000000000000bb","data":"0x02"},"0x64"]`, result: output},
+		{params: `[{"to":"0x00000000000000000000000000000000000000bb","data":"0x03"},"0x64"]`, err: &rpc.Error{Code: 3, Message: "execution reverted"}},
+	}
+	for _, r := range records {
+		if err := w.Record(0, "eth_call", json.RawMessage(r.params), r.result, r.err); err != nil {
+			t.Fatalf("Error recording call: %v", err)
+		}
+	}
+	if err := w.Record(10, "eth_blockNumber", nil, json.RawMessage(`"0x10"`), nil); err != nil {
+		t.Fatalf("Error recording L2 call: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Error closing archive: %v", err)
+	}
+	if objects, _ := os.ReadDir(filepath.Join(dir, "objects")); len(objects) != 3 {
+		t.Errorf("expected 3 objects, got %d", len(objects))
+	}
+
+	a, err := archive.Open(dir)
+	if err != nil {
+		t.Fatalf("Error opening archive: %v", err)
+	}
+	if m := a.Manifest(); m.ChainID != 8453 || len(m.Games) != 1 || m.Games[0] != game || len(m.Responses) != 3 {
+		t.Errorf("unexpected manifest %+v", m)
+	}
+
+	// requests are matched regardless of address checksums, and batches are answered element by element
+	client := a.Client(0)
+	msgs := []rpc.CallMsg{{To: game, Data: eth.Bytes{1}}, {To: game, Data: eth.Bytes{3}}, {To: game, Data: eth.Bytes{4}}}
+	outputs, errs, err := client.BatchCallContract(context.Background(), msgs, rpc.NumberAt(100))
+	if err != nil {
+		t.Fatalf("Error replaying batch: %v", err)
+	}
+	if eth.BytesToHash(outputs[0]) != eth.MustHash("0x01") {
+		t.Errorf("unexpected output %s", eth.EncodeHex(outputs[0]))
+	}
+	var rpcErr *rpc.Error
+	if !errors.As(errs[1], &rpcErr) || rpcErr.Code != 3 {
+		t.Errorf("expected recorded revert, got %v", errs[1])
+	}
+	if errs[2] == nil || !strings.Contains(errs[2].Error(), archive.ErrNotRecorded.Error()) {
+		t.Errorf("expected unrecorded call to fail, got %v", errs[2])
+	}
+
+	number, err := a.Client(10).BlockNumber(context.Background())
+	if err != nil || number != 16 {
+		t.Errorf("expected L2 block 16, got %d (%v)", number, err)
+	}
+	if _, err := client.BlockNumber(context.Background()); err == nil {
+		t.Errorf("expected L2 responses to be separate from L1 responses")
+	}
+}
+
+func TestCorruptedObject(t *testing.T) {
+	dir := t.TempDir()
+	w, err := archive.Create(dir, archive.Manifest{})
+	if err != nil {
+		t.Fatalf("Error creating archive: %v", err)
+	}
+	if err := w.Record(0, "eth_blockNumber", nil, json.RawMessage(`"0x10"`), nil); err != nil {
+		t.Fatalf("Error recording call: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Error closing archive: %v", err)
+	}
+	objects, _ := filepath.Glob(filepath.Join(dir, "objects", "*.json"))
+	if err := os.WriteFile(objects[0], []byte(`{"result":"0x11"}`), 0o644); err != nil {
+		t.Fatalf("Error tampering with object: %v", err)
+	}
+
+	a, err := archive.Open(dir)
+	if err != nil {
+		t.Fatalf("Error opening archive: %v", err)
+	}
+	if _, err := a.Client(0).BlockNumber(context.Background()); err == nil || !strings.Contains(err.Error(), "corrupted") {
+		t.Errorf("expected corrupted object error, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+// revertedCode is the JSON-RPC error code of a reverted call. Reverts are recorded since they are part of the state
+// of the chain, while other errors such as rate limits are not.
+const revertedCode = 3
+
+// Client returns a JSON-RPC client for the endpoint at url that records every response into the archive, under the
+// L2 chain with the given ID, or the chain the monitors are deployed on if it is zero.
+func (w *Writer) Client(url string, l2ChainID uint64) *rpc.Client {
+	client := rpc.NewClient(url)
+	client.SetHTTPClient(&http.Client{Transport: &recorder{writer: w, l2ChainID: l2ChainID, base: http.DefaultTransport}})
+	return client
+}
+
+// recorder forwards JSON-RPC requests, including batches, and records their responses.
+type recorder struct {
+	writer    *Writer
+	l2ChainID uint64
+	base      http.RoundTripper
+}
+
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	forwarded := req.Clone(req.Context())
+	forwarded.Body = io.NopCloser(bytes.NewReader(body))
+	forwarded.ContentLength = int64(len(body))
+
+	resp, err := r.base.RoundTrip(forwarded)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	if err := r.record(body, respBody); err != nil {
+		return nil, fmt.Errorf("recording response: %w", err)
+	}
+	return resp, nil
+}
+
+func (r *recorder) record(body, respBody []byte) error {
+	var reqs []rpc.Request
+	var resps []rpc.Response
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(respBody, &resps); err != nil {
+			return err
+		}
+	} else {
+		var req rpc.Request
+		var resp rpc.Response
+		if err := json.Unmarshal(body, &req); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(respBody, &resp); err != nil {
+			return err
+		}
+		reqs, resps = []rpc.Request{req}, []rpc.Response{resp}
+	}
+
+	byID := make(map[string]*rpc.Request, len(reqs))
+	for i := range reqs {
+		byID[string(reqs[i].ID)] = &reqs[i]
+	}
+	for _, resp := range resps {
+		req, ok := byID[string(resp.ID)]
+		if !ok || (resp.Error != nil && resp.Error.Code != revertedCode) {
+			continue
+		}
+		if err := r.writer.Record(r.l2ChainID, req.Method, req.Params, resp.Result, resp.Error); err != nil {
+			return err
+		}
+	}
+	return nil
+}
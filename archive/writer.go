@@ -0,0 +1,101 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+// Writer creates an archive. Responses are written as they are recorded, and the manifest when the writer is closed.
+type Writer struct {
+	dir string
+
+	mu       sync.Mutex
+	manifest Manifest
+}
+
+// Create starts an archive in dir, which is created if it does not exist. The responses of manifest are replaced by
+// the ones recorded through the writer.
+func Create(dir string, manifest Manifest) (*Writer, error) {
+	if err := os.MkdirAll(filepath.Join(dir, objectsDir), 0o755); err != nil {
+		return nil, err
+	}
+	manifest.Version = Version
+	manifest.Responses = make(map[string]string)
+	manifest.L2Responses = nil
+	return &Writer{dir: dir, manifest: manifest}, nil
+}
+
+// Record stores the response to a request made to the L2 chain with the given ID, or to the chain the monitors are
+// deployed on if it is zero. Exactly one of result and rpcErr is set.
+func (w *Writer) Record(l2ChainID uint64, method string, params json.RawMessage, result json.RawMessage, rpcErr *rpc.Error) error {
+	key, err := Key(method, params)
+	if err != nil {
+		return err
+	}
+	obj := object{Result: result, Error: rpcErr}
+	if rpcErr != nil {
+		obj.Result = nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	id := objectID(data)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	path := filepath.Join(w.dir, objectsDir, id+".json")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := writeFile(path, data); err != nil {
+			return err
+		}
+	}
+	responses := w.manifest.responses(l2ChainID)
+	if responses == nil {
+		if w.manifest.L2Responses == nil {
+			w.manifest.L2Responses = make(map[uint64]map[string]string)
+		}
+		responses = make(map[string]string)
+		w.manifest.L2Responses[l2ChainID] = responses
+	}
+	responses[key] = id
+	return nil
+}
+
+// SetManifest updates the description of the archive, keeping the recorded responses.
+func (w *Writer) SetManifest(update func(*Manifest)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	responses, l2 := w.manifest.Responses, w.manifest.L2Responses
+	update(&w.manifest)
+	w.manifest.Version = Version
+	w.manifest.Responses, w.manifest.L2Responses = responses, l2
+}
+
+// Close writes the manifest.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	data, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFile(filepath.Join(w.dir, manifestFile), data); err != nil {
+		return fmt.Errorf("writing archive manifest: %w", err)
+	}
+	return nil
+}
+
+// writeFile replaces the file at path atomically, so an interrupted write never leaves a partial file.
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,80 @@
+package autodeploy
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// JobsPath and RedrivePath are the routes of the handler returned by QueueHandler.
+const (
+	JobsPath    = "/jobs"
+	RedrivePath = "/jobs/redrive"
+)
+
+// QueueHandler serves the administration of q, for the jobs command:
+//
+//   - GET /jobs lists the jobs, filtered by the state, game and monitor query params;
+//   - POST /jobs/redrive queues the dead jobs again, filtered by the game and monitor query params, and lists them.
+//
+// Requests must carry token as `Authorization: Bearer <token>`, and are otherwise rejected with 401. It should still be
+// served on a private address only.
+func QueueHandler(q *Queue, token string) (http.Handler, error) {
+	if token == "" {
+		return nil, errors.New("empty admin token")
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(JobsPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJobs(w, q.Jobs(jobFilter(r)))
+	})
+	mux.HandleFunc(RedrivePath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		jobs, err := q.Redrive(jobFilter(r), time.Now().UTC())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJobs(w, jobs)
+	})
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "invalid admin token", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}), nil
+}
+
+// JobFilter returns a filter accepting the jobs matching every non-empty argument. The game is compared in any case.
+func JobFilter(state JobState, game, monitor string) func(Job) bool {
+	return func(j Job) bool {
+		return (state == "" || j.State == state) &&
+			(game == "" || strings.EqualFold(j.Game.String(), game)) &&
+			(monitor == "" || j.Monitor == monitor)
+	}
+}
+
+func jobFilter(r *http.Request) func(Job) bool {
+	q := r.URL.Query()
+	return JobFilter(JobState(q.Get("state")), q.Get("game"), q.Get("monitor"))
+}
+
+func writeJobs(w http.ResponseWriter, jobs []Job) {
+	if jobs == nil {
+		jobs = []Job{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(jobs)
+}
@@ -0,0 +1,307 @@
+// Package autodeploy deploys the monitors that run per dispute game. Hexagate sends an alert webhook when the
+// DisputeGameFactory creates a game, and the receiver creates a monitor for each configured template with the
+// disputeGame param set to the new game, through the management API. Likewise, the alerts of
+// fault_proof_detection_parent deploy fault_proof_detection_child to the reported game until it is resolved.
+package autodeploy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/monitors"
+)
+
+// Labels of the monitors deployed from this repository.
+const (
+	// LabelManagedBy is set to ManagedBy, which tells the deployed monitors from the ones created by hand.
+	LabelManagedBy = "managed-by"
+	ManagedBy      = "fault-proof-monitors"
+	// LabelMonitor is the name of the gate file.
+	LabelMonitor = "monitor"
+	// LabelGame is the dispute game of a monitor deployed per game, in lower case.
+	LabelGame = "game"
+)
+
+// PerGameMonitors are the monitors deployed to every dispute game, per the Per DisputeGame workflow.
+var PerGameMonitors = []string{
+	"challenged_proposal",
+	"challenger_loses",
+	"credit_and_bond_discrepancy",
+	"eth_deficit",
+	"eth_withdrawn_early",
+	"incorrect_bond_balance",
+	"incorrect_claim_bond",
+	"unresolvable_dispute_game",
+}
+
+// Template is a monitor deployed to each game. Its params are those of the gate file except disputeGame.
+type Template struct {
+	Monitor  string         `json:"monitor"`
+	Params   map[string]any `json:"params"`
+	Channels []string       `json:"channels,omitempty"`
+}
+
+// Config configures a Deployer.
+type Config struct {
+	// ChainID is the chain the games are created on.
+	ChainID uint64 `json:"chainId"`
+	// Monitors are deployed to every game.
+	Monitors []Template `json:"monitors"`
+	// WithdrawalGraceSeconds keeps eth_withdrawn_early and eth_deficit for this long after the final withdrawal of
+	// a game, measured in chain time from the first Teardown seeing the game settled.
+	WithdrawalGraceSeconds uint64 `json:"withdrawalGraceSeconds"`
+	// Factory, when set, is the DisputeGameFactory the games of a webhook must have been created by, which is
+	// confirmed through Chain before anything is deployed.
+	Factory eth.Address   `json:"-"`
+	Chain   monitor.Chain `json:"-"`
+	// Queue, when set, makes the webhook handler queue the monitors of the games instead of creating them, and Work
+	// create them.
+	Queue *Queue `json:"-"`
+	// State, when set, is the JSON file the monitors created per game and the time each game was first seen settled
+	// are kept in, so that a restart neither creates the monitors of a redelivered webhook again nor restarts the
+	// withdrawal grace of the settled games.
+	State string `json:"-"`
+	// OnError is called with the errors the webhook handler answers with, and those of the jobs processed by Work.
+	OnError func(error) `json:"-"`
+}
+
+// Deployer creates the monitors of each game once, so that webhooks delivered more than once do not duplicate them.
+type Deployer struct {
+	client *hexagate.Client
+	cfg    Config
+
+	// mu serializes deployments, so that concurrent deliveries of the same webhook do not race
+	mu       sync.Mutex
+	deployed map[eth.Address]map[string]hexagate.Monitor
+	// settled holds the time each game was first seen settled
+	settled map[eth.Address]uint64
+}
+
+// New returns a deployer creating monitors through client. The templates are checked against the gate files, so
+// that a misconfigured param fails at startup rather than on the first game.
+func New(client *hexagate.Client, cfg Config) (*Deployer, error) {
+	if len(cfg.Monitors) == 0 {
+		return nil, errors.New("no monitors to deploy")
+	}
+	if cfg.Factory != (eth.Address{}) && cfg.Chain == nil {
+		return nil, errors.New("confirming the games of the factory requires a chain")
+	}
+	seen := make(map[string]bool)
+	for _, t := range cfg.Monitors {
+		if seen[t.Monitor] {
+			return nil, fmt.Errorf("monitor %s is deployed twice", t.Monitor)
+		}
+		seen[t.Monitor] = true
+		if _, err := t.Render(cfg.ChainID, eth.Address{}); err != nil {
+			return nil, err
+		}
+	}
+	d := &Deployer{
+		client:   client,
+		cfg:      cfg,
+		deployed: make(map[eth.Address]map[string]hexagate.Monitor),
+		settled:  make(map[eth.Address]uint64),
+	}
+	if cfg.State != "" {
+		if err := d.load(); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// state is the content of Config.State.
+type state struct {
+	Deployed map[eth.Address]map[string]hexagate.Monitor `json:"deployed"`
+	Settled  map[eth.Address]uint64                      `json:"settled"`
+}
+
+// load reads Config.State, which does not exist before the first save.
+func (d *Deployer) load() error {
+	data, err := os.ReadFile(d.cfg.State)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	s := state{Deployed: d.deployed, Settled: d.settled}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("reading %s: %w", filepath.Base(d.cfg.State), err)
+	}
+	return nil
+}
+
+// save writes Config.State, if set. d.mu must be held.
+func (d *Deployer) save() error {
+	if d.cfg.State == "" {
+		return nil
+	}
+	if err := writeJSON(d.cfg.State, state{Deployed: d.deployed, Settled: d.settled}); err != nil {
+		return fmt.Errorf("saving the deployed monitors: %w", err)
+	}
+	return nil
+}
+
+// Deploy creates the monitors of game that were not created yet and returns all of them. When creating a monitor
+// fails, the ones created before it are kept, and deploying the game again only creates the remaining ones.
+func (d *Deployer) Deploy(ctx context.Context, game eth.Address) ([]hexagate.Monitor, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	created := d.deployed[game]
+	out := make([]hexagate.Monitor, 0, len(d.cfg.Monitors))
+	for _, t := range d.cfg.Monitors {
+		if m, ok := created[t.Monitor]; ok {
+			out = append(out, m)
+			continue
+		}
+		m, err := d.create(ctx, game, t)
+		if err != nil {
+			return out, err
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+// create creates the monitor of template t for game. d.mu must be held.
+func (d *Deployer) create(ctx context.Context, game eth.Address, t Template) (hexagate.Monitor, error) {
+	m, err := t.Render(d.cfg.ChainID, game)
+	if err != nil {
+		return hexagate.Monitor{}, err
+	}
+	stored, err := d.client.CreateMonitor(ctx, m, idempotencyKey(d.cfg.ChainID, game, t.Monitor))
+	if err != nil {
+		return hexagate.Monitor{}, fmt.Errorf("creating %s for game %s: %w", t.Monitor, game, err)
+	}
+	if d.deployed[game] == nil {
+		d.deployed[game] = make(map[string]hexagate.Monitor)
+	}
+	d.deployed[game][t.Monitor] = *stored
+	// the idempotency key keeps a retry from creating the monitor again if it cannot be saved
+	return *stored, d.save()
+}
+
+// Deployed returns the games with every monitor created.
+func (d *Deployer) Deployed() []eth.Address {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var games []eth.Address
+	for game, created := range d.deployed {
+		if len(created) == len(d.cfg.Monitors) {
+			games = append(games, game)
+		}
+	}
+	return games
+}
+
+// Teardown deletes the monitors of the games that reached the phase at which their invariants can no longer fail.
+// The monitors are listed from the API, so those deployed before a restart are deleted too, and the monitors of a
+// game that failed to be read are kept until the next call. It returns the deleted monitors.
+func (d *Deployer) Teardown(ctx context.Context, chain monitor.Chain) ([]hexagate.Monitor, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	deployed, err := d.client.AllMonitors(ctx, hexagate.ListOptions{
+		ChainID: d.cfg.ChainID,
+		Labels:  map[string]string{LabelManagedBy: ManagedBy},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing deployed monitors: %w", err)
+	}
+	byGame := make(map[eth.Address][]hexagate.Monitor)
+	var games []eth.Address
+	for _, m := range deployed {
+		if _, ok := retention[m.Labels[LabelMonitor]]; !ok || m.Labels[LabelGame] == "" {
+			continue
+		}
+		game, err := eth.ParseAddress(m.Labels[LabelGame])
+		if err != nil {
+			continue
+		}
+		if byGame[game] == nil {
+			games = append(games, game)
+		}
+		byGame[game] = append(byGame[game], m)
+	}
+
+	var removed []hexagate.Monitor
+	var errs []error
+	for _, game := range games {
+		lc, err := ReadLifecycle(ctx, chain, game)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if lc.Phase == Settled && d.settled[game] == 0 {
+			d.settled[game] = lc.Timestamp
+			if err := d.save(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		for _, m := range byGame[game] {
+			name := m.Labels[LabelMonitor]
+			if lc.Phase < retention[name] {
+				continue
+			}
+			if withdrawalMonitors[name] && lc.Timestamp < d.settled[game]+d.cfg.WithdrawalGraceSeconds {
+				continue
+			}
+			if err := d.client.DeleteMonitor(ctx, m.ID); err != nil && !hexagate.IsNotFound(err) {
+				errs = append(errs, fmt.Errorf("deleting %s of game %s: %w", name, game, err))
+				continue
+			}
+			removed = append(removed, m)
+		}
+	}
+	return removed, errors.Join(errs...)
+}
+
+// Render fills in the template for game on chainID, and checks that it sets exactly the params the gate file
+// declares.
+func (t Template) Render(chainID uint64, game eth.Address) (hexagate.Monitor, error) {
+	gate, err := monitors.Gate(t.Monitor)
+	if err != nil {
+		return hexagate.Monitor{}, err
+	}
+	if _, ok := t.Params["disputeGame"]; ok {
+		return hexagate.Monitor{}, fmt.Errorf("template of %s sets disputeGame, which is set per game", t.Monitor)
+	}
+	params := map[string]any{"disputeGame": game.String()}
+	for k, v := range t.Params {
+		params[k] = v
+	}
+	// the native monitor rejects unknown params and params of the wrong type
+	if _, err := monitor.New(t.Monitor, params); err != nil {
+		return hexagate.Monitor{}, err
+	}
+	if err := monitors.CheckParams(t.Monitor, params); err != nil {
+		return hexagate.Monitor{}, fmt.Errorf("template of %s: %w", t.Monitor, err)
+	}
+	return hexagate.Monitor{
+		Name:        fmt.Sprintf("%s %s", t.Monitor, game),
+		Description: fmt.Sprintf("%s for dispute game %s", t.Monitor, game),
+		ChainID:     chainID,
+		Gate:        gate,
+		Params:      params,
+		Channels:    t.Channels,
+		Labels: map[string]string{
+			LabelManagedBy: ManagedBy,
+			LabelMonitor:   t.Monitor,
+			LabelGame:      strings.ToLower(game.String()),
+		},
+	}, nil
+}
+
+// idempotencyKey identifies the creation of monitor for game, so that a request retried after its response was
+// lost, or after a restart, does not create the monitor twice.
+func idempotencyKey(chainID uint64, game eth.Address, monitor string) string {
+	return fmt.Sprintf("autodeploy-%d-%s-%s", chainID, strings.ToLower(game.String()), monitor)
+}
@@ -0,0 +1,185 @@
+package autodeploy_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/hexagate/hexagatetest"
+)
+
+var (
+	game1 = eth.MustAddress("0x00000000000000000000000000000000000000B1")
+	game2 = eth.MustAddress("0x00000000000000000000000000000000000000B2")
+)
+
+func templates() []autodeploy.Template {
+	honest := map[string]any{"honestChallenger": "0x00000000000000000000000000000000000000C1"}
+	return []autodeploy.Template{
+		{Monitor: "challenged_proposal", Params: map[string]any{
+			"honestProposer":   "0x00000000000000000000000000000000000000A1",
+			"honestChallenger": "0x00000000000000000000000000000000000000C1",
+		}},
+		{Monitor: "challenger_loses", Params: honest},
+		{Monitor: "credit_and_bond_discrepancy"},
+		{Monitor: "eth_deficit", Params: honest},
+		{Monitor: "eth_withdrawn_early", Params: map[string]any{"multicall3": "0xcA11bde05977b3631167028862bE2a173976CA11"}},
+		{Monitor: "incorrect_bond_balance"},
+		{Monitor: "incorrect_claim_bond"},
+		{Monitor: "unresolvable_dispute_game", Params: map[string]any{"extraTimeInSeconds": 3600}, Channels: []string{"pagerduty"}},
+	}
+}
+
+func TestCreatedGames(t *testing.T) {
+	topic := func(a eth.Address) string {
+		var h eth.Hash
+		copy(h[12:], a[:])
+		return h.String()
+	}
+	cases := []struct {
+		name    string
+		payload string
+		games   []eth.Address
+		err     error
+	}{
+		{
+			name:    "decoded event",
+			payload: `{"monitor_name": "DisputeGameCreated", "event": {"disputeProxy": "` + game1.String() + `", "gameType": 0}}`,
+			games:   []eth.Address{game1},
+		},
+		{
+			name:    "workflow field",
+			payload: `{"data": {"disputeGameAddress": "` + strings.ToLower(game2.String()) + `"}}`,
+			games:   []eth.Address{game2},
+		},
+		{
+			name: "raw logs",
+			payload: `{"logs": [{"topics": ["` + abi.DisputeGameCreated.Topic().String() + `", "` + topic(game1) + `"]},` +
+				`{"topics": ["` + abi.DisputeGameCreated.Topic().String() + `", "` + topic(game2) + `"]}]}`,
+			games: []eth.Address{game1, game2},
+		},
+		{
+			name: "event and log of the same game",
+			payload: `{"event": {"disputeProxy": "` + game1.String() + `"}, "logs": [{"topics": ["` +
+				abi.DisputeGameCreated.Topic().String() + `", "` + topic(game1) + `"]}]}`,
+			games: []eth.Address{game1},
+		},
+		{
+			name:    "other event",
+			payload: `{"logs": [{"topics": ["` + eth.Hash{}.String() + `", "` + topic(game1) + `"]}]}`,
+			err:     autodeploy.ErrNoGame,
+		},
+	}
+	for _, c := range cases {
+		games, err := autodeploy.CreatedGames([]byte(c.payload))
+		if c.err != nil {
+			if !errors.Is(err, c.err) {
+				t.Errorf("%s: expected %v, got %v", c.name, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: Error parsing payload: %v", c.name, err)
+		}
+		if len(games) != len(c.games) || games[0] != c.games[0] || games[len(games)-1] != c.games[len(c.games)-1] {
+			t.Errorf("%s: expected %v, got %v", c.name, c.games, games)
+		}
+	}
+
+	if _, err := autodeploy.CreatedGames([]byte(`{"disputeProxy": "not an address"}`)); err == nil {
+		t.Errorf("expected an invalid address to fail")
+	}
+}
+
+func TestNewChecksTemplates(t *testing.T) {
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+
+	cases := map[string]autodeploy.Template{
+		"unknown monitor":    {Monitor: "missing"},
+		"missing param":      {Monitor: "challenger_loses"},
+		"unknown param":      {Monitor: "incorrect_claim_bond", Params: map[string]any{"grace": 1}},
+		"invalid param":      {Monitor: "challenger_loses", Params: map[string]any{"honestChallenger": 1}},
+		"disputeGame is set": {Monitor: "incorrect_claim_bond", Params: map[string]any{"disputeGame": game1.String()}},
+	}
+	for name, tmpl := range cases {
+		if _, err := autodeploy.New(server.Client(), autodeploy.Config{Monitors: []autodeploy.Template{tmpl}}); err == nil {
+			t.Errorf("%s: expected the template to be rejected", name)
+		}
+	}
+	if _, err := autodeploy.New(server.Client(), autodeploy.Config{Monitors: templates()}); err != nil {
+		t.Errorf("Error checking templates: %v", err)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	api := hexagatetest.NewServer("key")
+	defer api.Close()
+	var errs []error
+	d, err := autodeploy.New(api.Client(), autodeploy.Config{
+		ChainID:  1,
+		Monitors: templates(),
+		OnError:  func(err error) { errs = append(errs, err) },
+	})
+	if err != nil {
+		t.Fatalf("Error creating deployer: %v", err)
+	}
+	webhook := httptest.NewServer(d)
+	defer webhook.Close()
+
+	post := func(payload string) int {
+		resp, err := http.Post(webhook.URL, "application/json", strings.NewReader(payload))
+		if err != nil {
+			t.Fatalf("Error posting webhook: %v", err)
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	created := `{"event": {"disputeProxy": "` + game1.String() + `"}}`
+
+	// the management API fails on the third monitor, which fails the delivery
+	api.FailNext(0, 0, http.StatusInternalServerError)
+	if status := post(created); status != http.StatusBadGateway {
+		t.Fatalf("expected the delivery to fail with 502, got %d", status)
+	}
+	if len(api.Monitors()) != 2 || len(errs) != 1 {
+		t.Fatalf("expected 2 monitors and 1 error, got %d and %v", len(api.Monitors()), errs)
+	}
+
+	// retried deliveries only create the missing monitors
+	for i := 0; i < 2; i++ {
+		if status := post(created); status != http.StatusOK {
+			t.Fatalf("expected the delivery to succeed, got %d", status)
+		}
+	}
+	monitors := api.Monitors()
+	if len(monitors) != len(autodeploy.PerGameMonitors) {
+		t.Fatalf("expected %d monitors, got %d", len(autodeploy.PerGameMonitors), len(monitors))
+	}
+	for i, m := range monitors {
+		if !strings.HasPrefix(m.Name, autodeploy.PerGameMonitors[i]+" ") || m.ChainID != 1 || m.Params["disputeGame"] != game1.String() {
+			t.Errorf("unexpected monitor %s on chain %d with params %v", m.Name, m.ChainID, m.Params)
+		}
+		if !strings.Contains(m.Gate, "invariant") {
+			t.Errorf("expected %s to run its gate file", m.Name)
+		}
+	}
+	if len(monitors[7].Channels) != 1 || monitors[7].Params["extraTimeInSeconds"] != float64(3600) {
+		t.Errorf("expected the template params and channels to be deployed, got %+v", monitors[7])
+	}
+	if games := d.Deployed(); len(games) != 1 || games[0] != game1 {
+		t.Errorf("expected %s to be deployed, got %v", game1, games)
+	}
+
+	if status := post(`{"event": {"name": "Resolved"}}`); status != http.StatusUnprocessableEntity {
+		t.Errorf("expected an alert without a game to be rejected with 422, got %d", status)
+	}
+	if status := post(`not json`); status != http.StatusBadRequest {
+		t.Errorf("expected an invalid payload to be rejected with 400, got %d", status)
+	}
+}
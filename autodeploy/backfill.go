@@ -0,0 +1,133 @@
+package autodeploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+var (
+	gameCountMethod   = abi.MustParseMethod("gameCount() returns (uint256 gameCount_)")
+	gameAtIndexMethod = abi.MustParseMethod("gameAtIndex(uint256 _index) returns (uint32 gameType_, uint64 timestamp_, address proxy_)")
+)
+
+// BackfillOptions selects the games Backfill deploys monitors to.
+type BackfillOptions struct {
+	// Factory is the DisputeGameFactory proxy the games are listed from.
+	Factory eth.Address
+	// Since skips the games created before this time. Games are listed from the newest, so an old game that never
+	// settled is only found when it is zero.
+	Since uint64
+	// DryRun reports the missing monitors without creating them.
+	DryRun bool
+}
+
+// BackfilledGame is a game Backfill found monitors missing for.
+type BackfilledGame struct {
+	Game      eth.Address
+	Index     uint64
+	CreatedAt uint64
+	Phase     Phase
+	// Missing are the monitors that were not deployed, in the order of the templates.
+	Missing []string
+	// Created are the monitors created, unset on a dry run.
+	Created []hexagate.Monitor
+}
+
+// Backfill deploys the monitors missing for the games of the factory that are not settled yet, such as games created
+// while no webhook was received. Only the monitors whose invariants can still fail in the phase of each game are
+// deployed, so a resolved game only gets the monitors Teardown would keep. The deployed monitors are listed from the
+// API, and the games are read from the latest block of chain.
+func (d *Deployer) Backfill(ctx context.Context, chain monitor.Chain, opts BackfillOptions) ([]BackfilledGame, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	deployed, err := d.client.AllMonitors(ctx, hexagate.ListOptions{
+		ChainID: d.cfg.ChainID,
+		Labels:  map[string]string{LabelManagedBy: ManagedBy},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing deployed monitors: %w", err)
+	}
+	existing := make(map[eth.Address]map[string]bool)
+	for _, m := range deployed {
+		game, err := eth.ParseAddress(m.Labels[LabelGame])
+		if m.Labels[LabelGame] == "" || err != nil {
+			continue
+		}
+		if existing[game] == nil {
+			existing[game] = make(map[string]bool)
+		}
+		existing[game][m.Labels[LabelMonitor]] = true
+		// remember them, so that a webhook delivered for the game later does not create them again
+		if d.deployed[game] == nil {
+			d.deployed[game] = make(map[string]hexagate.Monitor)
+		}
+		d.deployed[game][m.Labels[LabelMonitor]] = m
+	}
+	if err := d.save(); err != nil {
+		return nil, err
+	}
+
+	header, err := chain.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the latest block: %w", err)
+	}
+	number := rpc.NumberAt(uint64(header.Number))
+	count, err := call(ctx, chain, number, opts.Factory, gameCountMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	var games []BackfilledGame
+	var errs []error
+	for i := count.(*big.Int).Uint64(); i > 0; i-- {
+		index := i - 1
+		values, err := callAll(ctx, chain, number, opts.Factory, gameAtIndexMethod, index)
+		if err != nil {
+			return games, err
+		}
+		createdAt, game := values[1].(*big.Int).Uint64(), values[2].(eth.Address)
+		if createdAt < opts.Since {
+			break
+		}
+		lc, err := ReadLifecycle(ctx, chain, game)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if lc.Phase == Settled {
+			continue
+		}
+		g := BackfilledGame{Game: game, Index: index, CreatedAt: createdAt, Phase: lc.Phase}
+		for _, t := range d.cfg.Monitors {
+			phase, ok := retention[t.Monitor]
+			if !ok {
+				phase = Settled
+			}
+			if existing[game][t.Monitor] || lc.Phase >= phase {
+				continue
+			}
+			g.Missing = append(g.Missing, t.Monitor)
+			if opts.DryRun {
+				continue
+			}
+			m, err := d.create(ctx, game, t)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			g.Created = append(g.Created, m)
+		}
+		if len(g.Missing) > 0 {
+			games = append(games, g)
+		}
+	}
+	return games, errors.Join(errs...)
+}
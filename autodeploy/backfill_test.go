@@ -0,0 +1,145 @@
+package autodeploy_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/follower/chaintest"
+	"github.com/base-org/fault-proof-monitors/hexagate/hexagatetest"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+var (
+	gameCount   = abi.MustParseMethod("gameCount() returns (uint256 gameCount_)")
+	gameAtIndex = abi.MustParseMethod("gameAtIndex(uint256 _index) returns (uint32 gameType_, uint64 timestamp_, address proxy_)")
+
+	factory = eth.MustAddress("0x00000000000000000000000000000000000000FA")
+)
+
+// factoryGame is a game listed by the fake factory, resolved at resolvedAt unless it is zero, and holding balance in
+// DelayedWETH.
+type factoryGame struct {
+	address    eth.Address
+	createdAt  uint64
+	resolvedAt uint64
+	balance    int64
+}
+
+// factoryCalls answers the calls listing games from the factory and reading their lifecycle. DelayedWETH has a delay
+// of 100 seconds.
+func factoryCalls(games []factoryGame) chaintest.CallHandler {
+	return func(msg rpc.CallMsg, header *eth.Header) ([]byte, error) {
+		selector := msg.Data[:4]
+		switch {
+		case msg.To == factory && bytes.Equal(selector, gameCount.Selector()):
+			return gameCount.PackOutputs(big.NewInt(int64(len(games))))
+		case msg.To == factory && bytes.Equal(selector, gameAtIndex.Selector()):
+			args, err := gameAtIndex.UnpackInputs(msg.Data)
+			if err != nil {
+				return nil, err
+			}
+			g := games[args[0].(*big.Int).Int64()]
+			return gameAtIndex.PackOutputs(uint32(0), g.createdAt, g.address)
+		case msg.To == delayedWETH && bytes.Equal(selector, delay.Selector()):
+			return delay.PackOutputs(big.NewInt(100))
+		}
+		for _, g := range games {
+			switch {
+			case msg.To == g.address && bytes.Equal(selector, resolvedAt.Selector()):
+				return resolvedAt.PackOutputs(new(big.Int).SetUint64(g.resolvedAt))
+			case msg.To == g.address && bytes.Equal(selector, weth.Selector()):
+				return weth.PackOutputs(delayedWETH)
+			case msg.To == delayedWETH && bytes.Equal(selector, balanceOf.Selector()) && bytes.Equal(msg.Data[16:36], g.address[:]):
+				return balanceOf.PackOutputs(big.NewInt(g.balance))
+			}
+		}
+		return nil, &rpc.Error{Code: 3, Message: "execution reverted"}
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	settled := eth.MustAddress("0x00000000000000000000000000000000000000E1")
+	withdrawable := eth.MustAddress("0x00000000000000000000000000000000000000E2")
+	inProgress := eth.MustAddress("0x00000000000000000000000000000000000000E3")
+	deployed := eth.MustAddress("0x00000000000000000000000000000000000000E4")
+	old := eth.MustAddress("0x00000000000000000000000000000000000000E5")
+	chain := chaintest.NewChain(1000)
+	chain.Extend(100)
+	// the head is at 1200
+	chain.HandleCall(factoryCalls([]factoryGame{
+		{address: old, createdAt: 100},
+		{address: settled, createdAt: 900, resolvedAt: 1000, balance: 0},
+		{address: withdrawable, createdAt: 950, resolvedAt: 1050, balance: 1},
+		{address: inProgress, createdAt: 1100, balance: 1},
+		{address: deployed, createdAt: 1150, balance: 1},
+	}))
+
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+	ctx := context.Background()
+	d, err := autodeploy.New(server.Client(), autodeploy.Config{ChainID: 1, Monitors: templates()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Deploy(ctx, deployed); err != nil {
+		t.Fatal(err)
+	}
+	opts := autodeploy.BackfillOptions{Factory: factory, Since: 500, DryRun: true}
+
+	games, err := d.Backfill(ctx, chain, opts)
+	if err != nil {
+		t.Fatalf("Error backfilling: %v", err)
+	}
+	if len(games) != 2 || games[0].Game != inProgress || games[1].Game != withdrawable {
+		t.Fatalf("expected the in progress and withdrawable games, got %+v", games)
+	}
+	if len(games[0].Missing) != len(templates()) || games[0].Phase != autodeploy.InProgress {
+		t.Errorf("expected every monitor to be missing for the game in progress, got %+v", games[0])
+	}
+	want := "credit_and_bond_discrepancy eth_deficit eth_withdrawn_early incorrect_bond_balance"
+	if got := strings.Join(games[1].Missing, " "); got != want || games[1].Phase != autodeploy.Withdrawable {
+		t.Errorf("expected the withdrawable game to miss %s, got %s (%s)", want, got, games[1].Phase)
+	}
+	if n := server.Creates(); n != len(templates()) || games[0].Created != nil {
+		t.Fatalf("expected a dry run to create nothing, got %d creates", n)
+	}
+
+	// a failed create is retried by the next backfill
+	server.FailNext(0, 0, 500)
+	opts.DryRun = false
+	if _, err := d.Backfill(ctx, chain, opts); err == nil {
+		t.Fatal("expected the failed create to be reported")
+	}
+	games, err = d.Backfill(ctx, chain, opts)
+	if err != nil {
+		t.Fatalf("Error backfilling: %v", err)
+	}
+	if len(games) != 1 || len(games[0].Created) != 1 {
+		t.Fatalf("expected the failed monitor to be created, got %+v", games)
+	}
+	if n := server.Creates(); n != 2*len(templates())+4 {
+		t.Errorf("expected %d creates, got %d", 2*len(templates())+4, n)
+	}
+	if games, err = d.Backfill(ctx, chain, opts); err != nil || len(games) != 0 {
+		t.Errorf("expected nothing left to backfill, got %+v (%v)", games, err)
+	}
+
+	// without Since, the old game is found, while the monitors of the others are listed from the API
+	restarted, err := autodeploy.New(server.Client(), autodeploy.Config{ChainID: 1, Monitors: templates()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Since = 0
+	if games, err = restarted.Backfill(ctx, chain, opts); err != nil || len(games) != 1 || games[0].Game != old {
+		t.Errorf("expected only the old game to be backfilled, got %+v (%v)", games, err)
+	}
+	if _, err := restarted.Deploy(ctx, inProgress); err != nil || server.Creates() != 3*len(templates())+4 {
+		t.Errorf("expected a webhook for a backfilled game to create nothing, got %d creates (%v)", server.Creates(), err)
+	}
+}
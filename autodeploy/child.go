@@ -0,0 +1,261 @@
+package autodeploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+const (
+	// ParentMonitor detects games created with an invalid output root.
+	ParentMonitor = "fault_proof_detection_parent"
+	// ChildMonitor follows the moves in a game detected by ParentMonitor.
+	ChildMonitor = "fault_proof_detection_child"
+	// invalidProposal is the description of the invariant of ParentMonitor that triggers a deployment. The other one
+	// only fails when several games are created in a block, which says nothing about their output roots.
+	invalidProposal = "Dispute game created with incorrect L2 output proposal"
+)
+
+// ErrNoInvalidProposal is returned for alerts of the parent monitor that do not report an invalid output root.
+var ErrNoInvalidProposal = errors.New("alert does not report an incorrect L2 output proposal")
+
+// ParentAlert is an alert of ParentMonitor reporting games created with an invalid output root.
+type ParentAlert struct {
+	// MonitorID is the ID of the parent monitor, when the payload carries it.
+	MonitorID int64
+	Games     []eth.Address
+}
+
+// ParseParentAlert decodes the alert webhook payload of ParentMonitor. The failed invariant must be the invalid output
+// root check, and the games are read from the disputeProxy source in the context of the failure, as CreatedGames does.
+func ParseParentAlert(payload []byte) (*ParentAlert, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decoding webhook payload: %w", err)
+	}
+	alert := &ParentAlert{}
+	found := false
+	var visit func(v any)
+	visit = func(v any) {
+		switch v := v.(type) {
+		case string:
+			found = found || v == invalidProposal
+		case map[string]any:
+			if id, ok := v["monitor_id"].(json.Number); ok && alert.MonitorID == 0 {
+				alert.MonitorID, _ = id.Int64()
+			}
+			for _, value := range v {
+				visit(value)
+			}
+		case []any:
+			for _, item := range v {
+				visit(item)
+			}
+		}
+	}
+	visit(v)
+	if !found {
+		return nil, ErrNoInvalidProposal
+	}
+	games, err := CreatedGames(payload)
+	if err != nil {
+		return nil, err
+	}
+	alert.Games = games
+	return alert, nil
+}
+
+// ChildConfig configures a ChildDeployer.
+type ChildConfig struct {
+	// ChainID is the chain the games are created on.
+	ChainID uint64 `json:"chainId"`
+	// CBChallenger is the challenger expected to counter the invalid output roots.
+	CBChallenger eth.Address `json:"cbChallenger"`
+	Channels     []string    `json:"channels,omitempty"`
+	// Factory, when set, is the DisputeGameFactory the games of an alert must have been created by, which is
+	// confirmed through Chain before anything is deployed, as for Config.
+	Factory eth.Address   `json:"-"`
+	Chain   monitor.Chain `json:"-"`
+	// OnError is called with the errors the webhook handler answers with.
+	OnError func(error) `json:"-"`
+}
+
+// ChildDeployer deploys ChildMonitor to the games ParentMonitor alerts on, and deletes it once the game is resolved.
+// Deployments are recorded in a Store linking them to the parent monitor, so that each game is deployed to exactly
+// once, even across restarts.
+type ChildDeployer struct {
+	client *hexagate.Client
+	store  *Store
+	cfg    ChildConfig
+
+	// mu serializes deployments and teardowns
+	mu sync.Mutex
+}
+
+// NewChildDeployer returns a deployer creating monitors through client and recording them in store.
+func NewChildDeployer(client *hexagate.Client, store *Store, cfg ChildConfig) (*ChildDeployer, error) {
+	if cfg.CBChallenger == (eth.Address{}) {
+		return nil, errors.New("no cbChallenger")
+	}
+	if cfg.Factory != (eth.Address{}) && cfg.Chain == nil {
+		return nil, errors.New("confirming the games of the factory requires a chain")
+	}
+	d := &ChildDeployer{client: client, store: store, cfg: cfg}
+	if _, err := d.template().Render(cfg.ChainID, eth.Address{}); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *ChildDeployer) template() Template {
+	return Template{
+		Monitor:  ChildMonitor,
+		Params:   map[string]any{"cbChallenger": d.cfg.CBChallenger.String()},
+		Channels: d.cfg.Channels,
+	}
+}
+
+// Deploy deploys ChildMonitor to game unless it was deployed to it before, and returns the deployment.
+func (d *ChildDeployer) Deploy(ctx context.Context, game eth.Address, parent Parent) (Deployment, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if existing, ok := d.store.Get(game, ChildMonitor); ok {
+		return existing, nil
+	}
+	m, err := d.template().Render(d.cfg.ChainID, game)
+	if err != nil {
+		return Deployment{}, err
+	}
+	stored, err := d.client.CreateMonitor(ctx, m, idempotencyKey(d.cfg.ChainID, game, ChildMonitor))
+	if err != nil {
+		return Deployment{}, fmt.Errorf("creating %s for game %s: %w", ChildMonitor, game, err)
+	}
+	deployment := Deployment{
+		Game:      game,
+		Monitor:   ChildMonitor,
+		MonitorID: stored.ID,
+		Parent:    &parent,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := d.store.Put(deployment); err != nil {
+		// the next delivery creates the monitor with the same idempotency key, which returns this one
+		return Deployment{}, fmt.Errorf("recording %s for game %s: %w", ChildMonitor, game, err)
+	}
+	return deployment, nil
+}
+
+// Teardown deletes the child monitors of the games resolved at the latest block of chain, and returns their
+// deployments. Games that fail are skipped and reported in the returned error, and tried again on the next call.
+func (d *ChildDeployer) Teardown(ctx context.Context, chain monitor.Chain) ([]Deployment, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	active := d.store.Deployments(func(dep Deployment) bool {
+		return dep.Monitor == ChildMonitor && dep.Active()
+	})
+	var removed []Deployment
+	var errs []error
+	for _, dep := range active {
+		resolved, err := isResolved(ctx, chain, dep.Game)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !resolved {
+			continue
+		}
+		if err := d.client.DeleteMonitor(ctx, dep.MonitorID); err != nil && !hexagate.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("deleting %s of game %s: %w", ChildMonitor, dep.Game, err))
+			continue
+		}
+		now := time.Now().UTC()
+		dep.RemovedAt = &now
+		if err := d.store.Put(dep); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, dep)
+	}
+	return removed, errors.Join(errs...)
+}
+
+func isResolved(ctx context.Context, chain monitor.Chain, game eth.Address) (bool, error) {
+	resolvedAt, err := call(ctx, chain, rpc.LatestBlockNumber, game, resolvedAtMethod)
+	if err != nil {
+		return false, err
+	}
+	return resolvedAt.(*big.Int).Sign() > 0, nil
+}
+
+// ServeHTTP receives the alert webhooks of ParentMonitor and deploys ChildMonitor to the games they report. Alerts
+// of the other invariant of the parent are acknowledged without deploying anything. With a Factory, alerts reporting
+// a game it did not create are rejected with 422.
+func (d *ChildDeployer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		d.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	alert, err := ParseParentAlert(payload)
+	switch {
+	case errors.Is(err, ErrNoInvalidProposal):
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"deployed":[]}` + "\n"))
+		return
+	case errors.Is(err, ErrNoGame):
+		d.fail(w, http.StatusUnprocessableEntity, err)
+		return
+	case err != nil:
+		d.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	if d.cfg.Factory != (eth.Address{}) {
+		for _, game := range alert.Games {
+			if err := ConfirmGame(r.Context(), d.cfg.Chain, d.cfg.Factory, game); errors.Is(err, ErrUnknownGame) {
+				d.fail(w, http.StatusUnprocessableEntity, err)
+				return
+			} else if err != nil {
+				d.fail(w, http.StatusBadGateway, err)
+				return
+			}
+		}
+	}
+
+	resp := struct {
+		Deployed []Deployment `json:"deployed"`
+	}{}
+	for _, game := range alert.Games {
+		dep, err := d.Deploy(r.Context(), game, Parent{Monitor: ParentMonitor, MonitorID: alert.MonitorID})
+		if err != nil {
+			d.fail(w, http.StatusBadGateway, err)
+			return
+		}
+		resp.Deployed = append(resp.Deployed, dep)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (d *ChildDeployer) fail(w http.ResponseWriter, status int, err error) {
+	if d.cfg.OnError != nil {
+		d.cfg.OnError(err)
+	}
+	http.Error(w, err.Error(), status)
+}
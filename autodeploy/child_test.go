@@ -0,0 +1,124 @@
+package autodeploy_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/follower/chaintest"
+	"github.com/base-org/fault-proof-monitors/game"
+	"github.com/base-org/fault-proof-monitors/hexagate/hexagatetest"
+)
+
+var cbChallenger = eth.MustAddress("0x00000000000000000000000000000000000000C1")
+
+// parentAlert is the payload of an alert of the parent monitor failing the given invariant for game.
+func parentAlert(invariant string, game eth.Address) string {
+	return `{"monitor_id": 42, "monitor_name": "fault_proof_detection_parent", "failed": [{"description": "` + invariant +
+		`", "context": {"disputeProxy": "` + game.String() + `", "gameType": 0}}]}`
+}
+
+func TestParseParentAlert(t *testing.T) {
+	alert, err := autodeploy.ParseParentAlert([]byte(parentAlert("Dispute game created with incorrect L2 output proposal", game1)))
+	if err != nil {
+		t.Fatalf("Error parsing alert: %v", err)
+	}
+	if alert.MonitorID != 42 || len(alert.Games) != 1 || alert.Games[0] != game1 {
+		t.Errorf("unexpected alert %+v", alert)
+	}
+
+	_, err = autodeploy.ParseParentAlert([]byte(parentAlert("Only one DisputeGameCreated event should appear in the same block", game1)))
+	if !errors.Is(err, autodeploy.ErrNoInvalidProposal) {
+		t.Errorf("expected ErrNoInvalidProposal, got %v", err)
+	}
+}
+
+func TestChildDeployer(t *testing.T) {
+	api := hexagatetest.NewServer("key")
+	defer api.Close()
+	path := filepath.Join(t.TempDir(), "deployments.json")
+	store, err := autodeploy.OpenStore(path)
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	d, err := autodeploy.NewChildDeployer(api.Client(), store, autodeploy.ChildConfig{ChainID: 1, CBChallenger: cbChallenger})
+	if err != nil {
+		t.Fatalf("Error creating deployer: %v", err)
+	}
+	webhook := httptest.NewServer(d)
+	defer webhook.Close()
+	post := func(payload string) int {
+		resp, err := http.Post(webhook.URL, "application/json", strings.NewReader(payload))
+		if err != nil {
+			t.Fatalf("Error posting webhook: %v", err)
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// the other invariant of the parent deploys nothing
+	if status := post(parentAlert("Only one DisputeGameCreated event should appear in the same block", game1)); status != http.StatusOK {
+		t.Fatalf("expected the alert to be acknowledged, got %d", status)
+	}
+	if len(api.Monitors()) != 0 {
+		t.Fatalf("expected no monitor, got %d", len(api.Monitors()))
+	}
+
+	// repeated alerts deploy the child once
+	invalid := parentAlert("Dispute game created with incorrect L2 output proposal", game1)
+	for i := 0; i < 2; i++ {
+		if status := post(invalid); status != http.StatusOK {
+			t.Fatalf("expected the alert to be acknowledged, got %d", status)
+		}
+	}
+	monitors := api.Monitors()
+	if len(monitors) != 1 || monitors[0].Params["disputeGame"] != game1.String() || monitors[0].Params["cbChallenger"] != cbChallenger.String() {
+		t.Fatalf("expected the child monitor to be deployed to %s, got %+v", game1, monitors)
+	}
+
+	// the link survives a restart
+	store, err = autodeploy.OpenStore(path)
+	if err != nil {
+		t.Fatalf("Error reopening store: %v", err)
+	}
+	dep, ok := store.Get(game1, autodeploy.ChildMonitor)
+	if !ok || dep.MonitorID != monitors[0].ID || dep.Parent == nil || dep.Parent.Monitor != autodeploy.ParentMonitor || dep.Parent.MonitorID != 42 {
+		t.Fatalf("expected the deployment to be linked to the parent monitor, got %+v", dep)
+	}
+	d, err = autodeploy.NewChildDeployer(api.Client(), store, autodeploy.ChildConfig{ChainID: 1, CBChallenger: cbChallenger})
+	if err != nil {
+		t.Fatalf("Error creating deployer: %v", err)
+	}
+	if _, err := d.Deploy(context.Background(), game1, autodeploy.Parent{Monitor: autodeploy.ParentMonitor}); err != nil || len(api.Monitors()) != 1 {
+		t.Fatalf("expected no new monitor after a restart, got %d (%v)", len(api.Monitors()), err)
+	}
+
+	// the child is deleted once the game is resolved
+	g := game.NewGame(game.Config{MaxClockDuration: 20, MaxGameDepth: 73, SplitDepth: 30}, 1000)
+	chain := chaintest.NewChain(1000)
+	chain.Extend(10)
+	chain.HandleCall(chaintest.GameCalls(game1, g, 15))
+	removed, err := d.Teardown(context.Background(), chain)
+	if err != nil || len(removed) != 0 || len(api.Monitors()) != 1 {
+		t.Fatalf("expected the child of an unresolved game to stay deployed, got %v (%v)", removed, err)
+	}
+	chain.Extend(5)
+	removed, err = d.Teardown(context.Background(), chain)
+	if err != nil || len(removed) != 1 || removed[0].Active() || len(api.Monitors()) != 0 {
+		t.Fatalf("expected the child of a resolved game to be deleted, got %v (%v)", removed, err)
+	}
+	if dep, _ := store.Get(game1, autodeploy.ChildMonitor); dep.Active() {
+		t.Errorf("expected the deployment to be recorded as removed")
+	}
+
+	// later alerts for the game do not deploy it again
+	if _, err := d.Deploy(context.Background(), game1, autodeploy.Parent{Monitor: autodeploy.ParentMonitor}); err != nil || len(api.Monitors()) != 0 {
+		t.Errorf("expected a resolved game not to be deployed again, got %d monitors (%v)", len(api.Monitors()), err)
+	}
+}
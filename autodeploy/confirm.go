@@ -0,0 +1,71 @@
+package autodeploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+var (
+	gameTypeMethod  = abi.MustParseMethod("gameType() returns (uint32 gameType_)")
+	rootClaimMethod = abi.MustParseMethod("rootClaim() returns (bytes32 rootClaim_)")
+	extraDataMethod = abi.MustParseMethod("extraData() returns (bytes extraData_)")
+	gamesMethod     = abi.MustParseMethod("games(uint32 _gameType, bytes32 _rootClaim, bytes _extraData) returns (address proxy_, uint64 timestamp_)")
+)
+
+// ErrUnknownGame is returned for games the DisputeGameFactory did not create.
+var ErrUnknownGame = errors.New("dispute game not created by the factory")
+
+// ConfirmGame checks that game was created by factory: the game type, root claim and extra data read from the game
+// must map to game in the games of the factory. Addresses that are not games, or whose calls revert, are reported as
+// ErrUnknownGame, while the failures of the endpoint are returned as they are.
+func ConfirmGame(ctx context.Context, chain monitor.Chain, factory, game eth.Address) error {
+	header, err := chain.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return err
+	}
+	number := rpc.NumberAt(uint64(header.Number))
+	var args []any
+	for _, method := range []abi.Method{gameTypeMethod, rootClaimMethod, extraDataMethod} {
+		values, err := confirmCall(ctx, chain, number, game, game, method)
+		if err != nil {
+			return err
+		}
+		args = append(args, values[0])
+	}
+	values, err := confirmCall(ctx, chain, number, game, factory, gamesMethod, args...)
+	if err != nil {
+		return err
+	}
+	if proxy := values[0].(eth.Address); proxy != game {
+		return fmt.Errorf("%w: %s, the factory holds %s for its game type, root claim and extra data", ErrUnknownGame, game, proxy)
+	}
+	return nil
+}
+
+// confirmCall calls method on contract like callAll, but reports the calls that revert, and the outputs that cannot
+// be decoded, as ErrUnknownGame.
+func confirmCall(ctx context.Context, chain monitor.Chain, number rpc.BlockNumber, game, contract eth.Address, method abi.Method, args ...any) ([]any, error) {
+	calldata, err := method.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := chain.CallContract(ctx, rpc.CallMsg{To: contract, Data: calldata}, number)
+	var rpcErr *rpc.Error
+	if errors.As(err, &rpcErr) && (rpcErr.Code == 3 || strings.Contains(rpcErr.Message, "revert")) {
+		return nil, fmt.Errorf("%w: %s: %s reverted", ErrUnknownGame, game, method.Name)
+	} else if err != nil {
+		return nil, fmt.Errorf("calling %s on %s: %w", method.Name, contract, err)
+	}
+	values, err := method.UnpackOutputs(output)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: decoding %s: %v", ErrUnknownGame, game, method.Name, err)
+	}
+	return values, nil
+}
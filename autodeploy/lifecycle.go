@@ -0,0 +1,164 @@
+package autodeploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+var (
+	resolvedAtMethod = abi.MustParseMethod("resolvedAt() returns (uint256)")
+	wethMethod       = abi.MustParseMethod("weth() returns (address)")
+	delayMethod      = abi.MustParseMethod("delay() returns (uint256)")
+	balanceOfMethod  = abi.MustParseMethod("balanceOf(address) returns (uint256)")
+	// bondDistributionModeMethod only exists on the games unlocking credit when it is claimed.
+	bondDistributionModeMethod = abi.MustParseMethod("bondDistributionMode() returns (uint8)")
+)
+
+// Phase is a stage of the life of a dispute game. Fewer invariants can fail at each one.
+type Phase int
+
+const (
+	// InProgress games can still be moved on.
+	InProgress Phase = iota
+	// Resolved games have their bonds unlocked in DelayedWETH, as credit of the winners.
+	Resolved
+	// Withdrawable games were resolved at least the DelayedWETH delay ago, so their credit can be claimed. Games with a
+	// bondDistributionMode skip this phase: their credit is only unlocked in DelayedWETH when each recipient claims
+	// it, so there is no time from which all of it can be withdrawn, and they stay resolved until they are settled.
+	Withdrawable
+	// Settled games have no credit left in DelayedWETH, so nothing about them can change.
+	Settled
+)
+
+func (p Phase) String() string {
+	switch p {
+	case InProgress:
+		return "in progress"
+	case Resolved:
+		return "resolved"
+	case Withdrawable:
+		return "withdrawable"
+	case Settled:
+		return "settled"
+	}
+	return fmt.Sprintf("phase %d", int(p))
+}
+
+// retention is the phase at which each per game monitor is deleted. The monitors reading the moves and the
+// resolution of the game are kept until the delay elapses, well after the block resolving the game is evaluated. The
+// monitors reading the credit and DelayedWETH are kept until the game is settled, and the withdrawal monitors for
+// the grace period after that.
+var retention = map[string]Phase{
+	"challenged_proposal":         Withdrawable,
+	"challenger_loses":            Withdrawable,
+	"incorrect_claim_bond":        Withdrawable,
+	"unresolvable_dispute_game":   Withdrawable,
+	"credit_and_bond_discrepancy": Settled,
+	"incorrect_bond_balance":      Settled,
+	"eth_deficit":                 Settled,
+	"eth_withdrawn_early":         Settled,
+}
+
+// withdrawalMonitors are kept for the withdrawal grace period after the game is settled.
+var withdrawalMonitors = map[string]bool{
+	"eth_deficit":         true,
+	"eth_withdrawn_early": true,
+}
+
+// Lifecycle is the phase of a game at a block.
+type Lifecycle struct {
+	Phase Phase
+	// Block and Timestamp are those of the block the game was read at.
+	Block     uint64
+	Timestamp uint64
+	// ResolvedAt is zero until the game is resolved.
+	ResolvedAt uint64
+	// WithdrawableAt is the time the credit of a resolved game can be claimed from, zero for the games with a
+	// bondDistributionMode, which skip the Withdrawable phase.
+	WithdrawableAt uint64
+	// Balance is the ETH held by DelayedWETH for a resolved game.
+	Balance *big.Int
+}
+
+// ReadLifecycle reads the phase of game at the latest block of chain.
+func ReadLifecycle(ctx context.Context, chain monitor.Chain, game eth.Address) (Lifecycle, error) {
+	header, err := chain.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return Lifecycle{}, fmt.Errorf("fetching the latest block: %w", err)
+	}
+	lc := Lifecycle{Block: uint64(header.Number), Timestamp: uint64(header.Timestamp)}
+	number := rpc.NumberAt(lc.Block)
+	resolvedAt, err := call(ctx, chain, number, game, resolvedAtMethod)
+	if err != nil {
+		return Lifecycle{}, err
+	}
+	if resolvedAt.(*big.Int).Sign() == 0 {
+		return lc, nil
+	}
+	lc.Phase, lc.ResolvedAt = Resolved, resolvedAt.(*big.Int).Uint64()
+
+	weth, err := call(ctx, chain, number, game, wethMethod)
+	if err != nil {
+		return Lifecycle{}, err
+	}
+	balance, err := call(ctx, chain, number, weth.(eth.Address), balanceOfMethod, game)
+	if err != nil {
+		return Lifecycle{}, err
+	}
+	lc.Balance = balance.(*big.Int)
+
+	// the credit of older games is unlocked when they resolve, so that it can be withdrawn once the delay elapsed
+	var reverted *rpc.Error
+	if _, err := call(ctx, chain, number, game, bondDistributionModeMethod); !errors.As(err, &reverted) {
+		if err != nil {
+			return Lifecycle{}, err
+		}
+	} else {
+		delay, err := call(ctx, chain, number, weth.(eth.Address), delayMethod)
+		if err != nil {
+			return Lifecycle{}, err
+		}
+		lc.WithdrawableAt = lc.ResolvedAt + delay.(*big.Int).Uint64()
+		if lc.Timestamp >= lc.WithdrawableAt {
+			lc.Phase = Withdrawable
+		}
+	}
+	// a resolved game holding nothing has no credit left to claim, even before the delay elapses
+	if lc.Balance.Sign() == 0 {
+		lc.Phase = Settled
+	}
+	return lc, nil
+}
+
+// call calls method on contract at number and returns its single output.
+func call(ctx context.Context, chain monitor.Chain, number rpc.BlockNumber, contract eth.Address, method abi.Method, args ...any) (any, error) {
+	values, err := callAll(ctx, chain, number, contract, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return values[0], nil
+}
+
+// callAll calls method on contract at number and returns its outputs.
+func callAll(ctx context.Context, chain monitor.Chain, number rpc.BlockNumber, contract eth.Address, method abi.Method, args ...any) ([]any, error) {
+	calldata, err := method.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := chain.CallContract(ctx, rpc.CallMsg{To: contract, Data: calldata}, number)
+	if err != nil {
+		return nil, fmt.Errorf("calling %s on %s: %w", method.Name, contract, err)
+	}
+	values, err := method.UnpackOutputs(output)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s of %s: %w", method.Name, contract, err)
+	}
+	return values, nil
+}
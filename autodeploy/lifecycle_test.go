@@ -0,0 +1,230 @@
+package autodeploy_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/follower/chaintest"
+	"github.com/base-org/fault-proof-monitors/hexagate/hexagatetest"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+var (
+	resolvedAt           = abi.MustParseMethod("resolvedAt() returns (uint256)")
+	weth                 = abi.MustParseMethod("weth() returns (address)")
+	delay                = abi.MustParseMethod("delay() returns (uint256)")
+	balanceOf            = abi.MustParseMethod("balanceOf(address) returns (uint256)")
+	bondDistributionMode = abi.MustParseMethod("bondDistributionMode() returns (uint8)")
+
+	delayedWETH = eth.MustAddress("0x00000000000000000000000000000000000000D0")
+)
+
+// settlingGame answers the calls reading the lifecycle of game1, which is resolved at resolvedBlock and holds
+// balance in DelayedWETH until withdrawnBlock. Zero blocks never come. v3 games answer bondDistributionMode.
+type settlingGame struct {
+	resolvedBlock, withdrawnBlock uint64
+	delay                         uint64
+	v3                            bool
+}
+
+func (g *settlingGame) handle(msg rpc.CallMsg, header *eth.Header) ([]byte, error) {
+	selector := msg.Data[:4]
+	reached := func(block uint64) bool { return block != 0 && uint64(header.Number) >= block }
+	switch {
+	case msg.To == game1 && bytes.Equal(selector, resolvedAt.Selector()):
+		at := new(big.Int)
+		if reached(g.resolvedBlock) {
+			at.SetUint64(1000 + g.resolvedBlock*chaintest.BlockTime)
+		}
+		return resolvedAt.PackOutputs(at)
+	case msg.To == game1 && g.v3 && bytes.Equal(selector, bondDistributionMode.Selector()):
+		return bondDistributionMode.PackOutputs(uint8(0))
+	case msg.To == game1 && bytes.Equal(selector, weth.Selector()):
+		return weth.PackOutputs(delayedWETH)
+	case msg.To == delayedWETH && bytes.Equal(selector, delay.Selector()):
+		return delay.PackOutputs(new(big.Int).SetUint64(g.delay))
+	case msg.To == delayedWETH && bytes.Equal(selector, balanceOf.Selector()):
+		balance := big.NewInt(1e18)
+		if reached(g.withdrawnBlock) {
+			balance.SetUint64(0)
+		}
+		return balanceOf.PackOutputs(balance)
+	}
+	return nil, &rpc.Error{Code: 3, Message: "execution reverted"}
+}
+
+func TestReadLifecycle(t *testing.T) {
+	type step struct {
+		head  int
+		phase autodeploy.Phase
+	}
+	for _, c := range []struct {
+		name  string
+		v3    bool
+		steps []step
+	}{
+		{"legacy", false, []step{
+			{4, autodeploy.InProgress},
+			{5, autodeploy.Resolved},
+			// resolved at 1010, withdrawable from 1030
+			{14, autodeploy.Resolved},
+			{15, autodeploy.Withdrawable},
+			{30, autodeploy.Settled},
+		}},
+		// the credit of v3 games is unlocked as it is claimed, so they are never withdrawable as a whole
+		{"v3", true, []step{
+			{4, autodeploy.InProgress},
+			{5, autodeploy.Resolved},
+			{15, autodeploy.Resolved},
+			{29, autodeploy.Resolved},
+			{30, autodeploy.Settled},
+		}},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			chain := chaintest.NewChain(1000)
+			g := &settlingGame{resolvedBlock: 5, withdrawnBlock: 30, delay: 20, v3: c.v3}
+			chain.HandleCall(g.handle)
+			for _, s := range c.steps {
+				chain.Extend(s.head - int(chain.Head().Number))
+				lc, err := autodeploy.ReadLifecycle(context.Background(), chain, game1)
+				if err != nil {
+					t.Fatalf("Error reading lifecycle: %v", err)
+				}
+				if lc.Phase != s.phase {
+					t.Errorf("block %d: expected %s, got %s", s.head, s.phase, lc.Phase)
+				}
+				if c.v3 && lc.WithdrawableAt != 0 {
+					t.Errorf("block %d: expected no withdrawable time, got %d", s.head, lc.WithdrawableAt)
+				}
+			}
+		})
+	}
+}
+
+func TestTeardown(t *testing.T) {
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+	d, err := autodeploy.New(server.Client(), autodeploy.Config{
+		ChainID:                1,
+		Monitors:               templates(),
+		WithdrawalGraceSeconds: 60,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, game := range []eth.Address{game1, game2} {
+		if _, err := d.Deploy(ctx, game); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chain := chaintest.NewChain(1000)
+	g := &settlingGame{resolvedBlock: 5, withdrawnBlock: 30, delay: 20}
+	chain.HandleCall(g.handle)
+	teardown := func(head int) []string {
+		t.Helper()
+		chain.Extend(head - int(chain.Head().Number))
+		removed, err := d.Teardown(ctx, chain)
+		// game2 is not a contract of the chain, so it cannot be read and its monitors are kept
+		if err == nil {
+			t.Fatal("expected the unreadable game to be reported")
+		}
+		var names []string
+		for _, m := range removed {
+			if m.Labels[autodeploy.LabelGame] != "0x00000000000000000000000000000000000000b1" {
+				t.Errorf("unexpected monitor of another game removed: %+v", m)
+			}
+			names = append(names, m.Labels[autodeploy.LabelMonitor])
+		}
+		sort.Strings(names)
+		return names
+	}
+	expect := func(head int, want ...string) {
+		t.Helper()
+		got := teardown(head)
+		if len(got) != len(want) {
+			t.Fatalf("block %d: expected %v to be removed, got %v", head, want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("block %d: expected %v to be removed, got %v", head, want, got)
+			}
+		}
+	}
+
+	expect(10)
+	expect(15, "challenged_proposal", "challenger_loses", "incorrect_claim_bond", "unresolvable_dispute_game")
+	expect(20)
+	expect(30, "credit_and_bond_discrepancy", "incorrect_bond_balance")
+	// settled at the timestamp of block 30, the grace runs for 30 blocks
+	expect(59)
+	expect(60, "eth_deficit", "eth_withdrawn_early")
+	expect(61)
+	if n := len(server.Monitors()); n != len(templates()) {
+		t.Errorf("expected the monitors of the other game to remain, got %d monitors", n)
+	}
+}
+
+func TestStateSurvivesRestart(t *testing.T) {
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "games.json")
+	open := func() *autodeploy.Deployer {
+		t.Helper()
+		d, err := autodeploy.New(server.Client(), autodeploy.Config{
+			ChainID:                1,
+			Monitors:               templates(),
+			WithdrawalGraceSeconds: 60,
+			State:                  path,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	ctx := context.Background()
+	d := open()
+	if _, err := d.Deploy(ctx, game1); err != nil {
+		t.Fatal(err)
+	}
+	chain := chaintest.NewChain(1000)
+	g := &settlingGame{resolvedBlock: 5, withdrawnBlock: 30, delay: 20}
+	chain.HandleCall(g.handle)
+	teardown := func(head int) int {
+		t.Helper()
+		chain.Extend(head - int(chain.Head().Number))
+		removed, err := d.Teardown(ctx, chain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(removed)
+	}
+	if n := teardown(30); n != len(templates())-2 {
+		t.Fatalf("expected all but the withdrawal monitors to be removed, got %d", n)
+	}
+
+	// the monitors removed are not created again by a webhook redelivered after the restart
+	creates := server.Creates()
+	d = open()
+	if _, err := d.Deploy(ctx, game1); err != nil {
+		t.Fatal(err)
+	}
+	if n := server.Creates() - creates; n != 0 {
+		t.Errorf("expected no monitor to be created again, got %d", n)
+	}
+	// and the grace still runs from the timestamp of block 30
+	if n := teardown(59); n != 0 {
+		t.Errorf("expected the withdrawal monitors to be kept, got %d removed", n)
+	}
+	if n := teardown(60); n != 2 {
+		t.Errorf("expected the withdrawal monitors to be removed, got %d", n)
+	}
+}
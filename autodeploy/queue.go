@@ -0,0 +1,239 @@
+package autodeploy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// JobState is the state of a Job.
+type JobState string
+
+const (
+	// JobPending jobs are processed once their next attempt is due.
+	JobPending JobState = "pending"
+	// JobDone jobs created their monitor.
+	JobDone JobState = "done"
+	// JobDead jobs failed every attempt, and wait to be re-driven.
+	JobDead JobState = "dead"
+)
+
+// Job is the creation of a monitor for a game, queued by a webhook delivery.
+type Job struct {
+	ChainID uint64      `json:"chainId"`
+	Game    eth.Address `json:"game"`
+	Monitor string      `json:"monitor"`
+	State   JobState    `json:"state"`
+	// Attempts counts the failed attempts since the job was queued or re-driven.
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"nextAttempt"`
+	LastError   string    `json:"lastError,omitempty"`
+	// MonitorID is the monitor created by a done job.
+	MonitorID  int64     `json:"monitorId,omitempty"`
+	EnqueuedAt time.Time `json:"enqueuedAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// Key identifies the job, which is queued once per chain, game and monitor.
+func (j Job) Key() string {
+	return fmt.Sprintf("%d/%s/%s", j.ChainID, strings.ToLower(j.Game.String()), j.Monitor)
+}
+
+// RetryPolicy spaces the attempts of failing jobs.
+type RetryPolicy struct {
+	// MaxAttempts is the number of failed attempts after which a job is dead.
+	MaxAttempts int
+	// MinBackoff is the delay after the first failure, doubled after each one up to MaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy retries a job for over half an hour.
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 10, MinBackoff: 5 * time.Second, MaxBackoff: 15 * time.Minute}
+
+// Backoff returns the delay before the attempt following the given number of failed attempts.
+func (p RetryPolicy) Backoff(attempts int) time.Duration {
+	backoff := p.MinBackoff
+	for i := 1; i < attempts && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, p.MaxBackoff)
+}
+
+// Queue persists the jobs in a JSON file, which is replaced atomically on every change. Done jobs are kept, so that
+// a webhook delivered again does not queue its game twice.
+type Queue struct {
+	path   string
+	policy RetryPolicy
+
+	mu   sync.Mutex
+	jobs map[string]Job
+	// wake is signalled when jobs become due
+	wake chan struct{}
+}
+
+// OpenQueue loads the queue in path, creating the file on the first change if it does not exist.
+func OpenQueue(path string, policy RetryPolicy) (*Queue, error) {
+	if policy.MaxAttempts < 1 || policy.MinBackoff <= 0 || policy.MaxBackoff < policy.MinBackoff {
+		return nil, fmt.Errorf("invalid retry policy %+v", policy)
+	}
+	q := &Queue{path: path, policy: policy, jobs: make(map[string]Job), wake: make(chan struct{}, 1)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return q, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var jobs []Job
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+	for _, j := range jobs {
+		q.jobs[j.Key()] = j
+	}
+	return q, nil
+}
+
+// Enqueue adds the jobs that were never queued, as pending jobs due at now, and returns them.
+func (q *Queue) Enqueue(now time.Time, jobs ...Job) ([]Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var added []Job
+	seen := make(map[string]bool)
+	for _, j := range jobs {
+		if _, ok := q.jobs[j.Key()]; ok || seen[j.Key()] {
+			continue
+		}
+		seen[j.Key()] = true
+		j.State, j.Attempts, j.LastError, j.MonitorID = JobPending, 0, "", 0
+		j.NextAttempt, j.EnqueuedAt, j.UpdatedAt = now, now, now
+		added = append(added, j)
+	}
+	if err := q.update(added...); err != nil {
+		return nil, err
+	}
+	if len(added) > 0 {
+		q.notify()
+	}
+	return added, nil
+}
+
+// Due returns the pending jobs whose next attempt is at or before now, in the order they were queued.
+func (q *Queue) Due(now time.Time) []Job {
+	return q.Jobs(func(j Job) bool {
+		return j.State == JobPending && !j.NextAttempt.After(now)
+	})
+}
+
+// Complete marks the job done with the monitor it created.
+func (q *Queue) Complete(j Job, monitorID int64, now time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j.State, j.MonitorID, j.LastError, j.UpdatedAt = JobDone, monitorID, "", now
+	return q.update(j)
+}
+
+// Fail records a failed attempt of the job, and schedules the next one or marks the job dead. It returns the
+// updated job.
+func (q *Queue) Fail(j Job, cause error, now time.Time) (Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	j.Attempts++
+	j.LastError, j.UpdatedAt = cause.Error(), now
+	if j.Attempts >= q.policy.MaxAttempts {
+		j.State = JobDead
+	} else {
+		j.NextAttempt = now.Add(q.policy.Backoff(j.Attempts))
+	}
+	return j, q.update(j)
+}
+
+// Redrive queues the dead jobs accepted by filter, or all of them if it is nil, again with their attempts reset. It
+// returns the re-driven jobs.
+func (q *Queue) Redrive(filter func(Job) bool, now time.Time) ([]Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var redriven []Job
+	for _, j := range q.sorted(nil) {
+		if j.State != JobDead || (filter != nil && !filter(j)) {
+			continue
+		}
+		j.State, j.Attempts, j.NextAttempt, j.UpdatedAt = JobPending, 0, now, now
+		redriven = append(redriven, j)
+	}
+	if err := q.update(redriven...); err != nil {
+		return nil, err
+	}
+	if len(redriven) > 0 {
+		q.notify()
+	}
+	return redriven, nil
+}
+
+// Jobs returns the jobs accepted by filter, or all of them if it is nil, in the order they were queued.
+func (q *Queue) Jobs(filter func(Job) bool) []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.sorted(filter)
+}
+
+// Wake returns a channel receiving a value when jobs are queued or re-driven.
+func (q *Queue) Wake() <-chan struct{} {
+	return q.wake
+}
+
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// update stores the jobs and writes the queue, leaving the jobs unchanged if the write fails. q.mu must be held.
+func (q *Queue) update(jobs ...Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+	prev := make(map[string]Job)
+	for _, j := range jobs {
+		if old, ok := q.jobs[j.Key()]; ok {
+			prev[j.Key()] = old
+		}
+		q.jobs[j.Key()] = j
+	}
+	if err := writeJSON(q.path, q.sorted(nil)); err != nil {
+		for _, j := range jobs {
+			if old, ok := prev[j.Key()]; ok {
+				q.jobs[j.Key()] = old
+			} else {
+				delete(q.jobs, j.Key())
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+func (q *Queue) sorted(filter func(Job) bool) []Job {
+	jobs := make([]Job, 0, len(q.jobs))
+	for _, j := range q.jobs {
+		if filter == nil || filter(j) {
+			jobs = append(jobs, j)
+		}
+	}
+	sort.Slice(jobs, func(i, k int) bool {
+		if !jobs[i].EnqueuedAt.Equal(jobs[k].EnqueuedAt) {
+			return jobs[i].EnqueuedAt.Before(jobs[k].EnqueuedAt)
+		}
+		return jobs[i].Key() < jobs[k].Key()
+	})
+	return jobs
+}
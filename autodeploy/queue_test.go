@@ -0,0 +1,267 @@
+package autodeploy_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/hexagate/hexagatetest"
+)
+
+var testPolicy = autodeploy.RetryPolicy{MaxAttempts: 3, MinBackoff: time.Second, MaxBackoff: 3 * time.Second}
+
+func TestRetryPolicy(t *testing.T) {
+	for attempts, want := range []time.Duration{1: time.Second, 2: 2 * time.Second, 3: 3 * time.Second, 4: 3 * time.Second} {
+		if attempts > 0 && testPolicy.Backoff(attempts) != want {
+			t.Errorf("attempt %d: expected %s, got %s", attempts, want, testPolicy.Backoff(attempts))
+		}
+	}
+}
+
+func TestQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	q, err := autodeploy.OpenQueue(path, testPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	job := autodeploy.Job{ChainID: 1, Game: game1, Monitor: "eth_deficit"}
+	if added, err := q.Enqueue(now, job, job); err != nil || len(added) != 1 {
+		t.Fatalf("expected the job to be queued once, got %+v (%v)", added, err)
+	}
+	if added, err := q.Enqueue(now, job); err != nil || len(added) != 0 {
+		t.Fatalf("expected the job not to be queued again, got %+v (%v)", added, err)
+	}
+
+	due := q.Due(now)
+	if len(due) != 1 {
+		t.Fatalf("expected the job to be due, got %+v", due)
+	}
+	failed, err := q.Fail(due[0], errors.New("boom"), now)
+	if err != nil || failed.State != autodeploy.JobPending || !failed.NextAttempt.Equal(now.Add(time.Second)) {
+		t.Fatalf("expected the job to be retried in a second, got %+v (%v)", failed, err)
+	}
+	if len(q.Due(now)) != 0 || len(q.Due(now.Add(time.Second))) != 1 {
+		t.Error("expected the job to be due after its backoff")
+	}
+	failed, _ = q.Fail(failed, errors.New("boom"), now)
+	if failed, err = q.Fail(failed, errors.New("boom"), now); err != nil || failed.State != autodeploy.JobDead {
+		t.Fatalf("expected the job to be dead after 3 attempts, got %+v (%v)", failed, err)
+	}
+	if len(q.Due(now.Add(time.Hour))) != 0 {
+		t.Error("expected a dead job not to be due")
+	}
+
+	// the queue survives a restart
+	q, err = autodeploy.OpenQueue(path, testPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := q.Jobs(autodeploy.JobFilter(autodeploy.JobDead, "", ""))
+	if len(dead) != 1 || dead[0].LastError != "boom" || dead[0].Attempts != 3 {
+		t.Fatalf("expected the dead job to be reloaded, got %+v", dead)
+	}
+	redriven, err := q.Redrive(autodeploy.JobFilter("", strings.ToLower(game1.String()), ""), now)
+	if err != nil || len(redriven) != 1 || redriven[0].Attempts != 0 {
+		t.Fatalf("expected the job to be re-driven, got %+v (%v)", redriven, err)
+	}
+	if err := q.Complete(q.Due(now)[0], 7, now); err != nil {
+		t.Fatal(err)
+	}
+	if jobs := q.Jobs(nil); len(jobs) != 1 || jobs[0].State != autodeploy.JobDone || jobs[0].MonitorID != 7 {
+		t.Errorf("expected the job to be done, got %+v", jobs)
+	}
+}
+
+func TestQueuedWebhook(t *testing.T) {
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "queue.json")
+	q, err := autodeploy.OpenQueue(path, testPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := autodeploy.New(server.Client(), autodeploy.Config{ChainID: 1, Monitors: templates(), Queue: q})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := `{"event": {"disputeProxy": "` + game1.String() + `"}}`
+	deliver := func() []string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		d.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload)))
+		if rec.Code != http.StatusAccepted {
+			t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body)
+		}
+		var resp struct {
+			Queued []struct {
+				Game     eth.Address
+				Monitors []string
+			}
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Queued[0].Monitors
+	}
+	if queued := deliver(); len(queued) != len(templates()) {
+		t.Fatalf("expected every monitor to be queued, got %v", queued)
+	}
+	if queued := deliver(); len(queued) != 0 {
+		t.Fatalf("expected a delivery repeated to queue nothing, got %v", queued)
+	}
+	if server.Creates() != 0 {
+		t.Fatal("expected the webhook to create nothing")
+	}
+
+	// the third create fails every attempt, each job listing the monitors of its game before creating its own
+	ctx := context.Background()
+	now := time.Now().UTC()
+	server.FailNext(0, 0, 0, 0, 0, 500)
+	done, err := d.ProcessDue(ctx, now)
+	if err == nil || len(done) != len(templates())-1 {
+		t.Fatalf("expected one job to fail, got %d done (%v)", len(done), err)
+	}
+	server.FailNext(500)
+	if _, err := d.ProcessDue(ctx, now.Add(time.Second)); err == nil {
+		t.Fatal("expected the retry to fail")
+	}
+	server.FailNext(500)
+	if _, err := d.ProcessDue(ctx, now.Add(time.Hour)); err == nil || !strings.Contains(err.Error(), "giving up after 3 attempts") {
+		t.Fatalf("expected the job to die, got %v", err)
+	}
+
+	// the dead job is re-driven through the admin handler
+	handler, err := autodeploy.QueueHandler(q, "token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin := httptest.NewServer(handler)
+	defer admin.Close()
+	request := func(method, path string, token string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(method, admin.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	for _, token := range []string{"", "wrong"} {
+		resp := request(http.MethodPost, autodeploy.RedrivePath, token)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected a request with token %q to be rejected, got %s", token, resp.Status)
+		}
+	}
+	resp := request(http.MethodGet, autodeploy.JobsPath+"?state=dead", "token")
+	var dead []autodeploy.Job
+	if err := json.NewDecoder(resp.Body).Decode(&dead); err != nil || len(dead) != 1 || dead[0].Monitor != "credit_and_bond_discrepancy" {
+		t.Fatalf("expected the third monitor to be dead, got %+v (%v)", dead, err)
+	}
+	resp.Body.Close()
+	if resp = request(http.MethodPost, autodeploy.RedrivePath+"?monitor=credit_and_bond_discrepancy", "token"); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Error re-driving: %s", resp.Status)
+	}
+	resp.Body.Close()
+	if done, err = d.ProcessDue(ctx, now.Add(time.Hour)); err != nil || len(done) != 1 {
+		t.Fatalf("expected the re-driven job to be done, got %+v (%v)", done, err)
+	}
+	if server.Creates() != len(templates()) {
+		t.Errorf("expected each monitor to be created once, got %d creates", server.Creates())
+	}
+}
+
+func TestQueueAfterCrash(t *testing.T) {
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "queue.json")
+	q, err := autodeploy.OpenQueue(path, testPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := autodeploy.New(server.Client(), autodeploy.Config{ChainID: 1, Monitors: templates(), Queue: q})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Enqueue(game1); err != nil {
+		t.Fatal(err)
+	}
+	// the monitors are created, then the deployer crashes before the jobs are marked done
+	if _, err := d.Deploy(context.Background(), game1); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err = autodeploy.OpenQueue(path, testPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarted, err := autodeploy.New(server.Client(), autodeploy.Config{ChainID: 1, Monitors: templates(), Queue: q})
+	if err != nil {
+		t.Fatal(err)
+	}
+	done, err := restarted.ProcessDue(context.Background(), time.Now().UTC())
+	if err != nil || len(done) != len(templates()) {
+		t.Fatalf("expected every job to be done, got %d (%v)", len(done), err)
+	}
+	if server.Creates() != len(templates()) {
+		t.Errorf("expected the monitors not to be created again, got %d creates", server.Creates())
+	}
+}
+
+func TestQueueAfterExpiredKeys(t *testing.T) {
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+	q, err := autodeploy.OpenQueue(filepath.Join(t.TempDir(), "queue.json"), testPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := autodeploy.New(server.Client(), autodeploy.Config{ChainID: 1, Monitors: templates(), Queue: q})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Enqueue(game1); err != nil {
+		t.Fatal(err)
+	}
+	// the monitors were created before a crash, by requests whose idempotency keys have since expired
+	ctx := context.Background()
+	var ids []int64
+	for _, tmpl := range templates() {
+		m, err := tmpl.Render(1, game1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		created, err := server.Client().CreateMonitor(ctx, m, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+
+	done, err := d.ProcessDue(ctx, time.Now().UTC())
+	if err != nil || len(done) != len(templates()) {
+		t.Fatalf("expected every job to be done, got %d (%v)", len(done), err)
+	}
+	if server.Creates() != len(templates()) {
+		t.Errorf("expected the monitors not to be created again, got %d creates", server.Creates())
+	}
+	for i, j := range done {
+		if j.MonitorID != ids[i] {
+			t.Errorf("expected job %s to be done with monitor %d, got %d", j.Key(), ids[i], j.MonitorID)
+		}
+	}
+}
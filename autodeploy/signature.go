@@ -0,0 +1,195 @@
+package autodeploy
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SignatureHeader and TimestampHeader carry the signature of a webhook delivery and the unix time it was signed at.
+const (
+	SignatureHeader = "X-Signature-256"
+	TimestampHeader = "X-Webhook-Timestamp"
+)
+
+// TokenParam is the query param of the webhook URL carrying the token of deliveries that are not signed.
+const TokenParam = "token"
+
+// DefaultTolerance is the age after which a signed delivery is rejected.
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrBadSignature is returned for deliveries whose signature is missing or does not match.
+	ErrBadSignature = errors.New("invalid webhook signature")
+	// ErrBadToken is returned for unsigned deliveries whose token is missing or does not match.
+	ErrBadToken = errors.New("invalid webhook token")
+	// ErrStale is returned for deliveries signed too long ago, or too far in the future.
+	ErrStale = errors.New("webhook timestamp outside of tolerance")
+	// ErrReplay is returned for deliveries whose signature was already accepted.
+	ErrReplay = errors.New("webhook delivery replayed")
+)
+
+// Sign returns the signature of a payload sent at timestamp: sha256= followed by the hex HMAC-SHA256, keyed with the
+// shared secret, of the decimal timestamp, a dot and the payload. Signing the timestamp binds it to the payload, so
+// that an old delivery cannot be replayed with a fresh timestamp.
+func Sign(secret []byte, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verifier authenticates webhook deliveries signed with Sign, keyed with a shared secret, and rejects them when
+// replayed within the tolerance. Older deliveries are rejected by their timestamp, so signatures are only remembered
+// for that long.
+//
+// Deliveries that are not signed are rejected, unless a token is accepted with AcceptToken: a sender that cannot sign
+// its deliveries, such as a webhook channel configured with nothing but its URL, then passes the token in the token
+// query param of the URL, or as a bearer token.
+type Verifier struct {
+	secret    []byte
+	tolerance time.Duration
+	// token authenticates the unsigned deliveries, if set
+	token []byte
+
+	// OnError is called with the errors deliveries are rejected with.
+	OnError func(error)
+
+	mu sync.Mutex
+	// seen holds the accepted signatures with the time they were signed at
+	seen map[string]time.Time
+}
+
+// NewVerifier returns a verifier of the deliveries signed with secret at most tolerance ago, or DefaultTolerance if
+// it is zero.
+func NewVerifier(secret []byte, tolerance time.Duration) (*Verifier, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("empty webhook secret")
+	}
+	if tolerance == 0 {
+		tolerance = DefaultTolerance
+	}
+	return &Verifier{secret: secret, tolerance: tolerance, seen: make(map[string]time.Time)}, nil
+}
+
+// Verify checks the signature and the timestamp of a delivery of payload, and records the signature so that the same
+// delivery is rejected with ErrReplay until it expires.
+func (v *Verifier) Verify(header http.Header, payload []byte) error {
+	timestamp, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: missing or invalid %s", ErrBadSignature, TimestampHeader)
+	}
+	signature := strings.TrimSpace(header.Get(SignatureHeader))
+	if !hmac.Equal([]byte(signature), []byte(Sign(v.secret, timestamp, payload))) {
+		return ErrBadSignature
+	}
+	now := time.Now()
+	signed := time.Unix(timestamp, 0)
+	if now.Sub(signed) > v.tolerance || signed.Sub(now) > v.tolerance {
+		return fmt.Errorf("%w: signed at %s", ErrStale, signed.UTC().Format(time.RFC3339))
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for s, at := range v.seen {
+		if now.Sub(at) > v.tolerance {
+			delete(v.seen, s)
+		}
+	}
+	if _, ok := v.seen[signature]; ok {
+		return ErrReplay
+	}
+	v.seen[signature] = signed
+	return nil
+}
+
+// AcceptToken accepts the unsigned deliveries carrying token. Such deliveries are not protected against replays, and
+// the token ends up in the URLs and the logs of the sender, so it must be served over TLS and differ from the secret
+// signing the deliveries.
+func (v *Verifier) AcceptToken(token string) error {
+	if token == "" {
+		return errors.New("empty webhook token")
+	}
+	if subtle.ConstantTimeCompare([]byte(token), v.secret) == 1 {
+		return errors.New("webhook token must differ from the webhook secret")
+	}
+	v.token = []byte(token)
+	return nil
+}
+
+// Authenticate checks a delivery of payload: its signature, as Verify does, unless it is not signed and a token is
+// accepted, in which case it checks the token.
+func (v *Verifier) Authenticate(r *http.Request, payload []byte) error {
+	if r.Header.Get(SignatureHeader) != "" || v.token == nil {
+		return v.Verify(r.Header, payload)
+	}
+	token := r.URL.Query().Get(TokenParam)
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && token == "" {
+		token = bearer
+	}
+	if subtle.ConstantTimeCompare([]byte(token), v.token) != 1 {
+		return ErrBadToken
+	}
+	return nil
+}
+
+// forget removes an accepted signature, so that the sender can retry a delivery that failed.
+func (v *Verifier) forget(header http.Header) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.seen, strings.TrimSpace(header.Get(SignatureHeader)))
+}
+
+// Handler returns a handler passing the authenticated deliveries to next. Deliveries with an invalid token, signature
+// or timestamp are rejected with 401, and replayed signatures with 409. A signed delivery that next fails with a 5xx
+// status can be retried with the same signature, while it is within the tolerance.
+func (v *Verifier) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+		if err != nil {
+			v.fail(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := v.Authenticate(r, payload); errors.Is(err, ErrReplay) {
+			v.fail(w, http.StatusConflict, err)
+			return
+		} else if err != nil {
+			v.fail(w, http.StatusUnauthorized, err)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(payload))
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if rec.status >= http.StatusInternalServerError {
+			v.forget(r.Header)
+		}
+	})
+}
+
+func (v *Verifier) fail(w http.ResponseWriter, status int, err error) {
+	if v.OnError != nil {
+		v.OnError(err)
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// statusRecorder records the status a handler answers with.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,229 @@
+package autodeploy_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/follower/chaintest"
+	"github.com/base-org/fault-proof-monitors/hexagate/hexagatetest"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+var (
+	gameType  = abi.MustParseMethod("gameType() returns (uint32 gameType_)")
+	rootClaim = abi.MustParseMethod("rootClaim() returns (bytes32 rootClaim_)")
+	extraData = abi.MustParseMethod("extraData() returns (bytes extraData_)")
+	games     = abi.MustParseMethod("games(uint32 _gameType, bytes32 _rootClaim, bytes _extraData) returns (address proxy_, uint64 timestamp_)")
+)
+
+func signed(secret string, at time.Time, payload string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+	r.Header.Set(autodeploy.TimestampHeader, strconv.FormatInt(at.Unix(), 10))
+	r.Header.Set(autodeploy.SignatureHeader, autodeploy.Sign([]byte(secret), at.Unix(), []byte(payload)))
+	return r
+}
+
+func TestVerifier(t *testing.T) {
+	v, err := autodeploy.NewVerifier([]byte("secret"), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := http.StatusOK
+	var received string
+	handler := v.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(r.Body)
+		received = buf.String()
+		w.WriteHeader(status)
+	}))
+	serve := func(r *http.Request) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+		return rec.Code
+	}
+
+	now := time.Now()
+	payload := `{"event": {"disputeProxy": "` + game1.String() + `"}}`
+	unsigned := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+	withToken := func(token string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/?"+autodeploy.TokenParam+"="+token, strings.NewReader(payload))
+	}
+	bearer := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload))
+	bearer.Header.Set("Authorization", "Bearer token")
+	badSignature := signed("other", now, payload)
+	badSignature.URL.RawQuery = autodeploy.TokenParam + "=token"
+	tampered := signed("secret", now, payload)
+	tampered.Body = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload+" ")).Body
+	for name, tc := range map[string]struct {
+		r    *http.Request
+		want int
+	}{
+		"unsigned": {unsigned, http.StatusUnauthorized},
+		// unsigned deliveries are rejected until a token is accepted
+		"token":       {withToken("token"), http.StatusUnauthorized},
+		"secret":      {withToken("secret"), http.StatusUnauthorized},
+		"wrong key":   {signed("other", now, payload), http.StatusUnauthorized},
+		"tampered":    {tampered, http.StatusUnauthorized},
+		"stale":       {signed("secret", now.Add(-2*time.Minute), payload), http.StatusUnauthorized},
+		"future":      {signed("secret", now.Add(2*time.Minute), payload), http.StatusUnauthorized},
+		"first valid": {signed("secret", now, payload), http.StatusOK},
+	} {
+		if got := serve(tc.r); got != tc.want {
+			t.Errorf("%s: expected %d, got %d", name, tc.want, got)
+		}
+	}
+	if received != payload {
+		t.Errorf("expected the payload to be passed on, got %q", received)
+	}
+	if got := serve(signed("secret", now, payload)); got != http.StatusConflict {
+		t.Errorf("expected a replay to be rejected with 409, got %d", got)
+	}
+
+	// a delivery failing downstream can be retried
+	later := now.Add(time.Second)
+	status = http.StatusBadGateway
+	if got := serve(signed("secret", later, payload)); got != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d", got)
+	}
+	status = http.StatusOK
+	if got := serve(signed("secret", later, payload)); got != http.StatusOK {
+		t.Errorf("expected the retry to be accepted, got %d", got)
+	}
+
+	if err := v.AcceptToken("secret"); err == nil {
+		t.Error("expected the secret to be rejected as a token")
+	}
+	if err := v.AcceptToken("token"); err != nil {
+		t.Fatal(err)
+	}
+	for name, tc := range map[string]struct {
+		r    *http.Request
+		want int
+	}{
+		"unsigned":    {unsigned, http.StatusUnauthorized},
+		"wrong token": {withToken("other"), http.StatusUnauthorized},
+		"secret":      {withToken("secret"), http.StatusUnauthorized},
+		"token":       {withToken("token"), http.StatusOK},
+		"bearer":      {bearer, http.StatusOK},
+		// a signature is checked even when the token is valid
+		"bad signature, token": {badSignature, http.StatusUnauthorized},
+	} {
+		if got := serve(tc.r); got != tc.want {
+			t.Errorf("%s with a token: expected %d, got %d", name, tc.want, got)
+		}
+	}
+
+	if _, err := autodeploy.NewVerifier(nil, 0); err == nil {
+		t.Error("expected an empty secret to be rejected")
+	}
+}
+
+// factoryGames answers the calls confirming that the games were created by the fake factory.
+func factoryGames(created ...eth.Address) chaintest.CallHandler {
+	return func(msg rpc.CallMsg, header *eth.Header) ([]byte, error) {
+		selector := msg.Data[:4]
+		switch {
+		case bytes.Equal(selector, gameType.Selector()) && msg.To != factory:
+			return gameType.PackOutputs(uint32(0))
+		case bytes.Equal(selector, rootClaim.Selector()) && msg.To != factory:
+			return rootClaim.PackOutputs(eth.Hash{31: msg.To[19]})
+		case bytes.Equal(selector, extraData.Selector()) && msg.To != factory:
+			return extraData.PackOutputs([]byte{1})
+		case bytes.Equal(selector, games.Selector()) && msg.To == factory:
+			args, err := games.UnpackInputs(msg.Data)
+			if err != nil {
+				return nil, err
+			}
+			for _, game := range created {
+				if args[1].([]byte)[31] == game[19] {
+					return games.PackOutputs(game, uint64(1))
+				}
+			}
+			return games.PackOutputs(eth.Address{}, uint64(0))
+		}
+		return nil, &rpc.Error{Code: 3, Message: "execution reverted"}
+	}
+}
+
+func TestConfirmGame(t *testing.T) {
+	chain := chaintest.NewChain(1000)
+	chain.Extend(1)
+	spoofed := eth.MustAddress("0x00000000000000000000000000000000000000B2")
+	chain.HandleCall(factoryGames(game1))
+	ctx := context.Background()
+	if err := autodeploy.ConfirmGame(ctx, chain, factory, game1); err != nil {
+		t.Errorf("expected the game to be confirmed, got %v", err)
+	}
+	// a contract copying a game, and an address that is not a game
+	if err := autodeploy.ConfirmGame(ctx, chain, factory, spoofed); !errors.Is(err, autodeploy.ErrUnknownGame) {
+		t.Errorf("expected an unknown game, got %v", err)
+	}
+	if err := autodeploy.ConfirmGame(ctx, chain, factory, factory); !errors.Is(err, autodeploy.ErrUnknownGame) {
+		t.Errorf("expected an unknown game, got %v", err)
+	}
+
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+	d, err := autodeploy.New(server.Client(), autodeploy.Config{ChainID: 1, Monitors: templates(), Factory: factory, Chain: chain})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for game, want := range map[eth.Address]int{spoofed: http.StatusUnprocessableEntity, game1: http.StatusOK} {
+		rec := httptest.NewRecorder()
+		payload := `{"event": {"disputeProxy": "` + game.String() + `"}}`
+		d.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload)))
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d: %s", game, want, rec.Code, rec.Body)
+		}
+	}
+	if server.Creates() != len(templates()) {
+		t.Errorf("expected only the monitors of the confirmed game to be created, got %d creates", server.Creates())
+	}
+	if _, err := autodeploy.New(server.Client(), autodeploy.Config{ChainID: 1, Monitors: templates(), Factory: factory}); err == nil {
+		t.Error("expected a factory without a chain to be rejected")
+	}
+}
+
+func TestChildConfirmGame(t *testing.T) {
+	chain := chaintest.NewChain(1000)
+	chain.Extend(1)
+	spoofed := eth.MustAddress("0x00000000000000000000000000000000000000B2")
+	chain.HandleCall(factoryGames(game1))
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+	store, err := autodeploy.OpenStore(filepath.Join(t.TempDir(), "deployments.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := autodeploy.ChildConfig{ChainID: 1, CBChallenger: cbChallenger, Factory: factory, Chain: chain}
+	d, err := autodeploy.NewChildDeployer(server.Client(), store, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for game, want := range map[eth.Address]int{spoofed: http.StatusUnprocessableEntity, game1: http.StatusOK} {
+		rec := httptest.NewRecorder()
+		payload := parentAlert("Dispute game created with incorrect L2 output proposal", game)
+		d.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(payload)))
+		if rec.Code != want {
+			t.Errorf("%s: expected %d, got %d: %s", game, want, rec.Code, rec.Body)
+		}
+	}
+	if monitors := server.Monitors(); len(monitors) != 1 || monitors[0].Params["disputeGame"] != game1.String() {
+		t.Errorf("expected only the child of the confirmed game to be created, got %+v", monitors)
+	}
+	cfg.Chain = nil
+	if _, err := autodeploy.NewChildDeployer(server.Client(), store, cfg); err == nil {
+		t.Error("expected a factory without a chain to be rejected")
+	}
+}
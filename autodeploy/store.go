@@ -0,0 +1,143 @@
+package autodeploy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// Parent is the monitor whose alert triggered a deployment.
+type Parent struct {
+	Monitor string `json:"monitor"`
+	// MonitorID is the ID of the parent monitor on Hexagate, when the alert carries it.
+	MonitorID int64 `json:"monitorId,omitempty"`
+}
+
+// Deployment is a monitor deployed to a game.
+type Deployment struct {
+	Game      eth.Address `json:"game"`
+	Monitor   string      `json:"monitor"`
+	MonitorID int64       `json:"monitorId"`
+	Parent    *Parent     `json:"parent,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+	// RemovedAt is set once the monitor is deleted from Hexagate.
+	RemovedAt *time.Time `json:"removedAt,omitempty"`
+}
+
+// Active reports whether the monitor is still deployed.
+func (d Deployment) Active() bool {
+	return d.RemovedAt == nil
+}
+
+type deploymentKey struct {
+	game    eth.Address
+	monitor string
+}
+
+// Store persists the deployments in a JSON file, which is replaced atomically on every change. Removed deployments
+// are kept, so that a game is never deployed to twice.
+type Store struct {
+	path string
+
+	mu          sync.RWMutex
+	deployments map[deploymentKey]Deployment
+}
+
+// OpenStore loads the store in path, creating the file on the first change if it does not exist.
+func OpenStore(path string) (*Store, error) {
+	s := &Store{path: path, deployments: make(map[deploymentKey]Deployment)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var deployments []Deployment
+	if err := json.Unmarshal(data, &deployments); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+	for _, d := range deployments {
+		s.deployments[deploymentKey{d.Game, d.Monitor}] = d
+	}
+	return s, nil
+}
+
+// Get returns the deployment of monitor to game.
+func (s *Store) Get(game eth.Address, monitor string) (Deployment, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	d, ok := s.deployments[deploymentKey{game, monitor}]
+	return d, ok
+}
+
+// Put adds or replaces a deployment and writes the store.
+func (s *Store) Put(d Deployment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := deploymentKey{d.Game, d.Monitor}
+	prev, existed := s.deployments[key]
+	s.deployments[key] = d
+	if err := writeJSON(s.path, s.sorted(nil)); err != nil {
+		if existed {
+			s.deployments[key] = prev
+		} else {
+			delete(s.deployments, key)
+		}
+		return err
+	}
+	return nil
+}
+
+// Deployments returns the deployments accepted by filter, or all of them if it is nil, in order of creation.
+func (s *Store) Deployments(filter func(Deployment) bool) []Deployment {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sorted(filter)
+}
+
+func (s *Store) sorted(filter func(Deployment) bool) []Deployment {
+	deployments := make([]Deployment, 0, len(s.deployments))
+	for _, d := range s.deployments {
+		if filter == nil || filter(d) {
+			deployments = append(deployments, d)
+		}
+	}
+	sort.Slice(deployments, func(i, j int) bool {
+		if !deployments[i].CreatedAt.Equal(deployments[j].CreatedAt) {
+			return deployments[i].CreatedAt.Before(deployments[j].CreatedAt)
+		}
+		return deployments[i].MonitorID < deployments[j].MonitorID
+	})
+	return deployments
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,208 @@
+package autodeploy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+)
+
+// maxBodySize bounds the webhook payloads read.
+const maxBodySize = 1 << 20
+
+// ErrNoGame is returned for alerts that carry no DisputeGameCreated event.
+var ErrNoGame = errors.New("no created dispute game in alert")
+
+// gameKeys are the keys holding the created game in the decoded event of an alert: the name of the event param, and
+// the name used by the README workflow.
+var gameKeys = []string{"disputeproxy", "disputegameaddress"}
+
+// CreatedGames returns the dispute games created in a webhook payload, in the order they appear. The payload of a
+// Contract Event monitor holds the decoded event, whose disputeProxy or disputeGameAddress value is the game, and may
+// hold the raw logs, whose DisputeGameCreated entries have the game as first indexed topic. A payload may carry
+// several events.
+func CreatedGames(payload []byte) ([]eth.Address, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decoding webhook payload: %w", err)
+	}
+	var games []eth.Address
+	seen := make(map[eth.Address]bool)
+	add := func(game eth.Address) {
+		if !seen[game] && game != (eth.Address{}) {
+			seen[game] = true
+			games = append(games, game)
+		}
+	}
+	if err := walk(v, add); err != nil {
+		return nil, err
+	}
+	if len(games) == 0 {
+		return nil, ErrNoGame
+	}
+	return games, nil
+}
+
+func walk(v any, add func(eth.Address)) error {
+	switch v := v.(type) {
+	case map[string]any:
+		if game, ok, err := createdLog(v); err != nil {
+			return err
+		} else if ok {
+			add(game)
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			s, ok := v[k].(string)
+			if !ok {
+				if err := walk(v[k], add); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, key := range gameKeys {
+				if strings.ToLower(k) == key {
+					game, err := eth.ParseAddress(s)
+					if err != nil {
+						return fmt.Errorf("invalid %s: %w", k, err)
+					}
+					add(game)
+				}
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := walk(item, add); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// createdLog decodes the game of a raw DisputeGameCreated log.
+func createdLog(v map[string]any) (eth.Address, bool, error) {
+	topics, ok := v["topics"].([]any)
+	if !ok || len(topics) < 2 {
+		return eth.Address{}, false, nil
+	}
+	topic0, _ := topics[0].(string)
+	if !strings.EqualFold(topic0, abi.DisputeGameCreated.Topic().String()) {
+		return eth.Address{}, false, nil
+	}
+	topic1, _ := topics[1].(string)
+	h, err := eth.ParseHash(topic1)
+	if err != nil {
+		return eth.Address{}, false, fmt.Errorf("invalid DisputeGameCreated topic: %w", err)
+	}
+	var game eth.Address
+	copy(game[:], h[12:])
+	return game, true, nil
+}
+
+// deployment is a game in the response of the webhook handler.
+type deployment struct {
+	Game     eth.Address        `json:"game"`
+	Monitors []hexagate.Monitor `json:"monitors"`
+}
+
+// ServeHTTP receives alert webhooks and deploys the monitors of the games they carry. Payloads without a game, or
+// with a game the factory of the config did not create, are rejected with 422. Failures of the management API, and
+// of the chain when confirming games, are answered with 502, so that the delivery is retried. With a queue, the
+// handler answers 202 once the monitors are queued, and Work creates them.
+func (d *Deployer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		d.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	games, err := CreatedGames(payload)
+	if errors.Is(err, ErrNoGame) {
+		d.fail(w, http.StatusUnprocessableEntity, err)
+		return
+	} else if err != nil {
+		d.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	if d.cfg.Factory != (eth.Address{}) {
+		for _, game := range games {
+			if err := ConfirmGame(r.Context(), d.cfg.Chain, d.cfg.Factory, game); errors.Is(err, ErrUnknownGame) {
+				d.fail(w, http.StatusUnprocessableEntity, err)
+				return
+			} else if err != nil {
+				d.fail(w, http.StatusBadGateway, err)
+				return
+			}
+		}
+	}
+
+	if d.cfg.Queue != nil {
+		d.enqueue(w, games)
+		return
+	}
+	resp := struct {
+		Deployed []deployment `json:"deployed"`
+	}{}
+	for _, game := range games {
+		monitors, err := d.Deploy(r.Context(), game)
+		if err != nil {
+			d.fail(w, http.StatusBadGateway, err)
+			return
+		}
+		resp.Deployed = append(resp.Deployed, deployment{Game: game, Monitors: monitors})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// enqueue answers a webhook once the monitors of its games are persisted in the queue, with the monitors that were
+// not queued by an earlier delivery.
+func (d *Deployer) enqueue(w http.ResponseWriter, games []eth.Address) {
+	type queued struct {
+		Game     eth.Address `json:"game"`
+		Monitors []string    `json:"monitors"`
+	}
+	resp := struct {
+		Queued []queued `json:"queued"`
+	}{Queued: []queued{}}
+	for _, game := range games {
+		jobs, err := d.Enqueue(game)
+		if err != nil {
+			d.fail(w, http.StatusInternalServerError, err)
+			return
+		}
+		q := queued{Game: game, Monitors: []string{}}
+		for _, j := range jobs {
+			q.Monitors = append(q.Monitors, j.Monitor)
+		}
+		resp.Queued = append(resp.Queued, q)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (d *Deployer) fail(w http.ResponseWriter, status int, err error) {
+	if d.cfg.OnError != nil {
+		d.cfg.OnError(err)
+	}
+	http.Error(w, err.Error(), status)
+}
@@ -0,0 +1,123 @@
+package autodeploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+)
+
+// Enqueue queues the creation of the monitors of game in the queue of the config, and returns the jobs that were not
+// queued before.
+func (d *Deployer) Enqueue(game eth.Address) ([]Job, error) {
+	if d.cfg.Queue == nil {
+		return nil, errors.New("no queue configured")
+	}
+	jobs := make([]Job, len(d.cfg.Monitors))
+	for i, t := range d.cfg.Monitors {
+		jobs[i] = Job{ChainID: d.cfg.ChainID, Game: game, Monitor: t.Monitor}
+	}
+	return d.cfg.Queue.Enqueue(time.Now().UTC(), jobs...)
+}
+
+// ProcessDue attempts the jobs of the queue due at now once, and returns the jobs done. A monitor already created
+// for the game completes its job without being created again: the monitors are looked up by their labels before they
+// are created, so that a job interrupted by a crash after the monitor was created is done even once the idempotency
+// key of the request expired, and each monitor is created once.
+// Failed jobs are retried per the retry policy of the queue, and the errors of the attempts are returned together.
+func (d *Deployer) ProcessDue(ctx context.Context, now time.Time) ([]Job, error) {
+	q := d.cfg.Queue
+	if q == nil {
+		return nil, errors.New("no queue configured")
+	}
+	var done []Job
+	var errs []error
+	for _, j := range q.Due(now) {
+		if ctx.Err() != nil {
+			return done, ctx.Err()
+		}
+		id, err := d.process(ctx, j)
+		if err != nil {
+			failed, ferr := q.Fail(j, err, now)
+			if ferr != nil {
+				return done, ferr
+			}
+			if failed.State == JobDead {
+				err = fmt.Errorf("%w, giving up after %d attempts", err, failed.Attempts)
+			}
+			errs = append(errs, err)
+			continue
+		}
+		if err := q.Complete(j, id, now); err != nil {
+			return done, err
+		}
+		j.State, j.MonitorID = JobDone, id
+		done = append(done, j)
+	}
+	return done, errors.Join(errs...)
+}
+
+func (d *Deployer) process(ctx context.Context, j Job) (int64, error) {
+	if j.ChainID != d.cfg.ChainID {
+		return 0, fmt.Errorf("job %s is for chain %d, the deployer runs on chain %d", j.Key(), j.ChainID, d.cfg.ChainID)
+	}
+	var template *Template
+	for i := range d.cfg.Monitors {
+		if d.cfg.Monitors[i].Monitor == j.Monitor {
+			template = &d.cfg.Monitors[i]
+		}
+	}
+	if template == nil {
+		return 0, fmt.Errorf("job %s: %s is not deployed per game", j.Key(), j.Monitor)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if m, ok := d.deployed[j.Game][j.Monitor]; ok {
+		return m.ID, nil
+	}
+	existing, err := d.client.AllMonitors(ctx, hexagate.ListOptions{
+		ChainID: d.cfg.ChainID,
+		Labels: map[string]string{
+			LabelManagedBy: ManagedBy,
+			LabelMonitor:   j.Monitor,
+			LabelGame:      strings.ToLower(j.Game.String()),
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("job %s: listing the deployed monitors: %w", j.Key(), err)
+	}
+	if len(existing) > 0 {
+		if d.deployed[j.Game] == nil {
+			d.deployed[j.Game] = make(map[string]hexagate.Monitor)
+		}
+		d.deployed[j.Game][j.Monitor] = existing[0]
+		return existing[0].ID, d.save()
+	}
+	m, err := d.create(ctx, j.Game, *template)
+	if err != nil {
+		return 0, err
+	}
+	return m.ID, nil
+}
+
+// Work processes the due jobs of the queue when jobs are queued and every interval, until ctx is cancelled. The
+// errors of the attempts are passed to the OnError callback of the config.
+func (d *Deployer) Work(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.ProcessDue(ctx, time.Now().UTC()); err != nil && ctx.Err() == nil && d.cfg.OnError != nil {
+			d.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.cfg.Queue.Wake():
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package capture records everything the monitors read over a block range into an archive, so that the range can
+// be replayed offline. Rather than listing what to fetch, it evaluates the monitors through recording clients with
+// every source loaded: claim data, events, block traces, DelayedWETH state, block timestamps and L2 output roots
+// are captured because the monitors read them.
+package capture
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/archive"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/replay"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+// ErrGameNotFound is returned when the factory did not create the game being captured.
+var ErrGameNotFound = errors.New("dispute game not created by factory")
+
+// Config configures a capture.
+type Config struct {
+	// Endpoint is the JSON-RPC endpoint of the chain the monitors are deployed on. Capturing traces requires the
+	// debug namespace.
+	Endpoint string
+	// L2Endpoints are the JSON-RPC endpoints of the L2 chains whose outputs are proposed, keyed by chain ID.
+	L2Endpoints map[uint64]string
+	// Dir is the directory the archive is written to.
+	Dir         string
+	Description string
+
+	// FromBlock and ToBlock are the block range to capture. When Game is set, they default to the blocks from its
+	// creation until it was resolved, or until the head if it is not resolved yet.
+	FromBlock uint64
+	ToBlock   uint64
+	// Game is a dispute game created by Factory. Monitors taking a disputeGame param that is not set are deployed
+	// for it.
+	Game    eth.Address
+	Factory eth.Address
+
+	Monitors []replay.Deployment
+	// SkipTraces skips tracing blocks, for endpoints without the debug namespace. Monitors then see no calls.
+	SkipTraces bool
+}
+
+// Run captures the block range into a new archive and returns its manifest, along with the timeline of the alerts
+// the monitors raised while capturing.
+func Run(ctx context.Context, cfg Config) (*archive.Manifest, *replay.Timeline, error) {
+	w, err := archive.Create(cfg.Dir, archive.Manifest{Description: cfg.Description})
+	if err != nil {
+		return nil, nil, err
+	}
+	chain := w.Client(cfg.Endpoint, 0)
+	var chainID eth.Uint64
+	if err := chain.Call(ctx, &chainID, "eth_chainId"); err != nil {
+		return nil, nil, fmt.Errorf("fetching chain id: %w", err)
+	}
+
+	deployments := cfg.Monitors
+	if cfg.Game != (eth.Address{}) {
+		if cfg.FromBlock, cfg.ToBlock, err = gameRange(ctx, chain, cfg.Factory, cfg.Game, cfg.FromBlock, cfg.ToBlock); err != nil {
+			return nil, nil, err
+		}
+		deployments = forGame(deployments, cfg.Game)
+	}
+	if cfg.ToBlock < cfg.FromBlock {
+		return nil, nil, fmt.Errorf("invalid block range %d-%d", cfg.FromBlock, cfg.ToBlock)
+	}
+
+	rcfg := replay.Config{
+		Chain:     chain,
+		FromBlock: cfg.FromBlock,
+		ToBlock:   cfg.ToBlock,
+		Monitors:  deployments,
+		// loading the sources for mocks reads every source, including the ones the monitors skip when their
+		// invariants cannot fail, so the archive can also be replayed through the validate API
+		Validator: replay.MocksValidator{},
+	}
+	if !cfg.SkipTraces {
+		rcfg.Tracer = chain
+	}
+	for id, endpoint := range cfg.L2Endpoints {
+		if rcfg.L2 == nil {
+			rcfg.L2 = make(map[uint64]monitor.Chain)
+		}
+		rcfg.L2[id] = w.Client(endpoint, id)
+	}
+	tl, err := replay.Run(ctx, rcfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var manifest archive.Manifest
+	w.SetManifest(func(m *archive.Manifest) {
+		m.ChainID = uint64(chainID)
+		m.FromBlock, m.ToBlock = cfg.FromBlock, cfg.ToBlock
+		if cfg.Game != (eth.Address{}) {
+			m.Games = []eth.Address{cfg.Game}
+		}
+		m.Monitors = make([]archive.Monitor, len(deployments))
+		for i, d := range deployments {
+			m.Monitors[i] = archive.Monitor{Name: d.Name, Params: d.Params}
+		}
+		manifest = *m
+	})
+	if err := w.Close(); err != nil {
+		return nil, nil, err
+	}
+	return &manifest, tl, nil
+}
+
+// gameRange returns the block range of a game: from its creation, unless from is set, until it was resolved or the
+// head of the chain, unless to is set.
+func gameRange(ctx context.Context, chain *rpc.Client, factory, game eth.Address, from, to uint64) (uint64, uint64, error) {
+	created, err := chain.Logs(ctx, rpc.FilterQuery{
+		FromBlock: rpc.EarliestBlockNumber,
+		ToBlock:   rpc.LatestBlockNumber,
+		Addresses: []eth.Address{factory},
+		Topics:    [][]eth.Hash{{abi.DisputeGameCreated.Topic()}, {eth.BytesToHash(game.Bytes())}},
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("looking up creation of %s: %w", game, err)
+	}
+	if len(created) == 0 {
+		return 0, 0, fmt.Errorf("%w: %s", ErrGameNotFound, game)
+	}
+	createdBlock := uint64(created[0].BlockNumber)
+	if from == 0 {
+		from = createdBlock
+	}
+	if to != 0 {
+		return from, to, nil
+	}
+
+	resolved, err := chain.Logs(ctx, rpc.FilterQuery{
+		FromBlock: rpc.NumberAt(createdBlock),
+		ToBlock:   rpc.LatestBlockNumber,
+		Addresses: []eth.Address{game},
+		Topics:    [][]eth.Hash{{abi.Resolved.Topic()}},
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("looking up resolution of %s: %w", game, err)
+	}
+	if len(resolved) > 0 {
+		return from, uint64(resolved[0].BlockNumber), nil
+	}
+	head, err := chain.BlockNumber(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return from, head, nil
+}
+
+// forGame sets the disputeGame param of the deployments of monitors that take one, when it is not set already.
+func forGame(deployments []replay.Deployment, game eth.Address) []replay.Deployment {
+	out := make([]replay.Deployment, len(deployments))
+	for i, d := range deployments {
+		out[i] = d
+		if _, ok := d.Params["disputeGame"]; ok {
+			continue
+		}
+		params := map[string]any{"disputeGame": game.String()}
+		for k, v := range d.Params {
+			params[k] = v
+		}
+		// monitors reject params they do not take
+		if _, err := monitor.New(d.Name, params); err == nil {
+			out[i].Params = params
+		}
+	}
+	return out
+}
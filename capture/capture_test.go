@@ -0,0 +1,114 @@
+package capture_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/archive"
+	"github.com/base-org/fault-proof-monitors/capture"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/follower/chaintest"
+	"github.com/base-org/fault-proof-monitors/game"
+	"github.com/base-org/fault-proof-monitors/replay"
+)
+
+var (
+	factory     = eth.MustAddress("0x43edB88C4B80fDD2AdFF2412A7BebF9dF42cB40e")
+	disputeGame = eth.MustAddress("0x00000000000000000000000000000000000000BB")
+)
+
+// newChain serves a game created at block 1 with a 20 second clock, so that it is resolvable from block 10, which
+// is resolved at block 20.
+func newChain(t *testing.T) *chaintest.Chain {
+	g := game.NewGame(game.Config{MaxClockDuration: 20}, 1000)
+	chain := chaintest.NewChain(1000)
+	chain.Extend(30)
+	chain.HandleCall(chaintest.GameCalls(disputeGame, g, 20))
+
+	created, err := abi.DisputeGameCreated.Log(factory, disputeGame, big.NewInt(0), eth.Hash{1}.Bytes())
+	if err != nil {
+		t.Fatalf("Error encoding creation log: %v", err)
+	}
+	chain.AddLog(1, created)
+	resolved, err := abi.Resolved.Log(disputeGame, big.NewInt(2))
+	if err != nil {
+		t.Fatalf("Error encoding resolution log: %v", err)
+	}
+	chain.AddLog(20, resolved)
+	return chain
+}
+
+func TestCaptureGame(t *testing.T) {
+	server := chaintest.Serve(newChain(t), 8453)
+	dir := t.TempDir()
+	manifest, captured, err := capture.Run(context.Background(), capture.Config{
+		Endpoint: server.URL,
+		Dir:      dir,
+		Game:     disputeGame,
+		Factory:  factory,
+		Monitors: []replay.Deployment{{
+			Name:   "unresolvable_dispute_game",
+			Params: map[string]any{"extraTimeInSeconds": 0},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Error capturing game: %v", err)
+	}
+	if manifest.ChainID != 8453 || manifest.FromBlock != 1 || manifest.ToBlock != 20 {
+		t.Errorf("expected blocks 1-20 of chain 8453, got %+v", manifest)
+	}
+	if len(manifest.Monitors) != 1 || manifest.Monitors[0].Params["disputeGame"] != disputeGame.String() {
+		t.Errorf("expected the monitor to be deployed for the game, got %+v", manifest.Monitors)
+	}
+	if len(captured.Failures) > 0 {
+		t.Fatalf("unexpected failures while capturing: %+v", captured.Failures)
+	}
+	if server.Calls("debug_traceBlockByNumber") != 20 {
+		t.Errorf("expected every block to be traced, got %d traces", server.Calls("debug_traceBlockByNumber"))
+	}
+	// the claim is read at every block, but only stored once
+	objects, _ := os.ReadDir(filepath.Join(dir, "objects"))
+	if len(objects) >= len(manifest.Responses) {
+		t.Errorf("expected responses to be deduplicated, got %d objects for %d responses", len(objects), len(manifest.Responses))
+	}
+
+	// the archive replays offline with the same result
+	server.Close()
+	a, err := archive.Open(dir)
+	if err != nil {
+		t.Fatalf("Error opening archive: %v", err)
+	}
+	for _, validator := range []replay.Validator{nil, replay.MocksValidator{}} {
+		replayed, err := replay.Run(context.Background(), replay.Config{
+			Chain:     a.Client(0),
+			Tracer:    a.Client(0),
+			FromBlock: manifest.FromBlock,
+			ToBlock:   manifest.ToBlock,
+			Monitors:  []replay.Deployment{{Name: manifest.Monitors[0].Name, Params: manifest.Monitors[0].Params}},
+			Validator: validator,
+		})
+		if err != nil {
+			t.Fatalf("Error replaying archive: %v", err)
+		}
+		if len(replayed.Failures) > 0 {
+			t.Fatalf("archive is missing responses: %+v", replayed.Failures)
+		}
+		if replayed.Alerts() != 1 || replayed.Games[0].Alerts[0].FirstBlock != 11 || replayed.Games[0].Alerts[0].LastBlock != 19 {
+			t.Errorf("expected an alert over blocks 11-19, got %+v", replayed.Games)
+		}
+	}
+}
+
+func TestCaptureUnknownGame(t *testing.T) {
+	server := chaintest.Serve(chaintest.NewChain(1000), 8453)
+	defer server.Close()
+	_, _, err := capture.Run(context.Background(), capture.Config{Endpoint: server.URL, Dir: t.TempDir(), Game: disputeGame, Factory: factory})
+	if !errors.Is(err, capture.ErrGameNotFound) {
+		t.Errorf("expected ErrGameNotFound, got %v", err)
+	}
+}
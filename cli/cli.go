@@ -0,0 +1,146 @@
+// Package cli implements the subcommands of fpm, the command line tool covering the monitor workflow, from writing
+// and testing a gate file to deploying it, replaying it over past blocks and following the chain with the native
+// monitors. The deploy and replay commands run the same subcommands on their own.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// ErrUsage is returned for invalid command lines, which exit with status 2.
+var ErrUsage = errors.New("invalid usage")
+
+// Command is a subcommand of fpm. Run parses args, which follow the name of the command, and returns the exit status
+// of the command when it did not fail: 0, or a status the command documents, such as 2 for the changes planned by
+// deploy plan -exit-code.
+type Command struct {
+	Name    string
+	Summary string
+	Run     func(ctx context.Context, args []string) (int, error)
+}
+
+// Commands returns the subcommands of fpm.
+func Commands() []Command {
+	return []Command{
+		{"validate", "run a gate file once through the validate API", Validate},
+		{"test", "run the fixtures against the gate files or the native monitors", Test},
+		{"lint", "check the gate files and their fixtures", Lint},
+		{"fmt", "format the gate files and the fixtures", Fmt},
+		{"graph", "draw the params, sources and invariants of gate files", Graph},
+		{"deploy", "plan or apply the deployment manifest", Deploy},
+		{"plan", "same as deploy plan", func(ctx context.Context, args []string) (int, error) {
+			return Deploy(ctx, append([]string{"plan"}, args...))
+		}},
+		{"apply", "same as deploy apply", func(ctx context.Context, args []string) (int, error) {
+			return Deploy(ctx, append([]string{"apply"}, args...))
+		}},
+		{"games", "list the games of the DisputeGameFactory", Games},
+		{"index", "index the games of the DisputeGameFactory for enrich", Index},
+		{"networks", "list the network profiles resolving the params", Networks},
+		{"replay", "evaluate the monitors over a recorded block range", Replay},
+		{"follow", "evaluate the native monitors on every new block", Follow},
+	}
+}
+
+// Main runs the subcommand named by args[0], and returns the exit status of the process. Errors are printed to
+// stderr prefixed with name.
+func Main(name string, args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(os.Stderr, name)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+	for _, c := range Commands() {
+		if c.Name == args[0] {
+			return Exit(name, c.Run, args[1:])
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%s: unknown command %q\n", name, args[0])
+	usage(os.Stderr, name)
+	return 2
+}
+
+// Exit runs a command until it completes or the process is interrupted, and returns its exit status, 1 if it failed.
+func Exit(name string, run func(ctx context.Context, args []string) (int, error), args []string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	code, err := run(ctx, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	} else if errors.Is(err, ErrUsage) {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 2
+	} else if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	return code
+}
+
+func usage(w io.Writer, name string) {
+	fmt.Fprintf(w, "usage: %s <command> [flags]\n\ncommands:\n", name)
+	for _, c := range Commands() {
+		fmt.Fprintf(w, "  %-9s %s\n", c.Name, c.Summary)
+	}
+	fmt.Fprintf(w, "\nrun %s <command> -h for the flags of a command\n", name)
+}
+
+// newFlags returns the flag set of a command, whose errors are returned by parse rather than exiting.
+func newFlags(name string) *flag.FlagSet {
+	return flag.NewFlagSet(name, flag.ContinueOnError)
+}
+
+// parse parses the flags in args. Flags are also accepted after the positional arguments, as in
+// fpm validate eth_deficit -params params.json, and the positional arguments are returned.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", ErrUsage, err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// usageError returns an ErrUsage for the command of fs, after printing its flags.
+func usageError(fs *flag.FlagSet, format string, args ...any) error {
+	fs.Usage()
+	return fmt.Errorf("%w: %s: %s", ErrUsage, fs.Name(), fmt.Sprintf(format, args...))
+}
+
+// readJSONArg decodes a JSON argument given inline, or as the path of a file holding it.
+func readJSONArg(arg string, v any) error {
+	data := []byte(arg)
+	if s := strings.TrimSpace(arg); !strings.HasPrefix(s, "{") && !strings.HasPrefix(s, "[") {
+		var err error
+		if data, err = os.ReadFile(arg); err != nil {
+			return err
+		}
+	}
+	// keep the precision of large integers, such as uint256 params
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("decoding %s: %w", arg, err)
+	}
+	return nil
+}
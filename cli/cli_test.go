@@ -0,0 +1,181 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/networks"
+)
+
+const testGate = `use Call from hexagate;
+
+param disputeGame: address;
+param unused: integer;
+
+// a comment; with a semicolon
+source resolvedAt: integer = Call {
+    contract: disputeGame,
+    signature: "function resolvedAt() returns (uint256)"
+};
+source isResolved: boolean = resolvedAt > 0;
+source orphan: tuple<integer,integer> = tuple(1, 2);
+
+invariant {
+    description: "Game resolved",
+    condition: !isResolved
+};
+`
+
+// writeModule writes the files of a module, by path relative to its root. The tests use the monitor eth_deficit, so
+// that the gate file has a native monitor.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestParseGate(t *testing.T) {
+	g, err := parseGate("test", testGate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []decl{
+		{Kind: paramDecl, Name: "disputeGame", Line: 3},
+		{Kind: paramDecl, Name: "unused", Line: 4},
+		{Kind: sourceDecl, Name: "resolvedAt", Line: 7, Refs: []string{"disputeGame"}},
+		{Kind: sourceDecl, Name: "isResolved", Line: 11, Refs: []string{"resolvedAt"}},
+		{Kind: sourceDecl, Name: "orphan", Line: 12},
+		{Kind: invariantDecl, Name: "Game resolved", Line: 14, Refs: []string{"isResolved"}},
+	}
+	if !reflect.DeepEqual(g.Decls, want) {
+		t.Errorf("expected %+v, got %+v", want, g.Decls)
+	}
+
+	for _, src := range []string{"param x: integer", `source x: string = "unterminated;` + "\n", "emit x;"} {
+		if _, err := parseGate("test", src); err == nil {
+			t.Errorf("expected %q to be rejected", src)
+		}
+	}
+}
+
+func TestLintAndFmt(t *testing.T) {
+	root := writeModule(t, map[string]string{
+		"monitors/eth_deficit.gate": strings.ReplaceAll(testGate, "    contract", "\tcontract") + "\n\n",
+		"fixtures/eth_deficit/resolved.json": `{
+  "description": "Resolved",
+  "expectAlert": true,
+  "params": {"disputeGame": "0x00", "typo": 1},
+  "mocks": {"resolvedAt": 1, "missing": "${2 * unknown}"},
+  "alerts": ["Game resolved", "Other"]
+}
+`,
+	})
+	findings, err := lint(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, f := range findings {
+		rel, _ := filepath.Rel(root, f.Path)
+		f.Path = rel
+		got = append(got, f.String())
+	}
+	want := []string{
+		"fixtures/eth_deficit/resolved.json: param typo is not declared by eth_deficit",
+		"fixtures/eth_deficit/resolved.json: missing param unused",
+		"fixtures/eth_deficit/resolved.json: mock missing is not a source of eth_deficit",
+		`fixtures/eth_deficit/resolved.json: alert "Other" is not the description of an invariant of eth_deficit`,
+		"fixtures/eth_deficit/resolved.json: mocks: missing: ${2 * unknown}: unknown is not set or not an integer",
+		"fixtures/eth_deficit/resolved.json: not formatted, run fpm fmt",
+		"monitors/eth_deficit.gate: no fixture expecting no alert",
+		"monitors/eth_deficit.gate: not formatted, run fpm fmt",
+		"monitors/eth_deficit.gate:4: warning: param unused is never read",
+		"monitors/eth_deficit.gate:12: warning: source orphan is never read",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected findings\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	if code, err := Fmt(context.Background(), []string{"-dir", root, "-check"}); err != nil || code != 2 {
+		t.Fatalf("expected fmt -check to report the files, got %d (%v)", code, err)
+	}
+	if code, err := Fmt(context.Background(), []string{"-dir", root}); err != nil || code != 0 {
+		t.Fatalf("Error formatting: %d (%v)", code, err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "monitors", "eth_deficit.gate"))
+	if err != nil || string(data) != testGate {
+		t.Errorf("expected the gate file to be formatted, got %q (%v)", data, err)
+	}
+	if changes, err := unformatted(root, nil); err != nil || len(changes) != 0 {
+		t.Errorf("expected every file to be formatted, got %+v (%v)", changes, err)
+	}
+}
+
+func TestCheckedInModule(t *testing.T) {
+	findings, err := lint("..", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range findings {
+		if f.Warning {
+			t.Log(f)
+		} else {
+			t.Error(f)
+		}
+	}
+}
+
+// validateServer answers the validate requests with the invariants failed by the mocks: the alerts listed in the
+// "failing" mock.
+func validateServer(t *testing.T) *hexagate.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req hexagate.ValidateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ChainID != 1 || !strings.Contains(req.Gate, "invariant") {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		failed, _ := req.Mocks["failing"].([]any)
+		_ = json.NewEncoder(w).Encode(hexagate.ValidateResponse{Count: 1, Failed: append([]any{}, failed...)})
+	}))
+	t.Cleanup(server.Close)
+	client := hexagate.NewClient("key")
+	client.SetBaseURL(server.URL)
+	return client
+}
+
+func TestEvaluateGate(t *testing.T) {
+	root := writeModule(t, map[string]string{"monitors/eth_deficit.gate": testGate})
+	client := validateServer(t)
+	f := fixtureFile{}
+	f.Monitor, f.ExpectAlert, f.Alerts = "eth_deficit", true, []string{"Game resolved"}
+	f.Mocks = map[string]any{"failing": []any{map[string]any{"description": "Game resolved"}}}
+	profile, err := networks.Lookup("base-mainnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alerts, err := evaluate(context.Background(), client, profile, root, f.Fixture)
+	if err != nil || checkAlerts(f, alerts) != nil {
+		t.Errorf("expected the fixture to pass, got %q (%v)", alerts, err)
+	}
+	f.ExpectAlert = false
+	if err := checkAlerts(f, alerts); err == nil {
+		t.Error("expected an unexpected alert to fail the fixture")
+	}
+}
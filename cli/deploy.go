@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/manifest"
+)
+
+// Deploy reconciles the monitors deployed on Hexagate with the deployment manifest:
+//
+//	fpm deploy plan  -manifest deployments.yaml
+//	fpm deploy apply -manifest deployments.yaml
+//
+// plan prints the monitors to create, update and delete, and flags the monitors whose gate source differs from the
+// gate file. apply makes the same changes. Monitors deployed per dispute game are created by autodeploy, the manifest
+// only updates and deletes them.
+func Deploy(ctx context.Context, args []string) (int, error) {
+	if len(args) < 1 || (args[0] != "plan" && args[0] != "apply") {
+		return 0, fmt.Errorf("%w: expected deploy plan or deploy apply", ErrUsage)
+	}
+	command := args[0]
+	fs := newFlags(command)
+	var (
+		file     = fs.String("manifest", "deployments.yaml", "deployment manifest")
+		exitCode = fs.Bool("exit-code", false, "plan: exit with status 2 when there are changes")
+	)
+	load := config.Flags(fs)
+	if positional, err := parse(fs, args[1:]); err != nil {
+		return 0, err
+	} else if len(positional) > 0 {
+		return 0, usageError(fs, "unexpected argument %q", positional[0])
+	}
+	cfg, err := load()
+	if err != nil {
+		return 0, err
+	}
+	client, err := cfg.Client()
+	if err != nil {
+		return 0, err
+	}
+	m, err := manifest.Load(*file)
+	if err != nil {
+		return 0, err
+	}
+
+	plan, err := m.Plan(ctx, client)
+	if err != nil {
+		return 0, err
+	}
+	if err := plan.WriteText(os.Stdout); err != nil {
+		return 0, err
+	}
+	if command == "plan" {
+		if *exitCode && !plan.Empty() {
+			return 2, nil
+		}
+		return 0, nil
+	}
+
+	if plan.Empty() {
+		return 0, nil
+	}
+	fmt.Println()
+	err = plan.Apply(ctx, client, func(c manifest.Change) {
+		fmt.Printf("%sd %s on %s\n", c.Action, c.Monitor.Name, c.Network)
+	})
+	if err != nil {
+		return 0, err
+	}
+	fmt.Printf("Applied %d changes.\n", len(plan.Changes))
+	return 0, nil
+}
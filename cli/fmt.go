@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/fixtures"
+)
+
+// Fmt formats the gate files and the fixtures of the module in place:
+//
+//	fpm fmt [-l] [-check] [monitor...]
+//
+// Gate files get Unix line endings, no trailing whitespace, leading tabs replaced by four spaces and a single final
+// newline. Fixtures are written the way fixtures.Encode writes them. With -check, nothing is written and the status
+// is 2 when a file is not formatted.
+func Fmt(ctx context.Context, args []string) (int, error) {
+	fs := newFlags("fmt")
+	var (
+		list  = fs.Bool("l", false, "list the files that are not formatted")
+		check = fs.Bool("check", false, "only report the files that are not formatted, exiting with status 2")
+		root  = fs.String("dir", config.Root(), "root of the module holding the monitors and fixtures directories")
+	)
+	names, err := parse(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	changes, err := unformatted(*root, names)
+	if err != nil {
+		return 0, err
+	}
+	if *list || *check {
+		if err := writeChanges(os.Stdout, changes); err != nil {
+			return 0, err
+		}
+	}
+	for _, c := range changes {
+		if !*check {
+			if err := os.WriteFile(c.path, c.formatted, 0o644); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if *check && len(changes) > 0 {
+		return 2, nil
+	}
+	return 0, nil
+}
+
+// formatChange is a file whose content differs from its formatted content.
+type formatChange struct {
+	path      string
+	formatted []byte
+}
+
+func writeChanges(w io.Writer, changes []formatChange) error {
+	for _, c := range changes {
+		if _, err := fmt.Fprintln(w, c.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unformatted returns the gate files and fixtures of the monitors in names, or of every monitor, that are not
+// formatted.
+func unformatted(root string, names []string) ([]formatChange, error) {
+	if len(names) == 0 {
+		paths, err := filepath.Glob(filepath.Join(root, "monitors", "*.gate"))
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range paths {
+			names = append(names, strings.TrimSuffix(filepath.Base(p), ".gate"))
+		}
+	}
+	var changes []formatChange
+	for _, name := range names {
+		path := gatePath(root, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if formatted := formatGate(data); !bytes.Equal(data, formatted) {
+			changes = append(changes, formatChange{path, formatted})
+		}
+		all, err := loadFixtures(root, strings.TrimSuffix(filepath.Base(path), ".gate"))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range all {
+			formatted, err := fixtures.Encode(f.Fixture)
+			if err != nil {
+				return nil, fmt.Errorf("formatting %s: %w", f.Path, err)
+			}
+			if !bytes.Equal(f.Data, formatted) {
+				changes = append(changes, formatChange{f.Path, formatted})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// formatGate formats the source of a gate file.
+func formatGate(src []byte) []byte {
+	lines := strings.Split(strings.ReplaceAll(string(src), "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, " \t")
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		lines[i] = strings.ReplaceAll(line[:indent], "\t", "    ") + line[indent:]
+	}
+	return []byte(strings.Trim(strings.Join(lines, "\n"), "\n") + "\n")
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/follower"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/monitors"
+	"github.com/base-org/fault-proof-monitors/replay"
+	"github.com/base-org/fault-proof-monitors/rpc"
+	"github.com/base-org/fault-proof-monitors/trace"
+)
+
+// endpoints collects repeated -l2 flags.
+type endpoints map[uint64]string
+
+func (e endpoints) String() string { return fmt.Sprint(map[uint64]string(e)) }
+
+func (e endpoints) Set(v string) error {
+	id, url, ok := strings.Cut(v, "=")
+	chainID, err := strconv.ParseUint(id, 10, 64)
+	if !ok || err != nil {
+		return fmt.Errorf("expected chainId=url, got %q", v)
+	}
+	e[chainID] = url
+	return nil
+}
+
+// Follow evaluates the native monitors on every new block of the chain until it is interrupted, as a backup of the
+// Hexagate deployments of the gate files, and prints the alerts they raise along with those retracted by reorgs:
+//
+//	fpm follow -monitors monitors.json -history-from 17000000
+//
+// The monitors are listed as [{name, params}], as for replay, and the params the profile of the network resolves can
+// be left out. Past events are looked up with `eth_getLogs` from -history-from, and with -traces every block is traced
+// to fill in the calls the monitors read. Past calls cannot be looked up, so the monitors reading them report an
+// error on every block instead of their alerts.
+func Follow(ctx context.Context, args []string) (int, error) {
+	fs := newFlags("follow")
+	var (
+		file        = fs.String("monitors", "", "JSON file listing the monitors to evaluate as [{name, params}]")
+		from        = fs.Uint64("from", 0, "first block to evaluate (default: the head)")
+		historyFrom = fs.Uint64("history-from", 0, "first block searched for past events, such as the deployment block of the factory")
+		poll        = fs.Duration("poll", follower.DefaultPollInterval, "interval the head is polled at")
+		window      = fs.Int("window", follower.DefaultWindow, "number of recent blocks kept to undo reorgs")
+		concurrency = fs.Int("concurrency", follower.DefaultConcurrency, "number of monitors evaluated at once")
+		traces      = fs.Bool("traces", false, "trace each block to fill in the calls the monitors read")
+		asJSON      = fs.Bool("json", false, "print the alerts as JSON lines")
+		l2          = endpoints{}
+		overrides   overrides
+	)
+	fs.Var(l2, "l2", "JSON-RPC endpoint of an L2 chain as chainId=url, may be repeated")
+	fs.Var(&overrides, "set", "override a param as monitor.param=value, may be repeated")
+	load := config.Flags(fs)
+	if positional, err := parse(fs, args); err != nil {
+		return 0, err
+	} else if len(positional) > 0 {
+		return 0, usageError(fs, "unexpected argument %q", positional[0])
+	}
+	if *file == "" {
+		return 0, usageError(fs, "-monitors is required")
+	}
+	cfg, err := load()
+	if err != nil {
+		return 0, err
+	}
+	if cfg.RPC == "" {
+		return 0, usageError(fs, "no JSON-RPC endpoint, set -rpc, FPM_RPC or rpc in %s", config.FileName)
+	}
+	profile, err := cfg.Profile()
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return 0, err
+	}
+	var deployments []replay.Deployment
+	if err := json.Unmarshal(data, &deployments); err != nil {
+		return 0, fmt.Errorf("decoding %s: %w", *file, err)
+	}
+	if len(deployments) == 0 {
+		return 0, fmt.Errorf("no monitors in %s", *file)
+	}
+	for _, o := range overrides {
+		if err := replay.ApplyOverride(deployments, o); err != nil {
+			return 0, err
+		}
+	}
+	ms := make([]monitor.Monitor, len(deployments))
+	for i, d := range deployments {
+		gate, err := monitors.Gate(d.Name)
+		if err != nil {
+			return 0, err
+		}
+		params, err := profile.Resolve(monitors.DeclaredParams(gate), d.Params)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", d.Name, err)
+		}
+		if ms[i], err = monitor.New(d.Name, params); err != nil {
+			return 0, err
+		}
+	}
+
+	chain := rpc.NewClient(cfg.RPC)
+	fc := follower.Config{
+		Monitors:     ms,
+		Sink:         &alertPrinter{w: os.Stdout, json: *asJSON},
+		StartBlock:   *from,
+		PollInterval: *poll,
+		Window:       *window,
+		Concurrency:  *concurrency,
+		History:      monitor.LogHistory{Chain: chain, FromBlock: *historyFrom},
+		OnError: func(err error) {
+			fmt.Fprintln(os.Stderr, "follow:", err)
+		},
+	}
+	for chainID, url := range l2 {
+		if fc.L2 == nil {
+			fc.L2 = make(map[uint64]monitor.Chain)
+		}
+		fc.L2[chainID] = rpc.NewClient(url)
+	}
+	if *traces {
+		fc.Prepare = func(ctx context.Context, bc *monitor.BlockContext) error {
+			block, err := trace.Fetch(ctx, chain, bc.Number)
+			if err != nil {
+				return err
+			}
+			bc.Calls = block.MonitorCalls()
+			return nil
+		}
+	}
+
+	err = follower.New(chain, fc).Run(ctx)
+	if ctx.Err() != nil {
+		return 0, nil
+	}
+	return 0, err
+}
+
+// alertPrinter is a follower.Sink printing the alerts raised and retracted.
+type alertPrinter struct {
+	mu   sync.Mutex
+	w    io.Writer
+	json bool
+}
+
+// printedAlert is a line of the output of follow -json.
+type printedAlert struct {
+	monitor.Violation
+	Retracted bool `json:"retracted,omitempty"`
+}
+
+func (p *alertPrinter) Report(_ context.Context, _ follower.Block, violations []monitor.Violation) error {
+	return p.print(violations, false)
+}
+
+func (p *alertPrinter) Retract(_ context.Context, _ follower.Block, violations []monitor.Violation) error {
+	return p.print(violations, true)
+}
+
+func (p *alertPrinter) print(violations []monitor.Violation, retracted bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, v := range violations {
+		if p.json {
+			if err := json.NewEncoder(p.w).Encode(printedAlert{Violation: v, Retracted: retracted}); err != nil {
+				return err
+			}
+			continue
+		}
+		prefix := "alert"
+		if retracted {
+			prefix = "retracted"
+		}
+		if _, err := fmt.Fprintf(p.w, "%s %s %s %s\n", prefix, v, v.Contract, v.BlockHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
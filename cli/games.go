@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+var (
+	gameCountMethod   = abi.MustParseMethod("gameCount() returns (uint256 gameCount_)")
+	gameAtIndexMethod = abi.MustParseMethod("gameAtIndex(uint256 _index) returns (uint32 gameType_, uint64 timestamp_, address proxy_)")
+)
+
+// listedGame is a game in the output of games list.
+type listedGame struct {
+	Index     uint64      `json:"index"`
+	Game      eth.Address `json:"game"`
+	GameType  uint32      `json:"gameType"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Phase     string      `json:"phase"`
+}
+
+// Games lists the games of the DisputeGameFactory, newest first, with the phase of their lifecycle:
+//
+//	fpm games list -limit 20
+//
+// The factory is the DisputeGameFactory of the network selected by -network, unless -factory is set.
+func Games(ctx context.Context, args []string) (int, error) {
+	if len(args) < 1 || args[0] != "list" {
+		return 0, fmt.Errorf("%w: expected games list", ErrUsage)
+	}
+	fs := newFlags("list")
+	var (
+		factory = fs.String("factory", "", "DisputeGameFactory proxy (default: the one of the network)")
+		limit   = fs.Uint64("limit", 20, "number of games to list, 0 for every game")
+		since   = fs.Duration("since", 0, "only list the games created in this period")
+		asJSON  = fs.Bool("json", false, "print the games as JSON")
+	)
+	load := config.Flags(fs)
+	if positional, err := parse(fs, args[1:]); err != nil {
+		return 0, err
+	} else if len(positional) > 0 {
+		return 0, usageError(fs, "unexpected argument %q", positional[0])
+	}
+	cfg, err := load()
+	if err != nil {
+		return 0, err
+	}
+	profile, err := cfg.Profile()
+	if err != nil {
+		return 0, err
+	}
+	address := profile.DisputeGameFactoryProxy
+	if *factory != "" {
+		if address, err = eth.ParseAddress(*factory); err != nil {
+			return 0, usageError(fs, "invalid -factory: %v", err)
+		}
+	}
+	if address == (eth.Address{}) {
+		return 0, usageError(fs, "network %s has no disputeGameFactoryProxy, set -factory", cfg.Network)
+	}
+	if cfg.RPC == "" {
+		return 0, usageError(fs, "no JSON-RPC endpoint, set -rpc, FPM_RPC or rpc in %s", config.FileName)
+	}
+	chain := rpc.NewClient(cfg.RPC)
+
+	header, err := chain.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return 0, err
+	}
+	number := rpc.NumberAt(uint64(header.Number))
+	count, err := callFactory(ctx, chain, number, address, gameCountMethod)
+	if err != nil {
+		return 0, err
+	}
+	var games []listedGame
+	for i := count[0].(*big.Int).Uint64(); i > 0 && (*limit == 0 || uint64(len(games)) < *limit); i-- {
+		values, err := callFactory(ctx, chain, number, address, gameAtIndexMethod, i-1)
+		if err != nil {
+			return 0, err
+		}
+		g := listedGame{
+			Index:     i - 1,
+			GameType:  uint32(values[0].(*big.Int).Uint64()),
+			CreatedAt: time.Unix(values[1].(*big.Int).Int64(), 0).UTC(),
+			Game:      values[2].(eth.Address),
+		}
+		if *since != 0 && g.CreatedAt.Before(time.Unix(int64(header.Timestamp), 0).Add(-*since)) {
+			break
+		}
+		lc, err := autodeploy.ReadLifecycle(ctx, chain, g.Game)
+		if err != nil {
+			return 0, err
+		}
+		g.Phase = lc.Phase.String()
+		games = append(games, g)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return 0, enc.Encode(games)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "INDEX\tGAME\tTYPE\tCREATED\tPHASE")
+	for _, g := range games {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%s\t%s\n", g.Index, g.Game, g.GameType, g.CreatedAt.Format(time.RFC3339), g.Phase)
+	}
+	return 0, tw.Flush()
+}
+
+func callFactory(ctx context.Context, chain *rpc.Client, number rpc.BlockNumber, factory eth.Address, method abi.Method, args ...any) ([]any, error) {
+	calldata, err := method.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := chain.CallContract(ctx, rpc.CallMsg{To: factory, Data: calldata}, number)
+	if err != nil {
+		return nil, fmt.Errorf("calling %s on %s: %w", method.Name, factory, err)
+	}
+	return method.UnpackOutputs(output)
+}
@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/fixtures"
+)
+
+// declKind is the kind of a top level statement of a gate file.
+type declKind string
+
+const (
+	paramDecl     declKind = "param"
+	sourceDecl    declKind = "source"
+	invariantDecl declKind = "invariant"
+)
+
+// decl is a param, source or invariant of a gate file. Refs are the params and sources its expression reads.
+type decl struct {
+	Kind declKind
+	// Name is the name of params and sources, and the description of invariants.
+	Name string
+	Line int
+	Refs []string
+}
+
+// gate is a gate file split into its declarations.
+type gate struct {
+	Name  string
+	Path  string
+	Decls []decl
+}
+
+func (g *gate) kind(k declKind) []decl {
+	var out []decl
+	for _, d := range g.Decls {
+		if d.Kind == k {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+var (
+	identPattern       = regexp.MustCompile(`[A-Za-z_]\w*`)
+	namedDeclPattern   = regexp.MustCompile(`^(param|source)\s+(\w+)\s*:`)
+	descriptionPattern = regexp.MustCompile(`description\s*:\s*"((?:[^"\\]|\\.)*)"`)
+)
+
+// parseGate splits a gate file into its top level statements, which end with a semicolon outside of strings, comments
+// and brackets, and finds the params and sources each one reads. It only understands the declarations, so it is
+// meant for the lint and graph commands, not for checking gate files: the validate API does that.
+func parseGate(name, src string) (*gate, error) {
+	g := &gate{Name: name}
+	var stmt, code strings.Builder
+	line, start, depth := 1, 0, 0
+	inString, inComment := false, false
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case inComment:
+			if c == '\n' {
+				inComment = false
+			}
+		case inString:
+			stmt.WriteByte(c)
+			if c == '\\' && i+1 < len(src) {
+				i++
+				stmt.WriteByte(src[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			inComment = true
+		case c == '"':
+			inString = true
+			stmt.WriteByte(c)
+		case c == ';' && depth == 0:
+			if err := g.add(stmt.String(), code.String(), start); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", name, start, err)
+			}
+			stmt.Reset()
+			code.Reset()
+			start = 0
+		default:
+			switch c {
+			case '{', '(', '[':
+				depth++
+			case '}', ')', ']':
+				depth--
+			}
+			if start == 0 && c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				start = line
+			}
+			stmt.WriteByte(c)
+			code.WriteByte(c)
+		}
+		if c == '\n' {
+			line++
+			if inString {
+				return nil, fmt.Errorf("%s:%d: unterminated string", name, line-1)
+			}
+		}
+	}
+	if strings.TrimSpace(stmt.String()) != "" {
+		return nil, fmt.Errorf("%s:%d: statement not terminated by a semicolon", name, start)
+	}
+	g.resolve()
+	return g, nil
+}
+
+// add records a statement, whose code is the statement without its strings.
+func (g *gate) add(stmt, code string, line int) error {
+	stmt = strings.TrimSpace(stmt)
+	switch {
+	case strings.HasPrefix(stmt, "use "):
+	case strings.HasPrefix(stmt, "param ") || strings.HasPrefix(stmt, "source "):
+		m := namedDeclPattern.FindStringSubmatch(stmt)
+		if m == nil {
+			return fmt.Errorf("invalid declaration %q", firstLine(stmt))
+		}
+		d := decl{Kind: declKind(m[1]), Name: m[2], Line: line}
+		if d.Kind == sourceDecl {
+			// the expression follows the first = of the declaration
+			if i := strings.Index(code, "="); i >= 0 {
+				d.Refs = identPattern.FindAllString(code[i+1:], -1)
+			}
+		}
+		g.Decls = append(g.Decls, d)
+	case strings.HasPrefix(stmt, "invariant"):
+		d := decl{Kind: invariantDecl, Line: line, Refs: identPattern.FindAllString(strings.TrimPrefix(code, "invariant"), -1)}
+		if m := descriptionPattern.FindStringSubmatch(stmt); m != nil {
+			d.Name = m[1]
+		}
+		g.Decls = append(g.Decls, d)
+	default:
+		return fmt.Errorf("unknown statement %q", firstLine(stmt))
+	}
+	return nil
+}
+
+// resolve keeps the references to the params and sources of the gate, once each.
+func (g *gate) resolve() {
+	declared := make(map[string]bool)
+	for _, d := range g.Decls {
+		if d.Kind != invariantDecl {
+			declared[d.Name] = true
+		}
+	}
+	for i, d := range g.Decls {
+		seen := make(map[string]bool)
+		var refs []string
+		for _, r := range d.Refs {
+			if declared[r] && !seen[r] && r != d.Name {
+				seen[r] = true
+				refs = append(refs, r)
+			}
+		}
+		g.Decls[i].Refs = refs
+	}
+}
+
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(s, "\n")
+	return s
+}
+
+// loadGates parses the gate files of the module at root, or only those of names.
+func loadGates(root string, names []string) ([]*gate, error) {
+	if len(names) == 0 {
+		paths, err := filepath.Glob(filepath.Join(root, "monitors", "*.gate"))
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range paths {
+			names = append(names, strings.TrimSuffix(filepath.Base(p), ".gate"))
+		}
+	}
+	var gates []*gate
+	for _, name := range names {
+		path := gatePath(root, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		g, err := parseGate(strings.TrimSuffix(filepath.Base(path), ".gate"), string(data))
+		if err != nil {
+			return nil, err
+		}
+		g.Path = path
+		gates = append(gates, g)
+	}
+	return gates, nil
+}
+
+// gatePath returns the path of a gate file given by name, or by path if it has the .gate extension.
+func gatePath(root, name string) string {
+	if strings.HasSuffix(name, ".gate") {
+		return name
+	}
+	return filepath.Join(root, "monitors", name+".gate")
+}
+
+// fixtureFile is a fixture read from the module, rather than the ones embedded in the build.
+type fixtureFile struct {
+	fixtures.Fixture
+	Path string
+	Data []byte
+}
+
+// loadFixtures reads the fixtures of the module at root, or only those of monitor, ordered by monitor and name.
+func loadFixtures(root, monitor string) ([]fixtureFile, error) {
+	pattern := filepath.Join(root, "fixtures", "*", "*.json")
+	if monitor != "" {
+		pattern = filepath.Join(root, "fixtures", monitor, "*.json")
+	}
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	out := make([]fixtureFile, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		f, err := fixtures.Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", path, err)
+		}
+		f.Monitor = filepath.Base(filepath.Dir(path))
+		f.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+		out = append(out, fixtureFile{Fixture: f, Path: path, Data: data})
+	}
+	return out, nil
+}
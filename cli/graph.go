@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/config"
+)
+
+// Graph prints the dependency graph of gate files, from the params to the sources reading them and the invariants
+// reading those, as a Mermaid flowchart to embed in the docs, or in the DOT language of Graphviz:
+//
+//	fpm graph [-format mermaid|dot] [monitor...]
+func Graph(ctx context.Context, args []string) (int, error) {
+	fs := newFlags("graph")
+	var (
+		format = fs.String("format", "mermaid", "output format, mermaid or dot")
+		root   = fs.String("dir", config.Root(), "root of the module holding the monitors directory")
+	)
+	names, err := parse(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	var write func(io.Writer, []*gate) error
+	switch *format {
+	case "mermaid":
+		write = writeMermaid
+	case "dot":
+		write = writeDot
+	default:
+		return 0, usageError(fs, "unknown format %q", *format)
+	}
+	gates, err := loadGates(*root, names)
+	if err != nil {
+		return 0, err
+	}
+	return 0, write(os.Stdout, gates)
+}
+
+// nodeID returns the id of the node of a declaration, unique across gates.
+func nodeID(g *gate, d decl) string {
+	if d.Kind == invariantDecl {
+		return fmt.Sprintf("%s_invariant_%d", g.Name, d.Line)
+	}
+	return g.Name + "_" + d.Name
+}
+
+// label returns the label of the node of a declaration, the description of an invariant.
+func label(d decl) string {
+	if d.Kind == invariantDecl && d.Name == "" {
+		return "invariant"
+	}
+	return d.Name
+}
+
+func writeMermaid(w io.Writer, gates []*gate) error {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	quote := func(s string) string { return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"` }
+	for _, g := range gates {
+		fmt.Fprintf(&b, "    subgraph %s [%s]\n", g.Name, quote(g.Name))
+		for _, d := range g.Decls {
+			id, l := nodeID(g, d), quote(label(d))
+			switch d.Kind {
+			case paramDecl:
+				fmt.Fprintf(&b, "        %s([%s])\n", id, l)
+			case sourceDecl:
+				fmt.Fprintf(&b, "        %s[%s]\n", id, l)
+			case invariantDecl:
+				fmt.Fprintf(&b, "        %s{{%s}}\n", id, l)
+			}
+		}
+		for _, d := range g.Decls {
+			for _, r := range d.Refs {
+				fmt.Fprintf(&b, "        %s_%s --> %s\n", g.Name, r, nodeID(g, d))
+			}
+		}
+		b.WriteString("    end\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeDot(w io.Writer, gates []*gate) error {
+	var b strings.Builder
+	b.WriteString("digraph gates {\n    rankdir=LR;\n")
+	shapes := map[declKind]string{paramDecl: "ellipse", sourceDecl: "box", invariantDecl: "hexagon"}
+	for _, g := range gates {
+		fmt.Fprintf(&b, "    subgraph cluster_%s {\n        label=%q;\n", g.Name, g.Name)
+		for _, d := range g.Decls {
+			fmt.Fprintf(&b, "        %s [label=%q, shape=%s];\n", nodeID(g, d), label(d), shapes[d.Kind])
+		}
+		for _, d := range g.Decls {
+			for _, r := range d.Refs {
+				fmt.Fprintf(&b, "        %s_%s -> %s;\n", g.Name, r, nodeID(g, d))
+			}
+		}
+		b.WriteString("    }\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/indexer"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+// Index builds the index of the games of the DisputeGameFactory in a directory, the store enrich -index reads the
+// claims of the games from, and with -follow keeps it up to date until it is interrupted:
+//
+//	fpm index -dir index -start-block 17000000 -follow
+//
+// The factory is the DisputeGameFactory of the network selected by -network, unless -factory is set. A store that
+// was already built resumes from its checkpoint, so -start-block only matters the first time, and a reorg of the
+// checkpoint fails, since the store then has to be built again. With -traces every block is traced to index the
+// unlocks and withdrawals of DelayedWETH, which needs a node serving the debug namespace.
+func Index(ctx context.Context, args []string) (int, error) {
+	fs := newFlags("index")
+	var (
+		dir           = fs.String("dir", "index", "directory of the store")
+		factory       = fs.String("factory", "", "DisputeGameFactory proxy (default: the one of the network)")
+		startBlock    = fs.Uint64("start-block", 0, "first block indexed, such as the deployment block of the factory")
+		confirmations = fs.Uint64("confirmations", indexer.DefaultConfirmations, "number of blocks the index stays behind the head")
+		batchSize     = fs.Uint64("batch", indexer.DefaultBatchSize, "number of blocks whose logs are fetched at once")
+		traces        = fs.Bool("traces", false, "trace each block to index the unlocks and withdrawals of DelayedWETH")
+		follow        = fs.Bool("follow", false, "keep indexing the new blocks until interrupted")
+		poll          = fs.Duration("poll", indexer.DefaultPollInterval, "interval the head is polled at with -follow")
+	)
+	load := config.Flags(fs)
+	if positional, err := parse(fs, args); err != nil {
+		return 0, err
+	} else if len(positional) > 0 {
+		return 0, usageError(fs, "unexpected argument %q", positional[0])
+	}
+	cfg, err := load()
+	if err != nil {
+		return 0, err
+	}
+	profile, err := cfg.Profile()
+	if err != nil {
+		return 0, err
+	}
+	address := profile.DisputeGameFactoryProxy
+	if *factory != "" {
+		if address, err = eth.ParseAddress(*factory); err != nil {
+			return 0, usageError(fs, "invalid -factory: %v", err)
+		}
+	}
+	if address == (eth.Address{}) {
+		return 0, usageError(fs, "network %s has no disputeGameFactoryProxy, set -factory", cfg.Network)
+	}
+	if cfg.RPC == "" {
+		return 0, usageError(fs, "no JSON-RPC endpoint, set -rpc, FPM_RPC or rpc in %s", config.FileName)
+	}
+
+	store, err := indexer.Open(*dir)
+	if err != nil {
+		return 0, err
+	}
+	chain := rpc.NewClient(cfg.RPC)
+	ic := indexer.Config{
+		Factory:       address,
+		StartBlock:    *startBlock,
+		Confirmations: *confirmations,
+		BatchSize:     *batchSize,
+		PollInterval:  *poll,
+		OnError: func(err error) {
+			fmt.Fprintln(os.Stderr, "index:", err)
+		},
+	}
+	if *traces {
+		ic.Tracer = chain
+	}
+	ix := indexer.New(chain, store, ic)
+
+	if !*follow {
+		cp, err := ix.Sync(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if cp == nil {
+			fmt.Printf("nothing indexed yet in %s\n", *dir)
+			return 0, nil
+		}
+		fmt.Printf("indexed %d games in %s up to block %d\n", store.Len(), *dir, cp.Number)
+		return 0, nil
+	}
+	fmt.Printf("indexing the games of %s in %s\n", address, *dir)
+	if err := ix.Run(ctx); err != nil {
+		return 0, err
+	}
+	if cp := store.Checkpoint(); cp != nil {
+		fmt.Printf("indexed %d games in %s up to block %d\n", store.Len(), *dir, cp.Number)
+	}
+	return 0, nil
+}
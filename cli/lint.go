@@ -0,0 +1,216 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/networks"
+)
+
+// finding is an issue reported by lint, at a line of a file, or at its start when Line is zero. Warnings do not fail
+// lint unless it is strict.
+type finding struct {
+	Path    string
+	Line    int
+	Message string
+	Warning bool
+}
+
+func (f finding) String() string {
+	message := f.Message
+	if f.Warning {
+		message = "warning: " + message
+	}
+	if f.Line == 0 {
+		return fmt.Sprintf("%s: %s", f.Path, message)
+	}
+	return fmt.Sprintf("%s:%d: %s", f.Path, f.Line, message)
+}
+
+// Lint checks the gate files and the fixtures of the module:
+//
+//	fpm lint [-strict] [monitor...]
+//
+// It reports invariants without a description, gate files without a native monitor or without fixtures both
+// alerting and passing, fixtures whose params, mocks or alerts do not match their gate file or whose placeholders
+// cannot be substituted by the default network, and files that are not formatted. The status is then 1.
+//
+// Params and sources that are never read are reported as warnings, which only fail lint with -strict: removing them
+// changes the source of the deployed monitors, so it is done on its own rather than to satisfy lint.
+func Lint(ctx context.Context, args []string) (int, error) {
+	fs := newFlags("lint")
+	var (
+		root   = fs.String("dir", config.Root(), "root of the module holding the monitors and fixtures directories")
+		strict = fs.Bool("strict", false, "fail on warnings too")
+	)
+	names, err := parse(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	findings, err := lint(*root, names)
+	if err != nil {
+		return 0, err
+	}
+	if err := writeFindings(os.Stdout, findings); err != nil {
+		return 0, err
+	}
+	for _, f := range findings {
+		if !f.Warning || *strict {
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func lint(root string, names []string) ([]finding, error) {
+	gates, err := loadGates(root, names)
+	if err != nil {
+		return nil, err
+	}
+	native := make(map[string]bool)
+	for _, name := range monitor.Names() {
+		native[name] = true
+	}
+	// the honest actors are unset in the built-in profiles, so the placeholders of the fixtures cannot name them
+	profile, err := networks.Lookup(networks.Default)
+	if err != nil {
+		return nil, err
+	}
+	vars := profile.Vars()
+	var findings []finding
+	for _, g := range gates {
+		report := func(line int, format string, args ...any) {
+			findings = append(findings, finding{Path: g.Path, Line: line, Message: fmt.Sprintf(format, args...)})
+		}
+		warn := func(line int, format string, args ...any) {
+			findings = append(findings, finding{Path: g.Path, Line: line, Message: fmt.Sprintf(format, args...), Warning: true})
+		}
+		read := make(map[string]bool)
+		for _, d := range g.Decls {
+			for _, r := range d.Refs {
+				read[r] = true
+			}
+		}
+		params := make(map[string]bool)
+		sources := make(map[string]bool)
+		for _, d := range g.kind(paramDecl) {
+			params[d.Name] = true
+			if !read[d.Name] {
+				warn(d.Line, "param %s is never read", d.Name)
+			}
+		}
+		for _, d := range g.kind(sourceDecl) {
+			sources[d.Name] = true
+			if !read[d.Name] {
+				warn(d.Line, "source %s is never read", d.Name)
+			}
+		}
+		invariants := g.kind(invariantDecl)
+		descriptions := make(map[string]bool)
+		if len(invariants) == 0 {
+			report(0, "no invariant")
+		}
+		for _, d := range invariants {
+			descriptions[d.Name] = true
+			if d.Name == "" {
+				report(d.Line, "invariant without a description")
+			}
+		}
+		if !native[g.Name] {
+			report(0, "no native monitor in the monitor package")
+		}
+
+		all, err := loadFixtures(root, g.Name)
+		if err != nil {
+			return nil, err
+		}
+		alerting, passing := false, false
+		for _, f := range all {
+			fixture := func(format string, args ...any) {
+				findings = append(findings, finding{Path: f.Path, Message: fmt.Sprintf(format, args...)})
+			}
+			alerting = alerting || f.ExpectAlert
+			passing = passing || !f.ExpectAlert
+			for _, p := range sortedKeys(f.Params) {
+				if !params[p] {
+					fixture("param %s is not declared by %s", p, g.Name)
+				}
+			}
+			for _, d := range g.kind(paramDecl) {
+				if _, ok := f.Params[d.Name]; !ok {
+					fixture("missing param %s", d.Name)
+				}
+			}
+			for _, s := range sortedKeys(f.Mocks) {
+				if !sources[s] {
+					fixture("mock %s is not a source of %s", s, g.Name)
+				}
+			}
+			for _, a := range f.Alerts {
+				if !descriptions[a] {
+					fixture("alert %q is not the description of an invariant of %s", a, g.Name)
+				}
+			}
+			if _, err := f.Substitute(vars); err != nil {
+				fixture("%v", err)
+			}
+			if f.Description == "" {
+				fixture("no description")
+			}
+		}
+		if !alerting {
+			report(0, "no fixture expecting an alert")
+		}
+		if !passing {
+			report(0, "no fixture expecting no alert")
+		}
+	}
+
+	changes, err := unformatted(root, names)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range changes {
+		findings = append(findings, finding{Path: c.path, Message: "not formatted, run fpm fmt"})
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Path != findings[j].Path {
+			return findings[i].Path < findings[j].Path
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings, nil
+}
+
+func writeFindings(w io.Writer, findings []finding) error {
+	for _, f := range findings {
+		if _, err := fmt.Fprintln(w, f); err != nil {
+			return err
+		}
+	}
+	warnings := 0
+	for _, f := range findings {
+		if f.Warning {
+			warnings++
+		}
+	}
+	if len(findings) > 0 {
+		_, err := fmt.Fprintf(w, "%d issues, %d warnings\n", len(findings)-warnings, warnings)
+		return err
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/networks"
+)
+
+// Networks lists the network profiles, the built-in ones and the ones of the config file, and marks the selected one:
+//
+//	fpm networks [-json]
+func Networks(ctx context.Context, args []string) (int, error) {
+	fs := newFlags("networks")
+	asJSON := fs.Bool("json", false, "print the profiles as JSON, with every address")
+	load := config.Flags(fs)
+	if positional, err := parse(fs, args); err != nil {
+		return 0, err
+	} else if len(positional) > 0 {
+		return 0, usageError(fs, "unexpected argument %q", positional[0])
+	}
+	cfg, err := load()
+	if err != nil {
+		return 0, err
+	}
+
+	names := cfg.NetworkNames()
+	profiles := make(map[string]networks.Profile, len(names))
+	for _, name := range names {
+		if profiles[name], err = cfg.Lookup(name); err != nil {
+			return 0, err
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return 0, enc.Encode(profiles)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tNAME\tL1\tL2\tDISPUTE GAME FACTORY")
+	for _, name := range names {
+		selected := ""
+		if name == cfg.Network {
+			selected = "*"
+		}
+		p := profiles[name]
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", selected, name, p.L1ChainID, p.L2ChainID, p.DisputeGameFactoryProxy)
+	}
+	return 0, tw.Flush()
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/archive"
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/replay"
+)
+
+// overrides collects repeated -set flags.
+type overrides []string
+
+func (o *overrides) String() string     { return strings.Join(*o, ",") }
+func (o *overrides) Set(v string) error { *o = append(*o, v); return nil }
+
+// Replay evaluates monitors over a block range recorded in an archive and prints the alerts they would have raised,
+// per game. Params can be overridden to backtest a threshold change:
+//
+//	fpm replay -archive ./archive -set unresolvable_dispute_game.extraTimeInSeconds=7200
+//
+// By default the native monitors are evaluated offline. With -validate, the sources they load are sent as mocks to
+// the Hexagate validate API instead, which runs the gate files themselves.
+func Replay(ctx context.Context, args []string) (int, error) {
+	fs := newFlags("replay")
+	var (
+		dir       = fs.String("archive", "", "directory of the recorded archive")
+		from      = fs.Uint64("from", 0, "first block to replay (default: first block of the archive)")
+		to        = fs.Uint64("to", 0, "last block to replay (default: last block of the archive)")
+		monitors  = fs.String("monitors", "", "JSON file listing the monitors to evaluate as [{name, params}] (default: the monitors of the archive)")
+		traces    = fs.Bool("traces", true, "trace each block to fill in the calls the monitors read")
+		validate  = fs.Bool("validate", false, "evaluate the gate files through the Hexagate validate API instead of the native monitors")
+		asJSON    = fs.Bool("json", false, "print the timeline as JSON")
+		overrides overrides
+	)
+	fs.Var(&overrides, "set", "override a param as monitor.param=value, may be repeated")
+	load := config.Flags(fs)
+	if positional, err := parse(fs, args); err != nil {
+		return 0, err
+	} else if len(positional) > 0 {
+		return 0, usageError(fs, "unexpected argument %q", positional[0])
+	}
+	if *dir == "" {
+		return 0, usageError(fs, "-archive is required")
+	}
+	cfg, err := load()
+	if err != nil {
+		return 0, err
+	}
+
+	a, err := archive.Open(*dir)
+	if err != nil {
+		return 0, err
+	}
+	manifest := a.Manifest()
+	rc := replay.Config{
+		Chain:     a.Client(0),
+		FromBlock: manifest.FromBlock,
+		ToBlock:   manifest.ToBlock,
+	}
+	if *from != 0 {
+		rc.FromBlock = *from
+	}
+	if *to != 0 {
+		rc.ToBlock = *to
+	}
+	if *traces {
+		rc.Tracer = a.Client(0)
+	}
+	for chainID := range manifest.L2Responses {
+		if rc.L2 == nil {
+			rc.L2 = make(map[uint64]monitor.Chain)
+		}
+		rc.L2[chainID] = a.Client(chainID)
+	}
+
+	for _, m := range manifest.Monitors {
+		rc.Monitors = append(rc.Monitors, replay.Deployment{Name: m.Name, Params: m.Params})
+	}
+	if *monitors != "" {
+		data, err := os.ReadFile(*monitors)
+		if err != nil {
+			return 0, err
+		}
+		rc.Monitors = nil
+		if err := json.Unmarshal(data, &rc.Monitors); err != nil {
+			return 0, fmt.Errorf("decoding %s: %w", *monitors, err)
+		}
+	}
+	if len(rc.Monitors) == 0 {
+		return 0, fmt.Errorf("no monitors to evaluate, pass -monitors")
+	}
+	for _, o := range overrides {
+		if err := replay.ApplyOverride(rc.Monitors, o); err != nil {
+			return 0, err
+		}
+	}
+	if *validate {
+		client, err := cfg.Client()
+		if err != nil {
+			return 0, fmt.Errorf("-validate: %w", err)
+		}
+		rc.Validator = replay.HexagateValidator{Client: client, ChainID: manifest.ChainID}
+	}
+
+	tl, err := replay.Run(ctx, rc)
+	if err != nil {
+		return 0, err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return 0, enc.Encode(tl)
+	}
+	return 0, tl.WriteText(os.Stdout)
+}
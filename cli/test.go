@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/fixtures"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/monitors"
+	"github.com/base-org/fault-proof-monitors/networks"
+)
+
+// Test runs the fixtures of the module, as the gate tests do, and prints a line per fixture:
+//
+//	fpm test [-native] [-run regexp] [-matrix base-mainnet,base-sepolia] [monitor...]
+//
+// By default the gate files are run through the validate API with the params and mocks of each fixture. With
+// -native, the native monitors evaluate the mocks offline instead, without an API key. A fixture passes when the
+// monitor alerts as expected, and raises the alerts it lists, if any. The status is 1 when a fixture fails.
+//
+// Every fixture runs on each network of the matrix, the selected network by default: the placeholders of its params
+// and mocks are replaced by the values of the profile, see fixtures.Fixture.Substitute, and the params it leaves
+// unset are resolved from the profile. The results are reported per network.
+func Test(ctx context.Context, args []string) (int, error) {
+	fs := newFlags("test")
+	var (
+		native  = fs.Bool("native", false, "evaluate the native monitors offline instead of the gate files")
+		run     = fs.String("run", "", "only run the fixtures whose monitor/name matches this regexp")
+		root    = fs.String("dir", config.Root(), "root of the module holding the monitors and fixtures directories")
+		verbose = fs.Bool("v", false, "print the passing fixtures too")
+		matrix  = fs.String("matrix", "", "comma separated networks to run the fixtures on, or all (default: FPM_MATRIX, or the network)")
+	)
+	load := config.Flags(fs)
+	names, err := parse(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	var filter *regexp.Regexp
+	if *run != "" {
+		if filter, err = regexp.Compile(*run); err != nil {
+			return 0, usageError(fs, "invalid -run: %v", err)
+		}
+	}
+	cfg, err := load()
+	if err != nil {
+		return 0, err
+	}
+	if *matrix != "" {
+		cfg.Matrix = config.SplitList(*matrix)
+	}
+	var client *hexagate.Client
+	if !*native {
+		if client, err = cfg.Client(); err != nil {
+			return 0, fmt.Errorf("%w, or pass -native", err)
+		}
+	}
+
+	var all []fixtureFile
+	if len(names) == 0 {
+		names = []string{""}
+	}
+	for _, name := range names {
+		fixtures, err := loadFixtures(*root, name)
+		if err != nil {
+			return 0, err
+		}
+		all = append(all, fixtures...)
+	}
+	type result struct{ passed, failed int }
+	networkNames := cfg.MatrixNetworks()
+	results := make([]result, len(networkNames))
+	for i, network := range networkNames {
+		profile, err := cfg.Lookup(network)
+		if err != nil {
+			return 0, err
+		}
+		for _, f := range all {
+			id := f.Monitor + "/" + f.Name
+			if filter != nil && !filter.MatchString(id) {
+				continue
+			}
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			alerts, err := evaluate(ctx, client, profile, *root, f.Fixture)
+			if err == nil {
+				err = checkAlerts(f, alerts)
+			}
+			if err != nil {
+				results[i].failed++
+				fmt.Printf("FAIL %s %s: %v\n", network, id, err)
+				continue
+			}
+			results[i].passed++
+			if *verbose {
+				fmt.Printf("ok   %s %s\n", network, id)
+			}
+		}
+	}
+	code := 0
+	for i, network := range networkNames {
+		fmt.Printf("%s: %d passed, %d failed\n", network, results[i].passed, results[i].failed)
+		if results[i].failed > 0 {
+			code = 1
+		}
+	}
+	return code, nil
+}
+
+// evaluate returns the alerts raised by the monitor of f on the network of profile, by the native monitor when client
+// is nil.
+func evaluate(ctx context.Context, client *hexagate.Client, profile networks.Profile, root string, f fixtures.Fixture) ([]string, error) {
+	f, err := f.Substitute(profile.Vars())
+	if err != nil {
+		return nil, err
+	}
+	gate, err := os.ReadFile(gatePath(root, f.Monitor))
+	if err != nil {
+		return nil, err
+	}
+	if f.Params, err = profile.Resolve(monitors.DeclaredParams(string(gate)), f.Params); err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return evaluateNative(f)
+	}
+	return evaluateGate(ctx, client, profile.L1ChainID, string(gate), f)
+}
+
+// evaluateNative returns the descriptions of the violations of the native monitor over the mocks of f.
+func evaluateNative(f fixtures.Fixture) ([]string, error) {
+	m, err := monitor.New(f.Monitor, f.Params)
+	if err != nil {
+		return nil, err
+	}
+	violations, err := monitor.EvaluateMocks(m, monitor.BlockContext{Number: 100}, f.Mocks)
+	if err != nil {
+		return nil, err
+	}
+	alerts := make([]string, len(violations))
+	for i, v := range violations {
+		alerts[i] = v.Description
+	}
+	return alerts, nil
+}
+
+// evaluateGate returns the descriptions of the invariants of gate that fail over the mocks of f.
+func evaluateGate(ctx context.Context, client *hexagate.Client, chainID uint64, gate string, f fixtures.Fixture) ([]string, error) {
+	resp, err := client.Validate(ctx, hexagate.ValidateRequest{
+		Gate:    gate,
+		ChainID: chainID,
+		Params:  f.Params,
+		Mocks:   f.Mocks,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Exceptions) > 0 {
+		exceptions := make([]string, len(resp.Exceptions))
+		for i, e := range resp.Exceptions {
+			exceptions[i] = hexagate.FailureDescription(e)
+		}
+		return nil, fmt.Errorf("exceptions: %s", strings.Join(exceptions, "; "))
+	}
+	alerts := make([]string, len(resp.Failed))
+	for i, failed := range resp.Failed {
+		alerts[i] = hexagate.FailureDescription(failed)
+	}
+	return alerts, nil
+}
+
+func checkAlerts(f fixtureFile, alerts []string) error {
+	if alert := len(alerts) > 0; alert != f.ExpectAlert {
+		return fmt.Errorf("%s: expected alert %t, got %q", f.Description, f.ExpectAlert, alerts)
+	}
+	if f.Alerts != nil && !slices.Equal(alerts, f.Alerts) {
+		return fmt.Errorf("%s: expected alerts %q, got %q", f.Description, f.Alerts, alerts)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/monitors"
+)
+
+// Validate runs a gate file once through the validate API, and prints the invariants that failed:
+//
+//	fpm validate eth_deficit -params params.json -mocks '{"bondDistributionMode": 1}'
+//
+// The monitor is the name of a gate file of this build, or the path of a .gate file. Params and mocks are JSON
+// objects, inline or in a file. The params left unset are resolved from the profile of the network selected by
+// -network. With -exit-code, the status is 2 when an invariant failed.
+func Validate(ctx context.Context, args []string) (int, error) {
+	fs := newFlags("validate")
+	var (
+		params   = fs.String("params", "{}", "params of the gate file, as a JSON object or the file holding it")
+		mocks    = fs.String("mocks", "{}", "source values overriding the chain, as a JSON object or the file holding it")
+		trace    = fs.Bool("trace", false, "print the trace of the evaluation")
+		exitCode = fs.Bool("exit-code", false, "exit with status 2 when an invariant failed")
+	)
+	load := config.Flags(fs)
+	positional, err := parse(fs, args)
+	if err != nil {
+		return 0, err
+	}
+	if len(positional) != 1 {
+		return 0, usageError(fs, "expected a single monitor")
+	}
+	cfg, err := load()
+	if err != nil {
+		return 0, err
+	}
+	client, err := cfg.Client()
+	if err != nil {
+		return 0, err
+	}
+	profile, err := cfg.Profile()
+	if err != nil {
+		return 0, err
+	}
+
+	req := hexagate.ValidateRequest{ChainID: profile.L1ChainID, Trace: *trace}
+	if err := readJSONArg(*params, &req.Params); err != nil {
+		return 0, err
+	}
+	if err := readJSONArg(*mocks, &req.Mocks); err != nil {
+		return 0, err
+	}
+	name := positional[0]
+	if strings.HasSuffix(name, ".gate") {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return 0, err
+		}
+		req.Gate = string(data)
+		if req.Params, err = profile.Resolve(monitors.DeclaredParams(req.Gate), req.Params); err != nil {
+			return 0, err
+		}
+	} else {
+		if req.Gate, err = monitors.Gate(name); err != nil {
+			return 0, err
+		}
+		if req.Params, err = profile.Resolve(monitors.DeclaredParams(req.Gate), req.Params); err != nil {
+			return 0, fmt.Errorf("%s: %w", name, err)
+		}
+		if err := monitors.CheckParams(name, req.Params); err != nil {
+			return 0, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	resp, err := client.Validate(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	fmt.Printf("%d invariants, %d failed\n", resp.Count, len(resp.Failed))
+	for _, f := range resp.Failed {
+		fmt.Printf("  failed: %s\n", hexagate.FailureDescription(f))
+	}
+	for _, e := range resp.Exceptions {
+		fmt.Printf("  exception: %s\n", hexagate.FailureDescription(e))
+	}
+	if *trace {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(resp.Trace); err != nil {
+			return 0, err
+		}
+	}
+	if len(resp.Exceptions) > 0 {
+		return 0, fmt.Errorf("the gate file raised %d exceptions", len(resp.Exceptions))
+	}
+	if *exitCode && len(resp.Failed) > 0 {
+		return 2, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,344 @@
+// Command autodeploy receives alert webhooks and deploys monitors in response, through the Hexagate management API:
+//
+//	autodeploy -config autodeploy.json -listen :8080 -state deployments.json -rpc $L1_RPC
+//
+// Alerts of a DisputeGameFactory Contract Event monitor, posted to /, deploy the per dispute game monitors to each
+// game created, and with -rpc they are deleted once the game is settled. Alerts of fault_proof_detection_parent,
+// posted to /fault_proof_detection, deploy fault_proof_detection_child to the reported game, which is deleted again
+// once the game is resolved. The config file holds the chain, a template per monitor with every param except disputeGame, and the child settings:
+//
+//	{
+//	  "chainId": 1,
+//	  "monitors": [{"monitor": "unresolvable_dispute_game", "params": {"extraTimeInSeconds": 3600}}],
+//	  "child": {"cbChallenger": "0x..."}
+//	}
+//
+// With -factory, the monitors missing for the games of the DisputeGameFactory that are not settled yet are deployed
+// at startup, so games created while no webhook was received are monitored too. -dry-run lists them and exits.
+//
+// With -queue, the per dispute game monitors are queued in the given file instead, and created in the background with
+// retries. The jobs that failed every attempt are listed and re-driven by the jobs command, through -admin-listen.
+// The monitors created per game, and the time each game was first seen settled, are kept next to the queue, or next to
+// -state without -queue, in a file ending in .games.json, so that a restart does not deploy them again.
+//
+// Webhooks must be signed with -webhook-secret: the X-Signature-256 header holds sha256= and the hex HMAC-SHA256 of the
+// unix time in X-Webhook-Timestamp, a dot and the body. Deliveries older than -webhook-tolerance, and those already
+// received, are rejected. With -webhook-token, unsigned deliveries carrying that token in the token query param of the
+// webhook URL are accepted too, without protection against replays. With -confirm-games, the games of a webhook are
+// checked to have been created by -factory before anything is deployed.
+//
+// With -manifest, the templates and the child settings of -network are read from the deployment manifest instead.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/manifest"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+// config is the config file.
+type config struct {
+	autodeploy.Config
+	Child *autodeploy.ChildConfig `json:"child"`
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "autodeploy:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		file         = flag.String("config", "", "JSON file holding the chain and the monitor templates")
+		manifestFile = flag.String("manifest", "", "deployment manifest to read the templates from instead of -config")
+		network      = flag.String("network", "", "network of the manifest to deploy on (default: its only network)")
+		listen       = flag.String("listen", ":8080", "address to receive webhooks on")
+		apiKey       = flag.String("api-key", os.Getenv("HEXAGATE_API_KEY"), "Hexagate API key")
+		apiURL       = flag.String("api-url", hexagate.DefaultBaseURL, "root of the Hexagate API")
+		state        = flag.String("state", "deployments.json", "file recording the child monitors deployed")
+		endpoint     = flag.String("rpc", "", "JSON-RPC endpoint used to delete the monitors of resolved and settled games")
+		pollInterval = flag.Duration("poll", time.Minute, "interval between checks for resolved and settled games")
+		factory      = flag.String("factory", "", "DisputeGameFactory proxy to backfill the monitors of unsettled games from at startup, requires -rpc")
+		since        = flag.Duration("backfill-since", 0, "only backfill the games created in this period (default: every game)")
+		dryRun       = flag.Bool("dry-run", false, "list the monitors -factory would backfill and exit")
+		queue        = flag.String("queue", "", "file queuing the monitors to create, to create them in the background with retries instead of during the webhook")
+		adminListen  = flag.String("admin-listen", "127.0.0.1:8081", "private address serving the administration of -queue to the jobs command")
+		adminToken   = flag.String("admin-token", os.Getenv("ADMIN_TOKEN"), "bearer token the jobs command authenticates to -admin-listen with, required with -queue")
+		secret       = flag.String("webhook-secret", os.Getenv("WEBHOOK_SECRET"), "shared secret signing the webhooks, required unless -insecure")
+		tolerance    = flag.Duration("webhook-tolerance", autodeploy.DefaultTolerance, "age after which a signed webhook is rejected")
+		token        = flag.String("webhook-token", os.Getenv("WEBHOOK_TOKEN"), "token accepting unsigned webhooks in the token param of their URL, differing from -webhook-secret")
+		insecure     = flag.Bool("insecure", false, "accept unauthenticated webhooks")
+		confirm      = flag.Bool("confirm-games", false, "only deploy to the games of webhooks that -factory created, requires -rpc")
+		grace        = flag.Duration("withdrawal-grace", 24*time.Hour, "time eth_withdrawn_early and eth_deficit are kept after the final withdrawal of a game, unless the config sets withdrawalGraceSeconds")
+	)
+	flag.Parse()
+	if (*file == "") == (*manifestFile == "") || *apiKey == "" {
+		flag.Usage()
+		return fmt.Errorf("-api-key and one of -config and -manifest are required")
+	}
+	if *secret == "" && !*insecure && !*dryRun {
+		flag.Usage()
+		return fmt.Errorf("-webhook-secret is required, or -insecure to accept unauthenticated webhooks")
+	}
+
+	var cfg config
+	var err error
+	if *manifestFile != "" {
+		cfg, err = fromManifest(*manifestFile, *network)
+	} else {
+		cfg, err = readConfig(*file)
+	}
+	if err != nil {
+		return err
+	}
+	logError := func(err error) {
+		fmt.Fprintln(os.Stderr, "autodeploy:", err)
+	}
+	client := hexagate.NewClient(*apiKey)
+	client.SetBaseURL(*apiURL)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var chain *rpc.Client
+	if *endpoint != "" {
+		chain = rpc.NewClient(*endpoint)
+	} else {
+		fmt.Fprintln(os.Stderr, "autodeploy: no -rpc, monitors are not deleted when their game resolves or settles")
+	}
+	var confirmFactory eth.Address
+	if *confirm {
+		if *factory == "" || chain == nil {
+			return fmt.Errorf("-confirm-games requires -factory and -rpc")
+		}
+		if confirmFactory, err = eth.ParseAddress(*factory); err != nil {
+			return fmt.Errorf("invalid -factory: %w", err)
+		}
+	}
+	mux := http.NewServeMux()
+	if len(cfg.Monitors) > 0 {
+		cfg.OnError = logError
+		if cfg.WithdrawalGraceSeconds == 0 {
+			cfg.WithdrawalGraceSeconds = uint64(grace.Seconds())
+		}
+		if *confirm {
+			cfg.Factory, cfg.Chain = confirmFactory, chain
+		}
+		cfg.State = gamesFile(*state)
+		if *queue != "" {
+			if cfg.Queue, err = autodeploy.OpenQueue(*queue, autodeploy.DefaultRetryPolicy); err != nil {
+				return err
+			}
+			cfg.State = gamesFile(*queue)
+		}
+		d, err := autodeploy.New(client, cfg.Config)
+		if err != nil {
+			return err
+		}
+		if *factory != "" {
+			if chain == nil {
+				return fmt.Errorf("-factory requires -rpc")
+			}
+			if err := backfill(ctx, d, chain, *factory, *since, *dryRun); err != nil {
+				logError(err)
+			}
+			if *dryRun {
+				return nil
+			}
+		}
+		mux.Handle("/", d)
+		fmt.Printf("deploying %d monitors per game on chain %d\n", len(cfg.Monitors), cfg.ChainID)
+		if cfg.Queue != nil {
+			handler, err := autodeploy.QueueHandler(cfg.Queue, *adminToken)
+			if err != nil {
+				return fmt.Errorf("-admin-token is required with -queue: %w", err)
+			}
+			go d.Work(ctx, *pollInterval)
+			admin := &http.Server{Addr: *adminListen, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+			go func() {
+				if err := admin.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+					logError(err)
+				}
+			}()
+			defer admin.Close()
+			fmt.Printf("queuing the monitors in %s, administered on %s\n", *queue, *adminListen)
+		}
+		if chain != nil {
+			go poll(ctx, *pollInterval, logError, func(ctx context.Context) error {
+				removed, err := d.Teardown(ctx, chain)
+				for _, m := range removed {
+					fmt.Printf("deleted %s monitor %d of game %s\n", m.Labels[autodeploy.LabelMonitor], m.ID, m.Labels[autodeploy.LabelGame])
+				}
+				return err
+			})
+		}
+	}
+	if cfg.Child != nil {
+		if cfg.Child.ChainID == 0 {
+			cfg.Child.ChainID = cfg.ChainID
+		}
+		cfg.Child.OnError = logError
+		if *confirm {
+			cfg.Child.Factory, cfg.Child.Chain = confirmFactory, chain
+		}
+		store, err := autodeploy.OpenStore(*state)
+		if err != nil {
+			return err
+		}
+		d, err := autodeploy.NewChildDeployer(client, store, *cfg.Child)
+		if err != nil {
+			return err
+		}
+		mux.Handle("/fault_proof_detection", d)
+		fmt.Printf("deploying %s on chain %d\n", autodeploy.ChildMonitor, cfg.Child.ChainID)
+		if chain != nil {
+			go poll(ctx, *pollInterval, logError, func(ctx context.Context) error {
+				removed, err := d.Teardown(ctx, chain)
+				for _, dep := range removed {
+					fmt.Printf("deleted %s monitor %d of resolved game %s\n", dep.Monitor, dep.MonitorID, dep.Game)
+				}
+				return err
+			})
+		}
+	}
+
+	handler := http.Handler(mux)
+	if *secret != "" {
+		v, err := autodeploy.NewVerifier([]byte(*secret), *tolerance)
+		if err != nil {
+			return err
+		}
+		v.OnError = logError
+		if *token != "" {
+			if err := v.AcceptToken(*token); err != nil {
+				return err
+			}
+		}
+		handler = v.Handler(mux)
+	} else {
+		fmt.Fprintln(os.Stderr, "autodeploy: -insecure, webhooks are not authenticated")
+	}
+	server := &http.Server{Addr: *listen, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+	fmt.Printf("listening on %s\n", *listen)
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	// let the deployments in progress finish
+	shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdown); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// gamesFile returns the file the deployer state is kept in next to path, such as queue.games.json for queue.json.
+func gamesFile(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".games.json"
+}
+
+func readConfig(path string) (config, error) {
+	var cfg config
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func fromManifest(path, network string) (config, error) {
+	m, err := manifest.Load(path)
+	if err != nil {
+		return config{}, err
+	}
+	if network == "" {
+		if len(m.Networks) != 1 {
+			return config{}, fmt.Errorf("-network is required, the manifest declares %d networks", len(m.Networks))
+		}
+		for name := range m.Networks {
+			network = name
+		}
+	}
+	cfg, child, err := m.Autodeploy(network)
+	if err != nil {
+		return config{}, err
+	}
+	return config{Config: cfg, Child: child}, nil
+}
+
+// backfill deploys the monitors missing for the unsettled games of factory, and prints them.
+func backfill(ctx context.Context, d *autodeploy.Deployer, chain *rpc.Client, factory string, since time.Duration, dryRun bool) error {
+	opts := autodeploy.BackfillOptions{DryRun: dryRun}
+	var err error
+	if opts.Factory, err = eth.ParseAddress(factory); err != nil {
+		return fmt.Errorf("invalid -factory: %w", err)
+	}
+	if since > 0 {
+		opts.Since = uint64(time.Now().Add(-since).Unix())
+	}
+	games, err := d.Backfill(ctx, chain, opts)
+	verb := "deployed"
+	if dryRun {
+		verb = "missing"
+	}
+	total := 0
+	for _, g := range games {
+		names := g.Missing
+		if !dryRun {
+			names = nil
+			for _, m := range g.Created {
+				names = append(names, m.Labels[autodeploy.LabelMonitor])
+			}
+		}
+		total += len(names)
+		fmt.Printf("game %s (index %d, %s): %s %s\n", g.Game, g.Index, g.Phase, verb, strings.Join(names, ", "))
+	}
+	fmt.Printf("backfill: %d monitors %s for %d games\n", total, verb, len(games))
+	return err
+}
+
+// poll calls teardown every interval until ctx is cancelled.
+func poll(ctx context.Context, interval time.Duration, onError func(error), teardown func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := teardown(ctx); err != nil && ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
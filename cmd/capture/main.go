@@ -0,0 +1,110 @@
+// Command capture records everything the monitors read over a block range into an archive that replay and tests can
+// use offline. Either a block range or a dispute game is captured:
+//
+//	capture -rpc $L1_RPC -out ./archive -monitors monitors.json -from 21000000 -to 21000100
+//	capture -rpc $L1_RPC -out ./archive -monitors monitors.json -factory 0x43ed... -game 0x1234...
+//
+// A game is captured from its creation until it was resolved, and monitors taking a disputeGame param are deployed
+// for it. Use -l2 chainId=url to capture the L2 outputs checked by fault_proof_detection_parent.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/capture"
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// endpoints collects repeated -l2 flags.
+type endpoints map[uint64]string
+
+func (e endpoints) String() string { return fmt.Sprint(map[uint64]string(e)) }
+
+func (e endpoints) Set(v string) error {
+	id, url, ok := strings.Cut(v, "=")
+	chainID, err := strconv.ParseUint(id, 10, 64)
+	if !ok || err != nil {
+		return fmt.Errorf("expected chainId=url, got %q", v)
+	}
+	e[chainID] = url
+	return nil
+}
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "capture:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		endpoint    = flag.String("rpc", "", "JSON-RPC endpoint of the chain the monitors are deployed on")
+		out         = flag.String("out", "", "directory to write the archive to")
+		monitors    = flag.String("monitors", "", "JSON file listing the monitors to capture as [{name, params}]")
+		from        = flag.Uint64("from", 0, "first block to capture (default: creation of -game)")
+		to          = flag.Uint64("to", 0, "last block to capture (default: resolution of -game, or the head)")
+		game        = flag.String("game", "", "dispute game to capture")
+		factory     = flag.String("factory", "", "DisputeGameFactory that created -game")
+		description = flag.String("description", "", "description stored in the archive manifest")
+		noTraces    = flag.Bool("no-traces", false, "do not trace blocks, for endpoints without the debug namespace")
+		l2          = endpoints{}
+	)
+	flag.Var(l2, "l2", "JSON-RPC endpoint of an L2 chain as chainId=url, may be repeated")
+	flag.Parse()
+	if *endpoint == "" || *out == "" || *monitors == "" {
+		flag.Usage()
+		return fmt.Errorf("-rpc, -out and -monitors are required")
+	}
+
+	cfg := capture.Config{
+		Endpoint:    *endpoint,
+		L2Endpoints: l2,
+		Dir:         *out,
+		Description: *description,
+		FromBlock:   *from,
+		ToBlock:     *to,
+		SkipTraces:  *noTraces,
+	}
+	data, err := os.ReadFile(*monitors)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &cfg.Monitors); err != nil {
+		return fmt.Errorf("decoding %s: %w", *monitors, err)
+	}
+	if *game != "" {
+		if cfg.Game, err = eth.ParseAddress(*game); err != nil {
+			return fmt.Errorf("invalid -game: %w", err)
+		}
+		if cfg.Factory, err = eth.ParseAddress(*factory); err != nil {
+			return fmt.Errorf("invalid -factory: %w", err)
+		}
+	} else if *to == 0 {
+		return fmt.Errorf("-to is required without -game")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	manifest, tl, err := capture.Run(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("captured blocks %d-%d of chain %d into %s (%d responses)\n", manifest.FromBlock, manifest.ToBlock, manifest.ChainID, *out, len(manifest.Responses))
+	if len(tl.Failures) > 0 {
+		// the archive is still written, but replaying the failed evaluations will fail too
+		fmt.Fprintf(os.Stderr, "%d evaluations failed while capturing, the archive is incomplete:\n", len(tl.Failures))
+		for _, f := range tl.Failures {
+			fmt.Fprintf(os.Stderr, "  block %d %s on %s: %s\n", f.BlockNumber, f.Monitor, f.Contract, f.Error)
+		}
+		return fmt.Errorf("incomplete capture")
+	}
+	return nil
+}
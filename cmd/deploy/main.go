@@ -0,0 +1,17 @@
+// Command deploy reconciles the monitors deployed on Hexagate with the deployment manifest:
+//
+//	deploy plan  -manifest deployments.yaml
+//	deploy apply -manifest deployments.yaml
+//
+// It is the same as fpm deploy, see cli.Deploy.
+package main
+
+import (
+	"os"
+
+	"github.com/base-org/fault-proof-monitors/cli"
+)
+
+func main() {
+	os.Exit(cli.Exit("deploy", cli.Deploy, os.Args[1:]))
+}
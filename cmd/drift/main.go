@@ -0,0 +1,91 @@
+// Command drift reports the deployed monitors running a gate source other than the gate files of this build:
+//
+//	drift -repo . -chain-id 1
+//
+// The source of every monitor deployed from this repository is looked up in the git history of its gate file, so
+// stale monitors are reported with the commit they run. With -upgrade, the stale monitors are updated in place to the
+// gate file of this build, and with -include-edited so are the monitors whose source was edited outside the
+// repository. Monitors whose params do not fit the gate file of this build are reported as incompatible and are not
+// upgraded. The gate file of the monitors without a monitor label naming one is found from their source, and those
+// whose source is in no revision of any gate file are reported as unmanaged. With -monitor, only the monitors labeled
+// with that gate file are checked.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/drift"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/monitors"
+)
+
+func main() {
+	code, err := run()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "drift:", err)
+		os.Exit(1)
+	}
+	os.Exit(code)
+}
+
+func run() (int, error) {
+	var (
+		repo     = flag.String("repo", ".", "git repository holding the history of the gate files")
+		apiKey   = flag.String("api-key", os.Getenv("HEXAGATE_API_KEY"), "Hexagate API key")
+		apiURL   = flag.String("api-url", hexagate.DefaultBaseURL, "root of the Hexagate API")
+		chainID  = flag.Uint64("chain-id", 0, "only check the monitors of this chain")
+		gate     = flag.String("monitor", "", "only check the monitors deployed from this gate file")
+		upgrade  = flag.Bool("upgrade", false, "update the gate source of stale monitors to the gate file of this build")
+		edited   = flag.Bool("include-edited", false, "with -upgrade, also replace the source of edited monitors")
+		exitCode = flag.Bool("exit-code", false, "exit with status 2 when monitors are not current")
+	)
+	flag.Parse()
+	if *apiKey == "" {
+		flag.Usage()
+		return 0, fmt.Errorf("-api-key is required")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	names := monitors.Names()
+	opts := hexagate.ListOptions{ChainID: *chainID}
+	if *gate != "" {
+		if _, err := monitors.Gate(*gate); err != nil {
+			return 0, err
+		}
+		names = []string{*gate}
+		opts.Labels = map[string]string{autodeploy.LabelMonitor: *gate}
+	}
+	history, err := drift.LoadHistory(ctx, *repo, names)
+	if err != nil {
+		return 0, err
+	}
+	client := hexagate.NewClient(*apiKey)
+	client.SetBaseURL(*apiURL)
+
+	report, err := drift.Check(ctx, client, history, opts)
+	if err != nil {
+		return 0, err
+	}
+	if err := report.WriteText(os.Stdout); err != nil {
+		return 0, err
+	}
+	if *upgrade {
+		upgraded := 0
+		err := report.Upgrade(ctx, client, *edited, func(e drift.Entry) {
+			upgraded++
+			fmt.Printf("upgraded %s (id %d)\n", e.Monitor.Name, e.Monitor.ID)
+		})
+		fmt.Printf("Upgraded %d monitors.\n", upgraded)
+		return 0, err
+	}
+	if *exitCode && report.Count(drift.Current) != len(report.Entries) {
+		return 2, nil
+	}
+	return 0, nil
+}
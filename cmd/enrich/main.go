@@ -0,0 +1,180 @@
+// Command enrich receives the alert webhooks of the monitors and turns them into incidents, with the context of the
+// dispute game they are about and the runbook of the monitor:
+//
+//	enrich -rpc $L1_RPC -network base-mainnet -listen :8082 -forward https://pager.example/incidents
+//
+// Each alert posted to / is answered with its incident, a JSON object holding the failed invariants, the link to the
+// runbook in docs/ and the game: its type, root claim, L2 block number, claim count and status, the claims of the
+// honest challenger with their clocks, and the balance of DelayedWETH for the game. With -forward, the incident is
+// posted to the given URL, and the webhook fails when it cannot be, so that Hexagate delivers it again.
+//
+// The chain and the honest challenger are those of the network profile, as set in fpm.yaml; with a Hexagate API key,
+// the game and the honest challenger are looked up in the params of the monitor that alerted. With -index, the claims
+// of the games indexed in that directory are read from it instead of the chain. The store is built by fpm index, and
+// enrich keeps indexing the games of the DisputeGameFactory of the network into it while it runs.
+//
+// Webhooks are authenticated with -webhook-secret, as the autodeploy webhooks are.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/enrich"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/indexer"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "enrich:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		listen     = flag.String("listen", ":8082", "address to receive webhooks on")
+		index      = flag.String("index", "", "directory of the indexer store, built by fpm index, to read the claims of the indexed games from")
+		traces     = flag.Bool("index-traces", false, "trace each block indexed to index the unlocks and withdrawals of DelayedWETH, as fpm index -traces")
+		honest     = flag.String("honest-challenger", "", "honest challenger whose claims are reported (default: the one of the network)")
+		forward    = flag.String("forward", "", "URL the incidents are posted to")
+		runbookURL = flag.String("runbook-url", enrich.DefaultRunbookURL, "root the paths of the runbooks are resolved against")
+		secret     = flag.String("webhook-secret", os.Getenv("WEBHOOK_SECRET"), "shared secret signing the webhooks, required unless -insecure")
+		tolerance  = flag.Duration("webhook-tolerance", autodeploy.DefaultTolerance, "age after which a signed webhook is rejected")
+		token      = flag.String("webhook-token", os.Getenv("WEBHOOK_TOKEN"), "token accepting unsigned webhooks in the token param of their URL, differing from -webhook-secret")
+		insecure   = flag.Bool("insecure", false, "accept unauthenticated webhooks")
+	)
+	load := config.Flags(flag.CommandLine)
+	flag.Parse()
+	settings, err := load()
+	if err != nil {
+		return err
+	}
+	if settings.RPC == "" {
+		flag.Usage()
+		return fmt.Errorf("-rpc is required")
+	}
+	if *secret == "" && !*insecure {
+		flag.Usage()
+		return fmt.Errorf("-webhook-secret is required, or -insecure to accept unauthenticated webhooks")
+	}
+	profile, err := settings.Profile()
+	if err != nil {
+		return err
+	}
+	logError := func(err error) {
+		fmt.Fprintln(os.Stderr, "enrich:", err)
+	}
+
+	chain := rpc.NewClient(settings.RPC)
+	cfg := enrich.Config{
+		ChainID:          profile.L1ChainID,
+		Chain:            chain,
+		HonestChallenger: profile.HonestChallenger,
+		RunbookURL:       *runbookURL,
+		OnError:          logError,
+	}
+	if settings.ChainID != 0 {
+		cfg.ChainID = settings.ChainID
+	}
+	if *honest != "" {
+		if cfg.HonestChallenger, err = eth.ParseAddress(*honest); err != nil {
+			return fmt.Errorf("invalid -honest-challenger: %w", err)
+		}
+	}
+	var ix *indexer.Indexer
+	if *index != "" {
+		if cfg.Index, err = indexer.Open(*index); err != nil {
+			return err
+		}
+		if cfg.Index.Checkpoint() == nil {
+			return fmt.Errorf("nothing is indexed in %s, build it with fpm index", *index)
+		}
+		if profile.DisputeGameFactoryProxy == (eth.Address{}) {
+			return fmt.Errorf("network %s has no disputeGameFactoryProxy to index the games of", settings.Network)
+		}
+		ic := indexer.Config{Factory: profile.DisputeGameFactoryProxy, OnError: logError}
+		if *traces {
+			ic.Tracer = chain
+		}
+		ix = indexer.New(chain, cfg.Index, ic)
+	}
+	if settings.APIKey != "" {
+		if cfg.Client, err = settings.Client(); err != nil {
+			return err
+		}
+	}
+	var post func(context.Context, enrich.Incident) error
+	if *forward != "" {
+		post = enrich.PostJSON(&http.Client{Timeout: 30 * time.Second}, *forward)
+	}
+	cfg.Forward = func(ctx context.Context, inc enrich.Incident) error {
+		fmt.Printf("%s: %s\n", inc.Key, inc.Summary)
+		if post == nil {
+			return nil
+		}
+		return post(ctx, inc)
+	}
+	e, err := enrich.New(cfg)
+	if err != nil {
+		return err
+	}
+
+	handler := http.Handler(e)
+	if *secret != "" {
+		v, err := autodeploy.NewVerifier([]byte(*secret), *tolerance)
+		if err != nil {
+			return err
+		}
+		v.OnError = logError
+		if *token != "" {
+			if err := v.AcceptToken(*token); err != nil {
+				return err
+			}
+		}
+		handler = v.Handler(e)
+	} else {
+		fmt.Fprintln(os.Stderr, "enrich: -insecure, webhooks are not authenticated")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	server := &http.Server{Addr: *listen, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+	if ix != nil {
+		// the games indexed before the reorg are still read, the store only needs to be built again to follow on
+		go func() {
+			if err := ix.Run(ctx); err != nil {
+				logError(fmt.Errorf("indexing stopped: %w", err))
+			}
+		}()
+	}
+	fmt.Printf("enriching the alerts of %s on chain %d, listening on %s\n", settings.Network, cfg.ChainID, *listen)
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdown); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Command fixturegen turns a captured game into a fixture for one of the monitors in the archive:
+//
+//	fixturegen -archive ./archive -monitor unresolvable_dispute_game
+//
+// The fixture is taken at the first block the native monitor alerts at, or at -block, and is written to
+// fixtures/<monitor>/<name>.json so that both the native and the gate tests pick it up.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/base-org/fault-proof-monitors/archive"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/fixturegen"
+	"github.com/base-org/fault-proof-monitors/fixtures"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "fixturegen:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		dir         = flag.String("archive", "", "directory of the archive written by capture")
+		name        = flag.String("monitor", "", "monitor to generate the fixture for")
+		game        = flag.String("game", "", "dispute game of the deployment, when the archive holds several")
+		block       = flag.Uint64("block", 0, "block to take the sources at (default: the first alert, or the last block)")
+		fixture     = flag.String("name", "", "file name of the fixture, without the extension (default: captured_<chainId>_<block>)")
+		description = flag.String("description", "", "description of the fixture")
+		out         = flag.String("out", "", "file to write the fixture to (default: fixtures/<monitor>/<name>.json)")
+		force       = flag.Bool("force", false, "overwrite an existing fixture")
+	)
+	flag.Parse()
+	if *dir == "" || *name == "" {
+		flag.Usage()
+		return fmt.Errorf("-archive and -monitor are required")
+	}
+
+	opts := fixturegen.Options{Monitor: *name, Block: *block, Name: *fixture, Description: *description}
+	if *game != "" {
+		var err error
+		if opts.Game, err = eth.ParseAddress(*game); err != nil {
+			return fmt.Errorf("invalid -game: %w", err)
+		}
+	}
+	a, err := archive.Open(*dir)
+	if err != nil {
+		return err
+	}
+	f, err := fixturegen.Generate(context.Background(), a, opts)
+	if err != nil {
+		return err
+	}
+	data, err := fixtures.Encode(*f)
+	if err != nil {
+		return err
+	}
+
+	path := *out
+	if path == "" {
+		path = filepath.Join("fixtures", f.Monitor, f.Name+".json")
+	}
+	if _, err := os.Stat(path); err == nil && !*force {
+		return fmt.Errorf("%s already exists, use -force to overwrite it", path)
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s (expectAlert: %t, alerts: %q)\n", path, f.ExpectAlert, f.Alerts)
+	return nil
+}
@@ -0,0 +1,27 @@
+// Command fpm covers the monitor workflow, from writing and testing a gate file to deploying it:
+//
+//	fpm validate eth_deficit -params params.json -mocks mocks.json
+//	fpm test -native
+//	fpm lint
+//	fpm fmt
+//	fpm graph eth_deficit
+//	fpm deploy plan -manifest deployments.yaml
+//	fpm games list
+//	fpm index -start-block 17000000
+//	fpm networks
+//	fpm replay -archive ./archive
+//	fpm follow -monitors monitors.json
+//
+// The API key, the API root, the JSON-RPC endpoint and the network are read from flags, the environment, a .env file
+// or fpm.yaml, see the config package. Run fpm without arguments for the list of commands.
+package main
+
+import (
+	"os"
+
+	"github.com/base-org/fault-proof-monitors/cli"
+)
+
+func main() {
+	os.Exit(cli.Main("fpm", os.Args[1:]))
+}
@@ -0,0 +1,128 @@
+// Command jobs administers the queue of monitors to create of an autodeploy started with -queue:
+//
+//	jobs list -state dead
+//	jobs redrive -game 0x... -monitor eth_deficit
+//
+// list prints the jobs, and redrive queues the dead jobs again with their attempts reset. Both go through the admin
+// address of autodeploy, authenticated with the -admin-token it was started with, or with -queue edit the queue file
+// directly, which must only be done while autodeploy is stopped.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "jobs:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	if len(os.Args) < 2 || (os.Args[1] != "list" && os.Args[1] != "redrive") {
+		fmt.Fprintln(os.Stderr, "usage: jobs list|redrive [flags]")
+		return fmt.Errorf("expected the list or redrive command")
+	}
+	command := os.Args[1]
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	var (
+		admin   = flags.String("admin", "http://127.0.0.1:8081", "admin address of autodeploy")
+		token   = flags.String("admin-token", os.Getenv("ADMIN_TOKEN"), "bearer token of the admin address")
+		queue   = flags.String("queue", "", "queue file to read and change directly instead of -admin, while autodeploy is stopped")
+		state   = flags.String("state", "", "list: only the jobs in this state, pending, done or dead")
+		game    = flags.String("game", "", "only the jobs of this game")
+		monitor = flags.String("monitor", "", "only the jobs of this monitor")
+	)
+	_ = flags.Parse(os.Args[2:])
+	switch autodeploy.JobState(*state) {
+	case "", autodeploy.JobPending, autodeploy.JobDone, autodeploy.JobDead:
+	default:
+		return fmt.Errorf("unknown state %q", *state)
+	}
+
+	var jobs []autodeploy.Job
+	var err error
+	if *queue != "" {
+		jobs, err = local(*queue, command, autodeploy.JobFilter(autodeploy.JobState(*state), *game, *monitor))
+	} else {
+		jobs, err = remote(*admin, *token, command, url.Values{"state": {*state}, "game": {*game}, "monitor": {*monitor}})
+	}
+	if err != nil {
+		return err
+	}
+	if command == "redrive" {
+		fmt.Printf("re-drove %d jobs\n", len(jobs))
+	}
+	return write(os.Stdout, jobs)
+}
+
+func local(path, command string, filter func(autodeploy.Job) bool) ([]autodeploy.Job, error) {
+	q, err := autodeploy.OpenQueue(path, autodeploy.DefaultRetryPolicy)
+	if err != nil {
+		return nil, err
+	}
+	if command == "redrive" {
+		return q.Redrive(filter, time.Now().UTC())
+	}
+	return q.Jobs(filter), nil
+}
+
+func remote(admin, token, command string, query url.Values) ([]autodeploy.Job, error) {
+	if token == "" {
+		return nil, fmt.Errorf("-admin-token is required to reach -admin, or -queue to edit the queue file")
+	}
+	for k, v := range query {
+		if v[0] == "" {
+			delete(query, k)
+		}
+	}
+	method, path := http.MethodGet, autodeploy.JobsPath
+	if command == "redrive" {
+		query.Del("state")
+		method, path = http.MethodPost, autodeploy.RedrivePath
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(admin, "/")+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	var jobs []autodeploy.Job
+	if err := json.NewDecoder(resp.Body).Decode(&jobs); err != nil {
+		return nil, fmt.Errorf("decoding the jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func write(w io.Writer, jobs []autodeploy.Job) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GAME\tMONITOR\tSTATE\tATTEMPTS\tNEXT ATTEMPT\tLAST ERROR")
+	for _, j := range jobs {
+		next := "-"
+		if j.State == autodeploy.JobPending {
+			next = j.NextAttempt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\n", j.Game, j.Monitor, j.State, j.Attempts, next, j.LastError)
+	}
+	return tw.Flush()
+}
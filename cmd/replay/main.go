@@ -0,0 +1,17 @@
+// Command replay evaluates monitors over a block range recorded in an archive and prints the alerts they would have
+// raised, per game. Params can be overridden to backtest a threshold change:
+//
+//	replay -archive ./archive -set unresolvable_dispute_game.extraTimeInSeconds=7200
+//
+// It is the same as fpm replay, see cli.Replay.
+package main
+
+import (
+	"os"
+
+	"github.com/base-org/fault-proof-monitors/cli"
+)
+
+func main() {
+	os.Exit(cli.Exit("replay", cli.Replay, os.Args[1:]))
+}
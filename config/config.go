@@ -0,0 +1,267 @@
+// Package config resolves the settings shared by the fpm command and the gate tests. Each setting is taken from, in
+// order of precedence, a command line flag, an environment variable, a .env file and the config file fpm.yaml:
+//
+//	apiKey: ...           # HEXAGATE_API_KEY, -api-key
+//	apiUrl: ...           # HEXAGATE_API_URL, -api-url, https://api.hexagate.com/api/v1 by default
+//	rpc: ...              # FPM_RPC, -rpc
+//	network: base-mainnet # FPM_NETWORK, -network, base-mainnet by default
+//	chainId: 1            # FPM_CHAIN_ID, -chain-id, the L1 chain of the network by default
+//	networks:             # profiles added to the built-in ones, or overriding some of their fields
+//	  base-mainnet:
+//	    honestChallenger: "0x..."
+//	matrix: [...]         # FPM_MATRIX, -matrix of fpm test, the networks the fixtures run on, the network by default
+//
+// The config file is the one named by -config or FPM_CONFIG, or else the first fpm.yaml found in the working
+// directory or its parents, up to the module root. .env files are looked up the same way, so commands and tests
+// find the settings wherever they are run from.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/networks"
+)
+
+// FileName is the name of the config file looked up from the working directory.
+const FileName = "fpm.yaml"
+
+// Config holds the settings of the commands.
+type Config struct {
+	// APIKey authenticates the requests to the Hexagate API.
+	APIKey string `yaml:"apiKey"`
+	// APIURL is the root of the Hexagate API.
+	APIURL string `yaml:"apiUrl"`
+	// RPC is the JSON-RPC endpoint of the chain the games are created on.
+	RPC string `yaml:"rpc"`
+	// Network names the profile of the network the monitors run on, which resolves the params naming its chains,
+	// contracts and honest actors.
+	Network string `yaml:"network"`
+	// ChainID is the chain the monitors are validated and deployed on, when it is not the L1 chain of the network.
+	ChainID uint64 `yaml:"chainId"`
+	// Networks add profiles to the built-in ones of the networks package, or override some of their fields.
+	Networks map[string]networks.Profile `yaml:"networks"`
+	// Matrix lists the networks the fixtures are run on, or "all" for every profile.
+	Matrix []string `yaml:"matrix"`
+}
+
+// Default returns the settings used when nothing sets them.
+func Default() Config {
+	return Config{APIURL: hexagate.DefaultBaseURL, Network: networks.Default}
+}
+
+// env are the environment variables of the settings.
+var env = []struct {
+	name string
+	set  func(c *Config, v string) error
+}{
+	{"HEXAGATE_API_KEY", func(c *Config, v string) error { c.APIKey = v; return nil }},
+	{"HEXAGATE_API_URL", func(c *Config, v string) error { c.APIURL = v; return nil }},
+	{"FPM_RPC", func(c *Config, v string) error { c.RPC = v; return nil }},
+	{"FPM_NETWORK", func(c *Config, v string) error { c.Network = v; return nil }},
+	{"FPM_MATRIX", func(c *Config, v string) error { c.Matrix = SplitList(v); return nil }},
+	{"FPM_CHAIN_ID", func(c *Config, v string) (err error) { c.ChainID, err = strconv.ParseUint(v, 10, 64); return err }},
+}
+
+// Load returns the default settings overridden by the config file at path, or the one found from the working
+// directory if path is empty, then by the .env file found from the working directory, and then by the environment.
+func Load(path string) (Config, error) {
+	cfg := Default()
+	if path == "" {
+		path = os.Getenv("FPM_CONFIG")
+	}
+	if path == "" {
+		path, _ = Find(FileName)
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, err
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return cfg, fmt.Errorf("decoding %s: %w", path, err)
+		}
+	}
+
+	dotenv := map[string]string{}
+	if path, ok := Find(".env"); ok {
+		var err error
+		if dotenv, err = godotenv.Read(path); err != nil {
+			return cfg, fmt.Errorf("reading %s: %w", path, err)
+		}
+	}
+	for _, e := range env {
+		v, ok := os.LookupEnv(e.name)
+		if !ok {
+			v, ok = dotenv[e.name]
+		}
+		if !ok || v == "" {
+			continue
+		}
+		if err := e.set(&cfg, v); err != nil {
+			return cfg, fmt.Errorf("invalid %s: %w", e.name, err)
+		}
+	}
+	return cfg, nil
+}
+
+// Flags registers -config, -api-key, -api-url, -rpc, -network and -chain-id on fs, and returns a function loading the settings
+// once fs is parsed, with the flags set overriding the other sources.
+func Flags(fs *flag.FlagSet) func() (Config, error) {
+	var flags Config
+	file := fs.String("config", "", "config file (default: FPM_CONFIG, or "+FileName+" in the working directory or its parents)")
+	fs.StringVar(&flags.APIKey, "api-key", "", "Hexagate API key (default: HEXAGATE_API_KEY)")
+	fs.StringVar(&flags.APIURL, "api-url", "", "root of the Hexagate API (default: HEXAGATE_API_URL, or "+hexagate.DefaultBaseURL+")")
+	fs.StringVar(&flags.RPC, "rpc", "", "JSON-RPC endpoint of the chain (default: FPM_RPC)")
+	fs.StringVar(&flags.Network, "network", "", "profile of the network the monitors run on (default: FPM_NETWORK, or "+networks.Default+")")
+	fs.Uint64Var(&flags.ChainID, "chain-id", 0, "chain the monitors run on (default: FPM_CHAIN_ID, or the L1 chain of the network)")
+	return func() (Config, error) {
+		cfg, err := Load(*file)
+		if err != nil {
+			return cfg, err
+		}
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "api-key":
+				cfg.APIKey = flags.APIKey
+			case "api-url":
+				cfg.APIURL = flags.APIURL
+			case "rpc":
+				cfg.RPC = flags.RPC
+			case "network":
+				cfg.Network = flags.Network
+			case "chain-id":
+				cfg.ChainID = flags.ChainID
+			}
+		})
+		return cfg, nil
+	}
+}
+
+// Client returns a client of the Hexagate API, or an error if no API key is set.
+func (c Config) Client() (*hexagate.Client, error) {
+	if c.APIKey == "" {
+		return nil, errors.New("no Hexagate API key, set -api-key, HEXAGATE_API_KEY or apiKey in " + FileName)
+	}
+	client := hexagate.NewClient(c.APIKey)
+	client.SetBaseURL(c.APIURL)
+	return client, nil
+}
+
+// Profile returns the profile of the network selected by Network, with its L1 chain replaced by ChainID when set.
+func (c Config) Profile() (networks.Profile, error) {
+	p, err := c.lookup(c.Network)
+	if err != nil {
+		return p, err
+	}
+	if c.ChainID != 0 {
+		p.L1ChainID = c.ChainID
+	}
+	if p.L1ChainID == 0 {
+		return p, fmt.Errorf("network %s has no l1ChainId", c.Network)
+	}
+	return p, nil
+}
+
+// Lookup returns the profile of the network name: the built-in one overridden by the entry of Networks, if any. The
+// profile of the selected network is the one of Profile.
+func (c Config) Lookup(name string) (networks.Profile, error) {
+	if name == c.Network {
+		return c.Profile()
+	}
+	p, err := c.lookup(name)
+	if err == nil && p.L1ChainID == 0 {
+		err = fmt.Errorf("network %s has no l1ChainId", name)
+	}
+	return p, err
+}
+
+func (c Config) lookup(name string) (networks.Profile, error) {
+	p, err := networks.Lookup(name)
+	override, ok := c.Networks[name]
+	if err != nil && !ok {
+		return p, err
+	}
+	return p.Merge(override), nil
+}
+
+// NetworkNames returns the names of the built-in profiles and of the ones of Networks, in order.
+func (c Config) NetworkNames() []string {
+	names := networks.Names()
+	for name := range c.Networks {
+		if _, err := networks.Lookup(name); err != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MatrixNetworks returns the networks the fixtures are run on: the ones of Matrix, every network and the test profile
+// of the networks package if it is "all", or else the selected network.
+func (c Config) MatrixNetworks() []string {
+	if len(c.Matrix) == 1 && c.Matrix[0] == "all" {
+		return append(c.NetworkNames(), networks.Test)
+	}
+	if len(c.Matrix) == 0 {
+		return []string{c.Network}
+	}
+	return c.Matrix
+}
+
+// SplitList splits a comma separated list, dropping the empty elements.
+func SplitList(s string) []string {
+	var list []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			list = append(list, e)
+		}
+	}
+	return list
+}
+
+// Find returns the path of the file name in the working directory or the closest of its parents, stopping at the
+// module root, the first directory holding a go.mod file.
+func Find(name string) (string, bool) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", false
+	}
+	for {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return "", false
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Root returns the module root, the closest directory holding a go.mod file from the working directory, or the
+// working directory if there is none.
+func Root() string {
+	if path, ok := Find("go.mod"); ok {
+		return filepath.Dir(path)
+	}
+	return "."
+}
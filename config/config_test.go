@@ -0,0 +1,137 @@
+package config_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/networks"
+)
+
+// chdir writes the files, by path relative to root, and moves the test to dir.
+func chdir(t *testing.T, root, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+}
+
+func TestLoad(t *testing.T) {
+	for _, name := range []string{"HEXAGATE_API_KEY", "HEXAGATE_API_URL", "FPM_RPC", "FPM_NETWORK", "FPM_CHAIN_ID", "FPM_CONFIG"} {
+		t.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+	root := t.TempDir()
+	chdir(t, root, filepath.Join(root, "tests"), map[string]string{
+		"go.mod":     "module example\n",
+		"fpm.yaml":   "apiKey: from-file\nrpc: http://file\nchainId: 10\n",
+		".env":       "HEXAGATE_API_KEY=from-dotenv\nFPM_CHAIN_ID=11\n",
+		"tests/x.go": "package tests\n",
+	})
+
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := config.Config{APIKey: "from-dotenv", APIURL: config.Default().APIURL, RPC: "http://file", Network: networks.Default, ChainID: 11}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("expected the .env file to override fpm.yaml, got %+v", cfg)
+	}
+
+	t.Setenv("FPM_CHAIN_ID", "12")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	load := config.Flags(fs)
+	if err := fs.Parse([]string{"-api-key", "from-flag"}); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err = load(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.APIKey != "from-flag" || cfg.ChainID != 12 || cfg.RPC != "http://file" {
+		t.Errorf("expected the flag and the environment to take precedence, got %+v", cfg)
+	}
+
+	t.Setenv("FPM_CHAIN_ID", "mainnet")
+	if _, err := config.Load(""); err == nil {
+		t.Error("expected an invalid chain id to be rejected")
+	}
+	if _, err := config.Load(filepath.Join(root, "missing.yaml")); err == nil {
+		t.Error("expected a missing config file to be reported")
+	}
+}
+
+func TestProfile(t *testing.T) {
+	challenger := eth.MustAddress("0x00000000000000000000000000000000000000A2")
+	cfg := config.Default()
+	cfg.Networks = map[string]networks.Profile{
+		"base-sepolia": {HonestChallenger: challenger},
+		"devnet":       {L1ChainID: 900, L2ChainID: 901},
+	}
+
+	cfg.Network = "base-sepolia"
+	p, err := cfg.Profile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sepolia, _ := networks.Lookup("base-sepolia")
+	if p.L1ChainID != 11155111 || p.HonestChallenger != challenger || p.DisputeGameFactoryProxy != sepolia.DisputeGameFactoryProxy {
+		t.Errorf("expected the built-in profile with the honest challenger of the config, got %+v", p)
+	}
+
+	cfg.Network, cfg.ChainID = "devnet", 1337
+	if p, err = cfg.Profile(); err != nil || p.L1ChainID != 1337 || p.L2ChainID != 901 {
+		t.Errorf("expected the profile of the config with the chain overridden, got %+v (%v)", p, err)
+	}
+	cfg.Network = "unknown"
+	if _, err := cfg.Profile(); err == nil {
+		t.Error("expected an unknown network to be rejected")
+	}
+
+	if got := cfg.MatrixNetworks(); !reflect.DeepEqual(got, []string{"unknown"}) {
+		t.Errorf("expected the matrix to default to the network, got %v", got)
+	}
+	cfg.Matrix = config.SplitList(" all, ")
+	want := append(networks.Names(), "devnet")
+	sort.Strings(want)
+	want = append(want, networks.Test)
+	if got := cfg.MatrixNetworks(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected every network, got %v", got)
+	}
+}
+
+func TestFindStopsAtModuleRoot(t *testing.T) {
+	root := t.TempDir()
+	chdir(t, root, filepath.Join(root, "module", "tests"), map[string]string{
+		".env":              "HEXAGATE_API_KEY=outside\n",
+		"module/go.mod":     "module example\n",
+		"module/tests/x.go": "package tests\n",
+	})
+	if path, ok := config.Find(".env"); ok {
+		t.Errorf("expected the search to stop at the module root, found %s", path)
+	}
+	if got := config.Root(); got != filepath.Join(root, "module") {
+		t.Errorf("expected the module root, got %s", got)
+	}
+	if _, err := config.Default().Client(); err == nil {
+		t.Error("expected a client without an API key to be rejected")
+	}
+}
@@ -0,0 +1,228 @@
+package drift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/monitors"
+)
+
+// Status is how the gate source of a deployed monitor relates to its gate file.
+type Status string
+
+const (
+	// Current monitors run the gate file of this build.
+	Current Status = "current"
+	// Stale monitors run an older revision of the gate file.
+	Stale Status = "stale"
+	// Edited monitors run a source found in no revision of the gate file, such as one changed in the Hexagate UI.
+	Edited Status = "edited"
+	// Incompatible monitors are stale or edited, but their params do not match the ones declared by the gate file of
+	// this build, so they cannot be upgraded in place and have to be redeployed.
+	Incompatible Status = "incompatible"
+	// Unmanaged monitors carry no monitor label naming a gate file of this build, and run a source found in no
+	// revision of any gate file, so the gate file they were deployed from is unknown.
+	Unmanaged Status = "unmanaged"
+)
+
+// Entry is a deployed monitor and the version of its gate file it runs.
+type Entry struct {
+	Monitor hexagate.Monitor
+	// Gate is the gate file the monitor was deployed from, unset for unmanaged monitors.
+	Gate string
+	// Matched is set when the monitor carries no monitor label naming a gate file of this build, and Gate was found
+	// from its source instead.
+	Matched bool
+	Hash    string
+	Status  Status
+	// Revision is the newest commit of the source, unset for edited monitors.
+	Revision *Revision
+	// Behind is the number of versions of the gate file committed after the one of a stale monitor.
+	Behind int
+	// ParamsErr is why the params of an incompatible monitor do not fit the gate file of this build.
+	ParamsErr error
+}
+
+// Report lists the deployed monitors by gate file and ID.
+type Report struct {
+	Entries []Entry
+}
+
+// Count returns the number of monitors with the given status.
+func (r *Report) Count(status Status) int {
+	n := 0
+	for _, e := range r.Entries {
+		if e.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Check lists the monitors deployed from this repository that match opts, and looks up the gate source of each one
+// in history. The gate file of the monitors without the monitor label, or labeled with a gate file that no longer
+// exists, is found by looking up their source in the current gate files and the history of every gate file, and
+// they are reported as unmanaged when it is in none.
+func Check(ctx context.Context, client *hexagate.Client, history *History, opts hexagate.ListOptions) (*Report, error) {
+	if opts.Labels == nil {
+		opts.Labels = make(map[string]string)
+	}
+	opts.Labels[autodeploy.LabelManagedBy] = autodeploy.ManagedBy
+	deployed, err := client.AllMonitors(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	for _, m := range deployed {
+		e := Entry{Monitor: m, Gate: m.Labels[autodeploy.LabelMonitor], Hash: Hash(m.Gate), Status: Edited}
+		source, err := monitors.Gate(e.Gate)
+		if err != nil {
+			if e.Gate, source, e.Matched = match(history, e.Hash); !e.Matched {
+				e.Status = Unmanaged
+				report.Entries = append(report.Entries, e)
+				continue
+			}
+		}
+		name, current := e.Gate, Hash(source)
+		if version, newer, ok := history.Lookup(name, e.Hash); ok {
+			e.Revision = &version.Revisions[0]
+			e.Status = Stale
+			e.Behind = newer + 1
+			// the gate file of this build may be uncommitted, then every revision is behind it
+			if _, currentNewer, ok := history.Lookup(name, current); ok {
+				e.Behind = newer - currentNewer
+			}
+		}
+		if e.Hash == current {
+			e.Status, e.Behind = Current, 0
+		} else if err := monitors.CheckParams(name, m.Params); err != nil {
+			e.Status, e.ParamsErr = Incompatible, err
+		}
+		report.Entries = append(report.Entries, e)
+	}
+	// the unmanaged monitors come first, with no gate file
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		a, b := report.Entries[i], report.Entries[j]
+		if a.Gate != b.Gate {
+			return a.Gate < b.Gate
+		}
+		return a.Monitor.ID < b.Monitor.ID
+	})
+	return report, nil
+}
+
+// match returns the gate file of this build whose current source, or a committed one, has the given hash, along with
+// its current source.
+func match(history *History, hash string) (string, string, bool) {
+	for _, name := range monitors.Names() {
+		if source, err := monitors.Gate(name); err == nil && Hash(source) == hash {
+			return name, source, true
+		}
+	}
+	name, _, _, ok := history.Find(hash)
+	if !ok {
+		return "", "", false
+	}
+	source, err := monitors.Gate(name)
+	if err != nil {
+		return "", "", false
+	}
+	return name, source, true
+}
+
+// WriteText prints the unlabeled, stale, edited and unmanaged monitors, then the number of monitors of each version
+// of every gate file.
+func (r *Report) WriteText(w io.Writer) error {
+	for _, e := range r.Entries {
+		if e.Matched {
+			if _, err := fmt.Fprintf(w, "unlabeled %s (id %d): runs a version of %s.gate\n", e.Monitor.Name, e.Monitor.ID, e.Gate); err != nil {
+				return err
+			}
+		}
+		var err error
+		switch e.Status {
+		case Stale:
+			_, err = fmt.Fprintf(w, "stale   %s (id %d): %s %s, %d versions behind\n",
+				e.Monitor.Name, e.Monitor.ID, e.Revision.Short(), e.Revision.Subject, e.Behind)
+		case Edited:
+			_, err = fmt.Fprintf(w, "edited  %s (id %d): source %s is in no revision of %s.gate\n",
+				e.Monitor.Name, e.Monitor.ID, e.Hash[:12], e.Gate)
+		case Incompatible:
+			_, err = fmt.Fprintf(w, "incompatible %s (id %d): params do not fit %s.gate: %v\n",
+				e.Monitor.Name, e.Monitor.ID, e.Gate, e.ParamsErr)
+		case Unmanaged:
+			_, err = fmt.Fprintf(w, "unmanaged %s (id %d): source %s is in no revision of any gate file\n",
+				e.Monitor.Name, e.Monitor.ID, e.Hash[:12])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	type version struct {
+		gate, label string
+	}
+	counts := make(map[version]int)
+	var order []version
+	for _, e := range r.Entries {
+		v := version{e.Gate, string(e.Status)}
+		if e.Revision != nil {
+			v.label = fmt.Sprintf("%s (%s)", e.Status, e.Revision.Short())
+		}
+		if counts[v] == 0 {
+			order = append(order, v)
+		}
+		counts[v]++
+	}
+	if len(order) > 0 {
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	for _, v := range order {
+		if _, err := fmt.Fprintf(w, "%-32s %-20s %d\n", v.gate, v.label, counts[v]); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%d monitors: %d current, %d stale, %d edited, %d incompatible, %d unmanaged.\n",
+		len(r.Entries), r.Count(Current), r.Count(Stale), r.Count(Edited), r.Count(Incompatible), r.Count(Unmanaged))
+	return err
+}
+
+// Upgrade replaces the gate source of the stale monitors, and of the edited ones if edited is set, with the gate file
+// of this build. The params are left unchanged, so incompatible monitors are skipped, and so is any other whose params
+// do not fit the gate file, which is reported as a failure. Failures do not stop the upgrade of the other monitors,
+// and are returned together.
+func (r *Report) Upgrade(ctx context.Context, client *hexagate.Client, edited bool, onUpgrade func(Entry)) error {
+	var errs []error
+	for _, e := range r.Entries {
+		if e.Status == Current || e.Status == Incompatible || e.Status == Unmanaged || (e.Status == Edited && !edited) {
+			continue
+		}
+		source, err := monitors.Gate(e.Gate)
+		if err != nil {
+			return err
+		}
+		if err := monitors.CheckParams(e.Gate, e.Monitor.Params); err != nil {
+			errs = append(errs, fmt.Errorf("upgrading %s (id %d): %w", e.Monitor.Name, e.Monitor.ID, err))
+			continue
+		}
+		if _, err := client.UpdateMonitor(ctx, e.Monitor.ID, hexagate.MonitorUpdate{Gate: source}); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			errs = append(errs, fmt.Errorf("upgrading %s (id %d): %w", e.Monitor.Name, e.Monitor.ID, err))
+			continue
+		}
+		if onUpgrade != nil {
+			onUpgrade(e)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,213 @@
+package drift_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/drift"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/hexagate/hexagatetest"
+	"github.com/base-org/fault-proof-monitors/monitors"
+)
+
+// repository creates a git repository committing each source in turn to monitors/<name>.gate.
+func repository(t *testing.T, name string, sources ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	run("init", "-q")
+	if err := os.Mkdir(filepath.Join(dir, "monitors"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for i, source := range sources {
+		if err := os.WriteFile(filepath.Join(dir, "monitors", name+".gate"), []byte(source), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		run("add", "-A")
+		run("commit", "-q", "-m", "version "+string(rune('1'+i)))
+	}
+	return dir
+}
+
+func TestCheckAndUpgrade(t *testing.T) {
+	current, err := monitors.Gate("eth_deficit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v1 := "param disputeGame: address;\n"
+	v2 := "param disputeGame: address;\nparam honestChallenger: address;\n"
+	// v1 is restored by the last commit before the current source
+	dir := repository(t, "eth_deficit", v1, v2, v1+"\n", current)
+	ctx := context.Background()
+	history, err := drift.LoadHistory(ctx, dir, []string{"eth_deficit", "challenger_loses"})
+	if err != nil {
+		t.Fatalf("Error loading history: %v", err)
+	}
+
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+	client := server.Client()
+	params := map[string]any{"disputeGame": "0x00000000000000000000000000000000000000B1", "honestChallenger": "0x00000000000000000000000000000000000000C1"}
+	deployWith := func(gate, source string, params map[string]any) {
+		_, err := client.CreateMonitor(ctx, hexagate.Monitor{Name: gate, ChainID: 1, Gate: source, Params: params, Labels: map[string]string{
+			autodeploy.LabelManagedBy: autodeploy.ManagedBy,
+			autodeploy.LabelMonitor:   gate,
+		}}, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	deploy := func(gate, source string) {
+		deployWith(gate, source, params)
+	}
+	deploy("eth_deficit", current)
+	deploy("eth_deficit", v2)
+	deploy("eth_deficit", "\r\n"+strings.ReplaceAll(v1, "\n", "\r\n"))
+	deploy("eth_deficit", "edited")
+	deploy("challenger_loses", "edited")
+	deploy("removed_gate", "edited")
+	// deployed from v1, which declares no honestChallenger
+	deployWith("eth_deficit", v1, map[string]any{"disputeGame": params["disputeGame"]})
+	if _, err := client.CreateMonitor(ctx, hexagate.Monitor{Name: "by hand", ChainID: 1, Gate: current}, ""); err != nil {
+		t.Fatal(err)
+	}
+	// deployed from v2 without the monitor label, which is found by its source
+	if _, err := client.CreateMonitor(ctx, hexagate.Monitor{Name: "copy", ChainID: 1, Gate: v2, Params: params, Labels: map[string]string{
+		autodeploy.LabelManagedBy: autodeploy.ManagedBy,
+	}}, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := drift.Check(ctx, client, history, hexagate.ListOptions{})
+	if err != nil {
+		t.Fatalf("Error checking drift: %v", err)
+	}
+	want := []struct {
+		gate   string
+		status drift.Status
+		behind int
+	}{
+		{"", drift.Unmanaged, 0},
+		{"challenger_loses", drift.Edited, 0},
+		{"eth_deficit", drift.Current, 0},
+		{"eth_deficit", drift.Stale, 2},
+		{"eth_deficit", drift.Stale, 1},
+		{"eth_deficit", drift.Edited, 0},
+		{"eth_deficit", drift.Incompatible, 1},
+		{"eth_deficit", drift.Stale, 2},
+	}
+	if len(report.Entries) != len(want) {
+		t.Fatalf("expected %d monitors, got %+v", len(want), report.Entries)
+	}
+	for i, w := range want {
+		e := report.Entries[i]
+		if e.Gate != w.gate || e.Status != w.status || e.Behind != w.behind {
+			t.Errorf("monitor %d: expected %s %s %d versions behind, got %s %s %d", e.Monitor.ID, w.gate, w.status, w.behind, e.Gate, e.Status, e.Behind)
+		}
+	}
+	if e := report.Entries[4]; e.Revision == nil || e.Revision.Subject != "version 3" {
+		t.Errorf("expected the restored source to map to its newest revision, got %+v", e.Revision)
+	}
+	var out bytes.Buffer
+	if err := report.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	if e := report.Entries[6]; e.ParamsErr == nil || !strings.Contains(out.String(), "incompatible eth_deficit (id 7)") {
+		t.Errorf("expected the monitor missing a param to be reported as incompatible, got %+v", e)
+	}
+	if e := report.Entries[7]; !e.Matched || !strings.Contains(out.String(), "unlabeled copy (id 9): runs a version of eth_deficit.gate") {
+		t.Errorf("expected the unlabeled monitor to be matched by its source, got %+v", e)
+	}
+	if !strings.Contains(out.String(), "unmanaged removed_gate (id 6)") {
+		t.Errorf("expected the monitor of a removed gate file to be reported as unmanaged\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "8 monitors: 1 current, 3 stale, 2 edited, 1 incompatible, 1 unmanaged.") {
+		t.Errorf("unexpected report\n%s", out.String())
+	}
+
+	server.FailNext(500)
+	if err := report.Upgrade(ctx, client, false, nil); err == nil {
+		t.Fatal("expected the failed upgrade to be reported")
+	}
+	if report, err = drift.Check(ctx, client, history, hexagate.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if report.Count(drift.Stale) != 1 || report.Count(drift.Edited) != 2 {
+		t.Fatalf("expected the other stale monitor to be upgraded, got %+v", report.Entries)
+	}
+	if err := report.Upgrade(ctx, client, true, nil); err != nil {
+		t.Fatalf("Error upgrading: %v", err)
+	}
+	if report, err = drift.Check(ctx, client, history, hexagate.ListOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if report.Count(drift.Current) != 6 || report.Count(drift.Incompatible) != 1 || report.Count(drift.Unmanaged) != 1 {
+		t.Errorf("expected every compatible monitor to be upgraded, got %+v", report.Entries)
+	}
+	for _, m := range server.Monitors() {
+		if m.Name == "removed_gate" && m.Gate != "edited" {
+			t.Error("expected the monitor of a removed gate file to be left alone")
+		}
+		if m.ID == 7 && m.Gate != v1 {
+			t.Error("expected the incompatible monitor to be left alone")
+		}
+	}
+
+	// a report built without Check still has the params checked
+	stale := drift.Report{Entries: []drift.Entry{{Monitor: *monitorByID(t, server, 7), Gate: "eth_deficit", Status: drift.Stale}}}
+	if err := stale.Upgrade(ctx, client, false, nil); err == nil || !strings.Contains(err.Error(), "honestChallenger") {
+		t.Errorf("expected the upgrade of a monitor missing a param to fail, got %v", err)
+	}
+}
+
+func monitorByID(t *testing.T, server *hexagatetest.Server, id int64) *hexagate.Monitor {
+	t.Helper()
+	for _, m := range server.Monitors() {
+		if m.ID == id {
+			return &m
+		}
+	}
+	t.Fatalf("no monitor %d", id)
+	return nil
+}
+
+func TestUncommittedGate(t *testing.T) {
+	// the gate file of this build was never committed, so every committed version is behind it
+	dir := repository(t, "eth_deficit", "a", "b")
+	ctx := context.Background()
+	history, err := drift.LoadHistory(ctx, dir, []string{"eth_deficit"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+	client := server.Client()
+	_, err = client.CreateMonitor(ctx, hexagate.Monitor{Name: "eth_deficit", ChainID: 1, Gate: "a", Params: map[string]any{"disputeGame": "0x00", "honestChallenger": "0x00"}, Labels: map[string]string{
+		autodeploy.LabelManagedBy: autodeploy.ManagedBy,
+		autodeploy.LabelMonitor:   "eth_deficit",
+	}}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := drift.Check(ctx, client, history, hexagate.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Entries) != 1 || report.Entries[0].Status != drift.Stale || report.Entries[0].Behind != 2 {
+		t.Errorf("expected the monitor to be 2 versions behind, got %+v", report.Entries)
+	}
+}
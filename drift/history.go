@@ -0,0 +1,124 @@
+// Package drift finds the deployed monitors whose gate source differs from the gate files of the repository. The
+// source of every deployed monitor is hashed and looked up in the git history of its gate file, so that a monitor is
+// reported as current, as running the version of an older revision, or as edited outside of the repository.
+package drift
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Revision is a commit that changed a gate file.
+type Revision struct {
+	Commit  string
+	Date    time.Time
+	Subject string
+}
+
+// Short returns the abbreviated commit hash.
+func (r Revision) Short() string {
+	if len(r.Commit) > 8 {
+		return r.Commit[:8]
+	}
+	return r.Commit
+}
+
+// Version is a source of a gate file, with the revisions that committed it, newest first. A source restored by a
+// revert has several revisions.
+type Version struct {
+	Hash      string
+	Revisions []Revision
+}
+
+// History holds the versions of the gate files, newest first.
+type History struct {
+	names    []string
+	versions map[string][]Version
+}
+
+// Hash returns the hash identifying a gate source. Line endings and surrounding whitespace are ignored, since the
+// API does not return the source byte for byte.
+func Hash(source string) string {
+	source = strings.TrimSpace(strings.ReplaceAll(source, "\r\n", "\n"))
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoadHistory reads the versions of the gate files names from the git repository at dir, from every commit that
+// changed monitors/<name>.gate. Renames are not followed: the monitors are labeled with the name of their gate file,
+// so the sources of a gate file committed under another name are those of another monitor.
+func LoadHistory(ctx context.Context, dir string, names []string) (*History, error) {
+	h := &History{names: names, versions: make(map[string][]Version)}
+	for _, name := range names {
+		path := "monitors/" + name + ".gate"
+		out, err := git(ctx, dir, "log", "--format=%H%x09%cI%x09%s", "--", path)
+		if err != nil {
+			return nil, err
+		}
+		index := make(map[string]int)
+		for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+			if line == "" {
+				continue
+			}
+			fields := strings.SplitN(line, "\t", 3)
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("unexpected git log line %q", line)
+			}
+			date, err := time.Parse(time.RFC3339, fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("commit %s: %w", fields[0], err)
+			}
+			rev := Revision{Commit: fields[0], Date: date, Subject: fields[2]}
+			// the commits deleting the file have no source
+			source, err := git(ctx, dir, "show", rev.Commit+":"+path)
+			if err != nil {
+				continue
+			}
+			hash := Hash(source)
+			if i, ok := index[hash]; ok {
+				h.versions[name][i].Revisions = append(h.versions[name][i].Revisions, rev)
+				continue
+			}
+			index[hash] = len(h.versions[name])
+			h.versions[name] = append(h.versions[name], Version{Hash: hash, Revisions: []Revision{rev}})
+		}
+	}
+	return h, nil
+}
+
+// Lookup returns the version of the gate file name with the given hash, and the number of newer versions.
+func (h *History) Lookup(name, hash string) (Version, int, bool) {
+	for i, v := range h.versions[name] {
+		if v.Hash == hash {
+			return v, i, true
+		}
+	}
+	return Version{}, 0, false
+}
+
+// Find returns the gate file with a version of the given hash, among those of the history in order, along with the
+// version and the number of newer versions.
+func (h *History) Find(hash string) (string, Version, int, bool) {
+	for _, name := range h.names {
+		if v, newer, ok := h.Lookup(name, hash); ok {
+			return name, v, newer, true
+		}
+	}
+	return "", Version{}, 0, false
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
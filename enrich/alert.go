@@ -0,0 +1,126 @@
+package enrich
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/monitors"
+)
+
+// ErrUnknownAlert is returned for payloads that name no monitor of this repository and hold no description of one of
+// its invariants.
+var ErrUnknownAlert = errors.New("alert of no known monitor")
+
+// gameKeys are the keys holding the dispute game an alert is about: the param of the per dispute game monitors, and
+// the keys of the decoded events of the factory monitors.
+var gameKeys = map[string]bool{"disputegame": true, "game": true, "disputeproxy": true, "disputegameaddress": true}
+
+// invariants maps the description of every invariant of the gate files to the monitor declaring it.
+var invariants = func() map[string]string {
+	m := make(map[string]string)
+	for _, name := range monitors.Names() {
+		descriptions, _ := monitors.Invariants(name)
+		for _, d := range descriptions {
+			m[d] = name
+		}
+	}
+	return m
+}()
+
+// Alert is the part of a Hexagate alert webhook payload the enrichment is based on.
+type Alert struct {
+	MonitorID int64 `json:"monitorId,omitempty"`
+	// Name is the name of the monitor that alerted, such as "challenger_loses 0x..." for a per dispute game monitor.
+	Name string `json:"name,omitempty"`
+	// Monitor is the gate file the monitor runs, without the extension.
+	Monitor string `json:"monitor"`
+	// Failures are the descriptions of the failed invariants, in the order they appear.
+	Failures []string `json:"failures"`
+	// Game is the dispute game the alert is about, zero if the payload does not name one.
+	Game eth.Address `json:"game"`
+}
+
+// ParseAlert decodes an alert webhook payload. The payload is walked as a whole, so both the alerts of a single
+// invariant and the batches of failed invariants are understood: the monitor is the gate file named by the monitor
+// name, as the monitors deployed by autodeploy are named, or else the one declaring the failed invariants. The game
+// is the one named by the monitor name, a disputeGame param or the decoded event, in that order.
+func ParseAlert(payload []byte) (*Alert, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("decoding webhook payload: %w", err)
+	}
+	alert := &Alert{}
+	var games []eth.Address
+	seen := make(map[string]bool)
+	var visit func(key string, v any)
+	visit = func(key string, v any) {
+		switch v := v.(type) {
+		case string:
+			if _, ok := invariants[v]; ok && !seen[v] {
+				seen[v] = true
+				alert.Failures = append(alert.Failures, v)
+			}
+			if gameKeys[strings.ToLower(key)] {
+				if game, err := eth.ParseAddress(v); err == nil && game != (eth.Address{}) {
+					games = append(games, game)
+				}
+			}
+			if key == "monitor_name" && alert.Name == "" {
+				alert.Name = v
+			}
+		case map[string]any:
+			if id, ok := v["monitor_id"].(json.Number); ok && alert.MonitorID == 0 {
+				alert.MonitorID, _ = id.Int64()
+			}
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				visit(k, v[k])
+			}
+		case []any:
+			for _, item := range v {
+				visit(key, item)
+			}
+		}
+	}
+	visit("", v)
+
+	fields := strings.Fields(alert.Name)
+	if len(fields) > 0 {
+		if _, err := monitors.Gate(fields[0]); err == nil {
+			alert.Monitor = fields[0]
+		}
+	}
+	if len(fields) > 1 {
+		if game, err := eth.ParseAddress(fields[1]); err == nil {
+			alert.Game = game
+		}
+	}
+	if alert.Monitor == "" && len(alert.Failures) > 0 {
+		alert.Monitor = invariants[alert.Failures[0]]
+	}
+	if alert.Monitor == "" {
+		return nil, ErrUnknownAlert
+	}
+	if alert.Game == (eth.Address{}) && len(games) > 0 {
+		alert.Game = games[0]
+	}
+	if alert.Game == (eth.Address{}) {
+		// the raw DisputeGameCreated logs of the factory monitors
+		if created, err := autodeploy.CreatedGames(payload); err == nil {
+			alert.Game = created[0]
+		}
+	}
+	return alert, nil
+}
@@ -0,0 +1,166 @@
+// Package enrich turns the alerts of the monitors into incidents on-call can act on. A Hexagate alert only carries
+// the descriptions of the failed invariants; the incident adds the context of the dispute game the alert is about,
+// read from the chain and the indexer: its type, root claim, L2 block, claims and resolution status, the claims and
+// clocks of the honest challenger and the bonds held by DelayedWETH. It links the runbook of the monitor in docs/.
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/indexer"
+	"github.com/base-org/fault-proof-monitors/monitor"
+)
+
+// DefaultRunbookURL is the root the runbook paths are resolved against by default.
+const DefaultRunbookURL = "https://github.com/base-org/fault-proof-monitors/blob/main/"
+
+// runbooks are the runbooks in docs/ of the monitors that do not have one of their own name.
+var runbooks = map[string]string{
+	"fault_proof_detection_parent": "docs/fault_proof_detection_parent_and_child.md#fault-proof-detection-parent",
+	"fault_proof_detection_child":  "docs/fault_proof_detection_parent_and_child.md#fault-proof-detection-child",
+}
+
+// Runbook returns the path of the runbook of monitor, relative to the root of the repository.
+func Runbook(monitor string) string {
+	if path, ok := runbooks[monitor]; ok {
+		return path
+	}
+	return "docs/" + monitor + ".md"
+}
+
+// Config configures an Enricher.
+type Config struct {
+	// ChainID is the chain the games are created on, reported in the incidents.
+	ChainID uint64
+	// Chain is read for the context of the games.
+	Chain monitor.Chain
+	// Index, if set, provides the claims of the games it holds, which are otherwise read one call each.
+	Index *indexer.Store
+	// HonestChallenger selects the claims reported. It is read from the params of the monitor when Client is set.
+	HonestChallenger eth.Address
+	// Client, if set, looks up the monitor of an alert by its ID, for the game and the honest challenger it was
+	// deployed with.
+	Client *hexagate.Client
+	// RunbookURL is the root the runbook paths are resolved against, DefaultRunbookURL if empty.
+	RunbookURL string
+	// Forward, if set, is called with every incident, such as to post it to the paging service. An error fails the
+	// webhook, so that Hexagate delivers the alert again.
+	Forward func(context.Context, Incident) error
+	// OnError is called with the errors the webhook handler answers with.
+	OnError func(error)
+}
+
+// Incident is an alert with the context needed to act on it.
+type Incident struct {
+	// Key identifies the incident, for the paging service to group the deliveries of the same alert.
+	Key       string    `json:"key"`
+	Summary   string    `json:"summary"`
+	Monitor   string    `json:"monitor"`
+	MonitorID int64     `json:"monitorId,omitempty"`
+	ChainID   uint64    `json:"chainId"`
+	Failures  []string  `json:"failures"`
+	Runbook   string    `json:"runbook"`
+	Game      *Game     `json:"game,omitempty"`
+	Received  time.Time `json:"received"`
+	// Errors are the parts of the context that could not be read. The incident is raised without them.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Enricher turns alerts into incidents.
+type Enricher struct {
+	cfg Config
+}
+
+// New returns an Enricher reading the games from cfg.Chain.
+func New(cfg Config) (*Enricher, error) {
+	if cfg.Chain == nil {
+		return nil, fmt.Errorf("no chain to read the games from")
+	}
+	if cfg.RunbookURL == "" {
+		cfg.RunbookURL = DefaultRunbookURL
+	}
+	return &Enricher{cfg: cfg}, nil
+}
+
+// Enrich returns the incident of alert. Context that cannot be read is listed in the errors of the incident rather
+// than failing it, since an alert must page even when the chain cannot be read.
+func (e *Enricher) Enrich(ctx context.Context, alert *Alert) Incident {
+	inc := Incident{
+		Monitor:   alert.Monitor,
+		MonitorID: alert.MonitorID,
+		ChainID:   e.cfg.ChainID,
+		Failures:  alert.Failures,
+		Received:  time.Now().UTC(),
+	}
+	if inc.Failures == nil {
+		inc.Failures = []string{}
+	}
+	game, honest := alert.Game, e.cfg.HonestChallenger
+	if e.cfg.Client != nil && alert.MonitorID != 0 {
+		if m, err := e.cfg.Client.GetMonitor(ctx, alert.MonitorID); err != nil {
+			inc.Errors = append(inc.Errors, fmt.Sprintf("looking up monitor %d: %v", alert.MonitorID, err))
+		} else {
+			game, honest = monitorParams(m, game, honest)
+		}
+	}
+	inc.Runbook = strings.TrimSuffix(e.cfg.RunbookURL, "/") + "/" + Runbook(inc.Monitor)
+
+	if game != (eth.Address{}) {
+		g, err := ReadGame(ctx, e.cfg.Chain, e.cfg.Index, game, honest)
+		if err != nil {
+			inc.Errors = append(inc.Errors, fmt.Sprintf("reading game %s: %v", game, err))
+			g = &Game{Address: game, HonestChallenger: honest}
+		}
+		inc.Game = g
+	}
+	inc.Key = key(inc.ChainID, inc.Monitor, game)
+	inc.Summary = summary(inc)
+	return inc
+}
+
+// monitorParams returns the game and the honest challenger of the params of m, or else the given ones.
+func monitorParams(m *hexagate.Monitor, game, honest eth.Address) (eth.Address, eth.Address) {
+	if game == (eth.Address{}) {
+		s, _ := m.Params["disputeGame"].(string)
+		if s == "" {
+			s = m.Labels[autodeploy.LabelGame]
+		}
+		if a, err := eth.ParseAddress(s); err == nil {
+			game = a
+		}
+	}
+	if s, ok := m.Params["honestChallenger"].(string); ok {
+		if a, err := eth.ParseAddress(s); err == nil {
+			honest = a
+		}
+	}
+	return game, honest
+}
+
+// key identifies the incidents of a monitor for a game, so the deliveries of the same alert page once.
+func key(chainID uint64, monitor string, game eth.Address) string {
+	h := eth.Keccak256([]byte(fmt.Sprintf("%d/%s/%s", chainID, monitor, game)))
+	return monitor + "-" + eth.EncodeHex(h[:8])[2:]
+}
+
+func summary(inc Incident) string {
+	var b strings.Builder
+	b.WriteString(inc.Monitor)
+	if len(inc.Failures) > 0 {
+		b.WriteString(": " + strings.Join(inc.Failures, "; "))
+	}
+	if g := inc.Game; g != nil {
+		fmt.Fprintf(&b, " (game %s", g.Address)
+		if g.Status != "" {
+			fmt.Fprintf(&b, ", %s, L2 block %d, %d claims", g.Status, g.L2BlockNumber, g.ClaimCount)
+		}
+		b.WriteString(")")
+	}
+	return b.String()
+}
@@ -0,0 +1,307 @@
+package enrich_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/enrich"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/follower/chaintest"
+	"github.com/base-org/fault-proof-monitors/game"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/hexagate/hexagatetest"
+	"github.com/base-org/fault-proof-monitors/indexer"
+	"github.com/base-org/fault-proof-monitors/monitors"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+var (
+	gameType         = abi.MustParseMethod("gameType() returns (uint32 gameType_)")
+	rootClaim        = abi.MustParseMethod("rootClaim() returns (bytes32 rootClaim_)")
+	l2BlockNumber    = abi.MustParseMethod("l2BlockNumber() returns (uint256 l2BlockNumber_)")
+	createdAt        = abi.MustParseMethod("createdAt() returns (uint64)")
+	status           = abi.MustParseMethod("status() returns (uint8)")
+	resolvedAt       = abi.MustParseMethod("resolvedAt() returns (uint256)")
+	maxClockDuration = abi.MustParseMethod("maxClockDuration() returns (uint256)")
+	weth             = abi.MustParseMethod("weth() returns (address)")
+	delay            = abi.MustParseMethod("delay() returns (uint256)")
+	balanceOf        = abi.MustParseMethod("balanceOf(address) returns (uint256)")
+
+	game1       = eth.MustAddress("0x00000000000000000000000000000000000000b1")
+	delayedWETH = eth.MustAddress("0x00000000000000000000000000000000000000D0")
+	honest      = eth.MustAddress("0x00000000000000000000000000000000000000A2")
+	attacker    = eth.MustAddress("0x00000000000000000000000000000000000000E1")
+	root        = eth.MustHash("0x00000000000000000000000000000000000000000000000000000000000000aa")
+)
+
+// testGame is game1, created at 1000 with an invalid root claim, attacked by the honest challenger at 1100 and
+// defended by the attacker at 1300.
+type testGame struct {
+	tree      *game.Game
+	claimants []eth.Address
+}
+
+func newTestGame(t *testing.T) *testGame {
+	t.Helper()
+	g := &testGame{
+		tree:      game.NewGame(game.Config{MaxClockDuration: 302400, SplitDepth: 30, MaxGameDepth: 73}, 1000),
+		claimants: []eth.Address{attacker, honest, attacker},
+	}
+	for _, move := range []struct {
+		parent int
+		at     uint64
+	}{{0, 1100}, {1, 1300}} {
+		if _, err := g.tree.Move(move.parent, true, move.at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return g
+}
+
+func (g *testGame) claim(i int) abi.Claim {
+	value := root
+	if i > 0 {
+		value = eth.Hash{byte(i)}
+	}
+	return abi.NewClaim(g.tree.Claims[i], g.claimants[i], big.NewInt(int64(i+1)*1e17), value)
+}
+
+func (g *testGame) handle(msg rpc.CallMsg, header *eth.Header) ([]byte, error) {
+	selector := msg.Data[:4]
+	is := func(contract eth.Address, method abi.Method) bool {
+		return msg.To == contract && bytes.Equal(selector, method.Selector())
+	}
+	switch {
+	case is(game1, gameType):
+		return gameType.PackOutputs(big.NewInt(0))
+	case is(game1, rootClaim):
+		return rootClaim.PackOutputs(root)
+	case is(game1, l2BlockNumber):
+		return l2BlockNumber.PackOutputs(big.NewInt(21000000))
+	case is(game1, createdAt):
+		return createdAt.PackOutputs(big.NewInt(1000))
+	case is(game1, status):
+		return status.PackOutputs(new(big.Int))
+	case is(game1, resolvedAt):
+		return resolvedAt.PackOutputs(new(big.Int))
+	case is(game1, maxClockDuration):
+		return maxClockDuration.PackOutputs(big.NewInt(302400))
+	case is(game1, abi.ClaimDataLen):
+		return abi.ClaimDataLen.PackOutputs(big.NewInt(int64(len(g.tree.Claims))))
+	case is(game1, abi.ClaimData):
+		values, err := abi.ClaimData.UnpackInputs(msg.Data)
+		if err != nil {
+			return nil, err
+		}
+		return g.claim(int(values[0].(*big.Int).Int64())).Encode()
+	case is(game1, weth):
+		return weth.PackOutputs(delayedWETH)
+	case is(delayedWETH, delay):
+		return delay.PackOutputs(big.NewInt(302400))
+	case is(delayedWETH, balanceOf):
+		return balanceOf.PackOutputs(big.NewInt(6e17))
+	case is(delayedWETH, abi.Withdrawals):
+		return abi.Withdrawals.PackOutputs(new(big.Int), new(big.Int))
+	}
+	return nil, &rpc.Error{Code: 3, Message: "execution reverted"}
+}
+
+func TestParseAlert(t *testing.T) {
+	alert, err := enrich.ParseAlert([]byte(`{"monitor_id": 7, "monitor_name": "challenger_loses ` + game1.String() +
+		`", "failed": [{"description": "Challenger lost one or more subgames"}, {"description": "Challenger lost one or more subgames"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alert.MonitorID != 7 || alert.Monitor != "challenger_loses" || alert.Game != game1 ||
+		len(alert.Failures) != 1 || alert.Failures[0] != "Challenger lost one or more subgames" {
+		t.Errorf("unexpected alert %+v", alert)
+	}
+
+	// a monitor named by hand is recognized by its invariants, and the game by its param
+	alert, err = enrich.ParseAlert([]byte(`{"monitor_name": "mainnet eth deficit", "failed": [{"description": ` +
+		`"Deficit of ETH in DelayedWETH contract", "context": {"disputeGame": "` + game1.String() + `"}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alert.Monitor != "eth_deficit" || alert.Game != game1 {
+		t.Errorf("unexpected alert %+v", alert)
+	}
+
+	if _, err := enrich.ParseAlert([]byte(`{"monitor_name": "other", "failed": [{"description": "other"}]}`)); !errors.Is(err, enrich.ErrUnknownAlert) {
+		t.Errorf("expected ErrUnknownAlert, got %v", err)
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	chain := chaintest.NewChain(1000)
+	chain.Extend(500)
+	chain.HandleCall(newTestGame(t).handle)
+	var forwarded []enrich.Incident
+	e, err := enrich.New(enrich.Config{
+		ChainID:          1,
+		Chain:            chain,
+		HonestChallenger: honest,
+		Forward: func(_ context.Context, inc enrich.Incident) error {
+			forwarded = append(forwarded, inc)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(`{"monitor_id": 7, "monitor_name": "challenger_loses `+
+		game1.String()+`", "failed": [{"description": "Challenger lost one or more subgames"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var inc enrich.Incident
+	if err := json.NewDecoder(resp.Body).Decode(&inc); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected response %s: %v", resp.Status, err)
+	}
+	if len(forwarded) != 1 || forwarded[0].Key != inc.Key {
+		t.Errorf("expected the incident to be forwarded once, got %+v", forwarded)
+	}
+	if len(inc.Errors) > 0 {
+		t.Fatalf("unexpected errors %v", inc.Errors)
+	}
+	if !strings.HasSuffix(inc.Runbook, "/docs/challenger_loses.md") {
+		t.Errorf("unexpected runbook %s", inc.Runbook)
+	}
+	g := inc.Game
+	if g == nil || g.Address != game1 || g.RootClaim != root || g.L2BlockNumber != 21000000 || g.ClaimCount != 3 ||
+		g.Status != "IN_PROGRESS" || g.Timestamp != 2000 || g.Indexed {
+		t.Fatalf("unexpected game %+v", g)
+	}
+	if g.DelayedWETH.Address != delayedWETH || g.DelayedWETH.Delay != 302400 || g.DelayedWETH.Balance.Int().Int64() != 6e17 {
+		t.Errorf("unexpected DelayedWETH %+v", g.DelayedWETH)
+	}
+	// the attack of the honest challenger was made at 1100, 100s after the root claim, and the challenger duration
+	// counts from then like getChallengerDuration, even though the attack was countered since
+	want := enrich.Claim{
+		Index:              1,
+		Depth:              1,
+		Value:              eth.Hash{1},
+		Bond:               eth.NewBig(big.NewInt(2e17)),
+		Counters:           1,
+		ClockStartedAt:     1100,
+		ClockDuration:      100,
+		ChallengerDuration: 900,
+		ResolvableAt:       1100 + 302400,
+	}
+	if len(g.HonestClaims) != 1 || !claimEqual(g.HonestClaims[0], want) {
+		t.Errorf("expected honest claims %+v, got %+v", want, g.HonestClaims)
+	}
+
+	resp, err = http.Post(server.URL, "application/json", strings.NewReader(`{"monitor_name": "other"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected an unknown alert to be rejected, got %s", resp.Status)
+	}
+}
+
+func claimEqual(a, b enrich.Claim) bool {
+	bondsEqual := a.Bond.Int().Cmp(b.Bond.Int()) == 0
+	a.Bond, b.Bond = nil, nil
+	return bondsEqual && a == b
+}
+
+// TestEnrichFromMonitor checks that the game and the honest challenger are looked up from the monitor of an alert that
+// does not name them, and that the claims are read from the indexer when it holds the game.
+func TestEnrichFromMonitor(t *testing.T) {
+	api := hexagatetest.NewServer("key")
+	defer api.Close()
+	ctx := context.Background()
+	m, err := api.Client().CreateMonitor(ctx, hexagate.Monitor{
+		Name:    "unresolvable",
+		ChainID: 1,
+		Gate:    "gate",
+		Params:  map[string]any{"disputeGame": game1.String(), "honestChallenger": honest.String()},
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tg := newTestGame(t)
+	indexed := indexer.Game{Address: game1, GameType: 1, RootClaim: root, CreatedAt: 1000, Status: indexer.InProgress}
+	for i := range tg.tree.Claims {
+		c := tg.claim(i)
+		indexed.Claims = append(indexed.Claims, indexer.Claim{
+			ParentIndex: c.ParentIndex,
+			Claimant:    c.Claimant,
+			Bond:        eth.NewBig(c.Bond),
+			Value:       c.Value,
+			Position:    eth.NewBig(c.Position),
+			Clock:       eth.NewBig(c.Clock),
+		})
+	}
+	dir := t.TempDir()
+	data, err := json.Marshal(indexed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "games"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "games", game1.String()+".json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	index, err := indexer.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := chaintest.NewChain(1000)
+	chain.Extend(500)
+	chain.HandleCall(tg.handle)
+	e, err := enrich.New(enrich.Config{ChainID: 1, Chain: chain, Index: index, Client: api.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inc := e.Enrich(ctx, &enrich.Alert{MonitorID: m.ID, Monitor: "unresolvable_dispute_game", Failures: []string{"Dispute game is unresolved"}})
+	if len(inc.Errors) > 0 {
+		t.Fatalf("unexpected errors %v", inc.Errors)
+	}
+	g := inc.Game
+	if g == nil || !g.Indexed || g.GameType != 1 || g.ClaimCount != 3 || len(g.HonestClaims) != 1 || g.HonestChallenger != honest {
+		t.Fatalf("unexpected game %+v", g)
+	}
+	if !strings.Contains(inc.Summary, "unresolvable_dispute_game: Dispute game is unresolved (game "+game1.String()) {
+		t.Errorf("unexpected summary %q", inc.Summary)
+	}
+
+	// an unreadable game is reported in the incident rather than failing it
+	chain.HandleCall(func(rpc.CallMsg, *eth.Header) ([]byte, error) {
+		return nil, &rpc.Error{Code: -32000, Message: "unavailable"}
+	})
+	inc = e.Enrich(ctx, &enrich.Alert{MonitorID: m.ID, Monitor: "unresolvable_dispute_game"})
+	if len(inc.Errors) != 1 || inc.Game == nil || inc.Game.Address != game1 {
+		t.Errorf("expected the game to be reported unreadable, got %+v", inc)
+	}
+}
+
+// TestRunbooks checks that every monitor links a runbook of docs/.
+func TestRunbooks(t *testing.T) {
+	for _, name := range monitors.Names() {
+		path, _, _ := strings.Cut(enrich.Runbook(name), "#")
+		if _, err := os.Stat(filepath.Join("..", path)); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package enrich
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/game"
+	"github.com/base-org/fault-proof-monitors/indexer"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+var (
+	gameTypeMethod         = abi.MustParseMethod("gameType() returns (uint32 gameType_)")
+	rootClaimMethod        = abi.MustParseMethod("rootClaim() returns (bytes32 rootClaim_)")
+	l2BlockNumberMethod    = abi.MustParseMethod("l2BlockNumber() returns (uint256 l2BlockNumber_)")
+	createdAtMethod        = abi.MustParseMethod("createdAt() returns (uint64)")
+	statusMethod           = abi.MustParseMethod("status() returns (uint8)")
+	resolvedAtMethod       = abi.MustParseMethod("resolvedAt() returns (uint256)")
+	maxClockDurationMethod = abi.MustParseMethod("maxClockDuration() returns (uint256)")
+	wethMethod             = abi.MustParseMethod("weth() returns (address)")
+	delayMethod            = abi.MustParseMethod("delay() returns (uint256)")
+	balanceOfMethod        = abi.MustParseMethod("balanceOf(address) returns (uint256)")
+)
+
+// Game is the state of the dispute game an alert is about, as of the block it was read at.
+type Game struct {
+	Address       eth.Address `json:"address"`
+	GameType      uint32      `json:"gameType"`
+	RootClaim     eth.Hash    `json:"rootClaim"`
+	L2BlockNumber uint64      `json:"l2BlockNumber"`
+	ClaimCount    int         `json:"claimCount"`
+	// Status is the resolution status of the game: IN_PROGRESS, CHALLENGER_WINS or DEFENDER_WINS.
+	Status     string `json:"status"`
+	CreatedAt  uint64 `json:"createdAt"`
+	ResolvedAt uint64 `json:"resolvedAt,omitempty"`
+	// ResolvableAt is the earliest time the game can be resolved if no further moves are made.
+	ResolvableAt     uint64 `json:"resolvableAt"`
+	MaxClockDuration uint64 `json:"maxClockDuration"`
+
+	HonestChallenger eth.Address `json:"honestChallenger"`
+	// HonestClaims are the claims made by the honest challenger, empty when it is not configured.
+	HonestClaims []Claim     `json:"honestClaims"`
+	DelayedWETH  DelayedWETH `json:"delayedWETH"`
+
+	// Block and Timestamp are those of the block the game was read at.
+	Block     uint64 `json:"block"`
+	Timestamp uint64 `json:"timestamp"`
+	// Indexed reports whether the claims were read from the indexer rather than from the chain.
+	Indexed bool `json:"indexed"`
+}
+
+// Claim is a claim of the game, with its clocks as of the block the game was read at.
+type Claim struct {
+	Index       int      `json:"index"`
+	ParentIndex uint32   `json:"parentIndex"`
+	Depth       uint64   `json:"depth"`
+	Value       eth.Hash `json:"value"`
+	Bond        *eth.Big `json:"bond"`
+	// Counters is the number of moves made against the claim.
+	Counters int `json:"counters"`
+	// ClockStartedAt and ClockDuration are the clock of the claim: when it was made, and the time its team had used.
+	ClockStartedAt uint64 `json:"clockStartedAt"`
+	ClockDuration  uint64 `json:"clockDuration"`
+	// ChallengerDuration is the time used by the team countering the claim, at most the max clock duration.
+	ChallengerDuration uint64 `json:"challengerDuration"`
+	// ResolvableAt is the earliest time the subgame of the claim can be resolved if it is not countered.
+	ResolvableAt uint64 `json:"resolvableAt"`
+}
+
+// DelayedWETH is the DelayedWETH of the game and the bonds it holds for it.
+type DelayedWETH struct {
+	Address eth.Address `json:"address"`
+	// Delay is the time between the unlock and the withdrawal of a bond, in seconds.
+	Delay uint64 `json:"delay"`
+	// Balance is the ETH held for the game.
+	Balance *eth.Big `json:"balance"`
+	// HonestUnlocked is the credit of the honest challenger unlocked by the game and not withdrawn yet.
+	HonestUnlocked *eth.Big `json:"honestUnlocked,omitempty"`
+}
+
+// ReadGame reads the state of address at the latest block of chain. The claims, the type, the root claim and the
+// status are taken from index when it holds the game, which saves a call per claim; the rest is always read from the
+// chain. honestChallenger selects the claims reported, and may be zero.
+func ReadGame(ctx context.Context, chain monitor.Chain, index *indexer.Store, address, honestChallenger eth.Address) (*Game, error) {
+	header, err := chain.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the latest block: %w", err)
+	}
+	g := &Game{
+		Address:          address,
+		HonestChallenger: honestChallenger,
+		HonestClaims:     []Claim{},
+		Block:            uint64(header.Number),
+		Timestamp:        uint64(header.Timestamp),
+	}
+	number := rpc.NumberAt(g.Block)
+	uint64At := func(contract eth.Address, method abi.Method, args ...any) (uint64, error) {
+		v, err := call(ctx, chain, number, contract, method, args...)
+		if err != nil {
+			return 0, err
+		}
+		return v.(*big.Int).Uint64(), nil
+	}
+
+	var claims []abi.Claim
+	if indexed, ok := indexedGame(index, address); ok {
+		g.Indexed = true
+		g.GameType, g.RootClaim = indexed.GameType, indexed.RootClaim
+		g.CreatedAt, g.ResolvedAt = indexed.CreatedAt, indexed.ResolvedAt
+		g.Status = indexed.Status.String()
+		for _, c := range indexed.Claims {
+			claims = append(claims, abi.Claim{
+				ParentIndex: c.ParentIndex,
+				Claimant:    c.Claimant,
+				Bond:        c.Bond.Int(),
+				Value:       c.Value,
+				Position:    c.Position.Int(),
+				Clock:       c.Clock.Int(),
+			})
+		}
+	} else {
+		if err := readHeader(ctx, chain, number, g); err != nil {
+			return nil, err
+		}
+		if claims, err = readClaims(ctx, chain, number, address); err != nil {
+			return nil, err
+		}
+	}
+	g.ClaimCount = len(claims)
+
+	if g.L2BlockNumber, err = uint64At(address, l2BlockNumberMethod); err != nil {
+		return nil, err
+	}
+	if g.MaxClockDuration, err = uint64At(address, maxClockDurationMethod); err != nil {
+		return nil, err
+	}
+	if err := g.setClaims(claims); err != nil {
+		return nil, err
+	}
+
+	weth, err := call(ctx, chain, number, address, wethMethod)
+	if err != nil {
+		return nil, err
+	}
+	g.DelayedWETH.Address = weth.(eth.Address)
+	if g.DelayedWETH.Delay, err = uint64At(g.DelayedWETH.Address, delayMethod); err != nil {
+		return nil, err
+	}
+	balance, err := call(ctx, chain, number, g.DelayedWETH.Address, balanceOfMethod, address)
+	if err != nil {
+		return nil, err
+	}
+	g.DelayedWETH.Balance = eth.NewBig(balance.(*big.Int))
+	if honestChallenger != (eth.Address{}) {
+		withdrawal, err := callAll(ctx, chain, number, g.DelayedWETH.Address, abi.Withdrawals, address, honestChallenger)
+		if err != nil {
+			return nil, err
+		}
+		g.DelayedWETH.HonestUnlocked = eth.NewBig(withdrawal[0].(*big.Int))
+	}
+	return g, nil
+}
+
+func indexedGame(index *indexer.Store, address eth.Address) (*indexer.Game, bool) {
+	if index == nil {
+		return nil, false
+	}
+	g, ok := index.Game(address)
+	// a game indexed before its first claim was stored is read from the chain
+	return g, ok && len(g.Claims) > 0
+}
+
+// readHeader reads the fields of the game the indexer also stores.
+func readHeader(ctx context.Context, chain monitor.Chain, number rpc.BlockNumber, g *Game) error {
+	gameType, err := call(ctx, chain, number, g.Address, gameTypeMethod)
+	if err != nil {
+		return err
+	}
+	rootClaim, err := call(ctx, chain, number, g.Address, rootClaimMethod)
+	if err != nil {
+		return err
+	}
+	createdAt, err := call(ctx, chain, number, g.Address, createdAtMethod)
+	if err != nil {
+		return err
+	}
+	status, err := call(ctx, chain, number, g.Address, statusMethod)
+	if err != nil {
+		return err
+	}
+	resolvedAt, err := call(ctx, chain, number, g.Address, resolvedAtMethod)
+	if err != nil {
+		return err
+	}
+	g.GameType = uint32(gameType.(*big.Int).Uint64())
+	g.RootClaim = eth.BytesToHash(rootClaim.([]byte))
+	g.CreatedAt = createdAt.(*big.Int).Uint64()
+	g.Status = indexer.Status(status.(*big.Int).Uint64()).String()
+	g.ResolvedAt = resolvedAt.(*big.Int).Uint64()
+	return nil
+}
+
+// readClaims reads every claim of the game.
+func readClaims(ctx context.Context, chain monitor.Chain, number rpc.BlockNumber, address eth.Address) ([]abi.Claim, error) {
+	count, err := call(ctx, chain, number, address, abi.ClaimDataLen)
+	if err != nil {
+		return nil, err
+	}
+	if !count.(*big.Int).IsInt64() {
+		return nil, fmt.Errorf("claim count %s out of range", count)
+	}
+	claims := make([]abi.Claim, count.(*big.Int).Int64())
+	for i := range claims {
+		output, err := callRaw(ctx, chain, number, address, abi.ClaimData, i)
+		if err != nil {
+			return nil, err
+		}
+		if claims[i], err = abi.DecodeClaim(output); err != nil {
+			return nil, err
+		}
+	}
+	return claims, nil
+}
+
+// setClaims computes the clocks of the claims and keeps those of the honest challenger.
+func (g *Game) setClaims(claims []abi.Claim) error {
+	tree := &game.Game{Config: game.Config{MaxClockDuration: g.MaxClockDuration}}
+	counters := make([]int, len(claims))
+	for i, c := range claims {
+		gc, err := c.GameClaim()
+		if err != nil {
+			return fmt.Errorf("claim %d: %w", i, err)
+		}
+		if gc.ParentIndex != game.RootParentIndex && int(gc.ParentIndex) >= i {
+			return fmt.Errorf("claim %d: invalid parent index %d", i, gc.ParentIndex)
+		}
+		if gc.ParentIndex != game.RootParentIndex {
+			counters[gc.ParentIndex]++
+		}
+		tree.Claims = append(tree.Claims, gc)
+	}
+	if len(tree.Claims) > 0 {
+		g.ResolvableAt = tree.ResolvableAt()
+	}
+	if g.HonestChallenger == (eth.Address{}) {
+		return nil
+	}
+	for i, c := range claims {
+		if c.Claimant != g.HonestChallenger {
+			continue
+		}
+		gc := tree.Claims[i]
+		g.HonestClaims = append(g.HonestClaims, Claim{
+			Index:              i,
+			ParentIndex:        gc.ParentIndex,
+			Depth:              gc.Position.Depth(),
+			Value:              c.Value,
+			Bond:               eth.NewBig(c.Bond),
+			Counters:           counters[i],
+			ClockStartedAt:     gc.Clock.Timestamp,
+			ClockDuration:      gc.Clock.Duration,
+			ChallengerDuration: tree.ChallengerDuration(i, g.Timestamp),
+			ResolvableAt:       tree.ClaimResolvableAt(i),
+		})
+	}
+	return nil
+}
+
+// call calls method on contract at number and returns its single output.
+func call(ctx context.Context, chain monitor.Chain, number rpc.BlockNumber, contract eth.Address, method abi.Method, args ...any) (any, error) {
+	values, err := callAll(ctx, chain, number, contract, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return values[0], nil
+}
+
+// callAll calls method on contract at number and returns its outputs.
+func callAll(ctx context.Context, chain monitor.Chain, number rpc.BlockNumber, contract eth.Address, method abi.Method, args ...any) ([]any, error) {
+	output, err := callRaw(ctx, chain, number, contract, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return method.UnpackOutputs(output)
+}
+
+func callRaw(ctx context.Context, chain monitor.Chain, number rpc.BlockNumber, contract eth.Address, method abi.Method, args ...any) ([]byte, error) {
+	calldata, err := method.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := chain.CallContract(ctx, rpc.CallMsg{To: contract, Data: calldata}, number)
+	if err != nil {
+		return nil, fmt.Errorf("calling %s on %s: %w", method.Signature(), contract, err)
+	}
+	return output, nil
+}
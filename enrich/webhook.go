@@ -0,0 +1,87 @@
+package enrich
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxBodySize bounds the webhook payloads read.
+const maxBodySize = 1 << 20
+
+// ServeHTTP receives alert webhooks and answers with their incident, after passing it to Forward. Payloads of no
+// known monitor are rejected with 422, and failures of Forward are answered with 502, so that the delivery is
+// retried.
+func (e *Enricher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	payload, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		e.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	alert, err := ParseAlert(payload)
+	if errors.Is(err, ErrUnknownAlert) {
+		e.fail(w, http.StatusUnprocessableEntity, err)
+		return
+	} else if err != nil {
+		e.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	inc := e.Enrich(r.Context(), alert)
+	if e.cfg.OnError != nil {
+		for _, msg := range inc.Errors {
+			e.cfg.OnError(fmt.Errorf("%s: %s", inc.Key, msg))
+		}
+	}
+	if e.cfg.Forward != nil {
+		if err := e.cfg.Forward(r.Context(), inc); err != nil {
+			e.fail(w, http.StatusBadGateway, fmt.Errorf("forwarding %s: %w", inc.Key, err))
+			return
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(inc)
+}
+
+func (e *Enricher) fail(w http.ResponseWriter, status int, err error) {
+	if e.cfg.OnError != nil {
+		e.cfg.OnError(err)
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// PostJSON returns a Forward posting the incidents as JSON to url with client, http.DefaultClient if nil. Responses
+// other than 2xx are errors.
+func PostJSON(client *http.Client, url string) func(context.Context, Incident) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context, inc Incident) error {
+		body, err := json.Marshal(inc)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(msg))
+		}
+		return nil
+	}
+}
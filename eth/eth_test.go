@@ -0,0 +1,163 @@
+package eth
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestKeccak256(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{input: "", want: "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{input: "abc", want: "0x4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{input: "Transfer(address,address,uint256)", want: "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"},
+		{input: "Approval(address,address,uint256)", want: "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"},
+	}
+	for _, c := range cases {
+		if got := Keccak256([]byte(c.input)).String(); got != c.want {
+			t.Errorf("keccak256(%q): expected %s, got %s", c.input, c.want, got)
+		}
+	}
+}
+
+func TestKeccak256BlockBoundaries(t *testing.T) {
+	// hashing split inputs must match hashing the concatenated input, including around the 136 byte rate
+	data := make([]byte, 3*keccakRate+7)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	for _, size := range []int{0, 1, keccakRate - 1, keccakRate, keccakRate + 1, 2 * keccakRate, len(data)} {
+		want := Keccak256(data[:size])
+		for split := 0; split <= size; split += 17 {
+			if got := Keccak256(data[:split], data[split:size]); got != want {
+				t.Fatalf("size %d split %d: expected %s, got %s", size, split, want, got)
+			}
+		}
+	}
+}
+
+func TestRLP(t *testing.T) {
+	cases := []struct {
+		name string
+		item RLPItem
+		want string
+	}{
+		{name: "empty string", item: RLPString(nil), want: "0x80"},
+		{name: "single byte", item: RLPString([]byte{0x0f}), want: "0x0f"},
+		{name: "short string", item: RLPString([]byte("dog")), want: "0x83646f67"},
+		{name: "zero", item: RLPUint(0), want: "0x80"},
+		{name: "integer", item: RLPUint(1024), want: "0x820400"},
+		{name: "list", item: RLPList(RLPString([]byte("cat")), RLPString([]byte("dog"))), want: "0xc88363617483646f67"},
+		{name: "empty list", item: RLPList(), want: "0xc0"},
+		{
+			name: "long string",
+			item: RLPString([]byte("Lorem ipsum dolor sit amet, consectetur adipisicing elit")),
+			want: "0xb8384c6f72656d20697073756d20646f6c6f722073697420616d65742c20636f6e7365637465747572206164697069736963696e6720656c6974",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			encoded := EncodeRLP(c.item)
+			if got := EncodeHex(encoded); got != c.want {
+				t.Fatalf("expected %s, got %s", c.want, got)
+			}
+			decoded, err := DecodeRLP(encoded)
+			if err != nil {
+				t.Fatalf("Error decoding %s: %v", c.want, err)
+			}
+			if !bytes.Equal(EncodeRLP(decoded), encoded) {
+				t.Errorf("round trip mismatch for %s", c.want)
+			}
+		})
+	}
+
+	// the empty trie root is the hash of the RLP empty string
+	if got := Keccak256(EncodeRLP(RLPString(nil))); got != MustHash("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421") {
+		t.Errorf("unexpected empty trie root %s", got)
+	}
+
+	if _, err := DecodeRLP([]byte{0x83, 0x64}); err == nil {
+		t.Errorf("expected error decoding truncated value")
+	}
+}
+
+func TestJSONTypes(t *testing.T) {
+	var decoded struct {
+		Address Address `json:"address"`
+		Hash    Hash    `json:"hash"`
+		Data    Bytes   `json:"data"`
+		Number  Uint64  `json:"number"`
+		Balance *Big    `json:"balance"`
+	}
+	input := `{"address":"0x4200000000000000000000000000000000000016","hash":"0x01","data":"0xdeadbeef","number":"0x1b4","balance":"0xde0b6b3a7640000"}`
+	if err := json.Unmarshal([]byte(input), &decoded); err != nil {
+		t.Fatalf("Error decoding JSON: %v", err)
+	}
+
+	if decoded.Address != MustAddress("0x4200000000000000000000000000000000000016") {
+		t.Errorf("unexpected address %s", decoded.Address)
+	}
+	if decoded.Hash != BytesToHash([]byte{1}) {
+		t.Errorf("unexpected hash %s", decoded.Hash)
+	}
+	if decoded.Number != 436 {
+		t.Errorf("unexpected number %d", decoded.Number)
+	}
+	if decoded.Balance.Int().Cmp(big.NewInt(1e18)) != 0 {
+		t.Errorf("unexpected balance %s", decoded.Balance)
+	}
+
+	encoded, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatalf("Error encoding JSON: %v", err)
+	}
+	want := `{"address":"0x4200000000000000000000000000000000000016","hash":"0x0000000000000000000000000000000000000000000000000000000000000001","data":"0xdeadbeef","number":"0x1b4","balance":"0xde0b6b3a7640000"}`
+	if string(encoded) != want {
+		t.Errorf("expected %s, got %s", want, encoded)
+	}
+
+	// gate mocks sometimes use shortened addresses, which are left padded
+	short, err := ParseAddress("0x000000000000000000000000000000000000000")
+	if err != nil || short != (Address{}) {
+		t.Errorf("expected zero address, got %s (%v)", short, err)
+	}
+}
+
+func TestHeaderHash(t *testing.T) {
+	// Ethereum mainnet genesis block
+	input := `{
+		"hash": "0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3",
+		"parentHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+		"sha3Uncles": "0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347",
+		"miner": "0x0000000000000000000000000000000000000000",
+		"stateRoot": "0xd7f8974fb5ac78d9ac099b9ad5018bedc2ce0a72dad1827a1709da30580f0544",
+		"transactionsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+		"receiptsRoot": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+		"logsBloom": "0x` + strings.Repeat("00", 256) + `",
+		"difficulty": "0x400000000",
+		"number": "0x0",
+		"gasLimit": "0x1388",
+		"gasUsed": "0x0",
+		"timestamp": "0x0",
+		"extraData": "0x11bbe8db4e347b4e8c937c1c8370e4b5ed33adb3db69cbdb7a38e1e50b1b82fa",
+		"mixHash": "0x0000000000000000000000000000000000000000000000000000000000000000",
+		"nonce": "0x0000000000000042"
+	}`
+	var header Header
+	if err := json.Unmarshal([]byte(input), &header); err != nil {
+		t.Fatalf("Error decoding header: %v", err)
+	}
+	if err := header.Verify(); err != nil {
+		t.Fatalf("Error verifying genesis header: %v", err)
+	}
+
+	header.GasLimit++
+	if err := header.Verify(); err == nil {
+		t.Errorf("expected tampered header to fail verification")
+	}
+}
@@ -0,0 +1,87 @@
+package eth
+
+import (
+	"fmt"
+)
+
+// Header is a block header as returned by `eth_getBlockByNumber`. Fields introduced by later hard forks are
+// pointers and are only part of the header hash when present.
+type Header struct {
+	Hash             Hash    `json:"hash"`
+	ParentHash       Hash    `json:"parentHash"`
+	UncleHash        Hash    `json:"sha3Uncles"`
+	Coinbase         Address `json:"miner"`
+	StateRoot        Hash    `json:"stateRoot"`
+	TransactionsRoot Hash    `json:"transactionsRoot"`
+	ReceiptsRoot     Hash    `json:"receiptsRoot"`
+	LogsBloom        Bytes   `json:"logsBloom"`
+	Difficulty       *Big    `json:"difficulty"`
+	Number           Uint64  `json:"number"`
+	GasLimit         Uint64  `json:"gasLimit"`
+	GasUsed          Uint64  `json:"gasUsed"`
+	Timestamp        Uint64  `json:"timestamp"`
+	ExtraData        Bytes   `json:"extraData"`
+	MixHash          Hash    `json:"mixHash"`
+	Nonce            Bytes   `json:"nonce"`
+
+	BaseFee               *Big    `json:"baseFeePerGas,omitempty"`
+	WithdrawalsRoot       *Hash   `json:"withdrawalsRoot,omitempty"`
+	BlobGasUsed           *Uint64 `json:"blobGasUsed,omitempty"`
+	ExcessBlobGas         *Uint64 `json:"excessBlobGas,omitempty"`
+	ParentBeaconBlockRoot *Hash   `json:"parentBeaconBlockRoot,omitempty"`
+	RequestsHash          *Hash   `json:"requestsHash,omitempty"`
+}
+
+// ComputeHash returns the Keccak-256 hash of the RLP encoded header.
+func (h *Header) ComputeHash() Hash {
+	difficulty := RLPString(nil)
+	if h.Difficulty != nil {
+		difficulty = RLPBig(h.Difficulty.Int())
+	}
+	fields := []RLPItem{
+		RLPString(h.ParentHash[:]),
+		RLPString(h.UncleHash[:]),
+		RLPString(h.Coinbase[:]),
+		RLPString(h.StateRoot[:]),
+		RLPString(h.TransactionsRoot[:]),
+		RLPString(h.ReceiptsRoot[:]),
+		RLPString(h.LogsBloom),
+		difficulty,
+		RLPUint(uint64(h.Number)),
+		RLPUint(uint64(h.GasLimit)),
+		RLPUint(uint64(h.GasUsed)),
+		RLPUint(uint64(h.Timestamp)),
+		RLPString(h.ExtraData),
+		RLPString(h.MixHash[:]),
+		RLPString(h.Nonce),
+	}
+
+	// optional fields are appended in fork order, and each one requires all the previous ones
+	if h.BaseFee != nil {
+		fields = append(fields, RLPBig(h.BaseFee.Int()))
+	}
+	if h.WithdrawalsRoot != nil {
+		fields = append(fields, RLPString(h.WithdrawalsRoot[:]))
+	}
+	if h.BlobGasUsed != nil {
+		fields = append(fields, RLPUint(uint64(*h.BlobGasUsed)))
+	}
+	if h.ExcessBlobGas != nil {
+		fields = append(fields, RLPUint(uint64(*h.ExcessBlobGas)))
+	}
+	if h.ParentBeaconBlockRoot != nil {
+		fields = append(fields, RLPString(h.ParentBeaconBlockRoot[:]))
+	}
+	if h.RequestsHash != nil {
+		fields = append(fields, RLPString(h.RequestsHash[:]))
+	}
+	return Keccak256(EncodeRLP(RLPList(fields...)))
+}
+
+// Verify checks that the reported block hash matches the hash of the header fields.
+func (h *Header) Verify() error {
+	if computed := h.ComputeHash(); computed != h.Hash {
+		return fmt.Errorf("block %d hash mismatch: reported %s, computed %s", h.Number, h.Hash, computed)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package eth
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Bytes is a byte slice that is hex encoded with a 0x prefix in JSON, as used for JSON-RPC data fields.
+type Bytes []byte
+
+// DecodeHex decodes a 0x prefixed hex string. Odd length strings are left padded with a zero nibble.
+func DecodeHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex %q: %w", s, err)
+	}
+	return b, nil
+}
+
+// EncodeHex encodes b as a 0x prefixed hex string.
+func EncodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func (b Bytes) String() string {
+	return EncodeHex(b)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (b Bytes) MarshalText() ([]byte, error) {
+	return []byte(EncodeHex(b)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (b *Bytes) UnmarshalText(text []byte) error {
+	decoded, err := DecodeHex(string(text))
+	if err != nil {
+		return err
+	}
+	*b = decoded
+	return nil
+}
+
+// Uint64 is a JSON-RPC quantity that fits in 64 bits, hex encoded without leading zeros.
+type Uint64 uint64
+
+// MarshalText implements encoding.TextMarshaler.
+func (q Uint64) MarshalText() ([]byte, error) {
+	return []byte("0x" + strconv.FormatUint(uint64(q), 16)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (q *Uint64) UnmarshalText(text []byte) error {
+	s := string(text)
+	if !strings.HasPrefix(s, "0x") {
+		return fmt.Errorf("quantity %q is missing 0x prefix", s)
+	}
+	v, err := strconv.ParseUint(s[2:], 16, 64)
+	if err != nil {
+		return fmt.Errorf("invalid quantity %q: %w", s, err)
+	}
+	*q = Uint64(v)
+	return nil
+}
+
+// Big is an arbitrary precision JSON-RPC quantity, hex encoded without leading zeros.
+type Big big.Int
+
+// NewBig wraps v as a quantity.
+func NewBig(v *big.Int) *Big {
+	return (*Big)(new(big.Int).Set(v))
+}
+
+// Int returns the quantity as a big.Int.
+func (q *Big) Int() *big.Int {
+	return (*big.Int)(q)
+}
+
+func (q *Big) String() string {
+	return (*big.Int)(q).String()
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (q *Big) MarshalText() ([]byte, error) {
+	return []byte("0x" + (*big.Int)(q).Text(16)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (q *Big) UnmarshalText(text []byte) error {
+	s := string(text)
+	if !strings.HasPrefix(s, "0x") {
+		return fmt.Errorf("quantity %q is missing 0x prefix", s)
+	}
+	v, ok := new(big.Int).SetString(s[2:], 16)
+	if !ok {
+		return fmt.Errorf("invalid quantity %q", s)
+	}
+	*q = Big(*v)
+	return nil
+}
@@ -0,0 +1,14 @@
+package eth
+
+// Log is an event log as returned by `eth_getLogs`.
+type Log struct {
+	Address          Address `json:"address"`
+	Topics           []Hash  `json:"topics"`
+	Data             Bytes   `json:"data"`
+	BlockNumber      Uint64  `json:"blockNumber"`
+	BlockHash        Hash    `json:"blockHash"`
+	TransactionHash  Hash    `json:"transactionHash"`
+	TransactionIndex Uint64  `json:"transactionIndex"`
+	LogIndex         Uint64  `json:"logIndex"`
+	Removed          bool    `json:"removed"`
+}
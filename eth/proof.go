@@ -0,0 +1,56 @@
+package eth
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// StorageProof is a single storage slot proof from `eth_getProof`.
+type StorageProof struct {
+	Key   Hash    `json:"key"`
+	Value *Big    `json:"value"`
+	Proof []Bytes `json:"proof"`
+}
+
+// AccountProof is the result of `eth_getProof`.
+type AccountProof struct {
+	Address      Address        `json:"address"`
+	AccountProof []Bytes        `json:"accountProof"`
+	Balance      *Big           `json:"balance"`
+	CodeHash     Hash           `json:"codeHash"`
+	Nonce        Uint64         `json:"nonce"`
+	StorageHash  Hash           `json:"storageHash"`
+	StorageProof []StorageProof `json:"storageProof"`
+}
+
+// Verify checks the account proof against a state root and that the proven account matches the reported nonce,
+// balance, storage hash and code hash.
+func (p *AccountProof) Verify(stateRoot Hash) error {
+	nodes := make([][]byte, len(p.AccountProof))
+	for i, node := range p.AccountProof {
+		nodes[i] = node
+	}
+	key := Keccak256(p.Address[:])
+	value, err := VerifyProof(stateRoot, key[:], nodes)
+	if err != nil {
+		return fmt.Errorf("account proof for %s: %w", p.Address, err)
+	}
+	if value == nil {
+		return fmt.Errorf("account %s does not exist at state root %s", p.Address, stateRoot)
+	}
+
+	balance := RLPString(nil)
+	if p.Balance != nil {
+		balance = RLPBig(p.Balance.Int())
+	}
+	expected := EncodeRLP(RLPList(
+		RLPUint(uint64(p.Nonce)),
+		balance,
+		RLPString(p.StorageHash[:]),
+		RLPString(p.CodeHash[:]),
+	))
+	if !bytes.Equal(value, expected) {
+		return fmt.Errorf("account %s does not match the proven account %s", p.Address, EncodeHex(value))
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package eth
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// RLPItem is a decoded RLP value: either a byte string or a list of items.
+type RLPItem struct {
+	Bytes  []byte
+	List   []RLPItem
+	IsList bool
+}
+
+// RLPString returns a byte string item.
+func RLPString(b []byte) RLPItem {
+	return RLPItem{Bytes: b}
+}
+
+// RLPUint returns the canonical RLP item for an unsigned integer: big endian without leading zeros.
+func RLPUint(v uint64) RLPItem {
+	return RLPBig(new(big.Int).SetUint64(v))
+}
+
+// RLPBig returns the canonical RLP item for a non-negative big integer.
+func RLPBig(v *big.Int) RLPItem {
+	return RLPItem{Bytes: v.Bytes()}
+}
+
+// RLPList returns a list item.
+func RLPList(items ...RLPItem) RLPItem {
+	return RLPItem{List: items, IsList: true}
+}
+
+// EncodeRLP serializes an item using the Recursive Length Prefix encoding.
+func EncodeRLP(item RLPItem) []byte {
+	if !item.IsList {
+		if len(item.Bytes) == 1 && item.Bytes[0] < 0x80 {
+			return []byte{item.Bytes[0]}
+		}
+		return append(rlpHeader(0x80, len(item.Bytes)), item.Bytes...)
+	}
+	var payload []byte
+	for _, child := range item.List {
+		payload = append(payload, EncodeRLP(child)...)
+	}
+	return append(rlpHeader(0xc0, len(payload)), payload...)
+}
+
+func rlpHeader(offset byte, size int) []byte {
+	if size < 56 {
+		return []byte{offset + byte(size)}
+	}
+	sizeBytes := new(big.Int).SetInt64(int64(size)).Bytes()
+	return append([]byte{offset + 55 + byte(len(sizeBytes))}, sizeBytes...)
+}
+
+var errRLPTruncated = errors.New("rlp: value truncated")
+
+// DecodeRLP parses a single RLP item that must span the whole input.
+func DecodeRLP(data []byte) (RLPItem, error) {
+	item, rest, err := decodeRLPItem(data)
+	if err != nil {
+		return RLPItem{}, err
+	}
+	if len(rest) > 0 {
+		return RLPItem{}, fmt.Errorf("rlp: %d trailing bytes", len(rest))
+	}
+	return item, nil
+}
+
+func decodeRLPItem(data []byte) (RLPItem, []byte, error) {
+	if len(data) == 0 {
+		return RLPItem{}, nil, errRLPTruncated
+	}
+	prefix := data[0]
+	switch {
+	case prefix < 0x80:
+		return RLPItem{Bytes: data[:1]}, data[1:], nil
+	case prefix < 0xc0:
+		payload, rest, err := rlpPayload(data, 0x80)
+		return RLPItem{Bytes: payload}, rest, err
+	default:
+		payload, rest, err := rlpPayload(data, 0xc0)
+		if err != nil {
+			return RLPItem{}, nil, err
+		}
+		list := RLPItem{List: []RLPItem{}, IsList: true}
+		for len(payload) > 0 {
+			var child RLPItem
+			child, payload, err = decodeRLPItem(payload)
+			if err != nil {
+				return RLPItem{}, nil, err
+			}
+			list.List = append(list.List, child)
+		}
+		return list, rest, nil
+	}
+}
+
+// rlpPayload splits the payload of a string (offset 0x80) or list (offset 0xc0) from the rest of the input.
+func rlpPayload(data []byte, offset byte) ([]byte, []byte, error) {
+	prefix := data[0]
+	size, start := 0, 1
+	if prefix < offset+56 {
+		size = int(prefix - offset)
+	} else {
+		sizeLen := int(prefix - offset - 55)
+		if len(data) < 1+sizeLen {
+			return nil, nil, errRLPTruncated
+		}
+		sizeBig := new(big.Int).SetBytes(data[1 : 1+sizeLen])
+		if !sizeBig.IsInt64() || sizeBig.Int64() > int64(len(data)) {
+			return nil, nil, errRLPTruncated
+		}
+		size, start = int(sizeBig.Int64()), 1+sizeLen
+	}
+	if len(data) < start+size {
+		return nil, nil, errRLPTruncated
+	}
+	return data[start : start+size], data[start+size:], nil
+}
@@ -0,0 +1,103 @@
+package eth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// ErrProofMissingNode is returned when a Merkle Patricia proof ends before reaching the key.
+var ErrProofMissingNode = errors.New("proof is missing a trie node")
+
+// VerifyProof walks a Merkle Patricia trie proof, as returned by `eth_getProof`, from root towards key and returns
+// the value stored at key. A nil value with a nil error proves that the key is absent from the trie. The key is
+// the trie path, so account proofs must pass the Keccak-256 hash of the address.
+func VerifyProof(root Hash, key []byte, proof [][]byte) ([]byte, error) {
+	nibbles := make([]byte, 0, len(key)*2)
+	for _, b := range key {
+		nibbles = append(nibbles, b>>4, b&0x0f)
+	}
+
+	ref := RLPString(root[:])
+	next := 0
+	for {
+		var node RLPItem
+		if ref.IsList {
+			// nodes shorter than 32 bytes are embedded in their parent rather than referenced by hash
+			node = ref
+		} else {
+			if len(ref.Bytes) == 0 {
+				return nil, nil
+			}
+			if next >= len(proof) {
+				return nil, ErrProofMissingNode
+			}
+			encoded := proof[next]
+			if len(ref.Bytes) != len(Hash{}) || Keccak256(encoded) != BytesToHash(ref.Bytes) {
+				return nil, fmt.Errorf("proof node %d does not match hash %s", next, EncodeHex(ref.Bytes))
+			}
+			decoded, err := DecodeRLP(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("proof node %d: %w", next, err)
+			}
+			node = decoded
+			next++
+		}
+		if !node.IsList {
+			return nil, fmt.Errorf("proof node %d is not a list", next)
+		}
+
+		switch len(node.List) {
+		case 17:
+			// branch node: the value sits in the last slot, children are indexed by the next nibble
+			if len(nibbles) == 0 {
+				return nonEmpty(node.List[16].Bytes), nil
+			}
+			ref, nibbles = node.List[nibbles[0]], nibbles[1:]
+		case 2:
+			path, isLeaf, err := decodeHexPrefix(node.List[0].Bytes)
+			if err != nil {
+				return nil, err
+			}
+			if isLeaf {
+				if !bytes.Equal(path, nibbles) {
+					return nil, nil
+				}
+				return nonEmpty(node.List[1].Bytes), nil
+			}
+			if !bytes.HasPrefix(nibbles, path) {
+				return nil, nil
+			}
+			ref, nibbles = node.List[1], nibbles[len(path):]
+		default:
+			return nil, fmt.Errorf("invalid trie node with %d items", len(node.List))
+		}
+	}
+}
+
+func nonEmpty(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return b
+}
+
+// decodeHexPrefix decodes the compact hex prefix encoding of a leaf or extension path into nibbles.
+func decodeHexPrefix(compact []byte) ([]byte, bool, error) {
+	if len(compact) == 0 {
+		return nil, false, errors.New("empty trie node path")
+	}
+	flag := compact[0] >> 4
+	if flag > 3 {
+		return nil, false, fmt.Errorf("invalid trie node path flag %d", flag)
+	}
+	isLeaf := flag >= 2
+	nibbles := []byte{}
+	if flag%2 == 1 {
+		nibbles = append(nibbles, compact[0]&0x0f)
+	}
+	for _, b := range compact[1:] {
+		nibbles = append(nibbles, b>>4, b&0x0f)
+	}
+	return nibbles, isLeaf, nil
+}
@@ -0,0 +1,133 @@
+// Package eth holds the primitive Ethereum types shared by the native tooling: addresses, hashes, hex encoded
+// JSON-RPC quantities, Keccak-256, RLP and Merkle Patricia trie proofs.
+package eth
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Address is a 20 byte Ethereum account address.
+type Address [20]byte
+
+// Hash is a 32 byte Keccak-256 hash or bytes32 value.
+type Hash [32]byte
+
+// ParseAddress parses a hex encoded address. Like the gate mocks, shorter values are left padded with zeros.
+func ParseAddress(s string) (Address, error) {
+	var a Address
+	b, err := decodeHexPadded(s, len(a))
+	if err != nil {
+		return a, fmt.Errorf("invalid address %q: %w", s, err)
+	}
+	copy(a[:], b)
+	return a, nil
+}
+
+// MustAddress is like ParseAddress but panics on invalid input. It is intended for constants.
+func MustAddress(s string) Address {
+	a, err := ParseAddress(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// BytesToAddress returns the address held in the last 20 bytes of b.
+func BytesToAddress(b []byte) Address {
+	var a Address
+	if len(b) > len(a) {
+		b = b[len(b)-len(a):]
+	}
+	copy(a[len(a)-len(b):], b)
+	return a
+}
+
+// Bytes returns a copy of the address bytes.
+func (a Address) Bytes() []byte {
+	return append([]byte(nil), a[:]...)
+}
+
+func (a Address) String() string {
+	return "0x" + hex.EncodeToString(a[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *Address) UnmarshalText(text []byte) error {
+	parsed, err := ParseAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// ParseHash parses a hex encoded 32 byte value. Shorter values are left padded with zeros.
+func ParseHash(s string) (Hash, error) {
+	var h Hash
+	b, err := decodeHexPadded(s, len(h))
+	if err != nil {
+		return h, fmt.Errorf("invalid hash %q: %w", s, err)
+	}
+	copy(h[:], b)
+	return h, nil
+}
+
+// MustHash is like ParseHash but panics on invalid input. It is intended for constants.
+func MustHash(s string) Hash {
+	h, err := ParseHash(s)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// BytesToHash returns the hash held in the last 32 bytes of b, left padding shorter inputs.
+func BytesToHash(b []byte) Hash {
+	var h Hash
+	if len(b) > len(h) {
+		b = b[len(b)-len(h):]
+	}
+	copy(h[len(h)-len(b):], b)
+	return h
+}
+
+// Bytes returns a copy of the hash bytes.
+func (h Hash) Bytes() []byte {
+	return append([]byte(nil), h[:]...)
+}
+
+func (h Hash) String() string {
+	return "0x" + hex.EncodeToString(h[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (h *Hash) UnmarshalText(text []byte) error {
+	parsed, err := ParseHash(string(text))
+	if err != nil {
+		return err
+	}
+	*h = parsed
+	return nil
+}
+
+// decodeHexPadded decodes a 0x prefixed hex string of at most size bytes, left padding it to size bytes.
+func decodeHexPadded(s string, size int) ([]byte, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X")
+	if len(s) > size*2 {
+		return nil, fmt.Errorf("longer than %d bytes", size)
+	}
+	s = strings.Repeat("0", size*2-len(s)) + s
+	return hex.DecodeString(s)
+}
@@ -0,0 +1,165 @@
+// Package fixturegen turns a captured game into a fixture for one of its monitors. The mocks are the gate sources
+// the native monitor loads from the archive at a block, and the expected alerts are the invariants the native
+// monitor fails over those mocks, so the fixture pins both implementations to what happened on chain.
+package fixturegen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/archive"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/fixtures"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/replay"
+)
+
+// ErrNoDeployment is returned when the archive holds no deployment of the monitor.
+var ErrNoDeployment = errors.New("no deployment of monitor in archive")
+
+// Options selects what to turn into a fixture.
+type Options struct {
+	// Monitor is the name of the gate file.
+	Monitor string
+	// Game selects the deployment of Monitor for a game, when the archive holds several.
+	Game eth.Address
+	// Block is the block to take the sources at. If zero, it is the first block at which the monitor alerts, or the
+	// last block of the archive if it never does.
+	Block uint64
+	// Name and Description of the fixture. They default to ones naming the block.
+	Name        string
+	Description string
+}
+
+// Generate builds the fixture from the archive.
+func Generate(ctx context.Context, a *archive.Archive, opts Options) (*fixtures.Fixture, error) {
+	manifest := a.Manifest()
+	d, err := deployment(manifest, opts)
+	if err != nil {
+		return nil, err
+	}
+	cfg := replay.Config{
+		Chain:     a.Client(0),
+		Tracer:    a.Client(0),
+		FromBlock: manifest.FromBlock,
+		ToBlock:   manifest.ToBlock,
+		Monitors:  []replay.Deployment{d},
+	}
+	for chainID := range manifest.L2Responses {
+		if cfg.L2 == nil {
+			cfg.L2 = make(map[uint64]monitor.Chain)
+		}
+		cfg.L2[chainID] = a.Client(chainID)
+	}
+
+	block := opts.Block
+	if block == 0 {
+		tl, err := replay.Run(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		block = firstAlert(tl)
+		if block == 0 {
+			block = manifest.ToBlock
+		}
+	}
+	if block < manifest.FromBlock || block > manifest.ToBlock {
+		return nil, fmt.Errorf("block %d is outside of the archive, which holds blocks %d-%d", block, manifest.FromBlock, manifest.ToBlock)
+	}
+
+	// replay up to the block, since calls made in earlier blocks are only known from their traces
+	v := &lastMocks{}
+	cfg.ToBlock = block
+	cfg.Validator = v
+	tl, err := replay.Run(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range tl.Failures {
+		if f.BlockNumber == block {
+			return nil, fmt.Errorf("loading sources at block %d: %s", block, f.Error)
+		}
+	}
+
+	f := &fixtures.Fixture{
+		Monitor:     opts.Monitor,
+		Name:        opts.Name,
+		Description: opts.Description,
+		ExpectAlert: len(v.failed) > 0,
+		Params:      d.Params,
+		Mocks:       v.mocks,
+		Alerts:      v.failed,
+	}
+	if f.Alerts == nil {
+		// pin that the gate must not fail any invariant
+		f.Alerts = []string{}
+	}
+	if f.Name == "" {
+		f.Name = fmt.Sprintf("captured_%d_%d", manifest.ChainID, block)
+	}
+	if f.Description == "" {
+		expect := "We DO NOT expect an alert to be fired"
+		if f.ExpectAlert {
+			expect = "We expect an alert to be fired"
+		}
+		f.Description = fmt.Sprintf("%s at block %d of chain %d", expect, block, manifest.ChainID)
+		if game, ok := d.Params["disputeGame"]; ok {
+			f.Description += fmt.Sprintf(" for dispute game %v", game)
+		}
+	}
+	return f, nil
+}
+
+// deployment finds the deployment of the monitor in the archive.
+func deployment(manifest archive.Manifest, opts Options) (replay.Deployment, error) {
+	var matches []replay.Deployment
+	for _, m := range manifest.Monitors {
+		if m.Name != opts.Monitor {
+			continue
+		}
+		if opts.Game != (eth.Address{}) {
+			game, _ := m.Params["disputeGame"].(string)
+			if !strings.EqualFold(game, opts.Game.String()) {
+				continue
+			}
+		}
+		matches = append(matches, replay.Deployment{Name: m.Name, Params: m.Params})
+	}
+	switch len(matches) {
+	case 0:
+		return replay.Deployment{}, fmt.Errorf("%w: %s", ErrNoDeployment, opts.Monitor)
+	case 1:
+		return matches[0], nil
+	}
+	return replay.Deployment{}, fmt.Errorf("archive holds %d deployments of %s, select one by game", len(matches), opts.Monitor)
+}
+
+func firstAlert(tl *replay.Timeline) uint64 {
+	var first uint64
+	for _, g := range tl.Games {
+		for _, a := range g.Alerts {
+			if first == 0 || a.FirstBlock < first {
+				first = a.FirstBlock
+			}
+		}
+	}
+	return first
+}
+
+// lastMocks evaluates the native monitor over the mocks of every block, keeping those of the last one.
+type lastMocks struct {
+	replay.MocksValidator
+	mocks  map[string]any
+	failed []string
+}
+
+func (v *lastMocks) Validate(ctx context.Context, name string, params, mocks map[string]any) ([]string, error) {
+	failed, err := v.MocksValidator.Validate(ctx, name, params, mocks)
+	if err != nil {
+		return nil, err
+	}
+	v.mocks, v.failed = mocks, failed
+	return failed, nil
+}
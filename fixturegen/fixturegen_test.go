@@ -0,0 +1,113 @@
+package fixturegen_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/archive"
+	"github.com/base-org/fault-proof-monitors/capture"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/fixturegen"
+	"github.com/base-org/fault-proof-monitors/fixtures"
+	"github.com/base-org/fault-proof-monitors/follower/chaintest"
+	"github.com/base-org/fault-proof-monitors/game"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/replay"
+)
+
+var disputeGame = eth.MustAddress("0x00000000000000000000000000000000000000BB")
+
+// captureGame records blocks 1-25 of a game with an attacked root claim that is unresolved from block 12 and
+// resolved at block 20.
+func captureGame(t *testing.T) *archive.Archive {
+	g := game.NewGame(game.Config{MaxClockDuration: 20, MaxGameDepth: 73, SplitDepth: 30}, 1000)
+	if _, err := g.Move(0, true, 1002); err != nil {
+		t.Fatalf("Error attacking root claim: %v", err)
+	}
+	chain := chaintest.NewChain(1000)
+	chain.Extend(30)
+	chain.HandleCall(chaintest.GameCalls(disputeGame, g, 20))
+	server := chaintest.Serve(chain, 8453)
+	defer server.Close()
+
+	dir := t.TempDir()
+	_, _, err := capture.Run(context.Background(), capture.Config{
+		Endpoint:  server.URL,
+		Dir:       dir,
+		FromBlock: 1,
+		ToBlock:   25,
+		Monitors: []replay.Deployment{{
+			Name:   "unresolvable_dispute_game",
+			Params: map[string]any{"disputeGame": disputeGame.String(), "extraTimeInSeconds": 0},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Error capturing game: %v", err)
+	}
+	a, err := archive.Open(dir)
+	if err != nil {
+		t.Fatalf("Error opening archive: %v", err)
+	}
+	return a
+}
+
+func TestGenerate(t *testing.T) {
+	a := captureGame(t)
+	cases := []struct {
+		block  uint64
+		name   string
+		alerts []string
+	}{
+		// defaults to the first block that alerts
+		{block: 0, name: "captured_8453_12", alerts: []string{"Dispute game is unresolved"}},
+		{block: 5, name: "captured_8453_5", alerts: []string{}},
+		{block: 25, name: "captured_8453_25", alerts: []string{}},
+	}
+	for _, c := range cases {
+		f, err := fixturegen.Generate(context.Background(), a, fixturegen.Options{Monitor: "unresolvable_dispute_game", Block: c.block})
+		if err != nil {
+			t.Fatalf("Error generating fixture at block %d: %v", c.block, err)
+		}
+		if f.Name != c.name || !slices.Equal(f.Alerts, c.alerts) || f.ExpectAlert != (len(c.alerts) > 0) {
+			t.Errorf("block %d: expected %s with alerts %q, got %s with %q", c.block, c.name, c.alerts, f.Name, f.Alerts)
+		}
+		if len(f.Mocks) != 5 || f.Mocks["claimCount"] == nil {
+			t.Errorf("block %d: expected every source to be mocked, got %v", c.block, f.Mocks)
+		}
+
+		// the fixture file evaluates to the recorded alerts
+		data, err := fixtures.Encode(*f)
+		if err != nil {
+			t.Fatalf("Error encoding fixture: %v", err)
+		}
+		decoded, err := fixtures.Decode(data)
+		if err != nil {
+			t.Fatalf("Error decoding fixture:\n%s\n%v", data, err)
+		}
+		m, err := monitor.New(f.Monitor, decoded.Params)
+		if err != nil {
+			t.Fatalf("Error creating monitor: %v", err)
+		}
+		violations, err := monitor.EvaluateMocks(m, monitor.BlockContext{}, decoded.Mocks)
+		if err != nil {
+			t.Fatalf("Error evaluating fixture: %v", err)
+		}
+		if len(violations) != len(decoded.Alerts) {
+			t.Errorf("block %d: fixture evaluates to %v, expected %q", c.block, violations, decoded.Alerts)
+		}
+	}
+}
+
+func TestGenerateUnknownMonitor(t *testing.T) {
+	a := captureGame(t)
+	_, err := fixturegen.Generate(context.Background(), a, fixturegen.Options{Monitor: "challenger_loses"})
+	if !errors.Is(err, fixturegen.ErrNoDeployment) {
+		t.Errorf("expected ErrNoDeployment, got %v", err)
+	}
+	_, err = fixturegen.Generate(context.Background(), a, fixturegen.Options{Monitor: "unresolvable_dispute_game", Block: 26})
+	if err == nil {
+		t.Errorf("expected a block outside of the archive to fail")
+	}
+}
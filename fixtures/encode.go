@@ -0,0 +1,140 @@
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Encode formats a fixture the way the fixture files are written by hand: objects are indented with their keys
+// sorted, and lists of plain values, such as the tuples of a claim, are kept on a single line.
+func Encode(f Fixture) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	fields := []struct {
+		key   string
+		value any
+	}{
+		{"description", f.Description},
+		{"expectAlert", f.ExpectAlert},
+		{"params", f.Params},
+		{"mocks", f.Mocks},
+	}
+	if f.Alerts != nil {
+		fields = append(fields, struct {
+			key   string
+			value any
+		}{"alerts", f.Alerts})
+	}
+	for i, field := range fields {
+		fmt.Fprintf(&buf, "  %q: ", field.key)
+		if err := encodeValue(&buf, field.value, "  "); err != nil {
+			return nil, fmt.Errorf("encoding %s: %w", field.key, err)
+		}
+		if i < len(fields)-1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('\n')
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+func encodeValue(buf *bytes.Buffer, value any, indent string) error {
+	// normalize the value to the types produced by decoding JSON
+	data, err := marshal(value)
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+	return writeValue(buf, v, indent)
+}
+
+func writeValue(buf *bytes.Buffer, v any, indent string) error {
+	inner := indent + "  "
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		buf.WriteString("{\n")
+		for i, k := range keys {
+			fmt.Fprintf(buf, "%s%q: ", inner, k)
+			if err := writeValue(buf, v[k], inner); err != nil {
+				return err
+			}
+			if i < len(keys)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(indent + "}")
+	case []any:
+		if isFlat(v) {
+			items := make([]string, len(v))
+			for i, item := range v {
+				data, err := marshal(item)
+				if err != nil {
+					return err
+				}
+				items[i] = string(data)
+			}
+			buf.WriteString("[" + strings.Join(items, ", ") + "]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, item := range v {
+			buf.WriteString(inner)
+			if err := writeValue(buf, item, inner); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(indent + "]")
+	default:
+		data, err := marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+	return nil
+}
+
+// isFlat reports whether a list holds no objects or lists, so that it fits on one line.
+func isFlat(list []any) bool {
+	for _, item := range list {
+		switch item.(type) {
+		case map[string]any, []any:
+			return false
+		}
+	}
+	return true
+}
+
+// marshal encodes a value without escaping HTML characters, which gate descriptions may contain.
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,35 @@
+package fixtures_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/fixtures"
+)
+
+// TestEncodeMatchesFixtureFiles checks that generated fixtures are formatted like the hand-written ones, so
+// regenerating a fixture gives a readable diff.
+func TestEncodeMatchesFixtureFiles(t *testing.T) {
+	paths, err := filepath.Glob("*/*.json")
+	if err != nil {
+		t.Fatalf("Error listing fixtures: %v", err)
+	}
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("Error reading %s: %v", p, err)
+		}
+		f, err := fixtures.Decode(data)
+		if err != nil {
+			t.Fatalf("Error decoding %s: %v", p, err)
+		}
+		encoded, err := fixtures.Encode(f)
+		if err != nil {
+			t.Fatalf("Error encoding %s: %v", p, err)
+		}
+		if string(encoded) != string(data) {
+			t.Errorf("%s is not formatted like Encode:\n%s", p, encoded)
+		}
+	}
+}
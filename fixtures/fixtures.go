@@ -0,0 +1,85 @@
+// Package fixtures holds the test scenarios shared by the gate tests and the native monitors. Each scenario is a
+// JSON file under the directory of the monitor it targets, with the params and mocks sent to the validate API and
+// whether the monitor is expected to alert. Values that differ between networks are placeholders, substituted with
+// the values of a network profile by Fixture.Substitute.
+package fixtures
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed */*.json
+var files embed.FS
+
+// Fixture is a test scenario for a single monitor.
+type Fixture struct {
+	// Monitor is the name of the gate file, without the extension.
+	Monitor string `json:"-"`
+	// Name is the file name of the scenario, without the extension.
+	Name        string         `json:"-"`
+	Description string         `json:"description"`
+	ExpectAlert bool           `json:"expectAlert"`
+	Params      map[string]any `json:"params"`
+	Mocks       map[string]any `json:"mocks"`
+	// Alerts optionally lists the descriptions of the invariants expected to fail, one per violation, as recorded by
+	// fixtures generated from captured games.
+	Alerts []string `json:"alerts,omitempty"`
+}
+
+// All returns every fixture, ordered by monitor and name.
+func All() ([]Fixture, error) {
+	paths, err := fs.Glob(files, "*/*.json")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	fixtures := make([]Fixture, 0, len(paths))
+	for _, p := range paths {
+		data, err := files.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		fixture, err := Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("fixture %s: %w", p, err)
+		}
+		fixture.Monitor = path.Dir(p)
+		fixture.Name = strings.TrimSuffix(path.Base(p), ".json")
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures, nil
+}
+
+// ForMonitor returns the fixtures of a single monitor.
+func ForMonitor(monitor string) ([]Fixture, error) {
+	all, err := All()
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []Fixture
+	for _, f := range all {
+		if f.Monitor == monitor {
+			fixtures = append(fixtures, f)
+		}
+	}
+	return fixtures, nil
+}
+
+// Decode parses a fixture file. Numbers are kept as json.Number so large integers are not rounded.
+func Decode(data []byte) (Fixture, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	dec.DisallowUnknownFields()
+	var f Fixture
+	if err := dec.Decode(&f); err != nil {
+		return Fixture{}, err
+	}
+	return f, nil
+}
@@ -0,0 +1,138 @@
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+var (
+	placeholderPattern = regexp.MustCompile(`^\$\{(.*)\}$`)
+	variablePattern    = regexp.MustCompile(`^[A-Za-z_]\w*$`)
+	tokenPattern       = regexp.MustCompile(`\s*(?:([A-Za-z_]\w*)|(\d+)|([-+*]))`)
+)
+
+// Substitute returns f with the placeholders of its params and mocks replaced by the values of vars, such as the
+// settings of a network profile. A placeholder is a whole string holding an expression between ${ and }: a variable,
+// as in "${multicall3}", or the sum or difference of integers and variables multiplied by integers, as in
+// "${1000 + withdrawalDelay}" or "${2 * maxClockDuration - 1}". The expression of a placeholder replaced by an
+// integer is evaluated exactly, and its result is a json.Number like the other numbers of a fixture.
+func (f Fixture) Substitute(vars map[string]any) (Fixture, error) {
+	params, err := substitute(f.Params, vars)
+	if err != nil {
+		return f, fmt.Errorf("params: %w", err)
+	}
+	mocks, err := substitute(f.Mocks, vars)
+	if err != nil {
+		return f, fmt.Errorf("mocks: %w", err)
+	}
+	f.Params, _ = params.(map[string]any)
+	f.Mocks, _ = mocks.(map[string]any)
+	return f, nil
+}
+
+func substitute(v any, vars map[string]any) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		if v == nil {
+			return v, nil
+		}
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			s, err := substitute(e, vars)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", k, err)
+			}
+			out[k] = s
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			s, err := substitute(e, vars)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			out[i] = s
+		}
+		return out, nil
+	case string:
+		m := placeholderPattern.FindStringSubmatch(v)
+		if m == nil {
+			return v, nil
+		}
+		return evaluate(m[1], vars)
+	}
+	return v, nil
+}
+
+// evaluate returns the value of the expression of a placeholder.
+func evaluate(expr string, vars map[string]any) (any, error) {
+	if name := strings.TrimSpace(expr); variablePattern.MatchString(name) {
+		v, ok := vars[name]
+		if !ok {
+			return nil, fmt.Errorf("${%s}: %s is not set", expr, name)
+		}
+		if _, ok := v.(string); ok {
+			return v, nil
+		}
+	}
+
+	sum, term := new(big.Int), big.NewInt(1)
+	sign, op, operand := 1, "", false
+	for rest := expr; strings.TrimSpace(rest) != ""; {
+		loc := tokenPattern.FindStringSubmatchIndex(rest)
+		if loc == nil || loc[0] != 0 {
+			return nil, fmt.Errorf("${%s}: unexpected %q", expr, strings.TrimSpace(rest))
+		}
+		token := tokenPattern.FindStringSubmatch(rest)
+		rest = rest[loc[1]:]
+		if token[3] != "" {
+			if !operand {
+				return nil, fmt.Errorf("${%s}: unexpected %s", expr, token[3])
+			}
+			if token[3] != "*" {
+				sum.Add(sum, term.Mul(term, big.NewInt(int64(sign))))
+				term, sign = big.NewInt(1), 1
+				if token[3] == "-" {
+					sign = -1
+				}
+			}
+			op, operand = token[3], false
+			continue
+		}
+		if operand {
+			return nil, fmt.Errorf("${%s}: missing operator before %s", expr, strings.TrimSpace(token[0]))
+		}
+		n, ok := new(big.Int).SetString(token[2], 10)
+		if token[1] != "" {
+			if n, ok = integer(vars[token[1]]); !ok {
+				return nil, fmt.Errorf("${%s}: %s is not set or not an integer", expr, token[1])
+			}
+		}
+		term.Mul(term, n)
+		operand = true
+	}
+	if !operand {
+		if op == "" {
+			return nil, fmt.Errorf("${%s}: empty expression", expr)
+		}
+		return nil, fmt.Errorf("${%s}: missing operand after %s", expr, op)
+	}
+	sum.Add(sum, term.Mul(term, big.NewInt(int64(sign))))
+	return json.Number(sum.String()), nil
+}
+
+func integer(v any) (*big.Int, bool) {
+	switch v := v.(type) {
+	case uint64:
+		return new(big.Int).SetUint64(v), true
+	case int:
+		return big.NewInt(int64(v)), true
+	case json.Number:
+		return new(big.Int).SetString(string(v), 10)
+	}
+	return nil, false
+}
@@ -0,0 +1,53 @@
+package fixtures_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/fixtures"
+)
+
+func TestSubstitute(t *testing.T) {
+	vars := map[string]any{
+		"maxClockDuration":  uint64(302400),
+		"multicall3":        "0xca11bde05977b3631167028862be2a173976ca11",
+		"respectedGameType": uint64(0),
+	}
+	f, err := fixtures.Decode([]byte(`{
+  "description": "d",
+  "expectAlert": true,
+  "params": {"multicall3": "${ multicall3 }", "disputeGame": "0x00"},
+  "mocks": {
+    "clock": "${100 * 18446744073709551616 + 555655}",
+    "deadline": "${2 * maxClockDuration - 1 + 3600}",
+    "games": [["${respectedGameType}", "${respectedGameType + 1}", "$notaplaceholder"]]
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := f.Substitute(vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantParams := map[string]any{"multicall3": "0xca11bde05977b3631167028862be2a173976ca11", "disputeGame": "0x00"}
+	wantMocks := map[string]any{
+		"clock":    json.Number("1844674407370955717255"),
+		"deadline": json.Number("608399"),
+		"games":    []any{[]any{json.Number("0"), json.Number("1"), "$notaplaceholder"}},
+	}
+	if !reflect.DeepEqual(got.Params, wantParams) || !reflect.DeepEqual(got.Mocks, wantMocks) {
+		t.Errorf("unexpected substitution %v %v", got.Params, got.Mocks)
+	}
+	if f.Params["multicall3"] != "${ multicall3 }" {
+		t.Error("expected the fixture to be left as is")
+	}
+
+	for _, expr := range []string{"${unknown}", "${1 +}", "${* 2}", "${1 2}", "${multicall3 + 1}", "${}", "${1 / 2}"} {
+		f.Mocks = map[string]any{"x": expr}
+		if _, err := f.Substitute(vars); err == nil {
+			t.Errorf("expected %s to be rejected", expr)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Package chaintest provides a scripted in-memory chain for testing code that follows blocks. Tests extend the
+// chain and reorg it between steps, and every block of every fork stays addressable by hash.
+package chaintest
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+// BlockTime is the number of seconds between scripted blocks.
+const BlockTime = 2
+
+// CallHandler answers eth_call against the block header it is made at.
+type CallHandler func(msg rpc.CallMsg, header *eth.Header) ([]byte, error)
+
+// Chain is a fake chain implementing monitor.Chain. It starts with a genesis block only.
+type Chain struct {
+	mu        sync.Mutex
+	canonical []*eth.Header
+	logs      map[eth.Hash][]eth.Log
+	forks     uint64
+	call      CallHandler
+}
+
+// NewChain returns a chain whose genesis block has the given timestamp.
+func NewChain(genesisTime uint64) *Chain {
+	c := &Chain{logs: make(map[eth.Hash][]eth.Log)}
+	c.canonical = []*eth.Header{c.header(nil, genesisTime)}
+	return c
+}
+
+func (c *Chain) header(parent *eth.Header, timestamp uint64) *eth.Header {
+	h := &eth.Header{Timestamp: eth.Uint64(timestamp)}
+	if parent != nil {
+		h.Number = parent.Number + 1
+		h.ParentHash = parent.Hash
+		h.Timestamp = parent.Timestamp + BlockTime
+	}
+	var salt [16]byte
+	binary.BigEndian.PutUint64(salt[:8], uint64(h.Number))
+	binary.BigEndian.PutUint64(salt[8:], c.forks)
+	h.Hash = eth.Keccak256(h.ParentHash[:], salt[:])
+	return h
+}
+
+// HandleCall sets the handler for eth_call. Calls fail until one is set.
+func (c *Chain) HandleCall(fn CallHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.call = fn
+}
+
+// Extend appends n blocks to the canonical chain and returns the new head.
+func (c *Chain) Extend(n int) *eth.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := 0; i < n; i++ {
+		c.canonical = append(c.canonical, c.header(c.canonical[len(c.canonical)-1], 0))
+	}
+	return c.canonical[len(c.canonical)-1]
+}
+
+// Reorg replaces the last depth blocks with n blocks of a new fork and returns the new head. The genesis block
+// is never replaced.
+func (c *Chain) Reorg(depth, n int) *eth.Header {
+	c.mu.Lock()
+	if depth > len(c.canonical)-1 {
+		depth = len(c.canonical) - 1
+	}
+	c.canonical = c.canonical[:len(c.canonical)-depth]
+	c.forks++
+	c.mu.Unlock()
+	return c.Extend(n)
+}
+
+// Head returns the canonical head.
+func (c *Chain) Head() *eth.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.canonical[len(c.canonical)-1]
+}
+
+// Header returns the canonical block at number, or nil past the head.
+func (c *Chain) Header(number uint64) *eth.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if number >= uint64(len(c.canonical)) {
+		return nil
+	}
+	return c.canonical[number]
+}
+
+// AddLog attaches a log to the canonical block at number, filling in its block and index fields.
+func (c *Chain) AddLog(number uint64, log eth.Log) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h := c.canonical[number]
+	log.BlockNumber = h.Number
+	log.BlockHash = h.Hash
+	log.LogIndex = eth.Uint64(len(c.logs[h.Hash]))
+	c.logs[h.Hash] = append(c.logs[h.Hash], log)
+}
+
+func (c *Chain) resolve(number rpc.BlockNumber) (*eth.Header, error) {
+	switch number {
+	case rpc.LatestBlockNumber, rpc.SafeBlockNumber, rpc.FinalizedBlockNumber, rpc.PendingBlockNumber:
+		return c.canonical[len(c.canonical)-1], nil
+	}
+	if number < 0 {
+		return nil, errors.New("unknown block tag")
+	}
+	if uint64(number) >= uint64(len(c.canonical)) {
+		return nil, rpc.ErrNotFound
+	}
+	return c.canonical[number], nil
+}
+
+// HeaderByNumber implements monitor.Chain.
+func (c *Chain) HeaderByNumber(_ context.Context, number rpc.BlockNumber) (*eth.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	h, err := c.resolve(number)
+	if err != nil {
+		return nil, err
+	}
+	copied := *h
+	return &copied, nil
+}
+
+// CallContract implements monitor.Chain.
+func (c *Chain) CallContract(_ context.Context, msg rpc.CallMsg, number rpc.BlockNumber) ([]byte, error) {
+	c.mu.Lock()
+	h, err := c.resolve(number)
+	call := c.call
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if call == nil {
+		return nil, &rpc.Error{Code: 3, Message: "execution reverted"}
+	}
+	return call(msg, h)
+}
+
+// Logs implements monitor.Chain over the canonical chain. Only the block range, block hash and addresses of the
+// query are applied, along with the first topic.
+func (c *Chain) Logs(_ context.Context, query rpc.FilterQuery) ([]eth.Log, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var blocks []*eth.Header
+	if query.BlockHash != nil {
+		for _, h := range c.canonical {
+			if h.Hash == *query.BlockHash {
+				blocks = append(blocks, h)
+			}
+		}
+	} else {
+		from, err := c.resolve(query.FromBlock)
+		if err != nil {
+			return nil, err
+		}
+		to, err := c.resolve(query.ToBlock)
+		if err != nil {
+			return nil, err
+		}
+		if from.Number <= to.Number {
+			blocks = c.canonical[from.Number : to.Number+1]
+		}
+	}
+
+	var logs []eth.Log
+	for _, h := range blocks {
+		for _, log := range c.logs[h.Hash] {
+			if matches(query, log) {
+				logs = append(logs, log)
+			}
+		}
+	}
+	return logs, nil
+}
+
+func matches(query rpc.FilterQuery, log eth.Log) bool {
+	if len(query.Addresses) > 0 {
+		found := false
+		for _, a := range query.Addresses {
+			found = found || a == log.Address
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(query.Topics) > 0 && len(query.Topics[0]) > 0 {
+		if len(log.Topics) == 0 {
+			return false
+		}
+		found := false
+		for _, t := range query.Topics[0] {
+			found = found || t == log.Topics[0]
+		}
+		return found
+	}
+	return true
+}
+
+// Proof implements monitor.Chain. The fake chain has no state, so proofs are never available.
+func (c *Chain) Proof(context.Context, eth.Address, []eth.Hash, rpc.BlockNumber) (*eth.AccountProof, error) {
+	return nil, errors.New("proofs are not supported by the fake chain")
+}
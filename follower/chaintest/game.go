@@ -0,0 +1,50 @@
+package chaintest
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/game"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+var (
+	maxClockDurationMethod = abi.MustParseMethod("maxClockDuration() returns (uint256)")
+	resolvedAtMethod       = abi.MustParseMethod("resolvedAt() returns (uint256)")
+)
+
+// GameCalls answers the calls the clock monitors make to a dispute game at address holding the claims of g, which
+// is resolved at the timestamp of block resolvedBlock, or never if it is zero. Calls to other contracts revert.
+func GameCalls(address eth.Address, g *game.Game, resolvedBlock uint64) CallHandler {
+	return func(msg rpc.CallMsg, header *eth.Header) ([]byte, error) {
+		if msg.To != address || len(msg.Data) < 4 {
+			return nil, &rpc.Error{Code: 3, Message: "execution reverted"}
+		}
+		selector := msg.Data[:4]
+		switch {
+		case bytes.Equal(selector, maxClockDurationMethod.Selector()):
+			return maxClockDurationMethod.PackOutputs(new(big.Int).SetUint64(g.Config.MaxClockDuration))
+		case bytes.Equal(selector, resolvedAtMethod.Selector()):
+			resolvedAt := new(big.Int)
+			if resolvedBlock != 0 && uint64(header.Number) >= resolvedBlock {
+				resolvedAt.SetUint64(uint64(header.Timestamp))
+			}
+			return resolvedAtMethod.PackOutputs(resolvedAt)
+		case bytes.Equal(selector, abi.ClaimDataLen.Selector()):
+			return abi.ClaimDataLen.PackOutputs(big.NewInt(int64(len(g.Claims))))
+		case bytes.Equal(selector, abi.ClaimData.Selector()):
+			values, err := abi.ClaimData.UnpackInputs(msg.Data)
+			if err != nil {
+				return nil, err
+			}
+			idx := values[0].(*big.Int)
+			if !idx.IsInt64() || idx.Int64() >= int64(len(g.Claims)) {
+				return nil, &rpc.Error{Code: 3, Message: "execution reverted"}
+			}
+			return abi.NewClaim(g.Claims[idx.Int64()], eth.Address{}, big.NewInt(1), eth.Hash{}).Encode()
+		}
+		return nil, &rpc.Error{Code: 3, Message: "execution reverted"}
+	}
+}
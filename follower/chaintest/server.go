@@ -0,0 +1,91 @@
+package chaintest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/rpc"
+	"github.com/base-org/fault-proof-monitors/rpc/rpctest"
+)
+
+// Serve starts a JSON-RPC server over the chain, for code that takes an endpoint rather than a monitor.Chain.
+// Blocks have no transactions, so every block traces to an empty list. Callers must Close the server.
+func Serve(c *Chain, chainID uint64) *rpctest.Server {
+	ctx := context.Background()
+	server := rpctest.NewServer()
+	server.HandleResult("eth_chainId", eth.Uint64(chainID))
+	server.Handle("eth_blockNumber", func(json.RawMessage) (any, error) {
+		return c.Head().Number, nil
+	})
+	server.Handle("eth_getBlockByNumber", func(params json.RawMessage) (any, error) {
+		var number rpc.BlockNumber
+		if err := decodeParams(params, &number); err != nil {
+			return nil, err
+		}
+		header, err := c.HeaderByNumber(ctx, number)
+		if errors.Is(err, rpc.ErrNotFound) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return rpc.Block{Header: *header, Transactions: []json.RawMessage{}}, nil
+	})
+	server.Handle("eth_call", func(params json.RawMessage) (any, error) {
+		var msg rpc.CallMsg
+		var number rpc.BlockNumber
+		if err := decodeParams(params, &msg, &number); err != nil {
+			return nil, err
+		}
+		output, err := c.CallContract(ctx, msg, number)
+		if err != nil {
+			return nil, err
+		}
+		return eth.Bytes(output), nil
+	})
+	server.Handle("eth_getLogs", func(params json.RawMessage) (any, error) {
+		var query rpc.FilterQuery
+		if err := decodeParams(params, &query); err != nil {
+			return nil, err
+		}
+		logs, err := c.Logs(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		if logs == nil {
+			logs = []eth.Log{}
+		}
+		return logs, nil
+	})
+	server.Handle("debug_traceBlockByNumber", func(params json.RawMessage) (any, error) {
+		var number rpc.BlockNumber
+		if err := decodeParams(params, &number); err != nil {
+			return nil, err
+		}
+		if _, err := c.HeaderByNumber(ctx, number); err != nil {
+			return nil, err
+		}
+		return []any{}, nil
+	})
+	return server
+}
+
+// decodeParams decodes the leading positional params into dst, ignoring the rest.
+func decodeParams(params json.RawMessage, dst ...any) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(params, &raw); err != nil {
+		return &rpc.Error{Code: -32602, Message: err.Error()}
+	}
+	if len(raw) < len(dst) {
+		return &rpc.Error{Code: -32602, Message: fmt.Sprintf("expected %d params, got %d", len(dst), len(raw))}
+	}
+	for i, d := range dst {
+		if err := json.Unmarshal(raw[i], d); err != nil {
+			return &rpc.Error{Code: -32602, Message: err.Error()}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+// Package follower evaluates the native monitors on every block of a chain, the job Hexagate does for the gate
+// files. It polls for new heads, keeps a bounded window of recent blocks to detect reorgs, and retracts the
+// violations of blocks that are orphaned before evaluating their replacements.
+package follower
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+const (
+	// DefaultPollInterval is how often the head is polled when Config.PollInterval is zero.
+	DefaultPollInterval = 2 * time.Second
+	// DefaultWindow is the number of recent blocks kept when Config.Window is zero.
+	DefaultWindow = 64
+	// DefaultConcurrency is the number of monitors evaluated at once when Config.Concurrency is zero.
+	DefaultConcurrency = 4
+)
+
+// ErrReorgTooDeep is reported when a reorg replaces every block in the window. Violations raised for blocks that
+// already left the window cannot be retracted.
+var ErrReorgTooDeep = errors.New("reorg deeper than the block window")
+
+// Block identifies a block that was evaluated.
+type Block struct {
+	Number     uint64
+	Hash       eth.Hash
+	ParentHash eth.Hash
+}
+
+// Sink receives the outcome of evaluating each block.
+type Sink interface {
+	// Report is called once for every canonical block evaluated, with the violations raised in it, if any.
+	Report(ctx context.Context, block Block, violations []monitor.Violation) error
+	// Retract is called when a block is orphaned by a reorg, with the violations previously reported for it.
+	Retract(ctx context.Context, block Block, violations []monitor.Violation) error
+}
+
+// Config configures a Follower.
+type Config struct {
+	// Monitors are evaluated on every block.
+	Monitors []monitor.Monitor
+	// Sink receives the violations. It is required.
+	Sink Sink
+	// StartBlock is the first block evaluated. Zero starts at the head when the follower first runs.
+	StartBlock uint64
+	// PollInterval is how often the head is polled.
+	PollInterval time.Duration
+	// Heads optionally signals new heads, e.g. from a websocket subscription, so blocks are picked up without
+	// waiting for the next poll.
+	Heads <-chan struct{}
+	// Window is the number of recent blocks kept to detect reorgs, which bounds how deep a reorg can be undone.
+	Window int
+	// Concurrency bounds the number of monitors evaluated at once on a block.
+	Concurrency int
+	// History and L2 are passed to the monitors in every BlockContext.
+	History monitor.History
+	L2      map[uint64]monitor.Chain
+	// Prepare fills in per-block data before the monitors run, such as the calls flattened from the block trace.
+	Prepare func(ctx context.Context, bc *monitor.BlockContext) error
+	// OnError is called with the errors Run recovers from, and with the errors of the monitors that fail to evaluate
+	// a block. A block whose head, preparation or report fails is retried on the next poll, while a monitor failing
+	// on a block only loses its violations for that block, so that it cannot hold back the others.
+	OnError func(error)
+}
+
+// evaluated is a block in the window along with the violations reported for it.
+type evaluated struct {
+	Block
+	violations []monitor.Violation
+}
+
+// Follower follows a chain and evaluates monitors on each block in order.
+type Follower struct {
+	chain monitor.Chain
+	cfg   Config
+
+	// window holds the most recent evaluated blocks, oldest first, each the parent of the next
+	window []evaluated
+	// next is the number of the next block to evaluate, or zero before the first step without a StartBlock
+	next uint64
+}
+
+// New returns a follower of chain.
+func New(chain monitor.Chain, cfg Config) *Follower {
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.Window == 0 {
+		cfg.Window = DefaultWindow
+	}
+	if cfg.Concurrency == 0 {
+		cfg.Concurrency = DefaultConcurrency
+	}
+	return &Follower{chain: chain, cfg: cfg, next: cfg.StartBlock}
+}
+
+// Run follows the chain until ctx is cancelled. Errors are passed to OnError and the failed work retried.
+func (f *Follower) Run(ctx context.Context) error {
+	ticker := time.NewTicker(f.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := f.Step(ctx); err != nil && ctx.Err() == nil && f.cfg.OnError != nil {
+			f.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-f.cfg.Heads:
+		}
+	}
+}
+
+// Step evaluates every block up to the current head, first retracting blocks orphaned since the last step.
+func (f *Follower) Step(ctx context.Context) error {
+	head, err := f.chain.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return fmt.Errorf("fetching head: %w", err)
+	}
+	if f.next == 0 {
+		f.next = uint64(head.Number)
+	}
+
+	// a head at or below the last evaluated block means the chain was reorged onto a shorter or equal fork
+	if tail := f.tail(); tail != nil && uint64(head.Number) <= tail.Number && head.Hash != tail.Hash {
+		if err := f.rewind(ctx); err != nil {
+			return err
+		}
+	}
+
+	for f.next <= uint64(head.Number) {
+		header := head
+		if f.next != uint64(head.Number) {
+			if header, err = f.chain.HeaderByNumber(ctx, rpc.NumberAt(f.next)); err != nil {
+				return fmt.Errorf("fetching block %d: %w", f.next, err)
+			}
+		}
+		if tail := f.tail(); tail != nil && header.ParentHash != tail.Hash {
+			if err := f.rewind(ctx); err != nil {
+				return err
+			}
+			// the head may be on the fork that was just left, or on one that was since orphaned when the tail is
+			// still canonical, so it is fetched again
+			if head, err = f.chain.HeaderByNumber(ctx, rpc.LatestBlockNumber); err != nil {
+				return fmt.Errorf("fetching head: %w", err)
+			}
+			continue
+		}
+		if err := f.evaluate(ctx, header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Window returns the blocks currently kept for reorg detection, oldest first.
+func (f *Follower) Window() []Block {
+	blocks := make([]Block, len(f.window))
+	for i, b := range f.window {
+		blocks[i] = b.Block
+	}
+	return blocks
+}
+
+func (f *Follower) tail() *evaluated {
+	if len(f.window) == 0 {
+		return nil
+	}
+	return &f.window[len(f.window)-1]
+}
+
+// rewind retracts blocks from the end of the window until the last one is canonical again, so evaluation resumes
+// on the new fork right after the common ancestor.
+func (f *Follower) rewind(ctx context.Context) error {
+	for len(f.window) > 0 {
+		tail := f.window[len(f.window)-1]
+		canonical, err := f.chain.HeaderByNumber(ctx, rpc.NumberAt(tail.Number))
+		if err != nil && !errors.Is(err, rpc.ErrNotFound) {
+			return fmt.Errorf("fetching block %d: %w", tail.Number, err)
+		}
+		if err == nil && canonical.Hash == tail.Hash {
+			return nil
+		}
+		if err := f.cfg.Sink.Retract(ctx, tail.Block, tail.violations); err != nil {
+			return fmt.Errorf("retracting block %d: %w", tail.Number, err)
+		}
+		f.window = f.window[:len(f.window)-1]
+		f.next = tail.Number
+	}
+	return fmt.Errorf("%w: re-evaluating from block %d", ErrReorgTooDeep, f.next)
+}
+
+// evaluate runs every monitor on the block, reports the violations and appends the block to the window. The errors of
+// the monitors are passed to OnError rather than failing the block.
+func (f *Follower) evaluate(ctx context.Context, header *eth.Header) error {
+	bc := monitor.BlockContext{
+		Number:    uint64(header.Number),
+		Hash:      header.Hash,
+		Timestamp: uint64(header.Timestamp),
+		Chain:     f.chain,
+		History:   f.cfg.History,
+		L2:        f.cfg.L2,
+	}
+	if f.cfg.Prepare != nil {
+		if err := f.cfg.Prepare(ctx, &bc); err != nil {
+			return fmt.Errorf("preparing block %d: %w", bc.Number, err)
+		}
+	}
+
+	results := make([][]monitor.Violation, len(f.cfg.Monitors))
+	errs := make([]error, len(f.cfg.Monitors))
+	sem := make(chan struct{}, f.cfg.Concurrency)
+	var wg sync.WaitGroup
+	for i, m := range f.cfg.Monitors {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, m monitor.Monitor) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i], errs[i] = m.Evaluate(ctx, bc)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("evaluating %s on block %d: %w", m.Name(), bc.Number, errs[i])
+			}
+		}(i, m)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil && f.cfg.OnError != nil {
+			f.cfg.OnError(err)
+		}
+	}
+
+	var violations []monitor.Violation
+	for _, r := range results {
+		violations = append(violations, r...)
+	}
+	block := Block{Number: bc.Number, Hash: header.Hash, ParentHash: header.ParentHash}
+	if err := f.cfg.Sink.Report(ctx, block, violations); err != nil {
+		return fmt.Errorf("reporting block %d: %w", bc.Number, err)
+	}
+
+	f.window = append(f.window, evaluated{Block: block, violations: violations})
+	if len(f.window) > f.cfg.Window {
+		f.window = f.window[len(f.window)-f.cfg.Window:]
+	}
+	f.next = bc.Number + 1
+	return nil
+}
@@ -0,0 +1,391 @@
+package follower_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/follower"
+	"github.com/base-org/fault-proof-monitors/follower/chaintest"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+// everyBlock raises a violation on every block it evaluates.
+type everyBlock struct {
+	fail atomic.Bool
+}
+
+func (m *everyBlock) Name() string { return "every_block" }
+
+func (m *everyBlock) Contract() eth.Address { return eth.Address{} }
+
+func (m *everyBlock) Evaluate(_ context.Context, bc monitor.BlockContext) ([]monitor.Violation, error) {
+	if m.fail.Load() {
+		return nil, errors.New("node unavailable")
+	}
+	return []monitor.Violation{{Monitor: m.Name(), Description: "block evaluated", BlockNumber: bc.Number, BlockHash: bc.Hash}}, nil
+}
+
+// recorder is a Sink recording reports and retractions as "+number" and "-number".
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+	active map[eth.Hash][]monitor.Violation
+}
+
+func newRecorder() *recorder {
+	return &recorder{active: make(map[eth.Hash][]monitor.Violation)}
+}
+
+func (r *recorder) Report(_ context.Context, block follower.Block, violations []monitor.Violation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, fmt.Sprintf("+%d", block.Number))
+	r.active[block.Hash] = violations
+	return nil
+}
+
+func (r *recorder) Retract(_ context.Context, block follower.Block, violations []monitor.Violation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, fmt.Sprintf("-%d", block.Number))
+	if len(r.active[block.Hash]) != len(violations) {
+		return fmt.Errorf("retracted %d violations for block %d, reported %d", len(violations), block.Number, len(r.active[block.Hash]))
+	}
+	delete(r.active, block.Hash)
+	return nil
+}
+
+func (r *recorder) take() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := fmt.Sprint(r.events)
+	r.events = nil
+	return events
+}
+
+// checkCanonical verifies that the active violations are exactly those of the canonical blocks from start to head.
+func checkCanonical(t *testing.T, chain *chaintest.Chain, r *recorder, start uint64) {
+	t.Helper()
+	head := uint64(chain.Head().Number)
+	if want := int(head - start + 1); len(r.active) != want {
+		t.Errorf("expected violations for %d blocks, got %d", want, len(r.active))
+	}
+	for n := start; n <= head; n++ {
+		h := chain.Header(n)
+		if v := r.active[h.Hash]; len(v) != 1 || v[0].BlockNumber != n {
+			t.Errorf("expected one violation for canonical block %d, got %v", n, v)
+		}
+	}
+}
+
+func TestFollowFromStartBlock(t *testing.T) {
+	chain := chaintest.NewChain(1700000000)
+	chain.Extend(5)
+	r := newRecorder()
+	f := follower.New(chain, follower.Config{Monitors: []monitor.Monitor{&everyBlock{}}, Sink: r, StartBlock: 2})
+
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping: %v", err)
+	}
+	if events := r.take(); events != "[+2 +3 +4 +5]" {
+		t.Errorf("unexpected events %s", events)
+	}
+
+	// nothing happens until the chain moves
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping: %v", err)
+	}
+	chain.Extend(1)
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping: %v", err)
+	}
+	if events := r.take(); events != "[+6]" {
+		t.Errorf("unexpected events %s", events)
+	}
+	checkCanonical(t, chain, r, 2)
+}
+
+func TestFollowFromHead(t *testing.T) {
+	chain := chaintest.NewChain(1700000000)
+	chain.Extend(5)
+	r := newRecorder()
+	f := follower.New(chain, follower.Config{Monitors: []monitor.Monitor{&everyBlock{}}, Sink: r})
+
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping: %v", err)
+	}
+	if events := r.take(); events != "[+5]" {
+		t.Errorf("unexpected events %s", events)
+	}
+}
+
+func TestReorg(t *testing.T) {
+	cases := []struct {
+		name          string
+		depth, blocks int
+		events        string
+	}{
+		{name: "longer fork", depth: 3, blocks: 4, events: "[-10 -9 -8 +8 +9 +10 +11]"},
+		{name: "equal fork", depth: 2, blocks: 2, events: "[-10 -9 +9 +10]"},
+		{name: "shorter fork", depth: 3, blocks: 1, events: "[-10 -9 -8 +8]"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			chain := chaintest.NewChain(1700000000)
+			chain.Extend(10)
+			r := newRecorder()
+			f := follower.New(chain, follower.Config{Monitors: []monitor.Monitor{&everyBlock{}}, Sink: r, StartBlock: 1})
+			if err := f.Step(context.Background()); err != nil {
+				t.Fatalf("Error stepping: %v", err)
+			}
+			r.take()
+
+			chain.Reorg(c.depth, c.blocks)
+			if err := f.Step(context.Background()); err != nil {
+				t.Fatalf("Error stepping after reorg: %v", err)
+			}
+			if events := r.take(); events != c.events {
+				t.Errorf("unexpected events %s, expected %s", events, c.events)
+			}
+			checkCanonical(t, chain, r, 1)
+
+			window := f.Window()
+			if last := window[len(window)-1]; last.Hash != chain.Head().Hash {
+				t.Errorf("window ends at %d %s, head is %s", last.Number, last.Hash, chain.Head().Hash)
+			}
+		})
+	}
+}
+
+func TestReorgTooDeep(t *testing.T) {
+	chain := chaintest.NewChain(1700000000)
+	chain.Extend(10)
+	r := newRecorder()
+	f := follower.New(chain, follower.Config{Monitors: []monitor.Monitor{&everyBlock{}}, Sink: r, StartBlock: 1, Window: 3})
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping: %v", err)
+	}
+	if window := f.Window(); len(window) != 3 || window[0].Number != 8 {
+		t.Errorf("expected a window of blocks 8 to 10, got %v", window)
+	}
+	r.take()
+
+	chain.Reorg(5, 5)
+	if err := f.Step(context.Background()); !errors.Is(err, follower.ErrReorgTooDeep) {
+		t.Fatalf("expected ErrReorgTooDeep, got %v", err)
+	}
+	if events := r.take(); events != "[-10 -9 -8]" {
+		t.Errorf("unexpected events %s", events)
+	}
+
+	// the follower picks up again from the oldest block it could retract
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping after reorg: %v", err)
+	}
+	if events := r.take(); events != "[+8 +9 +10]" {
+		t.Errorf("unexpected events %s", events)
+	}
+}
+
+func TestMonitorErrorReported(t *testing.T) {
+	chain := chaintest.NewChain(1700000000)
+	chain.Extend(3)
+	failing, healthy := &everyBlock{}, &everyBlock{}
+	failing.fail.Store(true)
+	r := newRecorder()
+	var errs []error
+	f := follower.New(chain, follower.Config{
+		Monitors:   []monitor.Monitor{failing, healthy},
+		Sink:       r,
+		StartBlock: 1,
+		OnError:    func(err error) { errs = append(errs, err) },
+	})
+
+	// the failing monitor does not hold back the blocks, nor the violations of the other monitor
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping: %v", err)
+	}
+	if events := r.take(); events != "[+1 +2 +3]" {
+		t.Errorf("unexpected events %s", events)
+	}
+	checkCanonical(t, chain, r, 1)
+	if len(errs) != 3 {
+		t.Errorf("expected an error per block, got %v", errs)
+	}
+
+	failing.fail.Store(false)
+	chain.Extend(1)
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping: %v", err)
+	}
+	if v := r.active[chain.Head().Hash]; len(v) != 2 {
+		t.Errorf("expected the violations of both monitors once the error is gone, got %v", v)
+	}
+}
+
+// staleHead serves a head that is no longer canonical on the first request of the head.
+type staleHead struct {
+	*chaintest.Chain
+	stale *eth.Header
+}
+
+func (c *staleHead) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*eth.Header, error) {
+	if number == rpc.LatestBlockNumber && c.stale != nil {
+		stale := c.stale
+		c.stale = nil
+		return stale, nil
+	}
+	return c.Chain.HeaderByNumber(ctx, number)
+}
+
+func TestStaleHead(t *testing.T) {
+	chain := &staleHead{Chain: chaintest.NewChain(1700000000)}
+	chain.Extend(3)
+	r := newRecorder()
+	f := follower.New(chain, follower.Config{Monitors: []monitor.Monitor{&everyBlock{}}, Sink: r, StartBlock: 1})
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping: %v", err)
+	}
+	r.take()
+
+	// a head of a fork that was orphaned, whose parent is not the canonical tail
+	chain.stale = &eth.Header{Number: 4, Hash: eth.Hash{4}, ParentHash: eth.Hash{3}}
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping: %v", err)
+	}
+	if events := r.take(); events != "[]" {
+		t.Errorf("unexpected events %s", events)
+	}
+
+	chain.Extend(1)
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping: %v", err)
+	}
+	if events := r.take(); events != "[+4]" {
+		t.Errorf("unexpected events %s", events)
+	}
+	checkCanonical(t, chain.Chain, r, 1)
+}
+
+// slowMonitor tracks how many evaluations run at once.
+type slowMonitor struct {
+	running, peak *atomic.Int32
+}
+
+func (m slowMonitor) Name() string { return "slow" }
+
+func (m slowMonitor) Contract() eth.Address { return eth.Address{} }
+
+func (m slowMonitor) Evaluate(context.Context, monitor.BlockContext) ([]monitor.Violation, error) {
+	n := m.running.Add(1)
+	defer m.running.Add(-1)
+	for {
+		peak := m.peak.Load()
+		if n <= peak || m.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return nil, nil
+}
+
+func TestBoundedConcurrency(t *testing.T) {
+	chain := chaintest.NewChain(1700000000)
+	chain.Extend(2)
+	var running, peak atomic.Int32
+	monitors := make([]monitor.Monitor, 8)
+	for i := range monitors {
+		monitors[i] = slowMonitor{running: &running, peak: &peak}
+	}
+	f := follower.New(chain, follower.Config{Monitors: monitors, Sink: newRecorder(), StartBlock: 1, Concurrency: 3})
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping: %v", err)
+	}
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Errorf("expected up to 3 concurrent evaluations, got %d", p)
+	}
+}
+
+// logMonitor raises a violation for every log of its contract in the block, like the gate Events source.
+type logMonitor struct {
+	contract eth.Address
+}
+
+func (m logMonitor) Name() string { return "logs" }
+
+func (m logMonitor) Contract() eth.Address { return m.contract }
+
+func (m logMonitor) Evaluate(ctx context.Context, bc monitor.BlockContext) ([]monitor.Violation, error) {
+	logs, err := bc.Chain.Logs(ctx, rpc.FilterQuery{BlockHash: &bc.Hash, Addresses: []eth.Address{m.contract}})
+	if err != nil {
+		return nil, err
+	}
+	var violations []monitor.Violation
+	for range logs {
+		violations = append(violations, monitor.Violation{Monitor: m.Name(), Contract: m.contract, BlockNumber: bc.Number, BlockHash: bc.Hash})
+	}
+	return violations, nil
+}
+
+func TestOrphanedEventRetracted(t *testing.T) {
+	contract := eth.MustAddress("0x00000000000000000000000000000000000000AA")
+	chain := chaintest.NewChain(1700000000)
+	chain.Extend(5)
+	chain.AddLog(4, eth.Log{Address: contract})
+	r := newRecorder()
+	f := follower.New(chain, follower.Config{Monitors: []monitor.Monitor{logMonitor{contract}}, Sink: r, StartBlock: 1})
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping: %v", err)
+	}
+	if v := r.active[chain.Header(4).Hash]; len(v) != 1 {
+		t.Fatalf("expected a violation for the event in block 4, got %v", v)
+	}
+
+	// the event is not included in the new fork
+	chain.Reorg(2, 3)
+	if err := f.Step(context.Background()); err != nil {
+		t.Fatalf("Error stepping: %v", err)
+	}
+	for hash, v := range r.active {
+		if len(v) > 0 {
+			t.Errorf("violation %v for block %s remains after the reorg", v, hash)
+		}
+	}
+}
+
+func TestRunWakesOnHeads(t *testing.T) {
+	chain := chaintest.NewChain(1700000000)
+	chain.Extend(1)
+	r := newRecorder()
+	heads := make(chan struct{})
+	f := follower.New(chain, follower.Config{
+		Monitors:     []monitor.Monitor{&everyBlock{}},
+		Sink:         r,
+		StartBlock:   1,
+		PollInterval: time.Hour,
+		Heads:        heads,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- f.Run(ctx) }()
+
+	chain.Extend(1)
+	heads <- struct{}{}
+	// the second send only completes once the follower is back waiting, after stepping over block 2
+	heads <- struct{}{}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected Run to stop with the context, got %v", err)
+	}
+	if events := r.take(); events != "[+1 +2]" {
+		t.Errorf("unexpected events %s", events)
+	}
+}
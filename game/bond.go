@@ -0,0 +1,50 @@
+package game
+
+import (
+	"math/big"
+)
+
+var (
+	// assumedBaseFee, baseGasCharged and highGasCharged are the constants of the bond curve used by
+	// `getRequiredBond`. The root claim is charged baseGasCharged and a claim at the max game depth is
+	// charged highGasCharged, growing exponentially in between.
+	assumedBaseFee = big.NewInt(200_000_000_000) // 200 gwei
+	baseGasCharged = big.NewInt(400_000)
+	highGasCharged = big.NewInt(300_000_000)
+
+	// ClaimedBondFlag is `CLAIMED_BOND_FLAG`, `type(uint128).max`, which replaces the bond of a claim once its
+	// subgame is resolved and the bond is paid out.
+	ClaimedBondFlag = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+)
+
+// RequiredBond mirrors `getRequiredBond(position)`: the bond in wei that a claim at the given position must post
+// in a game with the given max game depth.
+func RequiredBond(position Position, maxGameDepth uint64) (*big.Int, error) {
+	depth := position.Depth()
+	if depth > maxGameDepth {
+		return nil, ErrGameDepthExceeded
+	}
+
+	// The contract solves for the multiplier (highGasCharged / baseGasCharged) ** (1 / maxGameDepth) that, applied
+	// depth times to baseGasCharged, yields the gas charged at that depth.
+	a := new(big.Int).Quo(highGasCharged, baseGasCharged)
+	b := wad
+	c := new(big.Int).Mul(new(big.Int).SetUint64(maxGameDepth), wad)
+
+	lnA, err := lnWad(new(big.Int).Mul(a, wad))
+	if err != nil {
+		return nil, err
+	}
+	bOverC := divWad(b, c)
+	base, err := expWad(mulWad(lnA, bOverC))
+	if err != nil {
+		return nil, err
+	}
+
+	rawGas, err := powWad(base, new(big.Int).Mul(new(big.Int).SetUint64(depth), wad))
+	if err != nil {
+		return nil, err
+	}
+	requiredGas := mulWad(baseGasCharged, rawGas)
+	return requiredGas.Mul(requiredGas, assumedBaseFee), nil
+}
@@ -0,0 +1,92 @@
+package game
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestFixedPointMath(t *testing.T) {
+	// reference values from Solady's FixedPointMathLib tests
+	cases := []struct {
+		name string
+		fn   func(*big.Int) (*big.Int, error)
+		in   string
+		want string
+	}{
+		{name: "expWad(0)", fn: expWad, in: "0", want: "1000000000000000000"},
+		{name: "expWad(1)", fn: expWad, in: "1000000000000000000", want: "2718281828459045235"},
+		{name: "expWad(-1)", fn: expWad, in: "-1000000000000000000", want: "367879441171442321"},
+		{name: "expWad(min)", fn: expWad, in: "-41446531673892822313", want: "0"},
+		{name: "lnWad(1)", fn: lnWad, in: "1000000000000000000", want: "0"},
+		{name: "lnWad(2)", fn: lnWad, in: "2000000000000000000", want: "693147180559945309"},
+		{name: "lnWad(1 wei)", fn: lnWad, in: "1", want: "-41446531673892822313"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := c.fn(bigFromString(c.in))
+			if err != nil {
+				t.Fatalf("Error evaluating %s: %v", c.name, err)
+			}
+			if got.String() != c.want {
+				t.Errorf("expected %s, got %s", c.want, got)
+			}
+		})
+	}
+
+	if _, err := lnWad(big.NewInt(0)); !errors.Is(err, errLnWadUndefined) {
+		t.Errorf("expected %v, got %v", errLnWadUndefined, err)
+	}
+	if _, err := expWad(bigFromString("135305999368893231589")); !errors.Is(err, errExpOverflow) {
+		t.Errorf("expected %v, got %v", errExpOverflow, err)
+	}
+}
+
+func TestRequiredBond(t *testing.T) {
+	// expected bonds for a game with the Base mainnet max game depth of 73
+	cases := []struct {
+		depth uint64
+		want  string
+	}{
+		{depth: 0, want: "80000000000000000"}, // 0.08 ETH for the root claim
+		{depth: 1, want: "87594000000000000"},
+		{depth: 2, want: "95908800000000000"},
+		{depth: 30, want: "1215127800000000000"},
+		{depth: 73, want: "59999999800000000000"}, // ~60 ETH at the max game depth
+	}
+	for _, c := range cases {
+		// the bond only depends on the depth of the position, not the index at that depth
+		for _, index := range []int64{0, 1} {
+			if c.depth == 0 && index > 0 {
+				continue
+			}
+			bond, err := RequiredBond(NewPosition(c.depth, big.NewInt(index)), 73)
+			if err != nil {
+				t.Fatalf("Error computing bond at depth %d: %v", c.depth, err)
+			}
+			if bond.String() != c.want {
+				t.Errorf("expected bond %s at depth %d, got %s", c.want, c.depth, bond)
+			}
+		}
+	}
+}
+
+func TestRequiredBondIncreasesWithDepth(t *testing.T) {
+	prev := new(big.Int)
+	for depth := uint64(0); depth <= 73; depth++ {
+		bond, err := RequiredBond(NewPosition(depth, big.NewInt(0)), 73)
+		if err != nil {
+			t.Fatalf("Error computing bond at depth %d: %v", depth, err)
+		}
+		if bond.Cmp(prev) <= 0 {
+			t.Fatalf("expected bond at depth %d to exceed %v, got %v", depth, prev, bond)
+		}
+		prev = bond
+	}
+}
+
+func TestRequiredBondGameDepthExceeded(t *testing.T) {
+	if _, err := RequiredBond(NewPosition(74, big.NewInt(0)), 73); !errors.Is(err, ErrGameDepthExceeded) {
+		t.Errorf("expected %v, got %v", ErrGameDepthExceeded, err)
+	}
+}
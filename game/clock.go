@@ -0,0 +1,178 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// RootParentIndex is the parent index stored for the root claim, `type(uint32).max` on-chain.
+const RootParentIndex = math.MaxUint32
+
+var (
+	// ErrClockTimeExceeded mirrors the contract's ClockTimeExceeded revert: the countered claim's clock has run out.
+	ErrClockTimeExceeded = errors.New("clock time exceeded")
+	// ErrGameDepthExceeded mirrors the contract's GameDepthExceeded revert.
+	ErrGameDepthExceeded = errors.New("game depth exceeded")
+)
+
+// Clock is a chess clock as packed into `claimData(i).clock`: the upper 64 bits hold the accumulated duration
+// and the lower 64 bits hold the timestamp at which the clock was started.
+type Clock struct {
+	Duration  uint64
+	Timestamp uint64
+}
+
+// DecodeClock unpacks a uint128 clock value.
+func DecodeClock(raw *big.Int) (Clock, error) {
+	if raw == nil || raw.Sign() < 0 || raw.BitLen() > 128 {
+		return Clock{}, fmt.Errorf("invalid clock %v", raw)
+	}
+	duration := new(big.Int).Rsh(raw, 64)
+	timestamp := new(big.Int).And(raw, new(big.Int).SetUint64(math.MaxUint64))
+	return Clock{Duration: duration.Uint64(), Timestamp: timestamp.Uint64()}, nil
+}
+
+// Raw packs the clock back into its uint128 representation.
+func (c Clock) Raw() *big.Int {
+	raw := new(big.Int).SetUint64(c.Duration)
+	raw.Lsh(raw, 64)
+	return raw.Or(raw, new(big.Int).SetUint64(c.Timestamp))
+}
+
+// Config holds the game immutables that drive the clock rules. All durations are in seconds.
+type Config struct {
+	// MaxClockDuration is `maxClockDuration()`, the time budget of each team.
+	MaxClockDuration uint64
+	// ClockExtension is `clockExtension()`, the minimum time a team is guaranteed when inheriting a clock.
+	ClockExtension uint64
+	// SplitDepth is `splitDepth()`, the depth at which output bisection turns into execution trace bisection.
+	SplitDepth uint64
+	// MaxGameDepth is `maxGameDepth()`.
+	MaxGameDepth uint64
+	// OracleChallengePeriod is the PreimageOracle `challengePeriod()`, granted on top of the clock extension
+	// ahead of a step at the bottom of the game.
+	OracleChallengePeriod uint64
+}
+
+// Extension returns the clock extension applied to a move landing at the given depth.
+func (c Config) Extension(depth uint64) uint64 {
+	switch {
+	case depth == c.MaxGameDepth-1:
+		return c.ClockExtension + c.OracleChallengePeriod
+	case depth == c.SplitDepth-1:
+		return c.ClockExtension * 2
+	default:
+		return c.ClockExtension
+	}
+}
+
+// Claim is the subset of `claimData(i)` that the clock and bond rules depend on.
+type Claim struct {
+	ParentIndex uint32
+	Position    Position
+	Clock       Clock
+}
+
+// Game is an in-memory view of a dispute game's claim tree.
+type Game struct {
+	Config Config
+	Claims []Claim
+}
+
+// NewGame returns a game holding only the root claim, created at the given timestamp.
+func NewGame(cfg Config, createdAt uint64) *Game {
+	return &Game{
+		Config: cfg,
+		Claims: []Claim{{
+			ParentIndex: RootParentIndex,
+			Position:    RootPosition,
+			Clock:       Clock{Duration: 0, Timestamp: createdAt},
+		}},
+	}
+}
+
+// CreatedAt returns the creation timestamp of the game, which is when the root claim's clock started.
+func (g *Game) CreatedAt() uint64 {
+	return g.Claims[0].Clock.Timestamp
+}
+
+// ChallengerDuration mirrors `getChallengerDuration(idx)`: the time accumulated on the clock of the team that
+// would counter the claim at idx, as of the given timestamp, capped at MaxClockDuration.
+func (g *Game) ChallengerDuration(idx int, now uint64) uint64 {
+	claim := g.Claims[idx]
+	var parentDuration uint64
+	if claim.ParentIndex != RootParentIndex {
+		parentDuration = g.Claims[claim.ParentIndex].Clock.Duration
+	}
+	var elapsed uint64
+	if now > claim.Clock.Timestamp {
+		elapsed = now - claim.Clock.Timestamp
+	}
+	duration := parentDuration + elapsed
+	if duration > g.Config.MaxClockDuration {
+		return g.Config.MaxClockDuration
+	}
+	return duration
+}
+
+// NextClock returns the clock a counter to the claim at idx would receive if made at the given timestamp,
+// applying the same clock extension rules as `move`.
+func (g *Game) NextClock(idx int, isAttack bool, now uint64) (Clock, Position, error) {
+	next := g.Claims[idx].Position.Move(isAttack)
+	depth := next.Depth()
+	if depth > g.Config.MaxGameDepth {
+		return Clock{}, Position{}, ErrGameDepthExceeded
+	}
+
+	duration := g.ChallengerDuration(idx, now)
+	if duration == g.Config.MaxClockDuration {
+		return Clock{}, Position{}, ErrClockTimeExceeded
+	}
+
+	extension := g.Config.Extension(depth)
+	if extension > g.Config.MaxClockDuration {
+		extension = g.Config.MaxClockDuration
+	}
+	if duration > g.Config.MaxClockDuration-extension {
+		duration = g.Config.MaxClockDuration - extension
+	}
+	return Clock{Duration: duration, Timestamp: now}, next, nil
+}
+
+// Move appends a counter to the claim at idx made at the given timestamp and returns the index of the new claim.
+func (g *Game) Move(idx int, isAttack bool, now uint64) (int, error) {
+	clock, position, err := g.NextClock(idx, isAttack, now)
+	if err != nil {
+		return 0, err
+	}
+	g.Claims = append(g.Claims, Claim{ParentIndex: uint32(idx), Position: position, Clock: clock})
+	return len(g.Claims) - 1, nil
+}
+
+// ClaimResolvableAt returns the earliest timestamp at which the subgame rooted at idx may be resolved, which is
+// when the clock of its would-be counter reaches MaxClockDuration.
+func (g *Game) ClaimResolvableAt(idx int) uint64 {
+	claim := g.Claims[idx]
+	var parentDuration uint64
+	if claim.ParentIndex != RootParentIndex {
+		parentDuration = g.Claims[claim.ParentIndex].Clock.Duration
+	}
+	if parentDuration > g.Config.MaxClockDuration {
+		return claim.Clock.Timestamp
+	}
+	return claim.Clock.Timestamp + g.Config.MaxClockDuration - parentDuration
+}
+
+// ResolvableAt returns the earliest timestamp at which the whole game may be resolved assuming no further
+// moves are made. Subgames resolve bottom up, so this is the latest resolvable time across all claims.
+func (g *Game) ResolvableAt() uint64 {
+	var deadline uint64
+	for idx := range g.Claims {
+		if at := g.ClaimResolvableAt(idx); at > deadline {
+			deadline = at
+		}
+	}
+	return deadline
+}
@@ -0,0 +1,172 @@
+package game
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// testConfig uses the Base mainnet FaultDisputeGame parameters
+var testConfig = Config{
+	MaxClockDuration:      302400, // 3.5 days
+	ClockExtension:        10800,  // 3 hours
+	SplitDepth:            30,
+	MaxGameDepth:          73,
+	OracleChallengePeriod: 86400, // 1 day
+}
+
+func TestClockRoundTrip(t *testing.T) {
+	clock := Clock{Duration: 302400, Timestamp: 1727000000}
+	raw := clock.Raw()
+
+	// duration is packed into the upper 64 bits
+	expected := new(big.Int).Lsh(big.NewInt(302400), 64)
+	expected.Add(expected, big.NewInt(1727000000))
+	if raw.Cmp(expected) != 0 {
+		t.Fatalf("expected raw clock %v, got %v", expected, raw)
+	}
+
+	decoded, err := DecodeClock(raw)
+	if err != nil {
+		t.Fatalf("Error decoding clock: %v", err)
+	}
+	if decoded != clock {
+		t.Errorf("expected %+v, got %+v", clock, decoded)
+	}
+}
+
+func TestResolvableAtRootClaimOnly(t *testing.T) {
+	// An unchallenged root claim can be resolved once the challenger's clock runs out
+	g := NewGame(testConfig, 1000)
+	if got, want := g.ResolvableAt(), uint64(1000+302400); got != want {
+		t.Errorf("expected resolvable at %d, got %d", want, got)
+	}
+}
+
+func TestResolvableAtAlternatingMoves(t *testing.T) {
+	// Each team's clock only ticks while it is their turn, so the deadline follows the latest claim
+	g := NewGame(testConfig, 0)
+
+	// challenger uses 100s of their clock to attack the root claim
+	attack, err := g.Move(0, true, 100)
+	if err != nil {
+		t.Fatalf("Error attacking root claim: %v", err)
+	}
+	// defender uses 200s of their clock to counter
+	counter, err := g.Move(attack, true, 300)
+	if err != nil {
+		t.Fatalf("Error countering attack: %v", err)
+	}
+
+	if got := g.Claims[attack].Clock; got != (Clock{Duration: 100, Timestamp: 100}) {
+		t.Errorf("unexpected attack clock %+v", got)
+	}
+	if got := g.Claims[counter].Clock; got != (Clock{Duration: 200, Timestamp: 300}) {
+		t.Errorf("unexpected counter clock %+v", got)
+	}
+
+	// the challenger's clock has 100s on it, so the counter can be resolved 302300s after it was made
+	if got, want := g.ClaimResolvableAt(counter), uint64(300+302400-100); got != want {
+		t.Errorf("expected counter resolvable at %d, got %d", want, got)
+	}
+	if got, want := g.ResolvableAt(), uint64(300+302400-100); got != want {
+		t.Errorf("expected game resolvable at %d, got %d", want, got)
+	}
+}
+
+func TestClockExtension(t *testing.T) {
+	// A move that would leave the next team with less than the extension is topped back up
+	g := NewGame(testConfig, 0)
+	now := uint64(302400 - 60) // challenger waits until a minute is left on their clock
+	attack, err := g.Move(0, true, now)
+	if err != nil {
+		t.Fatalf("Error attacking root claim: %v", err)
+	}
+
+	if got, want := g.Claims[attack].Clock.Duration, uint64(302400-10800); got != want {
+		t.Errorf("expected extended duration %d, got %d", want, got)
+	}
+	// the defender has not used any of their clock yet, so the attack is resolvable a full clock after it was made
+	if got, want := g.ResolvableAt(), now+302400; got != want {
+		t.Errorf("expected game resolvable at %d, got %d", want, got)
+	}
+}
+
+func TestClockExtensionSplitAndMaxDepth(t *testing.T) {
+	cases := []struct {
+		name  string
+		depth uint64
+		want  uint64
+	}{
+		{name: "standard", depth: 5, want: 10800},
+		{name: "split depth", depth: testConfig.SplitDepth - 1, want: 2 * 10800},
+		{name: "max depth", depth: testConfig.MaxGameDepth - 1, want: 10800 + 86400},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := testConfig.Extension(c.depth); got != c.want {
+				t.Errorf("expected extension %d, got %d", c.want, got)
+			}
+		})
+	}
+
+	// walk down the tree attacking with almost no time left until we reach the split depth; once both teams
+	// have been extended, they only ever have the extension left on their clock
+	g := NewGame(testConfig, 0)
+	idx, now := 0, uint64(0)
+	for depth := uint64(1); depth < testConfig.SplitDepth; depth++ {
+		if depth <= 2 {
+			now += 302400 - 1
+		} else {
+			now += 10800 - 1
+		}
+		var err error
+		if idx, err = g.Move(idx, true, now); err != nil {
+			t.Fatalf("Error moving at depth %d: %v", depth, err)
+		}
+	}
+	if got, want := g.Claims[idx].Position.Depth(), testConfig.SplitDepth-1; got != want {
+		t.Fatalf("expected depth %d, got %d", want, got)
+	}
+	if got, want := g.Claims[idx].Clock.Duration, uint64(302400-2*10800); got != want {
+		t.Errorf("expected split depth duration %d, got %d", want, got)
+	}
+}
+
+func TestMoveClockTimeExceeded(t *testing.T) {
+	g := NewGame(testConfig, 0)
+	if _, err := g.Move(0, true, 302400); !errors.Is(err, ErrClockTimeExceeded) {
+		t.Errorf("expected %v, got %v", ErrClockTimeExceeded, err)
+	}
+}
+
+func TestMoveGameDepthExceeded(t *testing.T) {
+	cfg := testConfig
+	cfg.MaxGameDepth = 2
+	cfg.SplitDepth = 1
+	g := NewGame(cfg, 0)
+
+	idx := 0
+	for i := 0; i < 2; i++ {
+		var err error
+		if idx, err = g.Move(idx, true, 1); err != nil {
+			t.Fatalf("Error moving: %v", err)
+		}
+	}
+	if _, err := g.Move(idx, true, 1); !errors.Is(err, ErrGameDepthExceeded) {
+		t.Errorf("expected %v, got %v", ErrGameDepthExceeded, err)
+	}
+}
+
+func TestPositionMove(t *testing.T) {
+	attack := RootPosition.Move(true)
+	if attack.GIndex().Int64() != 2 || attack.Depth() != 1 {
+		t.Errorf("unexpected attack position %v", attack)
+	}
+
+	// defending the attack moves to the left child of its right sibling
+	defend := attack.Move(false)
+	if defend.GIndex().Int64() != 6 || defend.Depth() != 2 || defend.IndexAtDepth().Int64() != 2 {
+		t.Errorf("unexpected defense position %v", defend)
+	}
+}
@@ -0,0 +1,155 @@
+package game
+
+import (
+	"errors"
+	"math/big"
+)
+
+// The functions below port the subset of Solady's FixedPointMathLib used by `getRequiredBond`. They operate on
+// 18 decimal fixed point numbers (WAD) and reproduce the contract's integer rounding exactly, so the result
+// matches the on-chain bond to the wei.
+
+var (
+	wad = big.NewInt(1e18)
+
+	errLnWadUndefined = errors.New("lnWad undefined for non-positive input")
+	errExpOverflow    = errors.New("expWad overflow")
+)
+
+func bigFromString(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid constant " + s)
+	}
+	return v
+}
+
+var (
+	expWadMin      = bigFromString("-41446531673892822313")
+	expWadMax      = bigFromString("135305999368893231589")
+	fiveToThe18    = new(big.Int).Exp(big.NewInt(5), big.NewInt(18), nil)
+	ln2Times2To96  = bigFromString("54916777467707473351141471128")
+	twoTo95        = new(big.Int).Lsh(big.NewInt(1), 95)
+	expWadScale    = bigFromString("3822833074963236453042738258902158003155416615667")
+	lnWadScale     = bigFromString("1677202110996718588342820967067443963516166")
+	lnWadLn2       = bigFromString("16597577552685614221487285958193947469193820559219878177908093499208371")
+	lnWadBaseShift = bigFromString("600920179829731861736702779321621459595472258049074101567377883020018308")
+)
+
+// mulWad returns x * y / WAD, rounded down.
+func mulWad(x, y *big.Int) *big.Int {
+	r := new(big.Int).Mul(x, y)
+	return r.Quo(r, wad)
+}
+
+// divWad returns x * WAD / y, rounded down.
+func divWad(x, y *big.Int) *big.Int {
+	r := new(big.Int).Mul(x, wad)
+	return r.Quo(r, y)
+}
+
+// sar is an arithmetic shift right, matching the EVM's SAR and Solidity's >> on signed integers.
+func sar(x *big.Int, n uint) *big.Int {
+	return new(big.Int).Rsh(x, n)
+}
+
+// expWad returns exp(x) for a WAD x using a (6, 7)-term rational approximation.
+func expWad(x *big.Int) (*big.Int, error) {
+	if x.Cmp(expWadMin) <= 0 {
+		return new(big.Int), nil
+	}
+	if x.Cmp(expWadMax) >= 0 {
+		return nil, errExpOverflow
+	}
+
+	// convert x from a WAD into a 2**96 basis: x = (x << 78) / 5**18
+	x = new(big.Int).Quo(new(big.Int).Lsh(x, 78), fiveToThe18)
+
+	// reduce the range of x to (-½ ln 2, ½ ln 2) * 2**96 by factoring out powers of two
+	k := new(big.Int).Quo(new(big.Int).Lsh(x, 96), ln2Times2To96)
+	k = sar(k.Add(k, twoTo95), 96)
+	x = new(big.Int).Sub(x, new(big.Int).Mul(k, ln2Times2To96))
+
+	y := new(big.Int).Add(x, bigFromString("1346386616545796478920950773328"))
+	y = sar(y.Mul(y, x), 96)
+	y.Add(y, bigFromString("57155421227552351082224309758442"))
+	p := new(big.Int).Add(y, x)
+	p.Sub(p, bigFromString("94201549194550492254356042504812"))
+	p = sar(p.Mul(p, y), 96)
+	p.Add(p, bigFromString("28719021644029726153956944680412240"))
+	p.Mul(p, x)
+	p.Add(p, new(big.Int).Lsh(bigFromString("4385272521454847904659076985693276"), 96))
+
+	q := new(big.Int).Sub(x, bigFromString("2855989394907223263936484059900"))
+	q = sar(q.Mul(q, x), 96)
+	q.Add(q, bigFromString("50020603652535783019961831881945"))
+	q = sar(q.Mul(q, x), 96)
+	q.Sub(q, bigFromString("533845033583426703283633433725380"))
+	q = sar(q.Mul(q, x), 96)
+	q.Add(q, bigFromString("3604857256930695427073651918091429"))
+	q = sar(q.Mul(q, x), 96)
+	q.Sub(q, bigFromString("14423608567350463180887372962807573"))
+	q = sar(q.Mul(q, x), 96)
+	q.Add(q, bigFromString("26449188498355588339934803723976023"))
+
+	r := new(big.Int).Quo(p, q)
+	r.Mul(r, expWadScale)
+	return r.Rsh(r, uint(195-k.Int64())), nil
+}
+
+// lnWad returns ln(x) for a WAD x using an (8, 8)-term rational approximation.
+func lnWad(x *big.Int) (*big.Int, error) {
+	if x.Sign() <= 0 {
+		return nil, errLnWadUndefined
+	}
+
+	// r = 255 - log2(x), so that x << r has its most significant bit at position 255
+	r := uint(256 - x.BitLen())
+
+	// reduce the range of x to (1, 2) * 2**96
+	x = new(big.Int).Rsh(new(big.Int).Lsh(x, r), 159)
+
+	p := new(big.Int).Add(bigFromString("3273285459638523848632254066296"), x)
+	p = sar(p.Mul(p, x), 96)
+	p.Add(p, bigFromString("24828157081833163892658089445524"))
+	p = sar(p.Mul(p, x), 96)
+	p.Add(p, bigFromString("43456485725739037958740375743393"))
+	p = sar(p.Mul(p, x), 96)
+	p.Sub(p, bigFromString("11111509109440967052023855526967"))
+	p = sar(p.Mul(p, x), 96)
+	p.Sub(p, bigFromString("45023709667254063763336534515857"))
+	p = sar(p.Mul(p, x), 96)
+	p.Sub(p, bigFromString("14706773417378608786704636184526"))
+	p.Mul(p, x)
+	p.Sub(p, new(big.Int).Lsh(bigFromString("795164235651350426258249787498"), 96))
+
+	q := new(big.Int).Add(bigFromString("5573035233440673466300451813936"), x)
+	q = sar(q.Mul(q, x), 96)
+	q.Add(q, bigFromString("71694874799317883764090561454958"))
+	q = sar(q.Mul(q, x), 96)
+	q.Add(q, bigFromString("283447036172924575727196451306956"))
+	q = sar(q.Mul(q, x), 96)
+	q.Add(q, bigFromString("401686690394027663651624208769553"))
+	q = sar(q.Mul(q, x), 96)
+	q.Add(q, bigFromString("204048457590392012362485061816622"))
+	q = sar(q.Mul(q, x), 96)
+	q.Add(q, bigFromString("31853899698501571402653359427138"))
+	q = sar(q.Mul(q, x), 96)
+	q.Add(q, bigFromString("909429971244387300277376558375"))
+
+	p.Quo(p, q)
+	p.Mul(p, lnWadScale)
+	p.Add(p, new(big.Int).Mul(lnWadLn2, big.NewInt(int64(159)-int64(r))))
+	p.Add(p, lnWadBaseShift)
+	return sar(p, 174), nil
+}
+
+// powWad returns x ** y for WAD x and y, computed as exp(ln(x) * y).
+func powWad(x, y *big.Int) (*big.Int, error) {
+	lnX, err := lnWad(x)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).Mul(lnX, y)
+	return expWad(exponent.Quo(exponent, wad))
+}
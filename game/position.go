@@ -0,0 +1,66 @@
+// Package game models the on-chain rules of the FaultDisputeGame contract that the monitors reason about:
+// claim positions, chess clocks and bond requirements.
+package game
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Position is a generalized index into the game tree, mirroring LibPosition. The root claim sits at gindex 1,
+// and the children of a position p are 2p (attack) and 2p+1.
+type Position struct {
+	gindex *big.Int
+}
+
+// RootPosition is the position of the root claim.
+var RootPosition = Position{gindex: big.NewInt(1)}
+
+// NewPosition returns the position at the given depth and index at depth.
+func NewPosition(depth uint64, indexAtDepth *big.Int) Position {
+	gindex := new(big.Int).Lsh(big.NewInt(1), uint(depth))
+	return Position{gindex: gindex.Add(gindex, indexAtDepth)}
+}
+
+// PositionFromGIndex wraps a raw generalized index, as stored in `claimData(i).position`.
+func PositionFromGIndex(gindex *big.Int) (Position, error) {
+	if gindex == nil || gindex.Sign() <= 0 {
+		return Position{}, fmt.Errorf("invalid position gindex %v", gindex)
+	}
+	return Position{gindex: new(big.Int).Set(gindex)}, nil
+}
+
+// GIndex returns the raw generalized index of the position.
+func (p Position) GIndex() *big.Int {
+	return new(big.Int).Set(p.gindex)
+}
+
+// Depth returns the depth of the position in the game tree; the root claim is at depth 0.
+func (p Position) Depth() uint64 {
+	return uint64(p.gindex.BitLen() - 1)
+}
+
+// IndexAtDepth returns the index of the position relative to the leftmost position at its depth.
+func (p Position) IndexAtDepth() *big.Int {
+	leftmost := new(big.Int).Lsh(big.NewInt(1), uint(p.Depth()))
+	return new(big.Int).Sub(p.gindex, leftmost)
+}
+
+// Move returns the position of a move against p, matching LibPosition.move: an attack goes to the left child
+// of p, and a defense goes to the left child of p's right sibling.
+func (p Position) Move(isAttack bool) Position {
+	next := new(big.Int).Set(p.gindex)
+	if !isAttack {
+		next.SetBit(next, 0, 1)
+	}
+	return Position{gindex: next.Lsh(next, 1)}
+}
+
+// Equal reports whether two positions share the same generalized index.
+func (p Position) Equal(other Position) bool {
+	return p.gindex.Cmp(other.gindex) == 0
+}
+
+func (p Position) String() string {
+	return p.gindex.String()
+}
@@ -3,3 +3,5 @@ module github.com/base-org/fault-proof-monitors
 go 1.21.1
 
 require github.com/joho/godotenv v1.5.1
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,110 @@
+// Package hexagate is a client for the Hexagate API: the validate endpoint, which runs the gate files in this
+// repository, and the monitoring management API, which deploys them as user monitors.
+package hexagate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultBaseURL is the root of the Hexagate API.
+	DefaultBaseURL = "https://api.hexagate.com/api/v1"
+	// DefaultTimeout bounds requests made with a context that has no deadline.
+	DefaultTimeout = time.Minute
+)
+
+// APIError is returned when the API answers with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("hexagate api request failed with status %d: %s", e.StatusCode, e.Body)
+}
+
+// Client sends authenticated requests to the Hexagate API.
+type Client struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+	timeout    time.Duration
+}
+
+// NewClient returns a client authenticating with apiKey.
+func NewClient(apiKey string) *Client {
+	return &Client{
+		baseURL:    DefaultBaseURL,
+		apiKey:     apiKey,
+		httpClient: &http.Client{},
+		timeout:    DefaultTimeout,
+	}
+}
+
+// SetBaseURL points the client at another deployment of the API, such as a fake server in tests.
+func (c *Client) SetBaseURL(baseURL string) {
+	c.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// SetTimeout changes the timeout applied to requests made with a context that has no deadline.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// do sends a request with optional headers and JSON body, and decodes the JSON response into result, which may be
+// nil.
+func (c *Client) do(ctx context.Context, method, path string, header http.Header, body, result any) error {
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		// gate sources contain comparisons such as `<`, which must not be escaped
+		data := new(bytes.Buffer)
+		enc := json.NewEncoder(data)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(body); err != nil {
+			return err
+		}
+		reqBody = data
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-Hexagate-Api-Key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &APIError{StatusCode: resp.StatusCode, Body: string(bytes.TrimSpace(respBody))}
+	}
+	if result == nil || len(respBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(respBody, result)
+}
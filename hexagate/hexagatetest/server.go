@@ -0,0 +1,263 @@
+// Package hexagatetest provides a fake Hexagate management API for testing code that deploys monitors.
+package hexagatetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/base-org/fault-proof-monitors/hexagate"
+)
+
+// Server is an HTTP server storing the monitors created through it in memory. Requests must carry its API key.
+type Server struct {
+	*httptest.Server
+
+	apiKey string
+
+	mu       sync.Mutex
+	monitors map[int64]hexagate.Monitor
+	nextID   int64
+	failures []int
+	// keys maps the idempotency keys of create requests to the monitors they created
+	keys    map[string]int64
+	creates int
+}
+
+// NewServer starts a server accepting apiKey. Callers must Close it.
+func NewServer(apiKey string) *Server {
+	s := &Server{
+		apiKey:   apiKey,
+		monitors: make(map[int64]hexagate.Monitor),
+		nextID:   1,
+		keys:     make(map[string]int64),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(hexagate.MonitorsPath, s.serveMonitors)
+	mux.HandleFunc(hexagate.MonitorsPath+"/", s.serveMonitor)
+	s.Server = httptest.NewServer(s.authenticate(mux))
+	return s
+}
+
+// Client returns a client authenticated with the server.
+func (s *Server) Client() *hexagate.Client {
+	c := hexagate.NewClient(s.apiKey)
+	c.SetBaseURL(s.URL)
+	return c
+}
+
+// FailNext makes the next requests fail with the given statuses, one request per status. A zero status lets its
+// request through.
+func (s *Server) FailNext(statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statuses...)
+}
+
+// Monitors returns the stored monitors ordered by ID.
+func (s *Server) Monitors() []hexagate.Monitor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sorted(nil)
+}
+
+// Creates returns the number of monitors created, excluding the requests that repeated an idempotency key.
+func (s *Server) Creates() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.creates
+}
+
+func (s *Server) sorted(filter func(hexagate.Monitor) bool) []hexagate.Monitor {
+	monitors := make([]hexagate.Monitor, 0, len(s.monitors))
+	for _, m := range s.monitors {
+		if filter == nil || filter(m) {
+			monitors = append(monitors, m)
+		}
+	}
+	sort.Slice(monitors, func(i, j int) bool { return monitors[i].ID < monitors[j].ID })
+	return monitors
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Hexagate-Api-Key") != s.apiKey {
+			writeError(w, http.StatusUnauthorized, "invalid api key")
+			return
+		}
+		s.mu.Lock()
+		var status int
+		if len(s.failures) > 0 {
+			status, s.failures = s.failures[0], s.failures[1:]
+		}
+		s.mu.Unlock()
+		if status != 0 {
+			writeError(w, status, http.StatusText(status))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) serveMonitors(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.list(w, r)
+	case http.MethodPost:
+		s.create(w, r)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) create(w http.ResponseWriter, r *http.Request) {
+	var m hexagate.Monitor
+	if err := json.NewDecoder(r.Body).Decode(&m); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if m.Name == "" || m.Gate == "" {
+		writeError(w, http.StatusBadRequest, "name and gate are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := r.Header.Get(hexagate.IdempotencyKeyHeader)
+	if id, ok := s.keys[key]; ok && key != "" {
+		if existing, ok := s.monitors[id]; ok {
+			writeJSON(w, http.StatusCreated, existing)
+			return
+		}
+	}
+	m.ID = s.nextID
+	m.Status = hexagate.MonitorActive
+	s.nextID++
+	s.monitors[m.ID] = m
+	s.creates++
+	if key != "" {
+		s.keys[key] = m.ID
+	}
+	writeJSON(w, http.StatusCreated, m)
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	page, err := strconv.Atoi(q.Get("page"))
+	if err != nil || page < 1 {
+		writeError(w, http.StatusBadRequest, "invalid page")
+		return
+	}
+	size, err := strconv.Atoi(q.Get("page_size"))
+	if err != nil || size < 1 {
+		writeError(w, http.StatusBadRequest, "invalid page_size")
+		return
+	}
+	labels := make(map[string]string)
+	for _, l := range q["label"] {
+		k, v, ok := strings.Cut(l, ":")
+		if !ok {
+			writeError(w, http.StatusBadRequest, "invalid label")
+			return
+		}
+		labels[k] = v
+	}
+
+	s.mu.Lock()
+	matching := s.sorted(func(m hexagate.Monitor) bool {
+		if id := q.Get("chain_id"); id != "" && id != strconv.FormatUint(m.ChainID, 10) {
+			return false
+		}
+		if status := q.Get("status"); status != "" && status != string(m.Status) {
+			return false
+		}
+		if !strings.Contains(m.Name, q.Get("name")) {
+			return false
+		}
+		for k, v := range labels {
+			if m.Labels[k] != v {
+				return false
+			}
+		}
+		return true
+	})
+	s.mu.Unlock()
+
+	resp := hexagate.MonitorPage{Items: []hexagate.Monitor{}, Page: page, PageSize: size, Total: len(matching)}
+	if start := (page - 1) * size; start < len(matching) {
+		resp.Items = matching[start:min(start+size, len(matching))]
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) serveMonitor(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, hexagate.MonitorsPath+"/")
+	idPart, action, _ := strings.Cut(rest, "/")
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "monitor not found")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.monitors[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "monitor not found")
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+	case action == "" && r.Method == http.MethodPatch:
+		var update hexagate.MonitorUpdate
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if update.Name != "" {
+			m.Name = update.Name
+		}
+		if update.Description != "" {
+			m.Description = update.Description
+		}
+		if update.Gate != "" {
+			m.Gate = update.Gate
+		}
+		if update.Params != nil {
+			m.Params = update.Params
+		}
+		if update.Channels != nil {
+			m.Channels = update.Channels
+		}
+		if update.Labels != nil {
+			m.Labels = update.Labels
+		}
+	case action == "" && r.Method == http.MethodDelete:
+		delete(s.monitors, id)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case action == "pause" && r.Method == http.MethodPost:
+		m.Status = hexagate.MonitorPaused
+	case action == "resume" && r.Method == http.MethodPost:
+		m.Status = hexagate.MonitorActive
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	s.monitors[id] = m
+	writeJSON(w, http.StatusOK, m)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
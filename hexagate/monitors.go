@@ -0,0 +1,210 @@
+package hexagate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+const (
+	// MonitorsPath is the path of the user monitors collection of the management API.
+	MonitorsPath = "/monitoring/user_monitors"
+	// IdempotencyKeyHeader carries the idempotency key of a create request. The API answers requests repeating a
+	// key with the monitor created by the first one.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// DefaultPageSize is the number of monitors listed per page when ListOptions.PageSize is zero.
+	DefaultPageSize = 100
+)
+
+// MonitorStatus is whether a monitor is evaluated.
+type MonitorStatus string
+
+const (
+	MonitorActive MonitorStatus = "active"
+	MonitorPaused MonitorStatus = "paused"
+)
+
+// Monitor is a user monitor with a single condition: a gate file run with a set of params.
+type Monitor struct {
+	// ID and Status are assigned by the API.
+	ID          int64          `json:"id,omitempty"`
+	Status      MonitorStatus  `json:"status,omitempty"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	ChainID     uint64         `json:"chain_id"`
+	Gate        string         `json:"gate"`
+	Params      map[string]any `json:"params,omitempty"`
+	// Channels are the notification channels the alerts of the monitor are sent to.
+	Channels []string `json:"channels,omitempty"`
+	// Labels annotate the monitor, so that it can be found by ListMonitors.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// MonitorUpdate changes a monitor. Empty fields are left unchanged, and Params replaces the params as a whole.
+type MonitorUpdate struct {
+	Name        string            `json:"name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Gate        string            `json:"gate,omitempty"`
+	Params      map[string]any    `json:"params,omitempty"`
+	Channels    []string          `json:"channels,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+}
+
+// ListOptions filters the monitors listed. Zero fields do not filter.
+type ListOptions struct {
+	ChainID uint64
+	Status  MonitorStatus
+	// Name matches monitors whose name contains it.
+	Name string
+	// Labels matches monitors holding every one of them.
+	Labels map[string]string
+	// Page is the page to list, starting at 1.
+	Page     int
+	PageSize int
+}
+
+func (o ListOptions) query() url.Values {
+	q := url.Values{}
+	if o.ChainID != 0 {
+		q.Set("chain_id", strconv.FormatUint(o.ChainID, 10))
+	}
+	if o.Status != "" {
+		q.Set("status", string(o.Status))
+	}
+	if o.Name != "" {
+		q.Set("name", o.Name)
+	}
+	keys := make([]string, 0, len(o.Labels))
+	for k := range o.Labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		q.Add("label", k+":"+o.Labels[k])
+	}
+	page, size := o.Page, o.PageSize
+	if page == 0 {
+		page = 1
+	}
+	if size == 0 {
+		size = DefaultPageSize
+	}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("page_size", strconv.Itoa(size))
+	return q
+}
+
+// MonitorPage is a page of listed monitors.
+type MonitorPage struct {
+	Items    []Monitor `json:"items"`
+	Page     int       `json:"page"`
+	PageSize int       `json:"page_size"`
+	// Total is the number of monitors matching the filters, over every page.
+	Total int `json:"total"`
+}
+
+// HasNext reports whether there are pages after this one.
+func (p *MonitorPage) HasNext() bool {
+	return p.Page*p.PageSize < p.Total
+}
+
+func monitorPath(id int64, action string) string {
+	path := fmt.Sprintf("%s/%d", MonitorsPath, id)
+	if action != "" {
+		path += "/" + action
+	}
+	return path
+}
+
+// CreateMonitor creates a user monitor and returns it as stored by the API. If idempotencyKey is not empty, retrying
+// the request with the same key returns the monitor created by the first attempt instead of creating another one.
+func (c *Client) CreateMonitor(ctx context.Context, m Monitor, idempotencyKey string) (*Monitor, error) {
+	var header http.Header
+	if idempotencyKey != "" {
+		header = http.Header{IdempotencyKeyHeader: {idempotencyKey}}
+	}
+	var created Monitor
+	if err := c.do(ctx, http.MethodPost, MonitorsPath, header, m, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// GetMonitor returns the user monitor with the given ID.
+func (c *Client) GetMonitor(ctx context.Context, id int64) (*Monitor, error) {
+	var m Monitor
+	if err := c.do(ctx, http.MethodGet, monitorPath(id, ""), nil, nil, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// UpdateMonitor changes the user monitor with the given ID and returns it.
+func (c *Client) UpdateMonitor(ctx context.Context, id int64, update MonitorUpdate) (*Monitor, error) {
+	var m Monitor
+	if err := c.do(ctx, http.MethodPatch, monitorPath(id, ""), nil, update, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ListMonitors returns a page of the user monitors matching opts.
+func (c *Client) ListMonitors(ctx context.Context, opts ListOptions) (*MonitorPage, error) {
+	var page MonitorPage
+	if err := c.do(ctx, http.MethodGet, MonitorsPath+"?"+opts.query().Encode(), nil, nil, &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// AllMonitors returns every user monitor matching opts, listing the pages from opts.Page on.
+func (c *Client) AllMonitors(ctx context.Context, opts ListOptions) ([]Monitor, error) {
+	if opts.Page == 0 {
+		opts.Page = 1
+	}
+	var monitors []Monitor
+	for {
+		page, err := c.ListMonitors(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		monitors = append(monitors, page.Items...)
+		if !page.HasNext() || len(page.Items) == 0 {
+			return monitors, nil
+		}
+		opts.Page = page.Page + 1
+	}
+}
+
+// PauseMonitor stops evaluating the user monitor with the given ID and returns it.
+func (c *Client) PauseMonitor(ctx context.Context, id int64) (*Monitor, error) {
+	var m Monitor
+	if err := c.do(ctx, http.MethodPost, monitorPath(id, "pause"), nil, nil, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// ResumeMonitor evaluates the paused user monitor with the given ID again and returns it.
+func (c *Client) ResumeMonitor(ctx context.Context, id int64) (*Monitor, error) {
+	var m Monitor
+	if err := c.do(ctx, http.MethodPost, monitorPath(id, "resume"), nil, nil, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// DeleteMonitor deletes the user monitor with the given ID.
+func (c *Client) DeleteMonitor(ctx context.Context, id int64) error {
+	return c.do(ctx, http.MethodDelete, monitorPath(id, ""), nil, nil, nil)
+}
+
+// IsNotFound reports whether err is an APIError for a missing resource, such as a deleted monitor.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
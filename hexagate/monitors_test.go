@@ -0,0 +1,129 @@
+package hexagate_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/hexagate/hexagatetest"
+)
+
+func TestMonitorLifecycle(t *testing.T) {
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	m := hexagate.Monitor{
+		Name:    "unresolvable_dispute_game 0xB1",
+		ChainID: 1,
+		Gate:    "invariant { description: \"a < b\", condition: true }",
+		Params:  map[string]any{"extraTimeInSeconds": 3600},
+		Labels:  map[string]string{"game": "0xb1"},
+	}
+	created, err := client.CreateMonitor(ctx, m, "create-0xb1")
+	if err != nil {
+		t.Fatalf("Error creating monitor: %v", err)
+	}
+	if created.ID == 0 || created.Status != hexagate.MonitorActive || created.Gate != m.Gate {
+		t.Errorf("unexpected monitor %+v", created)
+	}
+	// a retried request returns the monitor created by the first one
+	retried, err := client.CreateMonitor(ctx, m, "create-0xb1")
+	if err != nil || retried.ID != created.ID || server.Creates() != 1 {
+		t.Errorf("expected the retried request to return monitor %d, got %+v after %d creates (%v)", created.ID, retried, server.Creates(), err)
+	}
+
+	updated, err := client.UpdateMonitor(ctx, created.ID, hexagate.MonitorUpdate{Params: map[string]any{"extraTimeInSeconds": 7200}})
+	if err != nil {
+		t.Fatalf("Error updating monitor: %v", err)
+	}
+	if updated.Params["extraTimeInSeconds"] != float64(7200) || updated.Name != m.Name {
+		t.Errorf("expected only the params to change, got %+v", updated)
+	}
+
+	paused, err := client.PauseMonitor(ctx, created.ID)
+	if err != nil || paused.Status != hexagate.MonitorPaused {
+		t.Fatalf("expected the monitor to be paused, got %+v (%v)", paused, err)
+	}
+	got, err := client.GetMonitor(ctx, created.ID)
+	if err != nil || got.Status != hexagate.MonitorPaused {
+		t.Fatalf("expected the monitor to stay paused, got %+v (%v)", got, err)
+	}
+	resumed, err := client.ResumeMonitor(ctx, created.ID)
+	if err != nil || resumed.Status != hexagate.MonitorActive {
+		t.Fatalf("expected the monitor to be resumed, got %+v (%v)", resumed, err)
+	}
+
+	if err := client.DeleteMonitor(ctx, created.ID); err != nil {
+		t.Fatalf("Error deleting monitor: %v", err)
+	}
+	if _, err := client.GetMonitor(ctx, created.ID); !hexagate.IsNotFound(err) {
+		t.Errorf("expected a deleted monitor not to be found, got %v", err)
+	}
+	if err := client.DeleteMonitor(ctx, created.ID); !hexagate.IsNotFound(err) {
+		t.Errorf("expected deleting a deleted monitor to fail with not found, got %v", err)
+	}
+}
+
+func TestListMonitors(t *testing.T) {
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+
+	for i := 0; i < 7; i++ {
+		m := hexagate.Monitor{
+			Name:    fmt.Sprintf("challenger_loses %d", i),
+			ChainID: uint64(1 + i%2),
+			Gate:    "gate",
+			Labels:  map[string]string{"monitor": "challenger_loses", "game": fmt.Sprint(i)},
+		}
+		created, err := client.CreateMonitor(ctx, m, "")
+		if err != nil {
+			t.Fatalf("Error creating monitor: %v", err)
+		}
+		if i == 2 {
+			if _, err := client.PauseMonitor(ctx, created.ID); err != nil {
+				t.Fatalf("Error pausing monitor: %v", err)
+			}
+		}
+	}
+
+	page, err := client.ListMonitors(ctx, hexagate.ListOptions{PageSize: 3})
+	if err != nil {
+		t.Fatalf("Error listing monitors: %v", err)
+	}
+	if len(page.Items) != 3 || page.Total != 7 || !page.HasNext() {
+		t.Errorf("unexpected first page %+v", page)
+	}
+	page, err = client.ListMonitors(ctx, hexagate.ListOptions{Page: 3, PageSize: 3})
+	if err != nil || len(page.Items) != 1 || page.HasNext() {
+		t.Errorf("unexpected last page %+v (%v)", page, err)
+	}
+
+	cases := []struct {
+		opts hexagate.ListOptions
+		want int
+	}{
+		{hexagate.ListOptions{}, 7},
+		{hexagate.ListOptions{ChainID: 1}, 4},
+		{hexagate.ListOptions{ChainID: 1, Status: hexagate.MonitorActive}, 3},
+		{hexagate.ListOptions{Status: hexagate.MonitorPaused}, 1},
+		{hexagate.ListOptions{Name: "loses 6"}, 1},
+		{hexagate.ListOptions{Labels: map[string]string{"monitor": "challenger_loses", "game": "5"}}, 1},
+		{hexagate.ListOptions{Labels: map[string]string{"monitor": "eth_deficit"}}, 0},
+	}
+	for _, c := range cases {
+		// a small page size lists every page
+		c.opts.PageSize = 2
+		monitors, err := client.AllMonitors(ctx, c.opts)
+		if err != nil {
+			t.Fatalf("Error listing monitors: %v", err)
+		}
+		if len(monitors) != c.want {
+			t.Errorf("%+v: expected %d monitors, got %d", c.opts, c.want, len(monitors))
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package hexagate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ValidateRequest is the body of the validate endpoint, which runs a gate file once at the latest block. Mocks
+// override gate sources by name.
+type ValidateRequest struct {
+	Gate    string         `json:"gate"`
+	ChainID uint64         `json:"chain_id"`
+	Params  map[string]any `json:"params"`
+	Mocks   map[string]any `json:"mocks"`
+	Trace   bool           `json:"trace"`
+}
+
+// ValidateResponse is the result of a validate request. Failed holds one entry per invariant that failed.
+type ValidateResponse struct {
+	Count      int   `json:"count"`
+	Failed     []any `json:"failed"`
+	Exceptions []any `json:"exceptions"`
+	Trace      any   `json:"trace"`
+}
+
+// Validate runs a gate file through the validate endpoint.
+func (c *Client) Validate(ctx context.Context, req ValidateRequest) (*ValidateResponse, error) {
+	var resp ValidateResponse
+	if err := c.do(ctx, http.MethodPost, "/invariants/validate", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FailureDescription returns the description of an entry of ValidateResponse.Failed, which is the description of
+// the failed invariant in the gate file. Entries are either the description itself, an object holding it, or a list
+// whose first element is the description.
+func FailureDescription(failed any) string {
+	switch f := failed.(type) {
+	case string:
+		return f
+	case []any:
+		if len(f) > 0 {
+			if s, ok := f[0].(string); ok {
+				return s
+			}
+		}
+	case map[string]any:
+		for _, key := range []string{"description", "message", "name"} {
+			if s, ok := f[key].(string); ok {
+				return s
+			}
+		}
+	}
+	data, err := json.Marshal(failed)
+	if err != nil {
+		return fmt.Sprint(failed)
+	}
+	return string(data)
+}
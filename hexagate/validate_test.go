@@ -0,0 +1,70 @@
+package hexagate_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/hexagate"
+)
+
+func TestValidate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/invariants/validate" || r.Header.Get("X-Hexagate-Api-Key") != "key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var req hexagate.ValidateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.ChainID != 8453 || req.Gate != "invariant { description: \"a < b\", condition: true }" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"count": 1, "failed": []any{map[string]any{"description": "a < b"}}})
+	}))
+	defer server.Close()
+
+	client := hexagate.NewClient("key")
+	client.SetBaseURL(server.URL)
+	resp, err := client.Validate(context.Background(), hexagate.ValidateRequest{
+		Gate:    "invariant { description: \"a < b\", condition: true }",
+		ChainID: 8453,
+	})
+	if err != nil {
+		t.Fatalf("Error validating gate: %v", err)
+	}
+	if len(resp.Failed) != 1 || hexagate.FailureDescription(resp.Failed[0]) != "a < b" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	client = hexagate.NewClient("wrong")
+	client.SetBaseURL(server.URL)
+	_, err = client.Validate(context.Background(), hexagate.ValidateRequest{})
+	var apiErr *hexagate.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || !strings.Contains(apiErr.Body, "unauthorized") {
+		t.Errorf("expected unauthorized error, got %v", err)
+	}
+}
+
+func TestFailureDescription(t *testing.T) {
+	for _, tc := range []struct {
+		failed any
+		want   string
+	}{
+		{"a < b", "a < b"},
+		{map[string]any{"description": "a < b"}, "a < b"},
+		{[]any{"a < b", map[string]any{"a": 1}}, "a < b"},
+		{[]any{1.0}, "[1]"},
+	} {
+		if got := hexagate.FailureDescription(tc.failed); got != tc.want {
+			t.Errorf("FailureDescription(%v) = %q, want %q", tc.failed, got, tc.want)
+		}
+	}
+}
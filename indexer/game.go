@@ -0,0 +1,132 @@
+package indexer
+
+import (
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// Status mirrors the GameStatus enum of the dispute game.
+type Status uint8
+
+const (
+	InProgress Status = iota
+	ChallengerWins
+	DefenderWins
+)
+
+func (s Status) String() string {
+	switch s {
+	case InProgress:
+		return "IN_PROGRESS"
+	case ChallengerWins:
+		return "CHALLENGER_WINS"
+	case DefenderWins:
+		return "DEFENDER_WINS"
+	}
+	return "UNKNOWN"
+}
+
+// Claim is a claim of the game as stored in `claimData(i)` when it was made.
+type Claim struct {
+	ParentIndex uint32      `json:"parentIndex"`
+	Claimant    eth.Address `json:"claimant"`
+	Bond        *eth.Big    `json:"bond"`
+	Value       eth.Hash    `json:"value"`
+	Position    *eth.Big    `json:"position"`
+	Clock       *eth.Big    `json:"clock"`
+	BlockNumber uint64      `json:"blockNumber"`
+}
+
+// Transfer is an `unlock` or `withdraw` call made by the game on its DelayedWETH.
+type Transfer struct {
+	BlockNumber uint64      `json:"blockNumber"`
+	Recipient   eth.Address `json:"recipient"`
+	Amount      *eth.Big    `json:"amount"`
+}
+
+// Game is the indexed state of a dispute game.
+type Game struct {
+	Address eth.Address `json:"address"`
+	// Index is the position of the game in the order games were created.
+	Index       uint64      `json:"index"`
+	Factory     eth.Address `json:"factory"`
+	GameType    uint32      `json:"gameType"`
+	RootClaim   eth.Hash    `json:"rootClaim"`
+	DelayedWETH eth.Address `json:"delayedWETH"`
+
+	CreatedBlock  uint64 `json:"createdBlock"`
+	CreatedAt     uint64 `json:"createdAt"`
+	Status        Status `json:"status"`
+	ResolvedBlock uint64 `json:"resolvedBlock,omitempty"`
+	ResolvedAt    uint64 `json:"resolvedAt,omitempty"`
+
+	Claims      []Claim    `json:"claims"`
+	Unlocks     []Transfer `json:"unlocks"`
+	Withdrawals []Transfer `json:"withdrawals"`
+	// Logs are the raw logs of the game and its creation, kept to answer historical event queries.
+	Logs []eth.Log `json:"logs"`
+
+	// IndexedThrough is the last block whose data for this game is stored. Data from blocks up to it is skipped
+	// when blocks are ingested again after a restart.
+	IndexedThrough uint64 `json:"indexedThrough"`
+}
+
+// Resolved reports whether the game has been resolved.
+func (g *Game) Resolved() bool {
+	return g.Status != InProgress
+}
+
+// TotalBonds returns the sum of the bonds posted by every claim.
+func (g *Game) TotalBonds() *big.Int {
+	total := new(big.Int)
+	for _, c := range g.Claims {
+		if c.Bond != nil {
+			total.Add(total, c.Bond.Int())
+		}
+	}
+	return total
+}
+
+// Unlocked returns the total credit unlocked for each recipient.
+func (g *Game) Unlocked() map[eth.Address]*big.Int {
+	return totals(g.Unlocks)
+}
+
+// Withdrawn returns the total withdrawn from DelayedWETH for each recipient.
+func (g *Game) Withdrawn() map[eth.Address]*big.Int {
+	return totals(g.Withdrawals)
+}
+
+// Credits returns the credit each recipient has unlocked but not yet withdrawn.
+func (g *Game) Credits() map[eth.Address]*big.Int {
+	credits := g.Unlocked()
+	for recipient, amount := range g.Withdrawn() {
+		if credits[recipient] == nil {
+			credits[recipient] = new(big.Int)
+		}
+		credits[recipient].Sub(credits[recipient], amount)
+	}
+	return credits
+}
+
+func totals(transfers []Transfer) map[eth.Address]*big.Int {
+	sums := make(map[eth.Address]*big.Int)
+	for _, t := range transfers {
+		if sums[t.Recipient] == nil {
+			sums[t.Recipient] = new(big.Int)
+		}
+		sums[t.Recipient].Add(sums[t.Recipient], t.Amount.Int())
+	}
+	return sums
+}
+
+// clone returns a deep enough copy of g to update without changing the stored game until it is written.
+func (g *Game) clone() *Game {
+	c := *g
+	c.Claims = append([]Claim(nil), g.Claims...)
+	c.Unlocks = append([]Transfer(nil), g.Unlocks...)
+	c.Withdrawals = append([]Transfer(nil), g.Withdrawals...)
+	c.Logs = append([]eth.Log(nil), g.Logs...)
+	return &c
+}
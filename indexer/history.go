@@ -0,0 +1,121 @@
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/rpc"
+	"github.com/base-org/fault-proof-monitors/trace"
+)
+
+// History returns a monitor.History that answers from the index up to the checkpoint and from the chain for the
+// blocks after it, which are at most the confirmations behind the head when the indexer is kept in sync.
+func (ix *Indexer) History() monitor.History {
+	return history{ix}
+}
+
+type history struct {
+	ix *Indexer
+}
+
+// Logs implements monitor.History for the events of the factory and its games.
+func (h history) Logs(ctx context.Context, address eth.Address, topic eth.Hash, toBlock uint64) ([]eth.Log, error) {
+	indexed, start := h.indexed(toBlock)
+	var logs []eth.Log
+	for _, g := range h.ix.store.Games() {
+		for _, log := range g.Logs {
+			if log.Address == address && log.Topics[0] == topic && uint64(log.BlockNumber) <= indexed {
+				logs = append(logs, log)
+			}
+		}
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].LogIndex < logs[j].LogIndex
+	})
+
+	if start <= toBlock {
+		recent, err := h.ix.chain.Logs(ctx, rpc.FilterQuery{
+			FromBlock: rpc.NumberAt(start),
+			ToBlock:   rpc.NumberAt(toBlock),
+			Addresses: []eth.Address{address},
+			Topics:    [][]eth.Hash{{topic}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, recent...)
+	}
+	return logs, nil
+}
+
+// Calls implements monitor.History for the unlock and withdraw calls made by games on their DelayedWETH. It needs
+// the indexer to have a tracer.
+func (h history) Calls(ctx context.Context, address eth.Address, selector []byte, toBlock uint64) ([]monitor.Call, error) {
+	if h.ix.cfg.Tracer == nil {
+		return nil, monitor.ErrCallHistoryUnavailable
+	}
+	var method abi.Method
+	var transfers func(*Game) []Transfer
+	switch {
+	case bytes.Equal(selector, unlockMethod.Selector()):
+		method, transfers = unlockMethod, func(g *Game) []Transfer { return g.Unlocks }
+	case bytes.Equal(selector, withdrawMethod.Selector()):
+		method, transfers = withdrawMethod, func(g *Game) []Transfer { return g.Withdrawals }
+	default:
+		return nil, fmt.Errorf("%w: selector %s is not indexed", monitor.ErrCallHistoryUnavailable, eth.EncodeHex(selector))
+	}
+
+	// the calls are rebuilt from the decoded transfers, which hold every argument of the call
+	indexed, start := h.indexed(toBlock)
+	var calls []monitor.Call
+	for _, g := range h.ix.store.Games() {
+		if g.DelayedWETH != address {
+			continue
+		}
+		for _, t := range transfers(g) {
+			if t.BlockNumber > indexed {
+				continue
+			}
+			input, err := method.Pack(t.Recipient, t.Amount.Int())
+			if err != nil {
+				return nil, err
+			}
+			calls = append(calls, monitor.Call{BlockNumber: t.BlockNumber, From: g.Address, To: address, Input: input, Value: new(big.Int)})
+		}
+	}
+	sort.SliceStable(calls, func(i, j int) bool { return calls[i].BlockNumber < calls[j].BlockNumber })
+
+	for number := start; number <= toBlock; number++ {
+		block, err := trace.Fetch(ctx, h.ix.cfg.Tracer, number)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range block.MonitorCalls() {
+			if c.To == address && len(c.Input) >= 4 && bytes.Equal(c.Input[:4], selector) {
+				calls = append(calls, c)
+			}
+		}
+	}
+	return calls, nil
+}
+
+// indexed returns the last block answered from the index and the first block to read from the chain.
+func (h history) indexed(toBlock uint64) (uint64, uint64) {
+	cp := h.ix.store.Checkpoint()
+	if cp == nil {
+		return 0, h.ix.cfg.StartBlock
+	}
+	if cp.Number >= toBlock {
+		return toBlock, toBlock + 1
+	}
+	return cp.Number, cp.Number + 1
+}
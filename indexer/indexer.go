@@ -0,0 +1,425 @@
+// Package indexer keeps a local index of the dispute games created by a DisputeGameFactory: their claims, bonds,
+// credits, status and timestamps. Native monitors and CLI tools query the index instead of scanning the full
+// history of the chain on every block, which is what HistoricalEvents and HistoricalCalls do in the gate files.
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/game"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/rpc"
+	"github.com/base-org/fault-proof-monitors/trace"
+)
+
+const (
+	// DefaultConfirmations is the number of blocks the index stays behind the head when Config.Confirmations is
+	// zero, so that the stored data is not affected by reorgs.
+	DefaultConfirmations = 5
+	// DefaultBatchSize is the number of blocks indexed per step when Config.BatchSize is zero.
+	DefaultBatchSize = 1000
+	// DefaultPollInterval is the interval Run syncs at when Config.PollInterval is zero, about an L1 block.
+	DefaultPollInterval = 12 * time.Second
+	// MaxFilterAddresses is the number of games whose events are fetched with a single `eth_getLogs`, which nodes
+	// limit in size.
+	MaxFilterAddresses = 1000
+)
+
+// ErrReorged is returned when the checkpoint block is no longer canonical. The index has to be rebuilt from an
+// earlier block, which only happens for reorgs deeper than the confirmations.
+var ErrReorged = errors.New("checkpoint block is no longer canonical")
+
+var (
+	wethMethod   = abi.MustParseMethod("weth() returns (address)")
+	unlockMethod = abi.MustParseMethod("unlock(address _guy, uint256 _wad)")
+	// withdrawMethod is the withdrawal a dispute game makes on behalf of a recipient in claimCredit.
+	withdrawMethod = abi.MustParseMethod("withdraw(address _guy, uint256 _wad)")
+)
+
+// Config configures an Indexer.
+type Config struct {
+	// Factory is the DisputeGameFactory whose games are indexed.
+	Factory eth.Address
+	// StartBlock is the first block indexed, e.g. the deployment block of the factory.
+	StartBlock uint64
+	// Confirmations is the number of blocks behind the head that are indexed.
+	Confirmations uint64
+	// BatchSize is the number of blocks whose logs are fetched at once.
+	BatchSize uint64
+	// Tracer traces blocks to index the DelayedWETH unlock and withdraw calls of each game, which emit no event.
+	// Without it the games' unlocks and withdrawals stay empty and historical calls cannot be queried.
+	//
+	// Since the unlocks leave no log to select blocks by, every block from the creation of the first game on is
+	// traced with `debug_traceBlockByNumber`, one request per block. This is by far the most expensive part of
+	// indexing and needs a node serving the debug namespace for the whole range.
+	Tracer trace.Caller
+	// PollInterval is the interval Run syncs at.
+	PollInterval time.Duration
+	// OnError is called with the errors of the syncs of Run, which are retried at the next interval.
+	OnError func(error)
+}
+
+// Indexer ingests the events and calls of dispute games into a Store.
+type Indexer struct {
+	chain monitor.Chain
+	store *Store
+	cfg   Config
+}
+
+// New returns an indexer writing to store.
+func New(chain monitor.Chain, store *Store, cfg Config) *Indexer {
+	if cfg.Confirmations == 0 {
+		cfg.Confirmations = DefaultConfirmations
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	return &Indexer{chain: chain, store: store, cfg: cfg}
+}
+
+// Store returns the store the indexer writes to.
+func (ix *Indexer) Store() *Store {
+	return ix.store
+}
+
+// Sync indexes every confirmed block after the checkpoint and returns the new checkpoint.
+func (ix *Indexer) Sync(ctx context.Context) (*Checkpoint, error) {
+	head, err := ix.chain.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("fetching head: %w", err)
+	}
+	if uint64(head.Number) < ix.cfg.Confirmations {
+		return ix.store.Checkpoint(), nil
+	}
+	target := uint64(head.Number) - ix.cfg.Confirmations
+
+	from := ix.cfg.StartBlock
+	if cp := ix.store.Checkpoint(); cp != nil {
+		header, err := ix.chain.HeaderByNumber(ctx, rpc.NumberAt(cp.Number))
+		if err != nil {
+			return nil, fmt.Errorf("fetching checkpoint block %d: %w", cp.Number, err)
+		}
+		if header.Hash != cp.Hash {
+			return nil, fmt.Errorf("%w: block %d is %s, checkpoint has %s", ErrReorged, cp.Number, header.Hash, cp.Hash)
+		}
+		from = cp.Number + 1
+	}
+
+	for from <= target {
+		to := from + ix.cfg.BatchSize - 1
+		if to > target {
+			to = target
+		}
+		if err := ix.indexRange(ctx, from, to); err != nil {
+			return nil, err
+		}
+		from = to + 1
+	}
+	return ix.store.Checkpoint(), nil
+}
+
+// Run syncs the index every poll interval until ctx is cancelled, keeping the store up to date for the readers sharing
+// it. Failed syncs are passed to the OnError callback of the config and retried, except for ErrReorged, which is
+// returned since the index has to be rebuilt. Run returns nil once ctx is cancelled.
+func (ix *Indexer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(ix.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := ix.Sync(ctx); errors.Is(err, ErrReorged) {
+			return err
+		} else if err != nil && ctx.Err() == nil && ix.cfg.OnError != nil {
+			ix.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// batch holds the games changed while indexing a range of blocks until they are written.
+type batch struct {
+	ix      *Indexer
+	changed map[eth.Address]*Game
+	created int
+	headers map[uint64]*eth.Header
+}
+
+// game returns the game at address as changed in the batch, or nil if it is not a known game.
+func (b *batch) game(address eth.Address) *Game {
+	if g, ok := b.changed[address]; ok {
+		return g
+	}
+	if g, ok := b.ix.store.Game(address); ok {
+		g = g.clone()
+		b.changed[address] = g
+		return g
+	}
+	return nil
+}
+
+func (b *batch) header(ctx context.Context, number uint64) (*eth.Header, error) {
+	if h, ok := b.headers[number]; ok {
+		return h, nil
+	}
+	h, err := b.ix.chain.HeaderByNumber(ctx, rpc.NumberAt(number))
+	if err != nil {
+		return nil, fmt.Errorf("fetching block %d: %w", number, err)
+	}
+	b.headers[number] = h
+	return h, nil
+}
+
+func (ix *Indexer) indexRange(ctx context.Context, from, to uint64) error {
+	created, err := ix.chain.Logs(ctx, rpc.FilterQuery{
+		FromBlock: rpc.NumberAt(from),
+		ToBlock:   rpc.NumberAt(to),
+		Addresses: []eth.Address{ix.cfg.Factory},
+		Topics:    [][]eth.Hash{{abi.DisputeGameCreated.Topic()}},
+	})
+	if err != nil {
+		return fmt.Errorf("fetching games created in blocks %d to %d: %w", from, to, err)
+	}
+	// game events are fetched from the stored games and the ones created in the range
+	addresses, err := ix.gameAddresses(created)
+	if err != nil {
+		return fmt.Errorf("decoding games created in blocks %d to %d: %w", from, to, err)
+	}
+	logs := created
+	for start := 0; start < len(addresses); start += MaxFilterAddresses {
+		gameLogs, err := ix.chain.Logs(ctx, rpc.FilterQuery{
+			FromBlock: rpc.NumberAt(from),
+			ToBlock:   rpc.NumberAt(to),
+			Addresses: addresses[start:min(start+MaxFilterAddresses, len(addresses))],
+			Topics:    [][]eth.Hash{{abi.Move.Topic(), abi.Resolved.Topic(), abi.ReceiveETH.Topic()}},
+		})
+		if err != nil {
+			return fmt.Errorf("fetching game events in blocks %d to %d: %w", from, to, err)
+		}
+		logs = append(logs, gameLogs...)
+	}
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].LogIndex < logs[j].LogIndex
+	})
+
+	b := &batch{ix: ix, changed: make(map[eth.Address]*Game), headers: make(map[uint64]*eth.Header)}
+	blocks := make(map[uint64][]eth.Log)
+	for _, log := range logs {
+		if !log.Removed {
+			blocks[uint64(log.BlockNumber)] = append(blocks[uint64(log.BlockNumber)], log)
+		}
+	}
+	for number := from; number <= to; number++ {
+		for _, log := range blocks[number] {
+			if err := b.ingestLog(ctx, log); err != nil {
+				return fmt.Errorf("indexing log %d of block %d: %w", log.LogIndex, number, err)
+			}
+		}
+		// no DelayedWETH call can be made by a game before the first one is created
+		if ix.cfg.Tracer != nil && ix.store.Len()+b.created > 0 {
+			if err := b.ingestCalls(ctx, number); err != nil {
+				return err
+			}
+		}
+	}
+
+	last, err := b.header(ctx, to)
+	if err != nil {
+		return err
+	}
+	games := make([]*Game, 0, len(b.changed))
+	for _, g := range b.changed {
+		if g.IndexedThrough < to {
+			g.IndexedThrough = to
+		}
+		games = append(games, g)
+	}
+	return ix.store.put(games, Checkpoint{Number: to, Hash: last.Hash})
+}
+
+// gameAddresses returns the addresses of the stored games followed by those of the games created in logs.
+func (ix *Indexer) gameAddresses(created []eth.Log) ([]eth.Address, error) {
+	games := ix.store.Games()
+	addresses := make([]eth.Address, 0, len(games)+len(created))
+	for _, g := range games {
+		addresses = append(addresses, g.Address)
+	}
+	for _, log := range created {
+		if log.Removed {
+			continue
+		}
+		values, err := abi.DisputeGameCreated.Decode(log)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, values[0].(eth.Address))
+	}
+	return addresses, nil
+}
+
+func (b *batch) ingestLog(ctx context.Context, log eth.Log) error {
+	number := uint64(log.BlockNumber)
+	if log.Address == b.ix.cfg.Factory && log.Topics[0] == abi.DisputeGameCreated.Topic() {
+		return b.create(ctx, log)
+	}
+
+	g := b.game(log.Address)
+	if g == nil || g.IndexedThrough >= number {
+		return nil
+	}
+	switch log.Topics[0] {
+	case abi.Move.Topic():
+		claim, err := b.claim(ctx, g.Address, len(g.Claims), number)
+		if err != nil {
+			return err
+		}
+		g.Claims = append(g.Claims, claim)
+	case abi.Resolved.Topic():
+		values, err := abi.Resolved.Decode(log)
+		if err != nil {
+			return err
+		}
+		h, err := b.header(ctx, number)
+		if err != nil {
+			return err
+		}
+		g.Status = Status(values[0].(*big.Int).Uint64())
+		g.ResolvedBlock = number
+		g.ResolvedAt = uint64(h.Timestamp)
+	}
+	g.Logs = append(g.Logs, log)
+	return nil
+}
+
+func (b *batch) create(ctx context.Context, log eth.Log) error {
+	values, err := abi.DisputeGameCreated.Decode(log)
+	if err != nil {
+		return err
+	}
+	address := values[0].(eth.Address)
+	number := uint64(log.BlockNumber)
+	if g := b.game(address); g != nil {
+		// the game was stored before the index was restarted
+		return nil
+	}
+
+	out, err := callAt(ctx, b.ix.chain, number, address, wethMethod)
+	if err != nil {
+		return err
+	}
+	root, err := b.claim(ctx, address, 0, number)
+	if err != nil {
+		return err
+	}
+	clock, err := game.DecodeClock(root.Clock.Int())
+	if err != nil {
+		return err
+	}
+
+	g := &Game{
+		Address:      address,
+		Index:        uint64(b.ix.store.Len() + b.created),
+		Factory:      log.Address,
+		GameType:     uint32(values[1].(*big.Int).Uint64()),
+		RootClaim:    eth.BytesToHash(values[2].([]byte)),
+		DelayedWETH:  out[0].(eth.Address),
+		CreatedBlock: number,
+		CreatedAt:    clock.Timestamp,
+		Claims:       []Claim{root},
+		Unlocks:      []Transfer{},
+		Withdrawals:  []Transfer{},
+		Logs:         []eth.Log{log},
+	}
+	b.changed[address] = g
+	b.created++
+	return nil
+}
+
+// claim reads the claim at index as of the block it was made in.
+func (b *batch) claim(ctx context.Context, address eth.Address, index int, number uint64) (Claim, error) {
+	calldata, err := abi.ClaimData.Pack(index)
+	if err != nil {
+		return Claim{}, err
+	}
+	output, err := b.ix.chain.CallContract(ctx, rpc.CallMsg{To: address, Data: calldata}, rpc.NumberAt(number))
+	if err != nil {
+		return Claim{}, fmt.Errorf("reading claim %d of %s: %w", index, address, err)
+	}
+	c, err := abi.DecodeClaim(output)
+	if err != nil {
+		return Claim{}, err
+	}
+	return Claim{
+		ParentIndex: c.ParentIndex,
+		Claimant:    c.Claimant,
+		Bond:        eth.NewBig(c.Bond),
+		Value:       c.Value,
+		Position:    eth.NewBig(c.Position),
+		Clock:       eth.NewBig(c.Clock),
+		BlockNumber: number,
+	}, nil
+}
+
+// ingestCalls records the unlock and withdraw calls each game made on its DelayedWETH in the block.
+func (b *batch) ingestCalls(ctx context.Context, number uint64) error {
+	block, err := trace.Fetch(ctx, b.ix.cfg.Tracer, number)
+	if err != nil {
+		return err
+	}
+	for _, c := range block.MonitorCalls() {
+		if len(c.Input) < 4 {
+			continue
+		}
+		g := b.game(c.From)
+		if g == nil || g.IndexedThrough >= number || c.To != g.DelayedWETH {
+			continue
+		}
+		var method abi.Method
+		var transfers *[]Transfer
+		switch {
+		case bytes.Equal(c.Input[:4], unlockMethod.Selector()):
+			method, transfers = unlockMethod, &g.Unlocks
+		case bytes.Equal(c.Input[:4], withdrawMethod.Selector()):
+			method, transfers = withdrawMethod, &g.Withdrawals
+		default:
+			continue
+		}
+		args, err := method.UnpackInputs(c.Input)
+		if err != nil {
+			return fmt.Errorf("decoding %s call of %s in block %d: %w", method.Name, g.Address, number, err)
+		}
+		*transfers = append(*transfers, Transfer{
+			BlockNumber: number,
+			Recipient:   args[0].(eth.Address),
+			Amount:      eth.NewBig(args[1].(*big.Int)),
+		})
+	}
+	return nil
+}
+
+func callAt(ctx context.Context, chain monitor.Chain, number uint64, contract eth.Address, method abi.Method, args ...any) ([]any, error) {
+	calldata, err := method.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := chain.CallContract(ctx, rpc.CallMsg{To: contract, Data: calldata}, rpc.NumberAt(number))
+	if err != nil {
+		return nil, fmt.Errorf("calling %s on %s: %w", method.Signature(), contract, err)
+	}
+	return method.UnpackOutputs(output)
+}
@@ -0,0 +1,428 @@
+package indexer_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/follower/chaintest"
+	"github.com/base-org/fault-proof-monitors/indexer"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/rpc"
+	"github.com/base-org/fault-proof-monitors/trace"
+)
+
+var (
+	factory    = eth.MustAddress("0x43edB88C4B80fDD2AdFF2412A7BebF9dF42cB40e")
+	weth       = eth.MustAddress("0x84d9A9E5e7F9fDA1BBF0E5E4F11F3aF5Fe4E07dC")
+	firstGame  = eth.MustAddress("0x00000000000000000000000000000000000000B1")
+	secondGame = eth.MustAddress("0x00000000000000000000000000000000000000B2")
+	proposer   = eth.MustAddress("0x642229f238fb9dE03374Be34B0eD8D9De80752c5")
+	challenger = eth.MustAddress("0x8Ca1E12404d16373Aef756179B185F27b2994F3a")
+	rootClaim  = eth.MustHash("0x88fdedc50993402d6fd6e83126eccd8475be8b8039fc24ba4e0f9a0ec0af23c3")
+
+	wethMethod     = abi.MustParseMethod("weth() returns (address)")
+	unlockMethod   = abi.MustParseMethod("unlock(address _guy, uint256 _wad)")
+	withdrawMethod = abi.MustParseMethod("withdraw(address _guy, uint256 _wad)")
+
+	rootBond   = big.NewInt(8e16)
+	attackBond = big.NewInt(1e17)
+)
+
+// scriptedClaim is a claim that appears in claimData from the given block on.
+type scriptedClaim struct {
+	block uint64
+	claim abi.Claim
+}
+
+// fakeGames answers the calls the indexer makes to the games deployed on a chaintest chain.
+type fakeGames struct {
+	claims map[eth.Address][]scriptedClaim
+}
+
+func (f *fakeGames) handle(msg rpc.CallMsg, header *eth.Header) ([]byte, error) {
+	switch {
+	case bytes.Equal(msg.Data[:4], wethMethod.Selector()):
+		return wethMethod.PackOutputs(weth)
+	case bytes.Equal(msg.Data[:4], abi.ClaimData.Selector()):
+		args, err := abi.ClaimData.UnpackInputs(msg.Data)
+		if err != nil {
+			return nil, err
+		}
+		index := int(args[0].(*big.Int).Int64())
+		claims := f.claims[msg.To]
+		if index >= len(claims) || claims[index].block > uint64(header.Number) {
+			return nil, &rpc.Error{Code: 3, Message: "execution reverted"}
+		}
+		return claims[index].claim.Encode()
+	}
+	return nil, &rpc.Error{Code: 3, Message: "execution reverted"}
+}
+
+// fakeTracer serves the scripted traces of a chain, with no transactions in blocks that have none.
+type fakeTracer struct {
+	blocks map[uint64][]trace.Transaction
+	calls  int
+}
+
+func (f *fakeTracer) Call(_ context.Context, result any, method string, params ...any) error {
+	if method != "debug_traceBlockByNumber" {
+		return errors.New("unexpected method " + method)
+	}
+	f.calls++
+	txs := f.blocks[uint64(params[0].(rpc.BlockNumber))]
+	if txs == nil {
+		txs = []trace.Transaction{}
+	}
+	data, err := json.Marshal(txs)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+// transferTx is a transaction in which game calls method on DelayedWETH for recipient.
+func transferTx(t *testing.T, game eth.Address, method abi.Method, recipient eth.Address, amount *big.Int) trace.Transaction {
+	t.Helper()
+	input, err := method.Pack(recipient, amount)
+	if err != nil {
+		t.Fatalf("Error packing %s: %v", method.Name, err)
+	}
+	return trace.Transaction{Result: &trace.Frame{
+		Type: "CALL", From: recipient, To: game, Input: eth.Bytes{0x60, 0xe2, 0x74, 0x64},
+		Calls: []trace.Frame{{Type: "CALL", From: game, To: weth, Input: input}},
+	}}
+}
+
+func addLog(t *testing.T, chain *chaintest.Chain, number uint64, event abi.Event, address eth.Address, values ...any) {
+	t.Helper()
+	log, err := event.Log(address, values...)
+	if err != nil {
+		t.Fatalf("Error encoding %s: %v", event.Name, err)
+	}
+	chain.AddLog(number, log)
+}
+
+// scriptGame sets up a game created in block 2 and attacked in block 4. The attacker's credit is unlocked in
+// block 5, the game resolves in favor of the challenger in block 6 and the credit is withdrawn in block 8.
+func scriptGame(t *testing.T) (*chaintest.Chain, *fakeGames, *fakeTracer) {
+	chain := chaintest.NewChain(1700000000)
+	chain.Extend(10)
+	games := &fakeGames{claims: map[eth.Address][]scriptedClaim{
+		firstGame: {
+			{block: 2, claim: abi.Claim{ParentIndex: 1<<32 - 1, Claimant: proposer, Bond: rootBond, Value: rootClaim, Position: big.NewInt(1), Clock: big.NewInt(int64(chain.Header(2).Timestamp))}},
+			{block: 4, claim: abi.Claim{ParentIndex: 0, Claimant: challenger, Bond: attackBond, Value: eth.Keccak256([]byte("attack")), Position: big.NewInt(2), Clock: big.NewInt(int64(chain.Header(4).Timestamp))}},
+		},
+	}}
+	chain.HandleCall(games.handle)
+
+	addLog(t, chain, 2, abi.DisputeGameCreated, factory, firstGame, uint32(0), rootClaim)
+	addLog(t, chain, 4, abi.Move, firstGame, big.NewInt(0), eth.Keccak256([]byte("attack")), challenger)
+	addLog(t, chain, 6, abi.Resolved, firstGame, uint8(indexer.ChallengerWins))
+	addLog(t, chain, 8, abi.ReceiveETH, firstGame, new(big.Int).Add(rootBond, attackBond))
+
+	total := new(big.Int).Add(rootBond, attackBond)
+	tracer := &fakeTracer{blocks: map[uint64][]trace.Transaction{
+		5: {transferTx(t, firstGame, unlockMethod, challenger, total)},
+		8: {transferTx(t, firstGame, withdrawMethod, challenger, total)},
+	}}
+	return chain, games, tracer
+}
+
+func TestSync(t *testing.T) {
+	chain, _, tracer := scriptGame(t)
+	store, err := indexer.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	ix := indexer.New(chain, store, indexer.Config{Factory: factory, StartBlock: 1, Confirmations: 1, BatchSize: 3, Tracer: tracer})
+
+	cp, err := ix.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Error syncing: %v", err)
+	}
+	if cp.Number != 9 || cp.Hash != chain.Header(9).Hash {
+		t.Errorf("unexpected checkpoint %+v", cp)
+	}
+	if tracer.calls != 8 {
+		t.Errorf("expected blocks 2 to 9 to be traced, got %d traces", tracer.calls)
+	}
+
+	g, ok := store.Game(firstGame)
+	if !ok {
+		t.Fatalf("game %s was not indexed", firstGame)
+	}
+	checkFirstGame(t, chain, g)
+}
+
+// queryRecorder records the log queries made to a chain.
+type queryRecorder struct {
+	*chaintest.Chain
+	queries []rpc.FilterQuery
+}
+
+func (r *queryRecorder) Logs(ctx context.Context, query rpc.FilterQuery) ([]eth.Log, error) {
+	r.queries = append(r.queries, query)
+	return r.Chain.Logs(ctx, query)
+}
+
+func TestGameEventsFiltered(t *testing.T) {
+	chain, _, tracer := scriptGame(t)
+	// a contract that is not a game emits the same events
+	addLog(t, chain, 4, abi.Move, secondGame, big.NewInt(0), eth.Keccak256([]byte("foreign")), challenger)
+	store, err := indexer.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	recorder := &queryRecorder{Chain: chain}
+	ix := indexer.New(recorder, store, indexer.Config{Factory: factory, StartBlock: 1, Confirmations: 1, BatchSize: 3, Tracer: tracer})
+	if _, err := ix.Sync(context.Background()); err != nil {
+		t.Fatalf("Error syncing: %v", err)
+	}
+
+	for _, q := range recorder.queries {
+		if len(q.Addresses) == 0 {
+			t.Errorf("unfiltered log query for blocks %v to %v", q.FromBlock, q.ToBlock)
+		}
+		for _, a := range q.Addresses {
+			if a != factory && a != firstGame {
+				t.Errorf("unexpected address %s in log query", a)
+			}
+		}
+	}
+	if _, ok := store.Game(secondGame); ok {
+		t.Errorf("contract %s was indexed as a game", secondGame)
+	}
+	g, _ := store.Game(firstGame)
+	checkFirstGame(t, chain, g)
+}
+
+func checkFirstGame(t *testing.T, chain *chaintest.Chain, g *indexer.Game) {
+	t.Helper()
+	total := new(big.Int).Add(rootBond, attackBond)
+	if g.Index != 0 || g.Factory != factory || g.RootClaim != rootClaim || g.DelayedWETH != weth {
+		t.Errorf("unexpected game %+v", g)
+	}
+	if g.CreatedBlock != 2 || g.CreatedAt != uint64(chain.Header(2).Timestamp) {
+		t.Errorf("expected creation at block 2, got block %d at %d", g.CreatedBlock, g.CreatedAt)
+	}
+	if g.Status != indexer.ChallengerWins || g.ResolvedBlock != 6 || g.ResolvedAt != uint64(chain.Header(6).Timestamp) {
+		t.Errorf("expected resolution for the challenger at block 6, got %s at block %d", g.Status, g.ResolvedBlock)
+	}
+	if len(g.Claims) != 2 || g.Claims[1].Claimant != challenger || g.Claims[1].BlockNumber != 4 {
+		t.Errorf("unexpected claims %+v", g.Claims)
+	}
+	if g.TotalBonds().Cmp(total) != 0 {
+		t.Errorf("expected total bonds %s, got %s", total, g.TotalBonds())
+	}
+	if unlocked := g.Unlocked()[challenger]; unlocked == nil || unlocked.Cmp(total) != 0 {
+		t.Errorf("expected %s unlocked for the challenger, got %v", total, unlocked)
+	}
+	if credit := g.Credits()[challenger]; credit == nil || credit.Sign() != 0 {
+		t.Errorf("expected the challenger's credit to be withdrawn, got %v", credit)
+	}
+	if len(g.Logs) != 4 {
+		t.Errorf("expected 4 logs, got %d", len(g.Logs))
+	}
+}
+
+func TestResume(t *testing.T) {
+	chain, games, tracer := scriptGame(t)
+	dir := t.TempDir()
+	store, err := indexer.Open(dir)
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	cfg := indexer.Config{Factory: factory, StartBlock: 1, Confirmations: 1, Tracer: tracer}
+	if _, err := indexer.New(chain, store, cfg).Sync(context.Background()); err != nil {
+		t.Fatalf("Error syncing: %v", err)
+	}
+
+	// a second game is created after the restart
+	chain.Extend(3)
+	games.claims[secondGame] = []scriptedClaim{
+		{block: 12, claim: abi.Claim{ParentIndex: 1<<32 - 1, Claimant: proposer, Bond: rootBond, Value: rootClaim, Position: big.NewInt(1), Clock: big.NewInt(int64(chain.Header(12).Timestamp))}},
+	}
+	addLog(t, chain, 12, abi.DisputeGameCreated, factory, secondGame, uint32(0), rootClaim)
+
+	store, err = indexer.Open(dir)
+	if err != nil {
+		t.Fatalf("Error reopening store: %v", err)
+	}
+	if cp := store.Checkpoint(); cp == nil || cp.Number != 9 {
+		t.Fatalf("expected to resume from block 9, got %+v", cp)
+	}
+	tracer.calls = 0
+	cp, err := indexer.New(chain, store, cfg).Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Error syncing: %v", err)
+	}
+	if cp.Number != 12 || tracer.calls != 3 {
+		t.Errorf("expected blocks 10 to 12 to be indexed, got checkpoint %d after %d traces", cp.Number, tracer.calls)
+	}
+
+	stored := store.Games()
+	if len(stored) != 2 || stored[0].Address != firstGame || stored[1].Address != secondGame || stored[1].Index != 1 {
+		t.Fatalf("unexpected games %+v", stored)
+	}
+	checkFirstGame(t, chain, stored[0])
+}
+
+func TestReingestAfterCrash(t *testing.T) {
+	chain, _, tracer := scriptGame(t)
+	dir := t.TempDir()
+	store, err := indexer.Open(dir)
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	cfg := indexer.Config{Factory: factory, StartBlock: 1, Confirmations: 1, Tracer: tracer}
+	if _, err := indexer.New(chain, store, cfg).Sync(context.Background()); err != nil {
+		t.Fatalf("Error syncing: %v", err)
+	}
+
+	// losing the checkpoint, as if the process died after writing the games, replays every block
+	if err := os.Remove(filepath.Join(dir, "checkpoint.json")); err != nil {
+		t.Fatalf("Error removing checkpoint: %v", err)
+	}
+	store, err = indexer.Open(dir)
+	if err != nil {
+		t.Fatalf("Error reopening store: %v", err)
+	}
+	if _, err := indexer.New(chain, store, cfg).Sync(context.Background()); err != nil {
+		t.Fatalf("Error syncing: %v", err)
+	}
+	g, _ := store.Game(firstGame)
+	checkFirstGame(t, chain, g)
+	if len(g.Unlocks) != 1 || len(g.Withdrawals) != 1 {
+		t.Errorf("expected calls to be stored once, got %d unlocks and %d withdrawals", len(g.Unlocks), len(g.Withdrawals))
+	}
+}
+
+func TestReorgedCheckpoint(t *testing.T) {
+	chain, _, tracer := scriptGame(t)
+	store, err := indexer.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	ix := indexer.New(chain, store, indexer.Config{Factory: factory, StartBlock: 1, Confirmations: 1, Tracer: tracer})
+	if _, err := ix.Sync(context.Background()); err != nil {
+		t.Fatalf("Error syncing: %v", err)
+	}
+
+	chain.Reorg(3, 4)
+	if _, err := ix.Sync(context.Background()); !errors.Is(err, indexer.ErrReorged) {
+		t.Errorf("expected ErrReorged, got %v", err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	chain, _, tracer := scriptGame(t)
+	store, err := indexer.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	synced := make(chan struct{}, 1)
+	ix := indexer.New(chain, store, indexer.Config{
+		Factory:       factory,
+		StartBlock:    1,
+		Confirmations: 1,
+		Tracer:        tracer,
+		PollInterval:  time.Millisecond,
+		OnError:       func(err error) { t.Errorf("unexpected error: %v", err) },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- ix.Run(ctx)
+	}()
+	go func() {
+		for store.Checkpoint() == nil || store.Checkpoint().Number != uint64(chain.Head().Number)-1 {
+			time.Sleep(time.Millisecond)
+		}
+		synced <- struct{}{}
+	}()
+	select {
+	case <-synced:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the index did not reach the head")
+	}
+	if _, ok := store.Game(firstGame); !ok {
+		t.Errorf("expected %s to be indexed", firstGame)
+	}
+
+	// a reorg of the checkpoint stops Run, which cannot recover from it
+	chain.Reorg(3, 4)
+	select {
+	case err := <-done:
+		if !errors.Is(err, indexer.ErrReorged) {
+			t.Errorf("expected ErrReorged, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Run to stop on the reorg")
+	}
+}
+
+func TestHistory(t *testing.T) {
+	chain, _, tracer := scriptGame(t)
+	store, err := indexer.Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Error opening store: %v", err)
+	}
+	ix := indexer.New(chain, store, indexer.Config{Factory: factory, StartBlock: 1, Confirmations: 3, Tracer: tracer})
+	if _, err := ix.Sync(context.Background()); err != nil {
+		t.Fatalf("Error syncing: %v", err)
+	}
+	history := ix.History()
+
+	// the checkpoint is at block 7, so the ReceiveETH event in block 8 is read from the chain
+	logs, err := history.Logs(context.Background(), firstGame, abi.ReceiveETH.Topic(), 10)
+	if err != nil {
+		t.Fatalf("Error fetching logs: %v", err)
+	}
+	if len(logs) != 1 || logs[0].BlockNumber != 8 {
+		t.Errorf("unexpected ReceiveETH logs %+v", logs)
+	}
+	created, err := history.Logs(context.Background(), factory, abi.DisputeGameCreated.Topic(), 10)
+	if err != nil {
+		t.Fatalf("Error fetching logs: %v", err)
+	}
+	if len(created) != 1 || created[0].BlockNumber != 2 {
+		t.Errorf("unexpected DisputeGameCreated logs %+v", created)
+	}
+	if moves, _ := history.Logs(context.Background(), firstGame, abi.Move.Topic(), 3); len(moves) != 0 {
+		t.Errorf("expected no moves up to block 3, got %+v", moves)
+	}
+
+	unlocks, err := history.Calls(context.Background(), weth, unlockMethod.Selector(), 10)
+	if err != nil {
+		t.Fatalf("Error fetching calls: %v", err)
+	}
+	if len(unlocks) != 1 || unlocks[0].From != firstGame || unlocks[0].BlockNumber != 5 {
+		t.Fatalf("unexpected unlock calls %+v", unlocks)
+	}
+	args, err := unlockMethod.UnpackInputs(unlocks[0].Input)
+	if err != nil || args[0] != challenger {
+		t.Errorf("unexpected unlock arguments %v (%v)", args, err)
+	}
+	withdrawals, err := history.Calls(context.Background(), weth, withdrawMethod.Selector(), 10)
+	if err != nil {
+		t.Fatalf("Error fetching calls: %v", err)
+	}
+	if len(withdrawals) != 1 || withdrawals[0].BlockNumber != 8 {
+		t.Errorf("unexpected withdraw calls %+v", withdrawals)
+	}
+
+	noTracer := indexer.New(chain, store, indexer.Config{Factory: factory})
+	if _, err := noTracer.History().Calls(context.Background(), weth, unlockMethod.Selector(), 10); !errors.Is(err, monitor.ErrCallHistoryUnavailable) {
+		t.Errorf("expected ErrCallHistoryUnavailable without a tracer, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package indexer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// Checkpoint is the last block whose data is fully stored.
+type Checkpoint struct {
+	Number uint64   `json:"number"`
+	Hash   eth.Hash `json:"hash"`
+}
+
+// Store persists the indexed games in a directory: one JSON file per game under games/, and checkpoint.json,
+// which is written after the games so a crash can only cause blocks to be ingested again. Every file is replaced
+// atomically by renaming a temporary file over it.
+type Store struct {
+	dir string
+
+	mu         sync.RWMutex
+	checkpoint *Checkpoint
+	games      map[eth.Address]*Game
+}
+
+// Open loads the store in dir, creating the directory if it does not exist.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "games"), 0o755); err != nil {
+		return nil, err
+	}
+	s := &Store{dir: dir, games: make(map[eth.Address]*Game)}
+
+	data, err := os.ReadFile(filepath.Join(dir, "checkpoint.json"))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		s.checkpoint = new(Checkpoint)
+		if err := json.Unmarshal(data, s.checkpoint); err != nil {
+			return nil, fmt.Errorf("reading checkpoint: %w", err)
+		}
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "games", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		g := new(Game)
+		if err := json.Unmarshal(data, g); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", filepath.Base(file), err)
+		}
+		s.games[g.Address] = g
+	}
+	return s, nil
+}
+
+// Checkpoint returns the last fully stored block, or nil if nothing was stored yet.
+func (s *Store) Checkpoint() *Checkpoint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.checkpoint == nil {
+		return nil
+	}
+	cp := *s.checkpoint
+	return &cp
+}
+
+// Game returns the game at address.
+func (s *Store) Game(address eth.Address) (*Game, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g, ok := s.games[address]
+	return g, ok
+}
+
+// Games returns every game in order of creation.
+func (s *Store) Games() []*Game {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	games := make([]*Game, 0, len(s.games))
+	for _, g := range s.games {
+		games = append(games, g)
+	}
+	sort.Slice(games, func(i, j int) bool { return games[i].Index < games[j].Index })
+	return games
+}
+
+// Len returns the number of games stored.
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.games)
+}
+
+// put writes the games and then the checkpoint. Stored games are replaced rather than modified, so games returned
+// earlier are never changed underneath their readers.
+func (s *Store) put(games []*Game, cp Checkpoint) error {
+	for _, g := range games {
+		name := strings.ToLower(g.Address.String()) + ".json"
+		if err := writeJSON(filepath.Join(s.dir, "games", name), g); err != nil {
+			return err
+		}
+	}
+	if err := writeJSON(filepath.Join(s.dir, "checkpoint.json"), cp); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, g := range games {
+		s.games[g.Address] = g
+	}
+	s.checkpoint = &cp
+	return nil
+}
+
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
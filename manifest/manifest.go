@@ -0,0 +1,295 @@
+// Package manifest reads the deployment manifest, which declares the monitors run on Hexagate: which gate files, in
+// which deployment mode, on which network and with which params. Plan compares the manifest with the monitors
+// deployed from this repository, and Apply reconciles them.
+package manifest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/monitors"
+	"github.com/base-org/fault-proof-monitors/networks"
+)
+
+// Mode is a deployment workflow of the README.
+type Mode string
+
+const (
+	// SingleInstance monitors are deployed once, by Apply.
+	SingleInstance Mode = "single-instance"
+	// PerDisputeGame monitors are deployed to every game created, by autodeploy. The manifest holds their template.
+	PerDisputeGame Mode = "per-dispute-game"
+	// SpecificDisputeGame monitors are deployed to the games a parent monitor alerts on, by autodeploy.
+	SpecificDisputeGame Mode = "specific-dispute-game"
+)
+
+// LabelDeployment is the label holding the name of a single instance deployment.
+const LabelDeployment = "deployment"
+
+// Network is a chain the monitors run on.
+type Network struct {
+	// Profile names the profile of the networks package resolving the params of the deployments, and the chain
+	// when ChainID is not set.
+	Profile string `yaml:"profile"`
+	ChainID uint64 `yaml:"chainId"`
+	// Params are shared by the deployments declaring them, such as the honest actors, and take precedence over the
+	// profile.
+	Params map[string]any `yaml:"params"`
+}
+
+// Deployment is a gate file deployed in a mode on a network.
+type Deployment struct {
+	// Name identifies a single instance deployment on its network. It defaults to Monitor.
+	Name    string `yaml:"name"`
+	Monitor string `yaml:"monitor"`
+	Mode    Mode   `yaml:"mode"`
+	// Network defaults to the only network of the manifest.
+	Network string `yaml:"network"`
+	// Params are the params of the gate file, except disputeGame for monitors deployed per game.
+	Params map[string]any `yaml:"params"`
+	// Channels replace the notification channels of the monitors when set.
+	Channels []string `yaml:"channels"`
+}
+
+// Manifest is the deployment manifest.
+type Manifest struct {
+	Networks    map[string]Network `yaml:"networks"`
+	Deployments []Deployment       `yaml:"deployments"`
+}
+
+var variablePattern = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// Parse decodes and validates a manifest. References to environment variables written as ${NAME} are replaced
+// with their value, so that addresses that differ between operators need not be checked in.
+func Parse(data []byte) (*Manifest, error) {
+	var missing []string
+	data = variablePattern.ReplaceAllFunc(data, func(ref []byte) []byte {
+		name := string(variablePattern.FindSubmatch(ref)[1])
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return []byte(value)
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("manifest references unset environment variables %s", strings.Join(missing, ", "))
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	m := &Manifest{}
+	if err := dec.Decode(m); err != nil {
+		return nil, fmt.Errorf("decoding manifest: %w", err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Load reads and parses the manifest at path.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return m, nil
+}
+
+func (m *Manifest) validate() error {
+	if len(m.Networks) == 0 {
+		return errors.New("no networks")
+	}
+	profiles := make(map[string]networks.Profile)
+	for name, n := range m.Networks {
+		if n.Profile != "" {
+			p, err := networks.Lookup(n.Profile)
+			if err != nil {
+				return fmt.Errorf("network %s: %w", name, err)
+			}
+			if n.ChainID == 0 {
+				n.ChainID = p.L1ChainID
+				m.Networks[name] = n
+			}
+			profiles[name] = p
+		}
+		if n.ChainID == 0 {
+			return fmt.Errorf("network %s has no chainId", name)
+		}
+	}
+	names := make(map[string]bool)
+	for i := range m.Deployments {
+		d := &m.Deployments[i]
+		if d.Name == "" {
+			d.Name = d.Monitor
+		}
+		if d.Network == "" && len(m.Networks) == 1 {
+			for name := range m.Networks {
+				d.Network = name
+			}
+		}
+		if _, ok := m.Networks[d.Network]; !ok {
+			return fmt.Errorf("deployment %s: unknown network %q", d.Name, d.Network)
+		}
+		// per game monitors are identified by their gate file, since autodeploy deploys one of each per game
+		key := d.Network + "/" + d.Name
+		if d.Mode != SingleInstance {
+			key = d.Network + "/" + d.Monitor
+		}
+		if names[key] {
+			return fmt.Errorf("deployment %s is declared twice on %s", d.Name, d.Network)
+		}
+		names[key] = true
+		if err := d.resolve(m.Networks[d.Network], profiles[d.Network]); err != nil {
+			return fmt.Errorf("deployment %s: %w", d.Name, err)
+		}
+		if err := d.validate(); err != nil {
+			return fmt.Errorf("deployment %s: %w", d.Name, err)
+		}
+	}
+	return nil
+}
+
+// resolve sets the params declared by the gate file that the deployment leaves unset from the params of its network,
+// and then from its profile.
+func (d *Deployment) resolve(n Network, p networks.Profile) error {
+	declared, err := monitors.Params(d.Monitor)
+	if err != nil {
+		return err
+	}
+	for _, name := range declared {
+		v, ok := n.Params[name]
+		if _, set := d.Params[name]; set || !ok {
+			continue
+		}
+		if d.Params == nil {
+			d.Params = make(map[string]any)
+		}
+		d.Params[name] = v
+	}
+	d.Params, err = p.Resolve(declared, d.Params)
+	return err
+}
+
+func (d Deployment) validate() error {
+	declared, err := monitors.Params(d.Monitor)
+	if err != nil {
+		return err
+	}
+	perGame := false
+	for _, p := range declared {
+		perGame = perGame || p == "disputeGame"
+	}
+	switch d.Mode {
+	case SingleInstance:
+		if perGame {
+			return fmt.Errorf("%s takes a disputeGame, so it is deployed per dispute game", d.Monitor)
+		}
+		// the native monitor rejects unknown params and params of the wrong type, such as unquoted addresses
+		if _, err := monitor.New(d.Monitor, d.Params); err != nil {
+			return err
+		}
+		return monitors.CheckParams(d.Monitor, d.Params)
+	case PerDisputeGame, SpecificDisputeGame:
+		if !perGame {
+			return fmt.Errorf("%s takes no disputeGame, so it is deployed as a single instance", d.Monitor)
+		}
+		if d.Mode == SpecificDisputeGame && d.Monitor != autodeploy.ChildMonitor {
+			return fmt.Errorf("only %s is deployed to specific dispute games", autodeploy.ChildMonitor)
+		}
+		_, err := d.template().Render(0, eth.Address{})
+		return err
+	}
+	return fmt.Errorf("unknown mode %q, expected %s, %s or %s", d.Mode, SingleInstance, PerDisputeGame, SpecificDisputeGame)
+}
+
+func (d Deployment) template() autodeploy.Template {
+	return autodeploy.Template{Monitor: d.Monitor, Params: d.Params, Channels: d.Channels}
+}
+
+// monitor returns the monitor of a single instance deployment.
+func (d Deployment) monitor(chainID uint64) (hexagate.Monitor, error) {
+	gate, err := monitors.Gate(d.Monitor)
+	if err != nil {
+		return hexagate.Monitor{}, err
+	}
+	return hexagate.Monitor{
+		Name:        d.Name,
+		Description: fmt.Sprintf("%s deployed from the manifest", d.Monitor),
+		ChainID:     chainID,
+		Gate:        gate,
+		Params:      d.Params,
+		Channels:    d.Channels,
+		Labels: map[string]string{
+			autodeploy.LabelManagedBy: autodeploy.ManagedBy,
+			autodeploy.LabelMonitor:   d.Monitor,
+			LabelDeployment:           d.Name,
+		},
+	}, nil
+}
+
+// find returns the deployment of a single instance by name, or of a monitor deployed per game by gate file.
+func (m *Manifest) find(network string, single bool, name string) (Deployment, bool) {
+	for _, d := range m.Deployments {
+		if d.Network != network || (d.Mode == SingleInstance) != single {
+			continue
+		}
+		if (single && d.Name == name) || (!single && d.Monitor == name) {
+			return d, true
+		}
+	}
+	return Deployment{}, false
+}
+
+// networkNames returns the names of the networks in order.
+func (m *Manifest) networkNames() []string {
+	names := make([]string, 0, len(m.Networks))
+	for name := range m.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Autodeploy returns the configs of the autodeploy deployers for network: the templates of the monitors deployed
+// per dispute game, and the child monitor deployed to specific games, if any.
+func (m *Manifest) Autodeploy(network string) (autodeploy.Config, *autodeploy.ChildConfig, error) {
+	n, ok := m.Networks[network]
+	if !ok {
+		return autodeploy.Config{}, nil, fmt.Errorf("unknown network %q", network)
+	}
+	cfg := autodeploy.Config{ChainID: n.ChainID}
+	var child *autodeploy.ChildConfig
+	for _, d := range m.Deployments {
+		if d.Network != network {
+			continue
+		}
+		switch d.Mode {
+		case PerDisputeGame:
+			cfg.Monitors = append(cfg.Monitors, d.template())
+		case SpecificDisputeGame:
+			challenger, _ := d.Params["cbChallenger"].(string)
+			address, err := eth.ParseAddress(challenger)
+			if err != nil {
+				return autodeploy.Config{}, nil, fmt.Errorf("deployment %s: invalid cbChallenger: %w", d.Name, err)
+			}
+			child = &autodeploy.ChildConfig{ChainID: n.ChainID, CBChallenger: address, Channels: d.Channels}
+		}
+	}
+	return cfg, child, nil
+}
@@ -0,0 +1,273 @@
+package manifest_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/hexagate/hexagatetest"
+	"github.com/base-org/fault-proof-monitors/manifest"
+)
+
+const testManifest = `
+networks:
+  mainnet:
+    chainId: 1
+deployments:
+  - monitor: duplicate_dispute_game
+    mode: single-instance
+    params:
+      optimismPortalProxy: "0x00000000000000000000000000000000000000B0"
+  - monitor: fault_proof_detection_parent
+    mode: single-instance
+    channels: [slack]
+    params:
+      disputeGameFactoryProxy: "0x00000000000000000000000000000000000000F0"
+      l2ChainId: 8453
+  - monitor: unresolvable_dispute_game
+    mode: per-dispute-game
+    params:
+      extraTimeInSeconds: 3600
+  - monitor: fault_proof_detection_child
+    mode: specific-dispute-game
+    params:
+      cbChallenger: "0x00000000000000000000000000000000000000C1"
+`
+
+func parse(t *testing.T, data string) *manifest.Manifest {
+	t.Helper()
+	m, err := manifest.Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Error parsing manifest: %v", err)
+	}
+	return m
+}
+
+func TestParse(t *testing.T) {
+	m := parse(t, testManifest)
+	if len(m.Deployments) != 4 {
+		t.Fatalf("expected 4 deployments, got %d", len(m.Deployments))
+	}
+	for _, d := range m.Deployments {
+		if d.Network != "mainnet" || d.Name != d.Monitor {
+			t.Errorf("expected the name and network to default, got %+v", d)
+		}
+	}
+
+	cfg, child, err := m.Autodeploy("mainnet")
+	if err != nil {
+		t.Fatalf("Error reading autodeploy config: %v", err)
+	}
+	if cfg.ChainID != 1 || len(cfg.Monitors) != 1 || cfg.Monitors[0].Monitor != "unresolvable_dispute_game" {
+		t.Errorf("unexpected autodeploy config %+v", cfg)
+	}
+	if child == nil || child.CBChallenger != eth.MustAddress("0x00000000000000000000000000000000000000C1") {
+		t.Errorf("unexpected child config %+v", child)
+	}
+}
+
+func TestParseProfile(t *testing.T) {
+	m := parse(t, `
+networks:
+  sepolia:
+    profile: base-sepolia
+    params:
+      honestChallenger: "0x00000000000000000000000000000000000000A2"
+deployments:
+  - {monitor: fault_proof_detection_parent, mode: single-instance, params: {l2ChainId: 1}}
+  - {monitor: eth_withdrawn_early, mode: per-dispute-game}
+  - {monitor: eth_deficit, mode: per-dispute-game, params: {honestChallenger: "0x00000000000000000000000000000000000000A3"}}
+`)
+	if m.Networks["sepolia"].ChainID != 11155111 {
+		t.Errorf("expected the chain of the profile, got %d", m.Networks["sepolia"].ChainID)
+	}
+	want := []map[string]any{
+		{"disputeGameFactoryProxy": "0xd6e6dbf4f7ea0ac412fd8b65ed297e64bb7a06e1", "l2ChainId": 1},
+		{"multicall3": "0xca11bde05977b3631167028862be2a173976ca11"},
+		{"honestChallenger": "0x00000000000000000000000000000000000000A3"},
+	}
+	for i, d := range m.Deployments {
+		if !reflect.DeepEqual(d.Params, want[i]) {
+			t.Errorf("%s: expected params %v, got %v", d.Name, want[i], d.Params)
+		}
+	}
+
+	if _, err := manifest.Parse([]byte("networks: {sepolia: {profile: unknown}}\ndeployments: []\n")); err == nil {
+		t.Error("expected an unknown profile to be rejected")
+	}
+}
+
+func TestParseVariables(t *testing.T) {
+	data := strings.Replace(testManifest, `"0x00000000000000000000000000000000000000C1"`, `"${CB_CHALLENGER}"`, 1)
+	if _, err := manifest.Parse([]byte(data)); err == nil || !strings.Contains(err.Error(), "CB_CHALLENGER") {
+		t.Errorf("expected the unset variable to be reported, got %v", err)
+	}
+	t.Setenv("CB_CHALLENGER", "0x00000000000000000000000000000000000000C2")
+	_, child, err := parse(t, data).Autodeploy("mainnet")
+	if err != nil || child.CBChallenger != eth.MustAddress("0x00000000000000000000000000000000000000C2") {
+		t.Errorf("expected the variable to be expanded, got %+v (%v)", child, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"no networks": `deployments: []`,
+		"unknown field": `
+networks: {mainnet: {chainId: 1}}
+deployments: [{monitor: incorrect_claim_bond, mode: per-dispute-game, grace: 1}]`,
+		"unknown network": `
+networks: {mainnet: {chainId: 1}}
+deployments: [{monitor: incorrect_claim_bond, mode: per-dispute-game, network: sepolia}]`,
+		"unknown mode": `
+networks: {mainnet: {chainId: 1}}
+deployments: [{monitor: incorrect_claim_bond, mode: always}]`,
+		"unknown monitor": `
+networks: {mainnet: {chainId: 1}}
+deployments: [{monitor: missing, mode: per-dispute-game}]`,
+		"per game monitor deployed once": `
+networks: {mainnet: {chainId: 1}}
+deployments: [{monitor: incorrect_claim_bond, mode: single-instance}]`,
+		"single instance deployed per game": `
+networks: {mainnet: {chainId: 1}}
+deployments: [{monitor: duplicate_dispute_game, mode: per-dispute-game, params: {optimismPortalProxy: "0x00000000000000000000000000000000000000B0"}}]`,
+		"specific game": `
+networks: {mainnet: {chainId: 1}}
+deployments: [{monitor: incorrect_claim_bond, mode: specific-dispute-game}]`,
+		"missing param": `
+networks: {mainnet: {chainId: 1}}
+deployments: [{monitor: challenger_loses, mode: per-dispute-game}]`,
+		"invalid param": `
+networks: {mainnet: {chainId: 1}}
+deployments: [{monitor: duplicate_dispute_game, mode: single-instance, params: {optimismPortalProxy: 1}}]`,
+		"disputeGame set": `
+networks: {mainnet: {chainId: 1}}
+deployments: [{monitor: incorrect_claim_bond, mode: per-dispute-game, params: {disputeGame: "0x00000000000000000000000000000000000000B1"}}]`,
+		"declared twice": `
+networks: {mainnet: {chainId: 1}}
+deployments:
+  - {monitor: incorrect_claim_bond, mode: per-dispute-game}
+  - {monitor: incorrect_claim_bond, mode: per-dispute-game, name: again}`,
+	}
+	for name, data := range cases {
+		if _, err := manifest.Parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected the manifest to be rejected", name)
+		}
+	}
+}
+
+func TestCheckedInManifest(t *testing.T) {
+	t.Setenv("CB_CHALLENGER", "0x00000000000000000000000000000000000000C1")
+	t.Setenv("HONEST_PROPOSER", "0x00000000000000000000000000000000000000A1")
+	t.Setenv("HONEST_CHALLENGER", "0x00000000000000000000000000000000000000A2")
+	m, err := manifest.Load("../deployments.yaml")
+	if err != nil {
+		t.Fatalf("Error loading the manifest: %v", err)
+	}
+	cfg, child, err := m.Autodeploy("base-mainnet")
+	if err != nil {
+		t.Fatalf("Error reading autodeploy config: %v", err)
+	}
+	if len(cfg.Monitors) != len(autodeploy.PerGameMonitors) || child == nil {
+		t.Errorf("expected every per game monitor and the child to be deployed, got %d and %v", len(cfg.Monitors), child)
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	server := hexagatetest.NewServer("key")
+	defer server.Close()
+	client := server.Client()
+	ctx := context.Background()
+	m := parse(t, testManifest)
+
+	// a monitor deployed by autodeploy, one created by hand, and one of a deployment removed from the manifest
+	cfg, _, err := m.Autodeploy("mainnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	game := eth.MustAddress("0x00000000000000000000000000000000000000D1")
+	perGame, err := cfg.Monitors[0].Render(1, game)
+	if err != nil {
+		t.Fatal(err)
+	}
+	perGame.Params["extraTimeInSeconds"] = 7200
+	deployed := []hexagate.Monitor{
+		perGame,
+		{Name: "by hand", ChainID: 1, Gate: "gate"},
+		{Name: "removed", ChainID: 1, Gate: "gate", Labels: map[string]string{
+			autodeploy.LabelManagedBy: autodeploy.ManagedBy,
+			manifest.LabelDeployment:  "removed",
+		}},
+	}
+	for _, d := range deployed {
+		if _, err := client.CreateMonitor(ctx, d, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plan, err := m.Plan(ctx, client)
+	if err != nil {
+		t.Fatalf("Error planning: %v", err)
+	}
+	if plan.Count(manifest.Create) != 2 || plan.Count(manifest.Update) != 1 || plan.Count(manifest.Delete) != 1 {
+		t.Fatalf("expected 2 creates, 1 update and 1 delete, got %+v", plan.Changes)
+	}
+	var out bytes.Buffer
+	if err := plan.WriteText(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"+ create duplicate_dispute_game", "param extraTimeInSeconds: 7200 → 3600", "- delete removed"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the plan to contain %q, got\n%s", want, out.String())
+		}
+	}
+
+	// a failed apply is resumed by planning again
+	server.FailNext(0, 500)
+	if err := plan.Apply(ctx, client, nil); err == nil {
+		t.Fatal("expected the apply to fail")
+	}
+	if plan, err = m.Plan(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 3 {
+		t.Fatalf("expected 3 remaining changes, got %+v", plan.Changes)
+	}
+	if err := plan.Apply(ctx, client, nil); err != nil {
+		t.Fatalf("Error applying: %v", err)
+	}
+	if plan, err = m.Plan(ctx, client); err != nil || !plan.Empty() {
+		t.Fatalf("expected the monitors to match the manifest after apply, got %+v (%v)", plan, err)
+	}
+	if n := len(server.Monitors()); n != 4 {
+		t.Errorf("expected 4 monitors, got %d", n)
+	}
+
+	// editing a gate file drifts every monitor deployed from it
+	for _, d := range server.Monitors() {
+		if d.Labels[autodeploy.LabelMonitor] == "unresolvable_dispute_game" {
+			if _, err := client.UpdateMonitor(ctx, d.ID, hexagate.MonitorUpdate{Gate: "old gate"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if plan, err = m.Plan(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 1 || !plan.Changes[0].GateDrift {
+		t.Errorf("expected the gate source drift to be planned, got %+v", plan.Changes)
+	}
+
+	// monitors of games are deleted once the manifest stops deploying their gate file
+	m.Deployments = m.Deployments[:2]
+	if plan, err = m.Plan(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if plan.Count(manifest.Delete) != 1 || plan.Changes[0].ID != 1 {
+		t.Errorf("expected the per game monitor to be deleted, got %+v", plan.Changes)
+	}
+}
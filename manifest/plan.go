@@ -0,0 +1,307 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/autodeploy"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+)
+
+// Action is what a Change does to a monitor.
+type Action string
+
+const (
+	Create Action = "create"
+	Update Action = "update"
+	Delete Action = "delete"
+)
+
+// Change is a step of a Plan.
+type Change struct {
+	Action Action
+	// Deployment is the deployment of the manifest the monitor belongs to, empty for monitors it no longer declares.
+	Deployment string
+	Network    string
+	// Monitor is the monitor to create, the updated monitor or the monitor to delete.
+	Monitor hexagate.Monitor
+	// ID is the monitor to update or delete.
+	ID int64
+	// Diffs describe the fields an update changes.
+	Diffs []string
+	// GateDrift is set when the deployed gate source differs from the gate file.
+	GateDrift bool
+}
+
+func (c Change) String() string {
+	target := c.Monitor.Name
+	if c.ID != 0 {
+		target = fmt.Sprintf("%s (id %d)", target, c.ID)
+	}
+	symbol := map[Action]string{Create: "+", Update: "~", Delete: "-"}[c.Action]
+	return fmt.Sprintf("%s %s %s on %s", symbol, c.Action, target, c.Network)
+}
+
+// Plan is the list of changes reconciling the deployed monitors with the manifest. Creates come first, so that a
+// replaced monitor is deleted only once the new one is deployed.
+type Plan struct {
+	Changes []Change
+}
+
+// Empty reports whether the deployed monitors match the manifest.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// Count returns the number of changes of each action.
+func (p *Plan) Count(action Action) int {
+	n := 0
+	for _, c := range p.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteText prints the plan, one change per line with the diffs of updates below it.
+func (p *Plan) WriteText(w io.Writer) error {
+	if p.Empty() {
+		_, err := fmt.Fprintln(w, "No changes, the deployed monitors match the manifest.")
+		return err
+	}
+	for _, c := range p.Changes {
+		if _, err := fmt.Fprintln(w, c); err != nil {
+			return err
+		}
+		for _, d := range c.Diffs {
+			if _, err := fmt.Fprintf(w, "    %s\n", d); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "\nPlan: %d to create, %d to update, %d to delete.\n",
+		p.Count(Create), p.Count(Update), p.Count(Delete))
+	return err
+}
+
+// Plan lists the monitors deployed from this repository on the networks of the manifest, and computes the changes
+// that make them match it:
+//
+//   - single instance deployments are created when missing, and updated when their gate source, params or channels
+//     differ from the manifest;
+//   - monitors deployed per game are updated to the gate source and template of their deployment, and deleted when
+//     the manifest no longer deploys their gate file per game;
+//   - single instances the manifest no longer declares are deleted.
+//
+// Monitors created by hand, without the managed-by label, are left alone.
+func (m *Manifest) Plan(ctx context.Context, client *hexagate.Client) (*Plan, error) {
+	var creates, updates, deletes []Change
+	for _, network := range m.networkNames() {
+		chainID := m.Networks[network].ChainID
+		deployed, err := client.AllMonitors(ctx, hexagate.ListOptions{
+			ChainID: chainID,
+			Labels:  map[string]string{autodeploy.LabelManagedBy: autodeploy.ManagedBy},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("listing monitors on %s: %w", network, err)
+		}
+
+		matched := make(map[string]bool)
+		for _, current := range deployed {
+			var d Deployment
+			var ok bool
+			var desired hexagate.Monitor
+			switch {
+			case current.Labels[autodeploy.LabelGame] != "":
+				d, ok = m.find(network, false, current.Labels[autodeploy.LabelMonitor])
+				if ok {
+					game, err := gameOf(current)
+					if err != nil {
+						return nil, err
+					}
+					if desired, err = d.template().Render(chainID, game); err != nil {
+						return nil, err
+					}
+					// keep the name given when the game was deployed
+					desired.Name, desired.Description = current.Name, current.Description
+				}
+			case current.Labels[LabelDeployment] != "":
+				name := current.Labels[LabelDeployment]
+				d, ok = m.find(network, true, name)
+				// a deployment is matched by its first monitor, any other is a duplicate
+				if ok && !matched[name] {
+					matched[name] = true
+					if desired, err = d.monitor(chainID); err != nil {
+						return nil, err
+					}
+				} else {
+					ok = false
+				}
+			default:
+				continue
+			}
+
+			if !ok {
+				deletes = append(deletes, Change{Action: Delete, Network: network, Monitor: current, ID: current.ID})
+				continue
+			}
+			diffs, drift := diff(current, desired, d.Channels != nil)
+			if len(diffs) > 0 {
+				updates = append(updates, Change{
+					Action:     Update,
+					Deployment: d.Name,
+					Network:    network,
+					Monitor:    desired,
+					ID:         current.ID,
+					Diffs:      diffs,
+					GateDrift:  drift,
+				})
+			}
+		}
+
+		for _, d := range m.Deployments {
+			if d.Network != network || d.Mode != SingleInstance || matched[d.Name] {
+				continue
+			}
+			desired, err := d.monitor(chainID)
+			if err != nil {
+				return nil, err
+			}
+			creates = append(creates, Change{Action: Create, Deployment: d.Name, Network: network, Monitor: desired})
+		}
+	}
+	return &Plan{Changes: append(append(creates, updates...), deletes...)}, nil
+}
+
+// Apply makes the changes of the plan in order. It stops at the first failure, after which planning again lists
+// the remaining changes.
+func (p *Plan) Apply(ctx context.Context, client *hexagate.Client, onChange func(Change)) error {
+	for _, c := range p.Changes {
+		var err error
+		switch c.Action {
+		case Create:
+			_, err = client.CreateMonitor(ctx, c.Monitor, idempotencyKey(c.Monitor))
+		case Update:
+			_, err = client.UpdateMonitor(ctx, c.ID, hexagate.MonitorUpdate{
+				Name:        c.Monitor.Name,
+				Description: c.Monitor.Description,
+				Gate:        c.Monitor.Gate,
+				Params:      c.Monitor.Params,
+				Channels:    c.Monitor.Channels,
+				Labels:      c.Monitor.Labels,
+			})
+		case Delete:
+			err = client.DeleteMonitor(ctx, c.ID)
+			if hexagate.IsNotFound(err) {
+				err = nil
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s %s on %s: %w", c.Action, c.Monitor.Name, c.Network, err)
+		}
+		if onChange != nil {
+			onChange(c)
+		}
+	}
+	return nil
+}
+
+// idempotencyKey identifies the creation of a monitor with its content, so that a retried apply does not create it
+// twice, while a monitor deleted and declared again is created anew.
+func idempotencyKey(m hexagate.Monitor) string {
+	data, _ := json.Marshal(m)
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("manifest-%d-%s-%s", m.ChainID, m.Labels[LabelDeployment], hex.EncodeToString(sum[:8]))
+}
+
+// gameOf returns the game of a monitor deployed per game.
+func gameOf(m hexagate.Monitor) (eth.Address, error) {
+	game, _ := m.Params["disputeGame"].(string)
+	if game == "" {
+		game = m.Labels[autodeploy.LabelGame]
+	}
+	address, err := eth.ParseAddress(game)
+	if err != nil {
+		return eth.Address{}, fmt.Errorf("monitor %d: invalid dispute game: %w", m.ID, err)
+	}
+	return address, nil
+}
+
+// diff describes how the deployed monitor differs from the desired one. Channels are compared only when the
+// manifest sets them.
+func diff(current, desired hexagate.Monitor, channels bool) ([]string, bool) {
+	var diffs []string
+	if current.Name != desired.Name {
+		diffs = append(diffs, fmt.Sprintf("name: %q → %q", current.Name, desired.Name))
+	}
+	drift := current.Gate != desired.Gate
+	if drift {
+		diffs = append(diffs, "gate source differs from the gate file")
+	}
+
+	keys := make(map[string]bool)
+	for k := range current.Params {
+		keys[k] = true
+	}
+	for k := range desired.Params {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+	for _, k := range sorted {
+		before, after := normalize(current.Params[k]), normalize(desired.Params[k])
+		if before != after {
+			diffs = append(diffs, fmt.Sprintf("param %s: %s → %s", k, before, after))
+		}
+	}
+
+	if channels {
+		before, after := append([]string(nil), current.Channels...), append([]string(nil), desired.Channels...)
+		sort.Strings(before)
+		sort.Strings(after)
+		if strings.Join(before, ",") != strings.Join(after, ",") {
+			diffs = append(diffs, fmt.Sprintf("channels: %v → %v", before, after))
+		}
+	}
+	for k, v := range desired.Labels {
+		if current.Labels[k] != v {
+			diffs = append(diffs, fmt.Sprintf("label %s: %q → %q", k, current.Labels[k], v))
+		}
+	}
+	return diffs, drift
+}
+
+// normalize formats a param as JSON, with numbers kept exact and hex strings in lower case, so that values decoded
+// from the API compare equal to the ones of the manifest.
+func normalize(v any) string {
+	if v == nil {
+		return "<unset>"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var decoded any
+	if err := dec.Decode(&decoded); err != nil {
+		return string(data)
+	}
+	if s, ok := decoded.(string); ok && strings.HasPrefix(s, "0x") {
+		decoded = strings.ToLower(s)
+	}
+	data, _ = json.Marshal(decoded)
+	return string(data)
+}
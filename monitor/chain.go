@@ -0,0 +1,189 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+// call mirrors the gate language's Call, calling method on contract at the block being evaluated.
+func call(ctx context.Context, bc BlockContext, contract eth.Address, method abi.Method, args ...any) ([]any, error) {
+	return callAt(ctx, bc.Chain, rpc.NumberAt(bc.Number), contract, method, args...)
+}
+
+func callAt(ctx context.Context, chain Chain, number rpc.BlockNumber, contract eth.Address, method abi.Method, args ...any) ([]any, error) {
+	output, err := callRaw(ctx, chain, number, contract, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return method.UnpackOutputs(output)
+}
+
+func callRaw(ctx context.Context, chain Chain, number rpc.BlockNumber, contract eth.Address, method abi.Method, args ...any) ([]byte, error) {
+	calldata, err := method.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := chain.CallContract(ctx, rpc.CallMsg{To: contract, Data: calldata}, number)
+	if err != nil {
+		return nil, fmt.Errorf("calling %s on %s: %w", method.Signature(), contract, err)
+	}
+	return output, nil
+}
+
+// callUint calls a method returning a single integer.
+func callUint(ctx context.Context, bc BlockContext, contract eth.Address, method abi.Method, args ...any) (*big.Int, error) {
+	values, err := call(ctx, bc, contract, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+// callAddress calls a method returning a single address.
+func callAddress(ctx context.Context, bc BlockContext, contract eth.Address, method abi.Method, args ...any) (eth.Address, error) {
+	values, err := call(ctx, bc, contract, method, args...)
+	if err != nil {
+		return eth.Address{}, err
+	}
+	return values[0].(eth.Address), nil
+}
+
+// callBool calls a method returning a single bool.
+func callBool(ctx context.Context, bc BlockContext, contract eth.Address, method abi.Method, args ...any) (bool, error) {
+	values, err := call(ctx, bc, contract, method, args...)
+	if err != nil {
+		return false, err
+	}
+	return values[0].(bool), nil
+}
+
+// claims mirrors the claimCount and claimData sources shared by most dispute game monitors.
+func claims(ctx context.Context, bc BlockContext, disputeGame eth.Address) (*big.Int, []abi.Claim, error) {
+	count, err := callUint(ctx, bc, disputeGame, abi.ClaimDataLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !count.IsInt64() {
+		return nil, nil, fmt.Errorf("claim count %s out of range", count)
+	}
+	data := make([]abi.Claim, count.Int64())
+	for i := range data {
+		output, err := callRaw(ctx, bc.Chain, rpc.NumberAt(bc.Number), disputeGame, abi.ClaimData, i)
+		if err != nil {
+			return nil, nil, err
+		}
+		if data[i], err = abi.DecodeClaim(output); err != nil {
+			return nil, nil, err
+		}
+	}
+	return count, data, nil
+}
+
+// event is a decoded log, with the block it was emitted in for historical events.
+type event struct {
+	BlockNumber uint64
+	Values      []any
+}
+
+// events mirrors the gate language's Events, returning the events emitted by contract in the block.
+func events(ctx context.Context, bc BlockContext, contract eth.Address, e abi.Event) ([]event, error) {
+	hash := bc.Hash
+	logs, err := bc.Chain.Logs(ctx, rpc.FilterQuery{
+		BlockHash: &hash,
+		Addresses: []eth.Address{contract},
+		Topics:    [][]eth.Hash{{e.Topic()}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s events: %w", e.Name, err)
+	}
+	return decodeLogs(e, logs)
+}
+
+// historicalEvents mirrors the gate language's HistoricalEvents, returning the events emitted by contract up to
+// and including the block.
+func historicalEvents(ctx context.Context, bc BlockContext, contract eth.Address, e abi.Event) ([]event, error) {
+	if bc.History == nil {
+		return nil, fmt.Errorf("fetching %s events: no history configured", e.Name)
+	}
+	logs, err := bc.History.Logs(ctx, contract, e.Topic(), bc.Number)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s events: %w", e.Name, err)
+	}
+	return decodeLogs(e, logs)
+}
+
+func decodeLogs(e abi.Event, logs []eth.Log) ([]event, error) {
+	decoded := make([]event, 0, len(logs))
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		values, err := e.Decode(log)
+		if err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, event{BlockNumber: uint64(log.BlockNumber), Values: values})
+	}
+	return decoded, nil
+}
+
+// decodedCall is a call with its decoded arguments.
+type decodedCall struct {
+	Call
+	Args []any
+}
+
+// calls mirrors the gate language's Calls, returning the calls to method on contract made in the block.
+func calls(bc BlockContext, contract eth.Address, method abi.Method) ([]decodedCall, error) {
+	return decodeCalls(bc.Calls, contract, method)
+}
+
+// historicalCalls mirrors the gate language's HistoricalCalls, returning the calls to method on contract made up
+// to and including the block.
+func historicalCalls(ctx context.Context, bc BlockContext, contract eth.Address, method abi.Method) ([]decodedCall, error) {
+	if bc.History == nil {
+		return nil, fmt.Errorf("fetching %s calls: no history configured", method.Name)
+	}
+	past, err := bc.History.Calls(ctx, contract, method.Selector(), bc.Number)
+	if err != nil {
+		return nil, fmt.Errorf("fetching %s calls: %w", method.Name, err)
+	}
+	return decodeCalls(past, contract, method)
+}
+
+func decodeCalls(all []Call, contract eth.Address, method abi.Method) ([]decodedCall, error) {
+	var decoded []decodedCall
+	selector := method.Selector()
+	for _, c := range all {
+		if c.To != contract || len(c.Input) < 4 || !bytes.Equal(c.Input[:4], selector) {
+			continue
+		}
+		args, err := method.UnpackInputs(c.Input)
+		if err != nil {
+			return nil, err
+		}
+		decoded = append(decoded, decodedCall{Call: c, Args: args})
+	}
+	return decoded, nil
+}
+
+// addressesInTrace mirrors the gate language's FilterAddressesInTrace, returning the addresses that were called or
+// made calls in the block.
+func addressesInTrace(bc BlockContext, addresses ...eth.Address) []eth.Address {
+	found := []eth.Address{}
+	for _, address := range addresses {
+		for _, c := range bc.Calls {
+			if c.From == address || c.To == address {
+				found = append(found, address)
+				break
+			}
+		}
+	}
+	return found
+}
@@ -0,0 +1,98 @@
+package monitor
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+func init() {
+	register(func() Monitor { return new(ChallengedProposal) })
+}
+
+// ChallengedProposal mirrors challenged_proposal.gate, alerting when the honest challenger attacks a root claim
+// made by the honest proposer.
+type ChallengedProposal struct {
+	DisputeGame      eth.Address `json:"disputeGame"`
+	HonestProposer   eth.Address `json:"honestProposer"`
+	HonestChallenger eth.Address `json:"honestChallenger"`
+}
+
+type challengedProposalSources struct {
+	MoveEvents []moveEvent `source:"moveEvents"`
+	ClaimCount *big.Int    `source:"claimCount"`
+	ClaimData  []abi.Claim `source:"claimData"`
+}
+
+// Name implements Monitor.
+func (m *ChallengedProposal) Name() string { return "challenged_proposal" }
+
+// Contract implements Monitor.
+func (m *ChallengedProposal) Contract() eth.Address { return m.DisputeGame }
+
+// Evaluate implements Monitor.
+func (m *ChallengedProposal) Evaluate(ctx context.Context, bc BlockContext) ([]Violation, error) {
+	s, err := m.load(ctx, bc)
+	if s == nil || err != nil {
+		return nil, err
+	}
+	return m.check(bc, s), nil
+}
+
+func (m *ChallengedProposal) sources(ctx context.Context, bc BlockContext) (any, error) {
+	s, err := m.load(ctx, bc)
+	if err != nil {
+		return nil, err
+	}
+	m.check(bc, s)
+	return s, nil
+}
+
+// load reads the sources from the chain. It returns nil sources when the invariants cannot fail in the block,
+// unless every source is requested.
+func (m *ChallengedProposal) load(ctx context.Context, bc BlockContext) (*challengedProposalSources, error) {
+	var s challengedProposalSources
+	moves, err := events(ctx, bc, m.DisputeGame, abi.Move)
+	if err != nil {
+		return nil, err
+	}
+	s.MoveEvents = moveEvents(moves)
+	// the claims only matter when a move was made in the block
+	if len(s.MoveEvents) > 0 || bc.allSources {
+		if s.ClaimCount, s.ClaimData, err = claims(ctx, bc, m.DisputeGame); err != nil {
+			return nil, err
+		}
+	}
+	return &s, nil
+}
+
+func (m *ChallengedProposal) evaluateMocks(bc BlockContext, mocks map[string]any) ([]Violation, error) {
+	var s challengedProposalSources
+	if err := decodeSources(mocks, &s); err != nil {
+		return nil, err
+	}
+	return m.check(bc, &s), nil
+}
+
+func (m *ChallengedProposal) check(bc BlockContext, s *challengedProposalSources) []Violation {
+	if len(s.MoveEvents) == 0 || len(s.ClaimData) == 0 {
+		return nil
+	}
+
+	// attacks that are ultimately challenges to the root claim have an even parent index, no matter the depth
+	var challengerAttacks []bool
+	if s.ClaimData[0].Claimant == m.HonestProposer {
+		for _, claim := range s.ClaimData {
+			parentIndex := new(big.Int).SetUint64(uint64(claim.ParentIndex))
+			challengerAttacks = append(challengerAttacks,
+				claim.Claimant == m.HonestChallenger && rangeContains(0, s.ClaimCount, 2, parentIndex))
+		}
+	}
+
+	if containsBool(challengerAttacks, true) {
+		return []Violation{bc.violation(m.Name(), m.DisputeGame, "CB challenger attacked a state output root proposed by CB proposer")}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+func init() {
+	register(func() Monitor { return new(ChallengerLoses) })
+}
+
+// ChallengerLoses mirrors challenger_loses.gate, alerting when the honest challenger loses the game or any of the
+// subgames it took part in.
+type ChallengerLoses struct {
+	HonestChallenger eth.Address `json:"honestChallenger"`
+	DisputeGame      eth.Address `json:"disputeGame"`
+}
+
+type challengerLosesSources struct {
+	AddressesInTrace     []eth.Address `source:"addressesInTrace"`
+	ResolveEvents        [][]*big.Int  `source:"resolveEvents"`
+	HistoricalMoveEvents []moveEvent   `source:"historicalMoveEvents"`
+	ClaimCount           *big.Int      `source:"claimCount"`
+	ClaimResults         []abi.Claim   `source:"claimResults"`
+}
+
+// Name implements Monitor.
+func (m *ChallengerLoses) Name() string { return "challenger_loses" }
+
+// Contract implements Monitor.
+func (m *ChallengerLoses) Contract() eth.Address { return m.DisputeGame }
+
+// Evaluate implements Monitor.
+func (m *ChallengerLoses) Evaluate(ctx context.Context, bc BlockContext) ([]Violation, error) {
+	s, err := m.load(ctx, bc)
+	if s == nil || err != nil {
+		return nil, err
+	}
+	return m.check(bc, s), nil
+}
+
+func (m *ChallengerLoses) sources(ctx context.Context, bc BlockContext) (any, error) {
+	s, err := m.load(ctx, bc)
+	if err != nil {
+		return nil, err
+	}
+	m.check(bc, s)
+	return s, nil
+}
+
+// load reads the sources from the chain. It returns nil sources when the invariants cannot fail in the block,
+// unless every source is requested.
+func (m *ChallengerLoses) load(ctx context.Context, bc BlockContext) (*challengerLosesSources, error) {
+	s := challengerLosesSources{AddressesInTrace: addressesInTrace(bc, m.DisputeGame)}
+	if len(s.AddressesInTrace) == 0 && !bc.allSources {
+		return nil, nil
+	}
+
+	resolved, err := events(ctx, bc, m.DisputeGame, abi.Resolved)
+	if err != nil {
+		return nil, err
+	}
+	for _, ev := range resolved {
+		s.ResolveEvents = append(s.ResolveEvents, []*big.Int{ev.Values[0].(*big.Int)})
+	}
+	moves, err := historicalEvents(ctx, bc, m.DisputeGame, abi.Move)
+	if err != nil {
+		return nil, err
+	}
+	s.HistoricalMoveEvents = moveEvents(moves)
+	if s.ClaimCount, s.ClaimResults, err = claims(ctx, bc, m.DisputeGame); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (m *ChallengerLoses) evaluateMocks(bc BlockContext, mocks map[string]any) ([]Violation, error) {
+	var s challengerLosesSources
+	if err := decodeSources(mocks, &s); err != nil {
+		return nil, err
+	}
+	return m.check(bc, &s), nil
+}
+
+func (m *ChallengerLoses) check(bc BlockContext, s *challengerLosesSources) []Violation {
+	if len(s.AddressesInTrace) == 0 {
+		return nil
+	}
+
+	// a move with an even parent index is a challenge, and one with an odd parent index is a defense
+	var challengeMoves, defenseMoves int
+	for _, move := range s.HistoricalMoveEvents {
+		if move.Claimant != m.HonestChallenger {
+			continue
+		}
+		if rangeContains(0, s.ClaimCount, 2, move.ParentIndex) {
+			challengeMoves++
+		}
+		if rangeContains(1, s.ClaimCount, 2, move.ParentIndex) {
+			defenseMoves++
+		}
+	}
+
+	var violations []Violation
+	if len(s.ResolveEvents) > 0 && len(s.ResolveEvents[0]) > 0 {
+		// GameStatus is 1 when the challenger wins and 2 when the defender wins
+		status := s.ResolveEvents[0][0]
+		if status.Cmp(big.NewInt(2)) == 0 && challengeMoves > 0 {
+			violations = append(violations, bc.violation(m.Name(), m.DisputeGame, "Challenger lost the dispute game while challenging a state root"))
+		}
+		if status.Cmp(big.NewInt(1)) == 0 && defenseMoves > 0 {
+			violations = append(violations, bc.violation(m.Name(), m.DisputeGame, "Challenger lost the dispute game while defending a state root"))
+		}
+	}
+
+	for _, subgame := range s.ClaimResults {
+		if subgame.Claimant == m.HonestChallenger && subgame.CounteredBy != (eth.Address{}) {
+			violations = append(violations, bc.violation(m.Name(), m.DisputeGame, "Challenger lost one or more subgames"))
+			break
+		}
+	}
+	return violations
+}
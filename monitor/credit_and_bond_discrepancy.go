@@ -0,0 +1,147 @@
+package monitor
+
+import (
+	"context"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+func init() {
+	register(func() Monitor { return new(CreditAndBondDiscrepancy) })
+}
+
+// CreditAndBondDiscrepancy mirrors credit_and_bond_discrepancy.gate, alerting when a claimCredit call on the
+// dispute game is not matched by the expected unlock or withdraw on DelayedWETH.
+type CreditAndBondDiscrepancy struct {
+	DisputeGame eth.Address `json:"disputeGame"`
+}
+
+// recipientCall is a call taking a single recipient, such as `claimCredit(address _recipient)`.
+type recipientCall struct {
+	Recipient eth.Address
+}
+
+type creditAndBondDiscrepancySources struct {
+	AddressesInTrace         []eth.Address   `source:"addressesInTrace"`
+	CreditCalls              []recipientCall `source:"creditCalls"`
+	DelayedWeth              eth.Address     `source:"delayedWeth"`
+	Unlocks                  []addressAmount `source:"unlocks"`
+	Withdraws                []addressAmount `source:"withdraws"`
+	WithdrawList             []eth.Address   `source:"withdrawList,derived"`
+	WithdrawalsForRecipients []bool          `source:"withdrawalsForRecipients,derived"`
+	WinnersAndBonds          []addressAmount `source:"winnersAndBonds"`
+	FoundUnlocks             []bool          `source:"foundUnlocks,derived"`
+}
+
+// Name implements Monitor.
+func (m *CreditAndBondDiscrepancy) Name() string { return "credit_and_bond_discrepancy" }
+
+// Contract implements Monitor.
+func (m *CreditAndBondDiscrepancy) Contract() eth.Address { return m.DisputeGame }
+
+// Evaluate implements Monitor.
+func (m *CreditAndBondDiscrepancy) Evaluate(ctx context.Context, bc BlockContext) ([]Violation, error) {
+	s, err := m.load(ctx, bc)
+	if s == nil || err != nil {
+		return nil, err
+	}
+	return m.check(bc, s), nil
+}
+
+func (m *CreditAndBondDiscrepancy) sources(ctx context.Context, bc BlockContext) (any, error) {
+	s, err := m.load(ctx, bc)
+	if err != nil {
+		return nil, err
+	}
+	m.check(bc, s)
+	return s, nil
+}
+
+// load reads the sources from the chain. It returns nil sources when the invariants cannot fail in the block,
+// unless every source is requested.
+func (m *CreditAndBondDiscrepancy) load(ctx context.Context, bc BlockContext) (*creditAndBondDiscrepancySources, error) {
+	s := creditAndBondDiscrepancySources{AddressesInTrace: addressesInTrace(bc, m.DisputeGame)}
+	if len(s.AddressesInTrace) == 0 && !bc.allSources {
+		return nil, nil
+	}
+
+	creditCalls, err := calls(bc, m.DisputeGame, claimCreditMethod)
+	if err != nil {
+		return nil, err
+	}
+	s.CreditCalls = make([]recipientCall, len(creditCalls))
+	for i, c := range creditCalls {
+		s.CreditCalls[i] = recipientCall{Recipient: c.Args[0].(eth.Address)}
+	}
+	if s.DelayedWeth, err = callAddress(ctx, bc, m.DisputeGame, wethMethod); err != nil {
+		return nil, err
+	}
+	unlocks, err := calls(bc, s.DelayedWeth, unlockMethod)
+	if err != nil {
+		return nil, err
+	}
+	s.Unlocks = addressAmounts(unlocks)
+	withdraws, err := calls(bc, s.DelayedWeth, withdrawMethod)
+	if err != nil {
+		return nil, err
+	}
+	s.Withdraws = addressAmounts(withdraws)
+
+	// fetch the credit owed to each recipient for comparison against the unlocked bonds
+	s.WinnersAndBonds = make([]addressAmount, len(s.CreditCalls))
+	for i, c := range s.CreditCalls {
+		credit, err := callUint(ctx, bc, m.DisputeGame, creditMethod, c.Recipient)
+		if err != nil {
+			return nil, err
+		}
+		s.WinnersAndBonds[i] = addressAmount{Address: c.Recipient, Amount: credit}
+	}
+	return &s, nil
+}
+
+func (m *CreditAndBondDiscrepancy) evaluateMocks(bc BlockContext, mocks map[string]any) ([]Violation, error) {
+	var s creditAndBondDiscrepancySources
+	if err := decodeSources(mocks, &s); err != nil {
+		return nil, err
+	}
+	return m.check(bc, &s), nil
+}
+
+func (m *CreditAndBondDiscrepancy) check(bc BlockContext, s *creditAndBondDiscrepancySources) []Violation {
+	if len(s.AddressesInTrace) == 0 {
+		return nil
+	}
+
+	if s.WithdrawList == nil {
+		for _, withdraw := range s.Withdraws {
+			s.WithdrawList = append(s.WithdrawList, withdraw.Address)
+		}
+	}
+	if s.WithdrawalsForRecipients == nil {
+		for _, c := range s.CreditCalls {
+			s.WithdrawalsForRecipients = append(s.WithdrawalsForRecipients, containsAddress(s.WithdrawList, c.Recipient))
+		}
+	}
+	// every recipient's credit must be unlocked with exactly the same amount
+	if s.FoundUnlocks == nil {
+		for _, winner := range s.WinnersAndBonds {
+			found := false
+			for _, unlock := range s.Unlocks {
+				found = found || unlock.equal(winner)
+			}
+			s.FoundUnlocks = append(s.FoundUnlocks, found)
+		}
+	}
+
+	var violations []Violation
+	if len(s.Unlocks) > 0 && containsBool(s.FoundUnlocks, false) {
+		violations = append(violations, bc.violation(m.Name(), m.DisputeGame, "Credit discrepancy: could not find matching unlock for claimCredit call"))
+	}
+	if len(s.Withdraws) > 0 && containsBool(s.WithdrawalsForRecipients, false) {
+		violations = append(violations, bc.violation(m.Name(), m.DisputeGame, "Withdrawal discrepancy: could not find matching withdraw for claimCredit call"))
+	}
+	if len(s.CreditCalls) > 0 && len(s.Withdraws) == 0 && len(s.Unlocks) == 0 {
+		violations = append(violations, bc.violation(m.Name(), m.DisputeGame, "Credit and Bond discrepancy: could not find withdraws or unlocks for claimCredit call"))
+	}
+	return violations
+}
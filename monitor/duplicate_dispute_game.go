@@ -0,0 +1,176 @@
+package monitor
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+func init() {
+	register(func() Monitor { return new(DuplicateDisputeGame) })
+}
+
+// DuplicateDisputeGame mirrors duplicate_dispute_game.gate, alerting when a dispute game of the respected game type
+// is created with the same UUID as an earlier game or another game created in the same block.
+type DuplicateDisputeGame struct {
+	OptimismPortalProxy eth.Address `json:"optimismPortalProxy"`
+}
+
+// createCall is a `create(uint32 _gameType, bytes32 _rootClaim, bytes _extraData)` call.
+type createCall struct {
+	GameType  *big.Int
+	RootClaim eth.Hash
+	ExtraData eth.Bytes
+}
+
+// createdGame is a `DisputeGameCreated` event with the block it was emitted in.
+type createdGame struct {
+	BlockNumber *big.Int
+	Game        struct {
+		DisputeProxy eth.Address
+		GameType     *big.Int
+		RootClaim    eth.Hash
+	}
+}
+
+type duplicateDisputeGameSources struct {
+	DisputeGameFactory           eth.Address   `source:"disputeGameFactory"`
+	RespectedGameType            *big.Int      `source:"respectedGameType"`
+	CurrBlock                    *big.Int      `source:"currBlock"`
+	NewDisputeGames              []createCall  `source:"newDisputeGames"`
+	CreatedDisputeGames          []createdGame `source:"createdDisputeGames"`
+	CreatedDisputeGamesExtraData []eth.Bytes   `source:"createdDisputeGamesExtraData"`
+	NewDisputeGameUUIDs          []eth.Hash    `source:"newDisputeGameUUIDs"`
+	PreviousDisputeGameUUIDs     []eth.Hash    `source:"previousDisputeGameUUIDs"`
+}
+
+// Name implements Monitor.
+func (m *DuplicateDisputeGame) Name() string { return "duplicate_dispute_game" }
+
+// Contract implements Monitor.
+func (m *DuplicateDisputeGame) Contract() eth.Address { return m.OptimismPortalProxy }
+
+// Evaluate implements Monitor.
+func (m *DuplicateDisputeGame) Evaluate(ctx context.Context, bc BlockContext) ([]Violation, error) {
+	s, err := m.load(ctx, bc)
+	if s == nil || err != nil {
+		return nil, err
+	}
+	return m.check(bc, s), nil
+}
+
+func (m *DuplicateDisputeGame) sources(ctx context.Context, bc BlockContext) (any, error) {
+	s, err := m.load(ctx, bc)
+	if err != nil {
+		return nil, err
+	}
+	m.check(bc, s)
+	return s, nil
+}
+
+// load reads the sources from the chain. It returns nil sources when the invariants cannot fail in the block,
+// unless every source is requested.
+func (m *DuplicateDisputeGame) load(ctx context.Context, bc BlockContext) (*duplicateDisputeGameSources, error) {
+	var s duplicateDisputeGameSources
+	var err error
+	if s.DisputeGameFactory, err = callAddress(ctx, bc, m.OptimismPortalProxy, disputeGameFactoryMethod); err != nil {
+		return nil, err
+	}
+	if s.RespectedGameType, err = callUint(ctx, bc, m.OptimismPortalProxy, respectedGameTypeMethod); err != nil {
+		return nil, err
+	}
+	s.CurrBlock = new(big.Int).SetUint64(bc.Number)
+
+	creates, err := calls(bc, s.DisputeGameFactory, abi.Create)
+	if err != nil {
+		return nil, err
+	}
+	s.NewDisputeGames = make([]createCall, len(creates))
+	respected := false
+	for i, c := range creates {
+		s.NewDisputeGames[i] = createCall{
+			GameType:  c.Args[0].(*big.Int),
+			RootClaim: eth.BytesToHash(c.Args[1].([]byte)),
+			ExtraData: c.Args[2].([]byte),
+		}
+		respected = respected || s.NewDisputeGames[i].GameType.Cmp(s.RespectedGameType) == 0
+	}
+	// the history of created games is only needed when a game of the respected type was created in the block
+	if !respected && !bc.allSources {
+		return &s, nil
+	}
+
+	created, err := historicalEvents(ctx, bc, s.DisputeGameFactory, abi.DisputeGameCreated)
+	if err != nil {
+		return nil, err
+	}
+	s.CreatedDisputeGames = make([]createdGame, len(created))
+	s.CreatedDisputeGamesExtraData = make([]eth.Bytes, len(created))
+	for i, ev := range created {
+		game := &s.CreatedDisputeGames[i]
+		game.BlockNumber = new(big.Int).SetUint64(ev.BlockNumber)
+		game.Game.DisputeProxy = ev.Values[0].(eth.Address)
+		game.Game.GameType = ev.Values[1].(*big.Int)
+		game.Game.RootClaim = eth.BytesToHash(ev.Values[2].([]byte))
+
+		values, err := call(ctx, bc, game.Game.DisputeProxy, extraDataMethod)
+		if err != nil {
+			return nil, err
+		}
+		s.CreatedDisputeGamesExtraData[i] = values[0].([]byte)
+	}
+	return &s, nil
+}
+
+func (m *DuplicateDisputeGame) evaluateMocks(bc BlockContext, mocks map[string]any) ([]Violation, error) {
+	var s duplicateDisputeGameSources
+	if err := decodeSources(mocks, &s); err != nil {
+		return nil, err
+	}
+	return m.check(bc, &s), nil
+}
+
+func (m *DuplicateDisputeGame) check(bc BlockContext, s *duplicateDisputeGameSources) []Violation {
+	// the UUID getGameUUID returns is a pure hash of the game type, root claim and extra data
+	uuid := func(gameType *big.Int, rootClaim eth.Hash, extraData []byte) eth.Hash {
+		// encoding a uint32, bytes32 and bytes cannot fail
+		hash, _ := abi.GameUUID(uint32(bigOrZero(gameType).Uint64()), rootClaim, extraData)
+		return hash
+	}
+
+	if s.NewDisputeGameUUIDs == nil {
+		for _, game := range s.NewDisputeGames {
+			if bigEqual(game.GameType, s.RespectedGameType) {
+				s.NewDisputeGameUUIDs = append(s.NewDisputeGameUUIDs, uuid(game.GameType, game.RootClaim, game.ExtraData))
+			}
+		}
+	}
+	if s.PreviousDisputeGameUUIDs == nil {
+		// created games are zipped with their extra data, so only games with extra data are considered
+		for i, game := range s.CreatedDisputeGames {
+			if i >= len(s.CreatedDisputeGamesExtraData) {
+				break
+			}
+			if bigEqual(game.Game.GameType, s.RespectedGameType) && bigOrZero(game.BlockNumber).Cmp(bigOrZero(s.CurrBlock)) < 0 {
+				s.PreviousDisputeGameUUIDs = append(s.PreviousDisputeGameUUIDs, uuid(game.Game.GameType, game.Game.RootClaim, s.CreatedDisputeGamesExtraData[i]))
+			}
+		}
+	}
+
+	created := make(map[eth.Hash]bool, len(s.PreviousDisputeGameUUIDs))
+	for _, id := range s.PreviousDisputeGameUUIDs {
+		created[id] = true
+	}
+	duplicate := false
+	unique := make(map[eth.Hash]bool, len(s.NewDisputeGameUUIDs))
+	for _, id := range s.NewDisputeGameUUIDs {
+		duplicate = duplicate || created[id] || unique[id]
+		unique[id] = true
+	}
+	if duplicate {
+		return []Violation{bc.violation(m.Name(), m.OptimismPortalProxy, "Duplicate Game UUID (Dispute Game Type, Root Claim, and Extra Data) Detected")}
+	}
+	return nil
+}
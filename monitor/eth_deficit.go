@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+func init() {
+	register(func() Monitor { return new(ETHDeficit) })
+}
+
+// ETHDeficit mirrors eth_deficit.gate, alerting when the credit due to the honest challenger is not backed by
+// the ETH unlocked for it and held in DelayedWETH.
+type ETHDeficit struct {
+	DisputeGame      eth.Address `json:"disputeGame"`
+	HonestChallenger eth.Address `json:"honestChallenger"`
+}
+
+type ethDeficitSources struct {
+	DelayedWETH           eth.Address `source:"delayedWETH"`
+	BondDistributionMode  *big.Int    `source:"bondDistributionMode"`
+	HasUnlockedCredit     bool        `source:"hasUnlockedCredit"`
+	ClaimCredit           *big.Int    `source:"claimCredit"`
+	RefundModeCredit      *big.Int    `source:"refundModeCredit"`
+	TotalCredit           []*big.Int  `source:"totalCredit"`
+	EthBalanceDisputeGame *big.Int    `source:"ethBalanceDisputeGame"`
+}
+
+// Name implements Monitor.
+func (m *ETHDeficit) Name() string { return "eth_deficit" }
+
+// Contract implements Monitor.
+func (m *ETHDeficit) Contract() eth.Address { return m.DisputeGame }
+
+// Evaluate implements Monitor.
+func (m *ETHDeficit) Evaluate(ctx context.Context, bc BlockContext) ([]Violation, error) {
+	s, err := m.load(ctx, bc)
+	if s == nil || err != nil {
+		return nil, err
+	}
+	return m.check(bc, s), nil
+}
+
+func (m *ETHDeficit) sources(ctx context.Context, bc BlockContext) (any, error) {
+	s, err := m.load(ctx, bc)
+	if err != nil {
+		return nil, err
+	}
+	m.check(bc, s)
+	return s, nil
+}
+
+// load reads the sources from the chain. It returns nil sources when the invariants cannot fail in the block,
+// unless every source is requested.
+func (m *ETHDeficit) load(ctx context.Context, bc BlockContext) (*ethDeficitSources, error) {
+	var s ethDeficitSources
+	var err error
+	if s.BondDistributionMode, err = callUint(ctx, bc, m.DisputeGame, bondDistributionModeMethod); err != nil {
+		return nil, err
+	}
+	// nothing is checked until the bond distribution mode is decided
+	if s.BondDistributionMode.Sign() == 0 && !bc.allSources {
+		return nil, nil
+	}
+	if s.DelayedWETH, err = callAddress(ctx, bc, m.DisputeGame, wethMethod); err != nil {
+		return nil, err
+	}
+	if s.HasUnlockedCredit, err = callBool(ctx, bc, m.DisputeGame, hasUnlockedCreditMethod, m.HonestChallenger); err != nil {
+		return nil, err
+	}
+	if s.ClaimCredit, err = callUint(ctx, bc, m.DisputeGame, normalModeCreditMethod, m.HonestChallenger); err != nil {
+		return nil, err
+	}
+	if s.RefundModeCredit, err = callUint(ctx, bc, m.DisputeGame, refundModeCreditMethod, m.HonestChallenger); err != nil {
+		return nil, err
+	}
+	withdrawal, err := call(ctx, bc, s.DelayedWETH, abi.Withdrawals, m.DisputeGame, m.HonestChallenger)
+	if err != nil {
+		return nil, err
+	}
+	s.TotalCredit = []*big.Int{withdrawal[0].(*big.Int), withdrawal[1].(*big.Int)}
+	if s.EthBalanceDisputeGame, err = callUint(ctx, bc, s.DelayedWETH, balanceOfMethod, m.DisputeGame); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (m *ETHDeficit) evaluateMocks(bc BlockContext, mocks map[string]any) ([]Violation, error) {
+	var s ethDeficitSources
+	if err := decodeSources(mocks, &s); err != nil {
+		return nil, err
+	}
+	return m.check(bc, &s), nil
+}
+
+func (m *ETHDeficit) check(bc BlockContext, s *ethDeficitSources) []Violation {
+	mode := bigOrZero(s.BondDistributionMode)
+	if mode.Sign() == 0 {
+		return nil
+	}
+
+	// the credit to check depends on whether bonds are distributed normally (1) or refunded (2)
+	creditBalanceToCheck := new(big.Int)
+	switch mode.Int64() {
+	case 1:
+		creditBalanceToCheck = bigOrZero(s.ClaimCredit)
+	case 2:
+		creditBalanceToCheck = bigOrZero(s.RefundModeCredit)
+	}
+	totalCredit := new(big.Int)
+	if len(s.TotalCredit) > 0 {
+		totalCredit = bigOrZero(s.TotalCredit[0])
+	}
+
+	ok := s.HasUnlockedCredit &&
+		creditBalanceToCheck.Cmp(totalCredit) <= 0 &&
+		totalCredit.Cmp(bigOrZero(s.EthBalanceDisputeGame)) <= 0 &&
+		!(creditBalanceToCheck.Sign() == 0 && totalCredit.Sign() != 0)
+	if !ok {
+		return []Violation{bc.violation(m.Name(), m.DisputeGame, "Deficit of ETH in DelayedWETH contract")}
+	}
+	return nil
+}
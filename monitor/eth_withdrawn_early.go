@@ -0,0 +1,224 @@
+package monitor
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+func init() {
+	register(func() Monitor { return new(ETHWithdrawnEarly) })
+}
+
+// ETHWithdrawnEarly mirrors eth_withdrawn_early.gate, alerting when a bond is withdrawn from DelayedWETH before the
+// delay has passed since it was unlocked, or without a matching unlock.
+type ETHWithdrawnEarly struct {
+	// Multicall3 is only used by the gate file to read block timestamps, which are read from block headers here.
+	Multicall3  eth.Address `json:"multicall3"`
+	DisputeGame eth.Address `json:"disputeGame"`
+}
+
+// unlockCall is an `unlock(address _guy, uint256 _wad)` call with the block it was made in and its sender.
+type unlockCall struct {
+	BlockNumber *big.Int
+	Sender      eth.Address
+	Args        addressAmount
+}
+
+type ethWithdrawnEarlySources struct {
+	AddressesInTrace     []eth.Address   `source:"addressesInTrace"`
+	DelayedWETH          eth.Address     `source:"delayedWETH"`
+	Claims               []recipientCall `source:"claims"`
+	Withdrawals          []addressAmount `source:"withdrawals"`
+	ClaimsAndWithdrawals []addressAmount `source:"claimsAndWithdrawals,derived"`
+	DelayTime            *big.Int        `source:"delayTime"`
+	Unlocks              []unlockCall    `source:"unlocks"`
+	UnlockTimestamps     []*big.Int      `source:"unlockTimestamps"`
+	HasUnlockedCredit    []bool          `source:"hasUnlockedCredit"`
+	CurrTimestamp        *big.Int        `source:"currTimestamp"`
+}
+
+// Name implements Monitor.
+func (m *ETHWithdrawnEarly) Name() string { return "eth_withdrawn_early" }
+
+// Contract implements Monitor.
+func (m *ETHWithdrawnEarly) Contract() eth.Address { return m.DisputeGame }
+
+// Evaluate implements Monitor.
+func (m *ETHWithdrawnEarly) Evaluate(ctx context.Context, bc BlockContext) ([]Violation, error) {
+	s, err := m.load(ctx, bc)
+	if s == nil || err != nil {
+		return nil, err
+	}
+	return m.check(bc, s), nil
+}
+
+func (m *ETHWithdrawnEarly) sources(ctx context.Context, bc BlockContext) (any, error) {
+	s, err := m.load(ctx, bc)
+	if err != nil {
+		return nil, err
+	}
+	m.check(bc, s)
+	return s, nil
+}
+
+// load reads the sources from the chain. It returns nil sources when the invariants cannot fail in the block,
+// unless every source is requested.
+func (m *ETHWithdrawnEarly) load(ctx context.Context, bc BlockContext) (*ethWithdrawnEarlySources, error) {
+	s := ethWithdrawnEarlySources{AddressesInTrace: addressesInTrace(bc, m.DisputeGame)}
+	if len(s.AddressesInTrace) == 0 && !bc.allSources {
+		return nil, nil
+	}
+
+	var err error
+	if s.DelayedWETH, err = callAddress(ctx, bc, m.DisputeGame, wethMethod); err != nil {
+		return nil, err
+	}
+	claimCalls, err := calls(bc, m.DisputeGame, claimCreditMethod)
+	if err != nil {
+		return nil, err
+	}
+	s.Claims = make([]recipientCall, len(claimCalls))
+	for i, c := range claimCalls {
+		s.Claims[i] = recipientCall{Recipient: c.Args[0].(eth.Address)}
+	}
+	withdrawals, err := calls(bc, s.DelayedWETH, withdrawMethod)
+	if err != nil {
+		return nil, err
+	}
+	s.Withdrawals = addressAmounts(withdrawals)
+	s.ClaimsAndWithdrawals = m.claimsAndWithdrawals(&s)
+	s.CurrTimestamp = new(big.Int).SetUint64(bc.Timestamp)
+	// the unlock history is only needed when bonds were withdrawn in the block
+	if len(s.ClaimsAndWithdrawals) == 0 && !bc.allSources {
+		return &s, nil
+	}
+
+	if s.DelayTime, err = callUint(ctx, bc, s.DelayedWETH, delayMethod); err != nil {
+		return nil, err
+	}
+	unlocks, err := historicalCalls(ctx, bc, s.DelayedWETH, unlockMethod)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range unlocks {
+		unlock := unlockCall{
+			BlockNumber: new(big.Int).SetUint64(u.BlockNumber),
+			Sender:      u.From,
+			Args:        addressAmounts([]decodedCall{u})[0],
+		}
+		s.Unlocks = append(s.Unlocks, unlock)
+		if unlock.Sender != m.DisputeGame {
+			continue
+		}
+		header, err := bc.Chain.HeaderByNumber(ctx, rpc.NumberAt(u.BlockNumber))
+		if err != nil {
+			return nil, err
+		}
+		s.UnlockTimestamps = append(s.UnlockTimestamps, new(big.Int).SetUint64(uint64(header.Timestamp)))
+	}
+	for _, recipient := range uniqueRecipients(s.ClaimsAndWithdrawals) {
+		unlocked, err := callBool(ctx, bc, m.DisputeGame, hasUnlockedCreditMethod, recipient)
+		if err != nil {
+			return nil, err
+		}
+		s.HasUnlockedCredit = append(s.HasUnlockedCredit, unlocked)
+	}
+	return &s, nil
+}
+
+func (m *ETHWithdrawnEarly) evaluateMocks(bc BlockContext, mocks map[string]any) ([]Violation, error) {
+	var s ethWithdrawnEarlySources
+	if err := decodeSources(mocks, &s); err != nil {
+		return nil, err
+	}
+	return m.check(bc, &s), nil
+}
+
+// claimsAndWithdrawals correlates the claimCredit calls on the dispute game with the withdrawals from DelayedWETH.
+func (m *ETHWithdrawnEarly) claimsAndWithdrawals(s *ethWithdrawnEarlySources) []addressAmount {
+	var correlated []addressAmount
+	for _, withdrawal := range s.Withdrawals {
+		for _, claim := range s.Claims {
+			if claim.Recipient == withdrawal.Address {
+				correlated = append(correlated, withdrawal)
+				break
+			}
+		}
+	}
+	return correlated
+}
+
+func uniqueRecipients(amounts []addressAmount) []eth.Address {
+	var recipients []eth.Address
+	for _, a := range amounts {
+		if !containsAddress(recipients, a.Address) {
+			recipients = append(recipients, a.Address)
+		}
+	}
+	return recipients
+}
+
+func (m *ETHWithdrawnEarly) check(bc BlockContext, s *ethWithdrawnEarlySources) []Violation {
+	if len(s.AddressesInTrace) == 0 {
+		return nil
+	}
+	if s.ClaimsAndWithdrawals == nil {
+		s.ClaimsAndWithdrawals = m.claimsAndWithdrawals(s)
+	}
+
+	// group the unlocks made by the dispute game by recipient, pairing each with its block timestamp
+	var gameUnlocks []addressAmount
+	for _, unlock := range s.Unlocks {
+		if unlock.Sender == m.DisputeGame {
+			gameUnlocks = append(gameUnlocks, unlock.Args)
+		}
+	}
+	type recipientUnlocks struct {
+		timestamps []*big.Int
+		amounts    []*big.Int
+	}
+	unlocksAndAmounts := make(map[eth.Address]*recipientUnlocks)
+	for idx, unlock := range gameUnlocks {
+		r, ok := unlocksAndAmounts[unlock.Address]
+		if !ok {
+			r = &recipientUnlocks{}
+			unlocksAndAmounts[unlock.Address] = r
+		}
+		r.amounts = append(r.amounts, unlock.Amount)
+		if idx < len(s.UnlockTimestamps) {
+			r.timestamps = append(r.timestamps, s.UnlockTimestamps[idx])
+		}
+	}
+
+	// a withdrawal must match the total unlocked for the recipient, after the delay has passed since the last unlock
+	invalidWithdrawal := false
+	for _, withdrawal := range s.ClaimsAndWithdrawals {
+		r, ok := unlocksAndAmounts[withdrawal.Address]
+		if !ok {
+			invalidWithdrawal = true
+			continue
+		}
+		latest := new(big.Int)
+		for _, ts := range r.timestamps {
+			if ts.Cmp(latest) > 0 {
+				latest = ts
+			}
+		}
+		elapsed := new(big.Int).Sub(bigOrZero(s.CurrTimestamp), latest)
+		if !bigEqual(withdrawal.Amount, sum(r.amounts)) || elapsed.Cmp(bigOrZero(s.DelayTime)) <= 0 {
+			invalidWithdrawal = true
+		}
+	}
+
+	var violations []Violation
+	if invalidWithdrawal {
+		violations = append(violations, bc.violation(m.Name(), m.DisputeGame, "ETH bond withdrawn too early from DelayedWETH"))
+	}
+	if containsBool(s.HasUnlockedCredit, false) {
+		violations = append(violations, bc.violation(m.Name(), m.DisputeGame, "Withdrawal recipient has not unlocked their credit"))
+	}
+	return violations
+}
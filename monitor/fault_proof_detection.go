@@ -0,0 +1,229 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/outputroot"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+func init() {
+	register(func() Monitor { return new(FaultProofDetectionParent) })
+	register(func() Monitor { return new(FaultProofDetectionChild) })
+}
+
+// FaultProofDetectionParent mirrors fault_proof_detection_parent.gate, alerting when a dispute game is created with
+// a root claim that does not match the output root of the L2 block, or when several games are created in a block.
+type FaultProofDetectionParent struct {
+	DisputeGameFactoryProxy eth.Address `json:"disputeGameFactoryProxy"`
+	L2ChainID               uint64      `json:"l2ChainId"`
+}
+
+// gameCreatedEvent is a `DisputeGameCreated` event.
+type gameCreatedEvent struct {
+	DisputeProxy eth.Address
+	GameType     *big.Int
+	RootClaim    eth.Hash
+}
+
+type faultProofDetectionParentSources struct {
+	DisputeGameCreatedEvents []gameCreatedEvent `source:"disputeGameCreatedEvents"`
+	BlockNumber              *big.Int           `source:"blockNumber"`
+	BlockHash                eth.Hash           `source:"blockHash"`
+	StateRoot                eth.Hash           `source:"stateRoot"`
+	MessagePasserStorageHash eth.Hash           `source:"messagePasserStorageHash"`
+}
+
+// Name implements Monitor.
+func (m *FaultProofDetectionParent) Name() string { return "fault_proof_detection_parent" }
+
+// Contract implements Monitor.
+func (m *FaultProofDetectionParent) Contract() eth.Address { return m.DisputeGameFactoryProxy }
+
+// Evaluate implements Monitor.
+func (m *FaultProofDetectionParent) Evaluate(ctx context.Context, bc BlockContext) ([]Violation, error) {
+	s, err := m.load(ctx, bc)
+	if s == nil || err != nil {
+		return nil, err
+	}
+	return m.check(bc, s), nil
+}
+
+func (m *FaultProofDetectionParent) sources(ctx context.Context, bc BlockContext) (any, error) {
+	s, err := m.load(ctx, bc)
+	if err != nil {
+		return nil, err
+	}
+	m.check(bc, s)
+	return s, nil
+}
+
+// load reads the sources from the chain. It returns nil sources when the invariants cannot fail in the block,
+// unless every source is requested.
+func (m *FaultProofDetectionParent) load(ctx context.Context, bc BlockContext) (*faultProofDetectionParentSources, error) {
+	var s faultProofDetectionParentSources
+	created, err := events(ctx, bc, m.DisputeGameFactoryProxy, abi.DisputeGameCreated)
+	if err != nil {
+		return nil, err
+	}
+	if len(created) == 0 {
+		if bc.allSources {
+			return &s, nil
+		}
+		return nil, nil
+	}
+	for _, ev := range created {
+		s.DisputeGameCreatedEvents = append(s.DisputeGameCreatedEvents, gameCreatedEvent{
+			DisputeProxy: ev.Values[0].(eth.Address),
+			GameType:     ev.Values[1].(*big.Int),
+			RootClaim:    eth.BytesToHash(ev.Values[2].([]byte)),
+		})
+	}
+
+	l2, ok := bc.L2[m.L2ChainID]
+	if !ok {
+		return nil, fmt.Errorf("no L2 chain configured for chain ID %d", m.L2ChainID)
+	}
+	if s.BlockNumber, err = callUint(ctx, bc, s.DisputeGameCreatedEvents[0].DisputeProxy, l2BlockNumberMethod); err != nil {
+		return nil, err
+	}
+	if !s.BlockNumber.IsUint64() {
+		return nil, fmt.Errorf("l2 block number %s out of range", s.BlockNumber)
+	}
+	number := rpc.NumberAt(s.BlockNumber.Uint64())
+	header, err := l2.HeaderByNumber(ctx, number)
+	if err != nil {
+		return nil, fmt.Errorf("fetching l2 block %s: %w", number, err)
+	}
+	proof, err := l2.Proof(ctx, outputroot.MessagePasserAddress, nil, number)
+	if err != nil {
+		return nil, fmt.Errorf("fetching message passer proof at l2 block %s: %w", number, err)
+	}
+	output, err := outputroot.FromHeaderAndProof(header, proof)
+	if err != nil {
+		return nil, err
+	}
+	s.BlockHash = output.BlockHash
+	s.StateRoot = output.StateRoot
+	s.MessagePasserStorageHash = output.MessagePasserStorageRoot
+	return &s, nil
+}
+
+func (m *FaultProofDetectionParent) evaluateMocks(bc BlockContext, mocks map[string]any) ([]Violation, error) {
+	var s faultProofDetectionParentSources
+	if err := decodeSources(mocks, &s); err != nil {
+		return nil, err
+	}
+	return m.check(bc, &s), nil
+}
+
+func (m *FaultProofDetectionParent) check(bc BlockContext, s *faultProofDetectionParentSources) []Violation {
+	if len(s.DisputeGameCreatedEvents) == 0 {
+		return nil
+	}
+
+	var violations []Violation
+	computed := outputroot.OutputV0{
+		StateRoot:                s.StateRoot,
+		MessagePasserStorageRoot: s.MessagePasserStorageHash,
+		BlockHash:                s.BlockHash,
+	}.Root()
+	if computed != s.DisputeGameCreatedEvents[0].RootClaim {
+		violations = append(violations, bc.violation(m.Name(), m.DisputeGameFactoryProxy, "Dispute game created with incorrect L2 output proposal"))
+	}
+	if len(s.DisputeGameCreatedEvents) >= 2 {
+		violations = append(violations, bc.violation(m.Name(), m.DisputeGameFactoryProxy, "Only one DisputeGameCreated event should appear in the same block"))
+	}
+	return violations
+}
+
+// FaultProofDetectionChild mirrors fault_proof_detection_child.gate, which is deployed for a game created with an
+// incorrect root claim. It alerts when moves are made in the game without the honest challenger attacking the
+// root claim, or without anyone defending it.
+type FaultProofDetectionChild struct {
+	CBChallenger eth.Address `json:"cbChallenger"`
+	DisputeGame  eth.Address `json:"disputeGame"`
+}
+
+type faultProofDetectionChildSources struct {
+	MoveEvents []moveEvent `source:"moveEvents"`
+	ClaimCount *big.Int    `source:"claimCount"`
+}
+
+// Name implements Monitor.
+func (m *FaultProofDetectionChild) Name() string { return "fault_proof_detection_child" }
+
+// Contract implements Monitor.
+func (m *FaultProofDetectionChild) Contract() eth.Address { return m.DisputeGame }
+
+// Evaluate implements Monitor.
+func (m *FaultProofDetectionChild) Evaluate(ctx context.Context, bc BlockContext) ([]Violation, error) {
+	s, err := m.load(ctx, bc)
+	if s == nil || err != nil {
+		return nil, err
+	}
+	return m.check(bc, s), nil
+}
+
+func (m *FaultProofDetectionChild) sources(ctx context.Context, bc BlockContext) (any, error) {
+	s, err := m.load(ctx, bc)
+	if err != nil {
+		return nil, err
+	}
+	m.check(bc, s)
+	return s, nil
+}
+
+// load reads the sources from the chain. It returns nil sources when the invariants cannot fail in the block,
+// unless every source is requested.
+func (m *FaultProofDetectionChild) load(ctx context.Context, bc BlockContext) (*faultProofDetectionChildSources, error) {
+	var s faultProofDetectionChildSources
+	moves, err := events(ctx, bc, m.DisputeGame, abi.Move)
+	if err != nil {
+		return nil, err
+	}
+	if s.MoveEvents = moveEvents(moves); len(s.MoveEvents) == 0 && !bc.allSources {
+		return nil, nil
+	}
+	if s.ClaimCount, err = callUint(ctx, bc, m.DisputeGame, abi.ClaimDataLen); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (m *FaultProofDetectionChild) evaluateMocks(bc BlockContext, mocks map[string]any) ([]Violation, error) {
+	var s faultProofDetectionChildSources
+	if err := decodeSources(mocks, &s); err != nil {
+		return nil, err
+	}
+	return m.check(bc, &s), nil
+}
+
+func (m *FaultProofDetectionChild) check(bc BlockContext, s *faultProofDetectionChildSources) []Violation {
+	if len(s.MoveEvents) == 0 {
+		return nil
+	}
+
+	var challengeMoves, defenseMoves int
+	for _, move := range s.MoveEvents {
+		if move.Claimant == m.CBChallenger && rangeContains(0, s.ClaimCount, 2, move.ParentIndex) {
+			challengeMoves++
+		}
+		if rangeContains(1, s.ClaimCount, 2, move.ParentIndex) {
+			defenseMoves++
+		}
+	}
+
+	var violations []Violation
+	if challengeMoves == 0 {
+		violations = append(violations, bc.violation(m.Name(), m.DisputeGame, "Attacker is defending the output root"))
+	}
+	if defenseMoves == 0 {
+		violations = append(violations, bc.violation(m.Name(), m.DisputeGame, "CB challenger is challenging the invalid output root submitted"))
+	}
+	return violations
+}
@@ -0,0 +1,142 @@
+package monitor
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+func init() {
+	register(func() Monitor { return new(IncorrectBondBalance) })
+}
+
+// IncorrectBondBalance mirrors incorrect_bond_balance.gate, alerting when the ETH a dispute game holds in
+// DelayedWETH does not match the bonds posted to it or the amounts unlocked from it.
+type IncorrectBondBalance struct {
+	DisputeGame eth.Address `json:"disputeGame"`
+}
+
+// senderUnlock is an `unlock(address _guy, uint256 _wad)` call with its sender.
+type senderUnlock struct {
+	Sender eth.Address
+	Args   addressAmount
+}
+
+type incorrectBondBalanceSources struct {
+	AddressesInTrace      []eth.Address  `source:"addressesInTrace"`
+	DelayedWETH           eth.Address    `source:"delayedWETH"`
+	UnlocksWithSender     []senderUnlock `source:"unlocksWithSender"`
+	UnlockAmounts         []*big.Int     `source:"unlockAmounts,derived"`
+	ClaimData             []abi.Claim    `source:"claimData"`
+	CurrDisputeEthBalance *big.Int       `source:"currDisputeEthBalance"`
+	PastWithdrawalEvents  [][]*big.Int   `source:"pastWithdrawalEvents"`
+	PastWithdrawals       []*big.Int     `source:"pastWithdrawals,derived"`
+}
+
+// Name implements Monitor.
+func (m *IncorrectBondBalance) Name() string { return "incorrect_bond_balance" }
+
+// Contract implements Monitor.
+func (m *IncorrectBondBalance) Contract() eth.Address { return m.DisputeGame }
+
+// Evaluate implements Monitor.
+func (m *IncorrectBondBalance) Evaluate(ctx context.Context, bc BlockContext) ([]Violation, error) {
+	s, err := m.load(ctx, bc)
+	if s == nil || err != nil {
+		return nil, err
+	}
+	return m.check(bc, s), nil
+}
+
+func (m *IncorrectBondBalance) sources(ctx context.Context, bc BlockContext) (any, error) {
+	s, err := m.load(ctx, bc)
+	if err != nil {
+		return nil, err
+	}
+	m.check(bc, s)
+	return s, nil
+}
+
+// load reads the sources from the chain. It returns nil sources when the invariants cannot fail in the block,
+// unless every source is requested.
+func (m *IncorrectBondBalance) load(ctx context.Context, bc BlockContext) (*incorrectBondBalanceSources, error) {
+	s := incorrectBondBalanceSources{AddressesInTrace: addressesInTrace(bc, m.DisputeGame)}
+	if len(s.AddressesInTrace) == 0 && !bc.allSources {
+		return nil, nil
+	}
+
+	var err error
+	if s.DelayedWETH, err = callAddress(ctx, bc, m.DisputeGame, wethMethod); err != nil {
+		return nil, err
+	}
+	unlocks, err := historicalCalls(ctx, bc, s.DelayedWETH, unlockMethod)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range unlocks {
+		s.UnlocksWithSender = append(s.UnlocksWithSender, senderUnlock{Sender: u.From, Args: addressAmounts([]decodedCall{u})[0]})
+	}
+	if _, s.ClaimData, err = claims(ctx, bc, m.DisputeGame); err != nil {
+		return nil, err
+	}
+	if s.CurrDisputeEthBalance, err = callUint(ctx, bc, s.DelayedWETH, balanceOfMethod, m.DisputeGame); err != nil {
+		return nil, err
+	}
+	received, err := historicalEvents(ctx, bc, m.DisputeGame, abi.ReceiveETH)
+	if err != nil {
+		return nil, err
+	}
+	s.PastWithdrawalEvents = [][]*big.Int{}
+	for _, ev := range received {
+		s.PastWithdrawalEvents = append(s.PastWithdrawalEvents, []*big.Int{ev.Values[0].(*big.Int)})
+	}
+	return &s, nil
+}
+
+func (m *IncorrectBondBalance) evaluateMocks(bc BlockContext, mocks map[string]any) ([]Violation, error) {
+	var s incorrectBondBalanceSources
+	if err := decodeSources(mocks, &s); err != nil {
+		return nil, err
+	}
+	return m.check(bc, &s), nil
+}
+
+func (m *IncorrectBondBalance) check(bc BlockContext, s *incorrectBondBalanceSources) []Violation {
+	if len(s.AddressesInTrace) == 0 {
+		return nil
+	}
+	if s.UnlockAmounts == nil {
+		for _, unlock := range s.UnlocksWithSender {
+			if unlock.Sender == m.DisputeGame {
+				s.UnlockAmounts = append(s.UnlockAmounts, unlock.Args.Amount)
+			}
+		}
+	}
+	if s.PastWithdrawals == nil {
+		for _, withdrawal := range s.PastWithdrawalEvents {
+			if len(withdrawal) > 0 {
+				s.PastWithdrawals = append(s.PastWithdrawals, withdrawal[0])
+			}
+		}
+	}
+
+	currentEthUnlocked := sum(s.UnlockAmounts)
+	bonds := make([]*big.Int, len(s.ClaimData))
+	for i, claim := range s.ClaimData {
+		bonds[i] = claim.Bond
+	}
+	totalClaimBonds := sum(bonds)
+	// bonds already withdrawn were sent back to the game, so they still count towards what it held
+	totalDisputeEthBalance := new(big.Int).Add(bigOrZero(s.CurrDisputeEthBalance), sum(s.PastWithdrawals))
+
+	var violations []Violation
+	if currentEthUnlocked.Cmp(totalDisputeEthBalance) > 0 {
+		violations = append(violations, bc.violation(m.Name(), m.DisputeGame, "Dispute Game ETH imbalance detected between total DelayedWETH balance and total unlocks"))
+	}
+	if totalClaimBonds.Cmp(totalDisputeEthBalance) != 0 {
+		violations = append(violations, bc.violation(m.Name(), m.DisputeGame, "Dispute Game ETH imbalance detected between total claim bonds and total DelayedWETH balance"))
+	}
+	return violations
+}
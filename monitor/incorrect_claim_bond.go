@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/game"
+)
+
+func init() {
+	register(func() Monitor { return new(IncorrectClaimBond) })
+}
+
+// IncorrectClaimBond mirrors incorrect_claim_bond.gate, alerting when a claim posted a bond other than the one
+// required for its position. The claims of resolved subgames, whose bond is replaced by game.ClaimedBondFlag, are
+// skipped.
+//
+// The required bonds are computed with game.RequiredBond from the max game depth of the game, so that the check does
+// not rest on the game it checks. They are cross-checked against `getRequiredBond`, which the gate file reads, and the
+// monitor fails to evaluate when they differ, since the gate file would then disagree with it.
+type IncorrectClaimBond struct {
+	DisputeGame eth.Address `json:"disputeGame"`
+}
+
+type incorrectClaimBondSources struct {
+	AddressesInTrace []eth.Address `source:"addressesInTrace"`
+	ClaimCount       *big.Int      `source:"claimCount"`
+	ClaimData        []abi.Claim   `source:"claimData"`
+	RequiredBonds    []*big.Int    `source:"requiredBonds"`
+}
+
+// Name implements Monitor.
+func (m *IncorrectClaimBond) Name() string { return "incorrect_claim_bond" }
+
+// Contract implements Monitor.
+func (m *IncorrectClaimBond) Contract() eth.Address { return m.DisputeGame }
+
+// Evaluate implements Monitor.
+func (m *IncorrectClaimBond) Evaluate(ctx context.Context, bc BlockContext) ([]Violation, error) {
+	s, err := m.load(ctx, bc)
+	if s == nil || err != nil {
+		return nil, err
+	}
+	return m.check(bc, s), nil
+}
+
+func (m *IncorrectClaimBond) sources(ctx context.Context, bc BlockContext) (any, error) {
+	s, err := m.load(ctx, bc)
+	if err != nil {
+		return nil, err
+	}
+	m.check(bc, s)
+	return s, nil
+}
+
+// load reads the sources from the chain. It returns nil sources when the invariants cannot fail in the block,
+// unless every source is requested.
+func (m *IncorrectClaimBond) load(ctx context.Context, bc BlockContext) (*incorrectClaimBondSources, error) {
+	s := incorrectClaimBondSources{AddressesInTrace: addressesInTrace(bc, m.DisputeGame)}
+	if len(s.AddressesInTrace) == 0 && !bc.allSources {
+		return nil, nil
+	}
+
+	var err error
+	if s.ClaimCount, s.ClaimData, err = claims(ctx, bc, m.DisputeGame); err != nil {
+		return nil, err
+	}
+	maxGameDepth, err := callUint(ctx, bc, m.DisputeGame, maxGameDepthMethod)
+	if err != nil {
+		return nil, err
+	}
+	if !maxGameDepth.IsUint64() {
+		return nil, fmt.Errorf("invalid max game depth %s", maxGameDepth)
+	}
+	s.RequiredBonds = make([]*big.Int, len(s.ClaimData))
+	for i, claim := range s.ClaimData {
+		position, err := game.PositionFromGIndex(claim.Position)
+		if err != nil {
+			return nil, fmt.Errorf("claim %d: %w", i, err)
+		}
+		if s.RequiredBonds[i], err = game.RequiredBond(position, maxGameDepth.Uint64()); err != nil {
+			return nil, fmt.Errorf("claim %d: %w", i, err)
+		}
+		charged, err := callUint(ctx, bc, m.DisputeGame, abi.GetRequiredBond, claim.Position)
+		if err != nil {
+			return nil, err
+		}
+		if !bigEqual(charged, s.RequiredBonds[i]) {
+			return nil, fmt.Errorf("claim %d: getRequiredBond(%s) returned %s, computed %s", i, claim.Position, charged, s.RequiredBonds[i])
+		}
+	}
+	return &s, nil
+}
+
+func (m *IncorrectClaimBond) evaluateMocks(bc BlockContext, mocks map[string]any) ([]Violation, error) {
+	var s incorrectClaimBondSources
+	if err := decodeSources(mocks, &s); err != nil {
+		return nil, err
+	}
+	return m.check(bc, &s), nil
+}
+
+func (m *IncorrectClaimBond) check(bc BlockContext, s *incorrectClaimBondSources) []Violation {
+	if len(s.AddressesInTrace) == 0 {
+		return nil
+	}
+	for i, claim := range s.ClaimData {
+		if i >= len(s.RequiredBonds) {
+			break
+		}
+		if bigEqual(claim.Bond, game.ClaimedBondFlag) {
+			continue
+		}
+		if !bigEqual(claim.Bond, s.RequiredBonds[i]) {
+			return []Violation{bc.violation(m.Name(), m.DisputeGame, "Claim bond does not match the required bond for its position")}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+// Package monitor implements each gate file natively in Go so the invariants can be checked against any JSON-RPC
+// endpoint, independently of Hexagate. Every monitor mirrors the sources of its gate file: data is loaded from the
+// chain into a struct whose fields are named after the gate sources, and the invariants are evaluated over that
+// struct. Because the field names match, the mocks in the shared fixtures can be loaded in place of chain data,
+// which is how parity with the gate versions is tested.
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+// ErrCallHistoryUnavailable is returned by histories that cannot look up past calls, which requires traces of
+// every block since the game was created.
+var ErrCallHistoryUnavailable = errors.New("call history unavailable")
+
+// Monitor evaluates the invariants of one gate file at a block.
+type Monitor interface {
+	// Name is the name of the gate file the monitor mirrors, without the extension.
+	Name() string
+	// Contract is the contract the monitor is deployed for, which its violations are reported against.
+	Contract() eth.Address
+	// Evaluate returns the invariants violated at the block. An error means the invariants could not be checked.
+	Evaluate(ctx context.Context, bc BlockContext) ([]Violation, error)
+}
+
+// Violation is a failed invariant.
+type Violation struct {
+	Monitor string `json:"monitor"`
+	// Description is the description of the failed invariant in the gate file.
+	Description string `json:"description"`
+	// Contract is the contract the monitor was deployed for, such as the dispute game.
+	Contract    eth.Address `json:"contract"`
+	BlockNumber uint64      `json:"blockNumber"`
+	BlockHash   eth.Hash    `json:"blockHash"`
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s at block %d: %s", v.Monitor, v.BlockNumber, v.Description)
+}
+
+// Chain reads chain state. It is implemented by *rpc.Client.
+type Chain interface {
+	CallContract(ctx context.Context, msg rpc.CallMsg, number rpc.BlockNumber) ([]byte, error)
+	Logs(ctx context.Context, query rpc.FilterQuery) ([]eth.Log, error)
+	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*eth.Header, error)
+	Proof(ctx context.Context, account eth.Address, keys []eth.Hash, number rpc.BlockNumber) (*eth.AccountProof, error)
+}
+
+// Call is a call between contracts, flattened from the trace of a block.
+type Call struct {
+	BlockNumber uint64
+	From        eth.Address
+	To          eth.Address
+	Input       eth.Bytes
+	Value       *big.Int
+}
+
+// History looks up events and calls in past blocks, as the gate language's HistoricalEvents and HistoricalCalls do.
+type History interface {
+	// Logs returns the logs emitted by address with the given first topic, up to and including block toBlock.
+	Logs(ctx context.Context, address eth.Address, topic eth.Hash, toBlock uint64) ([]eth.Log, error)
+	// Calls returns the successful calls to address starting with selector, up to and including block toBlock.
+	Calls(ctx context.Context, address eth.Address, selector []byte, toBlock uint64) ([]Call, error)
+}
+
+// BlockContext is the block a monitor is evaluated at.
+type BlockContext struct {
+	Number    uint64
+	Hash      eth.Hash
+	Timestamp uint64
+
+	// Chain reads the state of the chain the monitors are deployed on. Calls are made at Number.
+	Chain Chain
+	// Calls are the calls made in the block, flattened from its trace.
+	Calls []Call
+	// History looks up events and calls up to the block.
+	History History
+	// L2 reads the state of the L2 chains whose outputs are proposed, keyed by chain ID.
+	L2 map[uint64]Chain
+
+	// allSources disables the shortcuts monitors take when the invariants cannot fail in the block, so that every
+	// source is loaded. It is set by Sources.
+	allSources bool
+}
+
+// NewBlockContext returns the context of block number, fetching its header from chain. Calls, History and L2 are
+// left for the caller to fill in.
+func NewBlockContext(ctx context.Context, chain Chain, number rpc.BlockNumber) (BlockContext, error) {
+	header, err := chain.HeaderByNumber(ctx, number)
+	if err != nil {
+		return BlockContext{}, fmt.Errorf("fetching block %s: %w", number, err)
+	}
+	return BlockContext{
+		Number:    uint64(header.Number),
+		Hash:      header.Hash,
+		Timestamp: uint64(header.Timestamp),
+		Chain:     chain,
+	}, nil
+}
+
+func (bc BlockContext) violation(name string, contract eth.Address, description string) Violation {
+	return Violation{
+		Monitor:     name,
+		Description: description,
+		Contract:    contract,
+		BlockNumber: bc.Number,
+		BlockHash:   bc.Hash,
+	}
+}
+
+// LogHistory is a History backed only by `eth_getLogs`. It cannot look up past calls.
+type LogHistory struct {
+	Chain Chain
+	// FromBlock is the first block searched, e.g. the deployment block of the DisputeGameFactory.
+	FromBlock uint64
+}
+
+// Logs implements History.
+func (h LogHistory) Logs(ctx context.Context, address eth.Address, topic eth.Hash, toBlock uint64) ([]eth.Log, error) {
+	return h.Chain.Logs(ctx, rpc.FilterQuery{
+		FromBlock: rpc.NumberAt(h.FromBlock),
+		ToBlock:   rpc.NumberAt(toBlock),
+		Addresses: []eth.Address{address},
+		Topics:    [][]eth.Hash{{topic}},
+	})
+}
+
+// Calls implements History.
+func (h LogHistory) Calls(context.Context, eth.Address, []byte, uint64) ([]Call, error) {
+	return nil, ErrCallHistoryUnavailable
+}
+
+var registry = map[string]func() Monitor{}
+
+// register adds a monitor to the registry under its name. It is called from the init function of each monitor.
+func register(factory func() Monitor) {
+	registry[factory().Name()] = factory
+}
+
+// Names returns the names of every native monitor.
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New returns the monitor for the gate file name, configured with the same params the gate file takes.
+func New(name string, params map[string]any) (Monitor, error) {
+	factory, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown monitor %q", name)
+	}
+	m := factory()
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("encoding params for %s: %w", name, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(encoded))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(m); err != nil {
+		return nil, fmt.Errorf("invalid params for %s: %w", name, err)
+	}
+	return m, nil
+}
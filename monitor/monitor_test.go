@@ -0,0 +1,355 @@
+package monitor_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/fixtures"
+	"github.com/base-org/fault-proof-monitors/game"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/networks"
+	"github.com/base-org/fault-proof-monitors/rpc"
+	"github.com/base-org/fault-proof-monitors/rpc/rpctest"
+)
+
+// matrix are the networks the fixtures are run on: the built-in profiles, and the test profile whose games, bonds and game
+// type differ from all of them.
+func matrix(t *testing.T) map[string]networks.Profile {
+	profiles := make(map[string]networks.Profile)
+	for _, name := range append(networks.Names(), networks.Test) {
+		p, err := networks.Lookup(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		profiles[name] = p
+	}
+	return profiles
+}
+
+func TestFixtureParity(t *testing.T) {
+	all, err := fixtures.All()
+	if err != nil {
+		t.Fatalf("Error loading fixtures: %v", err)
+	}
+	profiles := matrix(t)
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, network := range names {
+		profile := profiles[network]
+		for _, f := range all {
+			f, err := f.Substitute(profile.Vars())
+			if err != nil {
+				t.Fatalf("%s/%s: %v", f.Monitor, f.Name, err)
+			}
+			t.Run(network+"/"+f.Monitor+"/"+f.Name, func(t *testing.T) { checkFixture(t, f) })
+		}
+	}
+}
+
+func checkFixture(t *testing.T, f fixtures.Fixture) {
+	t.Helper()
+	m, err := monitor.New(f.Monitor, f.Params)
+	if err != nil {
+		t.Fatalf("Error creating monitor: %v", err)
+	}
+	violations, err := monitor.EvaluateMocks(m, monitor.BlockContext{Number: 100}, f.Mocks)
+	if err != nil {
+		t.Fatalf("Error evaluating mocks: %v", err)
+	}
+	if alert := len(violations) > 0; alert != f.ExpectAlert {
+		t.Errorf("%s: expected alert %t, got violations %v", f.Description, f.ExpectAlert, violations)
+	}
+	if f.Alerts == nil {
+		return
+	}
+	descriptions := make([]string, len(violations))
+	for i, v := range violations {
+		descriptions[i] = v.Description
+	}
+	if !slices.Equal(descriptions, f.Alerts) {
+		t.Errorf("%s: expected alerts %q, got %q", f.Description, f.Alerts, descriptions)
+	}
+}
+
+func TestEveryGateHasNativeMonitor(t *testing.T) {
+	gates, err := filepath.Glob("../monitors/*.gate")
+	if err != nil {
+		t.Fatalf("Error listing gate files: %v", err)
+	}
+	names := map[string]bool{}
+	for _, name := range monitor.Names() {
+		names[name] = true
+	}
+	for _, gate := range gates {
+		name := strings.TrimSuffix(filepath.Base(gate), ".gate")
+		if !names[name] {
+			t.Errorf("gate %s has no native monitor", name)
+		}
+		if f, err := fixtures.ForMonitor(name); err != nil || len(f) == 0 {
+			t.Errorf("gate %s has no fixtures (%v)", name, err)
+		}
+	}
+}
+
+func TestNewRejectsUnknownParams(t *testing.T) {
+	_, err := monitor.New("eth_deficit", map[string]any{"disputeGame": "0x00000000000000000000000000000000000000BB", "typo": 1})
+	if err == nil {
+		t.Errorf("expected unknown param to be rejected")
+	}
+	if _, err := monitor.New("not_a_monitor", nil); err == nil {
+		t.Errorf("expected unknown monitor to be rejected")
+	}
+}
+
+// gameServer serves the dispute game g at address, answering eth_call by selector.
+func gameServer(t *testing.T, address eth.Address, g *game.Game, resolvedAt uint64) *rpctest.Server {
+	maxClockDuration := abi.MustParseMethod("maxClockDuration() returns (uint256)")
+	resolvedAtMethod := abi.MustParseMethod("resolvedAt() returns (uint256)")
+
+	server := rpctest.NewServer()
+	server.Handle("eth_call", func(params json.RawMessage) (any, error) {
+		var args []json.RawMessage
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		var msg rpc.CallMsg
+		if err := json.Unmarshal(args[0], &msg); err != nil {
+			return nil, err
+		}
+		if msg.To != address {
+			return nil, errors.New("unexpected target " + msg.To.String())
+		}
+		selector := msg.Data[:4]
+		var output []byte
+		var err error
+		switch {
+		case bytes.Equal(selector, maxClockDuration.Selector()):
+			output, err = maxClockDuration.PackOutputs(new(big.Int).SetUint64(g.Config.MaxClockDuration))
+		case bytes.Equal(selector, resolvedAtMethod.Selector()):
+			output, err = resolvedAtMethod.PackOutputs(new(big.Int).SetUint64(resolvedAt))
+		case bytes.Equal(selector, abi.ClaimDataLen.Selector()):
+			output, err = abi.ClaimDataLen.PackOutputs(big.NewInt(int64(len(g.Claims))))
+		case bytes.Equal(selector, abi.ClaimData.Selector()):
+			values, err := abi.ClaimData.UnpackInputs(msg.Data)
+			if err != nil {
+				return nil, err
+			}
+			claim := g.Claims[values[0].(*big.Int).Int64()]
+			output, err = abi.NewClaim(claim, eth.Address{}, big.NewInt(1), eth.Hash{}).Encode()
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, &rpc.Error{Code: 3, Message: "execution reverted"}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return eth.Bytes(output), nil
+	})
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestUnresolvableDisputeGameEvaluate(t *testing.T) {
+	cfg := game.Config{MaxClockDuration: 302400, ClockExtension: 10800, SplitDepth: 30, MaxGameDepth: 73, OracleChallengePeriod: 86400}
+	g := game.NewGame(cfg, 555555)
+	if _, err := g.Move(0, true, 555655); err != nil {
+		t.Fatalf("Error attacking root claim: %v", err)
+	}
+	disputeGame := eth.MustAddress("0x00000000000000000000000000000000000000BB")
+
+	m, err := monitor.New("unresolvable_dispute_game", map[string]any{
+		"disputeGame":        disputeGame.String(),
+		"extraTimeInSeconds": 3600,
+	})
+	if err != nil {
+		t.Fatalf("Error creating monitor: %v", err)
+	}
+
+	cases := []struct {
+		resolvedAt uint64
+		timestamp  uint64
+		alert      bool
+	}{
+		{resolvedAt: 0, timestamp: g.ResolvableAt() + 3600, alert: false},
+		{resolvedAt: 0, timestamp: g.ResolvableAt() + 3601, alert: true},
+		{resolvedAt: g.ResolvableAt() + 10, timestamp: g.ResolvableAt() + 3601, alert: false},
+	}
+	for _, c := range cases {
+		server := gameServer(t, disputeGame, g, c.resolvedAt)
+		bc := monitor.BlockContext{Number: 100, Timestamp: c.timestamp, Chain: rpc.NewClient(server.URL)}
+		violations, err := m.Evaluate(context.Background(), bc)
+		if err != nil {
+			t.Fatalf("Error evaluating monitor: %v", err)
+		}
+		if alert := len(violations) > 0; alert != c.alert {
+			t.Errorf("resolvedAt %d at %d: expected alert %t, got %v", c.resolvedAt, c.timestamp, c.alert, violations)
+			continue
+		}
+		if c.alert && (violations[0].Description != "Dispute game is unresolved" || violations[0].Contract != disputeGame || violations[0].BlockNumber != 100) {
+			t.Errorf("unexpected violation %+v", violations[0])
+		}
+
+		// the sources loaded for the validate API must give the same result when evaluated as mocks
+		mocks, err := monitor.Sources(context.Background(), m, bc)
+		if err != nil {
+			t.Fatalf("Error loading sources: %v", err)
+		}
+		if len(mocks) != 5 {
+			t.Errorf("expected every source to be loaded, got %v", mocks)
+		}
+		encoded, err := json.Marshal(mocks)
+		if err != nil {
+			t.Fatalf("Error encoding sources: %v", err)
+		}
+		dec := json.NewDecoder(bytes.NewReader(encoded))
+		dec.UseNumber()
+		var decoded map[string]any
+		if err := dec.Decode(&decoded); err != nil {
+			t.Fatalf("Error decoding sources: %v", err)
+		}
+		mocked, err := monitor.EvaluateMocks(m, bc, decoded)
+		if err != nil {
+			t.Fatalf("Error evaluating sources: %v", err)
+		}
+		if len(mocked) != len(violations) {
+			t.Errorf("resolvedAt %d at %d: sources gave %v, chain gave %v", c.resolvedAt, c.timestamp, mocked, violations)
+		}
+	}
+}
+
+// bondServer serves a dispute game at address holding the claims of g, which posted the given bonds, and whose
+// getRequiredBond charges the bond computed by the game package plus surcharge.
+func bondServer(t *testing.T, address eth.Address, g *game.Game, bonds []*big.Int, surcharge int64) *rpctest.Server {
+	maxGameDepth := abi.MustParseMethod("maxGameDepth() returns (uint256)")
+	server := rpctest.NewServer()
+	server.Handle("eth_call", func(params json.RawMessage) (any, error) {
+		var args []json.RawMessage
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		var msg rpc.CallMsg
+		if err := json.Unmarshal(args[0], &msg); err != nil {
+			return nil, err
+		}
+		if msg.To != address {
+			return nil, errors.New("unexpected target " + msg.To.String())
+		}
+		selector := msg.Data[:4]
+		var output []byte
+		var err error
+		switch {
+		case bytes.Equal(selector, maxGameDepth.Selector()):
+			output, err = maxGameDepth.PackOutputs(new(big.Int).SetUint64(g.Config.MaxGameDepth))
+		case bytes.Equal(selector, abi.ClaimDataLen.Selector()):
+			output, err = abi.ClaimDataLen.PackOutputs(big.NewInt(int64(len(g.Claims))))
+		case bytes.Equal(selector, abi.ClaimData.Selector()):
+			values, err := abi.ClaimData.UnpackInputs(msg.Data)
+			if err != nil {
+				return nil, err
+			}
+			i := values[0].(*big.Int).Int64()
+			if output, err = abi.NewClaim(g.Claims[i], eth.Address{}, bonds[i], eth.Hash{}).Encode(); err != nil {
+				return nil, err
+			}
+		case bytes.Equal(selector, abi.GetRequiredBond.Selector()):
+			values, err := abi.GetRequiredBond.UnpackInputs(msg.Data)
+			if err != nil {
+				return nil, err
+			}
+			position, err := game.PositionFromGIndex(values[0].(*big.Int))
+			if err != nil {
+				return nil, err
+			}
+			bond, err := game.RequiredBond(position, g.Config.MaxGameDepth)
+			if err != nil {
+				return nil, err
+			}
+			output, err = abi.GetRequiredBond.PackOutputs(bond.Add(bond, big.NewInt(surcharge)))
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, &rpc.Error{Code: 3, Message: "execution reverted"}
+		}
+		if err != nil {
+			return nil, err
+		}
+		return eth.Bytes(output), nil
+	})
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestIncorrectClaimBondEvaluate(t *testing.T) {
+	cfg := game.Config{MaxClockDuration: 302400, ClockExtension: 10800, SplitDepth: 30, MaxGameDepth: 73}
+	g := game.NewGame(cfg, 555555)
+	if _, err := g.Move(0, true, 555655); err != nil {
+		t.Fatalf("Error attacking root claim: %v", err)
+	}
+	if _, err := g.Move(1, true, 555755); err != nil {
+		t.Fatalf("Error attacking claim: %v", err)
+	}
+	required := make([]*big.Int, len(g.Claims))
+	for i, claim := range g.Claims {
+		bond, err := game.RequiredBond(claim.Position, cfg.MaxGameDepth)
+		if err != nil {
+			t.Fatalf("Error computing bond: %v", err)
+		}
+		required[i] = bond
+	}
+	disputeGame := eth.MustAddress("0x00000000000000000000000000000000000000BB")
+	m, err := monitor.New("incorrect_claim_bond", map[string]any{"disputeGame": disputeGame.String()})
+	if err != nil {
+		t.Fatalf("Error creating monitor: %v", err)
+	}
+
+	underpaid := slices.Clone(required)
+	underpaid[2] = new(big.Int).Sub(required[2], big.NewInt(1))
+	cases := []struct {
+		name      string
+		bonds     []*big.Int
+		surcharge int64
+		alert     bool
+		fails     bool
+	}{
+		{name: "required bonds", bonds: required},
+		{name: "underpaid claim", bonds: underpaid, alert: true},
+		// the game charges other bonds than the ones computed, which the gate file would follow
+		{name: "bonds differ on chain", bonds: required, surcharge: 1, fails: true},
+	}
+	for _, c := range cases {
+		server := bondServer(t, disputeGame, g, c.bonds, c.surcharge)
+		bc := monitor.BlockContext{
+			Number: 100,
+			Chain:  rpc.NewClient(server.URL),
+			Calls:  []monitor.Call{{BlockNumber: 100, To: disputeGame}},
+		}
+		violations, err := m.Evaluate(context.Background(), bc)
+		if c.fails {
+			if err == nil || !strings.Contains(err.Error(), "getRequiredBond") {
+				t.Errorf("%s: expected the cross-check to fail, got %v, %v", c.name, violations, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: Error evaluating monitor: %v", c.name, err)
+		}
+		if alert := len(violations) > 0; alert != c.alert {
+			t.Errorf("%s: expected alert %t, got %v", c.name, c.alert, violations)
+		}
+	}
+}
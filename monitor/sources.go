@@ -0,0 +1,267 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// mockable is implemented by every monitor. It evaluates the invariants over gate source values, such as the mocks
+// of a fixture, instead of loading them from the chain. Sources missing from mocks are left empty.
+type mockable interface {
+	Monitor
+	evaluateMocks(bc BlockContext, mocks map[string]any) ([]Violation, error)
+}
+
+// EvaluateMocks evaluates m over the gate source values in mocks, keyed by source name as in the validate API.
+func EvaluateMocks(m Monitor, bc BlockContext, mocks map[string]any) ([]Violation, error) {
+	mm, ok := m.(mockable)
+	if !ok {
+		return nil, fmt.Errorf("monitor %s cannot be evaluated over mocks", m.Name())
+	}
+	return mm.evaluateMocks(bc, mocks)
+}
+
+// sourcer is implemented by every monitor. It loads every gate source from the chain, including the ones the
+// monitor would skip because the invariants cannot fail in the block, and fills in the derived ones.
+type sourcer interface {
+	Monitor
+	sources(ctx context.Context, bc BlockContext) (any, error)
+}
+
+// Sources loads the gate sources of m at the block from the chain, keyed by source name in the format of the
+// validate API's mocks. Sources the gate file derives from other sources are left out, so the gate computes them
+// itself. Evaluating the result with EvaluateMocks gives the same violations as Evaluate.
+func Sources(ctx context.Context, m Monitor, bc BlockContext) (map[string]any, error) {
+	sm, ok := m.(sourcer)
+	if !ok {
+		return nil, fmt.Errorf("monitor %s cannot load its sources", m.Name())
+	}
+	bc.allSources = true
+	s, err := sm.sources(ctx, bc)
+	if err != nil {
+		return nil, err
+	}
+	return encodeSources(s), nil
+}
+
+var (
+	bigType     = reflect.TypeOf((*big.Int)(nil))
+	addressType = reflect.TypeOf(eth.Address{})
+	hashType    = reflect.TypeOf(eth.Hash{})
+)
+
+// decodeSources sets the fields of the struct pointed to by dst from the values in mocks, matching each field's
+// `source` tag against the gate source name. Tuples are decoded positionally into structs. Sources tagged
+// `derived` are decoded too, since fixtures may mock them.
+func decodeSources(mocks map[string]any, dst any) error {
+	rv := reflect.ValueOf(dst).Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		name, _, _ := strings.Cut(rt.Field(i).Tag.Get("source"), ",")
+		value, ok := mocks[name]
+		if name == "" || !ok {
+			continue
+		}
+		if err := decodeSource(value, rv.Field(i)); err != nil {
+			return fmt.Errorf("source %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func decodeSource(value any, rv reflect.Value) error {
+	switch rv.Type() {
+	case bigType:
+		n, err := toBig(value)
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(n))
+		return nil
+	case addressType:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected address, got %v", value)
+		}
+		addr, err := eth.ParseAddress(s)
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(addr))
+		return nil
+	case hashType:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected bytes32, got %v", value)
+		}
+		hash, err := eth.ParseHash(s)
+		if err != nil {
+			return err
+		}
+		rv.Set(reflect.ValueOf(hash))
+		return nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(rv.Type().Elem())
+		if err := decodeSource(value, elem.Elem()); err != nil {
+			return err
+		}
+		rv.Set(elem)
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected boolean, got %v", value)
+		}
+		rv.SetBool(b)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := toBig(value)
+		if err != nil {
+			return err
+		}
+		if n.Sign() < 0 || n.BitLen() > rv.Type().Bits() {
+			return fmt.Errorf("%s out of range for %s", n, rv.Type())
+		}
+		rv.SetUint(n.Uint64())
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			s, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("expected bytes, got %v", value)
+			}
+			b, err := eth.DecodeHex(s)
+			if err != nil {
+				return err
+			}
+			rv.SetBytes(b)
+			return nil
+		}
+		list, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("expected list, got %v", value)
+		}
+		slice := reflect.MakeSlice(rv.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := decodeSource(item, slice.Index(i)); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		rv.Set(slice)
+	case reflect.Struct:
+		list, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("expected tuple, got %v", value)
+		}
+		if len(list) != rv.NumField() {
+			return fmt.Errorf("expected tuple of %d items, got %d", rv.NumField(), len(list))
+		}
+		for i, item := range list {
+			if err := decodeSource(item, rv.Field(i)); err != nil {
+				return fmt.Errorf("tuple item %d: %w", i, err)
+			}
+		}
+	case reflect.Interface:
+		rv.Set(reflect.ValueOf(value))
+	default:
+		return fmt.Errorf("unsupported source type %s", rv.Type())
+	}
+	return nil
+}
+
+// encodeSources is the reverse of decodeSources. Unset sources and those tagged `derived` are left out.
+func encodeSources(src any) map[string]any {
+	rv := reflect.ValueOf(src).Elem()
+	rt := rv.Type()
+	mocks := make(map[string]any)
+	for i := 0; i < rt.NumField(); i++ {
+		name, opts, _ := strings.Cut(rt.Field(i).Tag.Get("source"), ",")
+		field := rv.Field(i)
+		if name == "" || opts == "derived" || (field.Kind() == reflect.Pointer && field.IsNil()) {
+			continue
+		}
+		mocks[name] = encodeSource(field)
+	}
+	return mocks
+}
+
+func encodeSource(rv reflect.Value) any {
+	switch v := rv.Interface().(type) {
+	case *big.Int:
+		return json.Number(bigOrZero(v).String())
+	case eth.Address:
+		return v.String()
+	case eth.Hash:
+		return v.String()
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return nil
+		}
+		return encodeSource(rv.Elem())
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return json.Number(strconv.FormatUint(rv.Uint(), 10))
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return eth.EncodeHex(rv.Bytes())
+		}
+		list := make([]any, rv.Len())
+		for i := range list {
+			list[i] = encodeSource(rv.Index(i))
+		}
+		return list
+	case reflect.Struct:
+		tuple := make([]any, rv.NumField())
+		for i := range tuple {
+			tuple[i] = encodeSource(rv.Field(i))
+		}
+		return tuple
+	}
+	return rv.Interface()
+}
+
+// toBig converts a gate integer, which may be a JSON number or a decimal or hex string.
+func toBig(value any) (*big.Int, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return parseBig(string(v))
+	case string:
+		return parseBig(v)
+	case float64:
+		n, accuracy := big.NewFloat(v).Int(nil)
+		if accuracy != big.Exact {
+			return nil, fmt.Errorf("expected integer, got %v", v)
+		}
+		return n, nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	}
+	return nil, fmt.Errorf("expected integer, got %v", value)
+}
+
+func parseBig(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		if f, _, err := big.ParseFloat(s, 10, 256, big.ToNearestEven); err == nil && f.IsInt() {
+			n, _ = f.Int(nil)
+			return n, nil
+		}
+		return nil, fmt.Errorf("invalid integer %q", s)
+	}
+	return n, nil
+}
@@ -0,0 +1,113 @@
+package monitor
+
+import (
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// Methods called by the monitors that are not part of the fault proof bindings in the abi package.
+var (
+	wethMethod                 = abi.MustParseMethod("weth() returns (address)")
+	delayMethod                = abi.MustParseMethod("delay() returns (uint256)")
+	balanceOfMethod            = abi.MustParseMethod("balanceOf(address) returns (uint256)")
+	creditMethod               = abi.MustParseMethod("credit(address recipient) view returns (uint256)")
+	claimCreditMethod          = abi.MustParseMethod("claimCredit(address _recipient)")
+	unlockMethod               = abi.MustParseMethod("unlock(address _guy, uint256 _wad)")
+	withdrawMethod             = abi.MustParseMethod("withdraw(address _guy, uint256 _wad)")
+	hasUnlockedCreditMethod    = abi.MustParseMethod("hasUnlockedCredit(address) view returns (bool)")
+	normalModeCreditMethod     = abi.MustParseMethod("normalModeCredit(address) returns (uint256)")
+	refundModeCreditMethod     = abi.MustParseMethod("refundModeCredit(address) returns (uint256)")
+	bondDistributionModeMethod = abi.MustParseMethod("bondDistributionMode() returns (uint8)")
+	maxClockDurationMethod     = abi.MustParseMethod("maxClockDuration() returns (uint256)")
+	resolvedAtMethod           = abi.MustParseMethod("resolvedAt() returns (uint256)")
+	maxGameDepthMethod         = abi.MustParseMethod("maxGameDepth() returns (uint256 maxGameDepth_)")
+	l2BlockNumberMethod        = abi.MustParseMethod("l2BlockNumber() public pure returns (uint256 l2BlockNumber_)")
+	extraDataMethod            = abi.MustParseMethod("extraData() returns (bytes extraData_)")
+	disputeGameFactoryMethod   = abi.MustParseMethod("disputeGameFactory() returns (address)")
+	respectedGameTypeMethod    = abi.MustParseMethod("respectedGameType() returns (uint32)")
+)
+
+// moveEvent is a `Move(uint256 indexed parentIndex, bytes32 indexed claim, address indexed claimant)` event.
+type moveEvent struct {
+	ParentIndex *big.Int
+	Claim       eth.Hash
+	Claimant    eth.Address
+}
+
+func moveEvents(evs []event) []moveEvent {
+	moves := make([]moveEvent, len(evs))
+	for i, ev := range evs {
+		moves[i] = moveEvent{
+			ParentIndex: ev.Values[0].(*big.Int),
+			Claim:       eth.BytesToHash(ev.Values[1].([]byte)),
+			Claimant:    ev.Values[2].(eth.Address),
+		}
+	}
+	return moves
+}
+
+// addressAmount is a call or tuple of a recipient and an amount of ETH, such as `unlock(address _guy, uint256 _wad)`.
+type addressAmount struct {
+	Address eth.Address
+	Amount  *big.Int
+}
+
+func (a addressAmount) equal(b addressAmount) bool {
+	return a.Address == b.Address && bigEqual(a.Amount, b.Amount)
+}
+
+func addressAmounts(cs []decodedCall) []addressAmount {
+	amounts := make([]addressAmount, len(cs))
+	for i, c := range cs {
+		amounts[i] = addressAmount{Address: c.Args[0].(eth.Address), Amount: c.Args[1].(*big.Int)}
+	}
+	return amounts
+}
+
+// rangeContains mirrors `Contains { sequence: Range { start, stop, step }, item: x }`.
+func rangeContains(start int64, stop *big.Int, step int64, x *big.Int) bool {
+	if x == nil || stop == nil || x.Cmp(big.NewInt(start)) < 0 || x.Cmp(stop) >= 0 {
+		return false
+	}
+	offset := new(big.Int).Sub(x, big.NewInt(start))
+	return offset.Mod(offset, big.NewInt(step)).Sign() == 0
+}
+
+func bigEqual(a, b *big.Int) bool {
+	return bigOrZero(a).Cmp(bigOrZero(b)) == 0
+}
+
+func bigOrZero(n *big.Int) *big.Int {
+	if n == nil {
+		return new(big.Int)
+	}
+	return n
+}
+
+func sum(values []*big.Int) *big.Int {
+	total := new(big.Int)
+	for _, v := range values {
+		total.Add(total, bigOrZero(v))
+	}
+	return total
+}
+
+func containsBool(values []bool, item bool) bool {
+	for _, v := range values {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAddress(values []eth.Address, item eth.Address) bool {
+	for _, v := range values {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/game"
+)
+
+func init() {
+	register(func() Monitor { return new(UnresolvableDisputeGame) })
+}
+
+// UnresolvableDisputeGame mirrors unresolvable_dispute_game.gate, alerting when a dispute game is still unresolved
+// after every clock in it has expired and the resolution grace period has passed.
+type UnresolvableDisputeGame struct {
+	DisputeGame        eth.Address `json:"disputeGame"`
+	ExtraTimeInSeconds uint64      `json:"extraTimeInSeconds"`
+}
+
+type unresolvableDisputeGameSources struct {
+	GameDuration     *big.Int    `source:"gameDuration"`
+	ResolvedAt       *big.Int    `source:"resolvedAt"`
+	ClaimCount       *big.Int    `source:"claimCount"`
+	ClaimData        []abi.Claim `source:"claimData"`
+	CurrentTimestamp *big.Int    `source:"currentTimestamp"`
+}
+
+// Name implements Monitor.
+func (m *UnresolvableDisputeGame) Name() string { return "unresolvable_dispute_game" }
+
+// Contract implements Monitor.
+func (m *UnresolvableDisputeGame) Contract() eth.Address { return m.DisputeGame }
+
+// Evaluate implements Monitor.
+func (m *UnresolvableDisputeGame) Evaluate(ctx context.Context, bc BlockContext) ([]Violation, error) {
+	s, err := m.load(ctx, bc)
+	if s == nil || err != nil {
+		return nil, err
+	}
+	return m.check(bc, s)
+}
+
+func (m *UnresolvableDisputeGame) sources(ctx context.Context, bc BlockContext) (any, error) {
+	s, err := m.load(ctx, bc)
+	if err != nil {
+		return nil, err
+	}
+	_, err = m.check(bc, s)
+	return s, err
+}
+
+// load reads the sources from the chain. It returns nil sources when the invariants cannot fail in the block,
+// unless every source is requested.
+func (m *UnresolvableDisputeGame) load(ctx context.Context, bc BlockContext) (*unresolvableDisputeGameSources, error) {
+	var s unresolvableDisputeGameSources
+	var err error
+	if s.ResolvedAt, err = callUint(ctx, bc, m.DisputeGame, resolvedAtMethod); err != nil {
+		return nil, err
+	}
+	if s.ResolvedAt.Sign() != 0 && !bc.allSources {
+		return nil, nil
+	}
+	if s.GameDuration, err = callUint(ctx, bc, m.DisputeGame, maxClockDurationMethod); err != nil {
+		return nil, err
+	}
+	if s.ClaimCount, s.ClaimData, err = claims(ctx, bc, m.DisputeGame); err != nil {
+		return nil, err
+	}
+	s.CurrentTimestamp = new(big.Int).SetUint64(bc.Timestamp)
+	return &s, nil
+}
+
+func (m *UnresolvableDisputeGame) evaluateMocks(bc BlockContext, mocks map[string]any) ([]Violation, error) {
+	var s unresolvableDisputeGameSources
+	if err := decodeSources(mocks, &s); err != nil {
+		return nil, err
+	}
+	return m.check(bc, &s)
+}
+
+func (m *UnresolvableDisputeGame) check(bc BlockContext, s *unresolvableDisputeGameSources) ([]Violation, error) {
+	if bigOrZero(s.ResolvedAt).Sign() != 0 || len(s.ClaimData) == 0 {
+		return nil, nil
+	}
+	if !bigOrZero(s.GameDuration).IsUint64() {
+		return nil, fmt.Errorf("max clock duration %s out of range", s.GameDuration)
+	}
+
+	// the game can be resolved once every clock in it has expired, as computed by the clock model
+	g := &game.Game{Config: game.Config{MaxClockDuration: s.GameDuration.Uint64()}}
+	for i, claim := range s.ClaimData {
+		c, err := claim.GameClaim()
+		if err != nil {
+			return nil, fmt.Errorf("claim %d: %w", i, err)
+		}
+		if c.ParentIndex != game.RootParentIndex && int(c.ParentIndex) >= i {
+			return nil, fmt.Errorf("claim %d: invalid parent index %d", i, c.ParentIndex)
+		}
+		g.Claims = append(g.Claims, c)
+	}
+	expected := new(big.Int).SetUint64(g.ResolvableAt())
+	expected.Add(expected, new(big.Int).SetUint64(m.ExtraTimeInSeconds))
+
+	if bigOrZero(s.CurrentTimestamp).Cmp(expected) > 0 {
+		return []Violation{bc.violation(m.Name(), m.DisputeGame, "Dispute game is unresolved")}, nil
+	}
+	return nil, nil
+}
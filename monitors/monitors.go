@@ -0,0 +1,89 @@
+// Package monitors embeds the gate files, so tools that deploy or validate them do not depend on the working
+// directory.
+package monitors
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//go:embed *.gate
+var files embed.FS
+
+// Names returns the name of every gate file, without the extension.
+func Names() []string {
+	paths, _ := fs.Glob(files, "*.gate")
+	names := make([]string, len(paths))
+	for i, p := range paths {
+		names[i] = strings.TrimSuffix(p, ".gate")
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Gate returns the source of the gate file name, without the extension.
+func Gate(name string) (string, error) {
+	data, err := files.ReadFile(name + ".gate")
+	if err != nil {
+		return "", fmt.Errorf("unknown gate file %q", name)
+	}
+	return string(data), nil
+}
+
+var (
+	paramPattern       = regexp.MustCompile(`(?m)^\s*param\s+(\w+)\s*:`)
+	descriptionPattern = regexp.MustCompile(`description\s*:\s*"((?:[^"\\]|\\.)*)"`)
+)
+
+// Params returns the names of the params declared by the gate file name, in order of declaration.
+func Params(name string) ([]string, error) {
+	gate, err := Gate(name)
+	if err != nil {
+		return nil, err
+	}
+	return DeclaredParams(gate), nil
+}
+
+// DeclaredParams returns the names of the params declared by the source of a gate file, in order of declaration.
+func DeclaredParams(gate string) []string {
+	var params []string
+	for _, m := range paramPattern.FindAllStringSubmatch(gate, -1) {
+		params = append(params, m[1])
+	}
+	return params
+}
+
+// Invariants returns the descriptions of the invariants of the gate file name, in order of declaration. They are the
+// descriptions Hexagate reports the failed invariants of an alert with.
+func Invariants(name string) ([]string, error) {
+	gate, err := Gate(name)
+	if err != nil {
+		return nil, err
+	}
+	var descriptions []string
+	for _, m := range descriptionPattern.FindAllStringSubmatch(gate, -1) {
+		descriptions = append(descriptions, m[1])
+	}
+	return descriptions, nil
+}
+
+// CheckParams checks that params sets exactly the params declared by the gate file name.
+func CheckParams(name string, params map[string]any) error {
+	declared, err := Params(name)
+	if err != nil {
+		return err
+	}
+	for _, p := range declared {
+		if _, ok := params[p]; !ok {
+			return fmt.Errorf("missing param %s", p)
+		}
+	}
+	if len(declared) != len(params) {
+		return fmt.Errorf("sets %d params, the gate file declares %d", len(params), len(declared))
+	}
+	return nil
+}
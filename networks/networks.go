@@ -0,0 +1,246 @@
+// Package networks holds the profiles of the networks the monitors run on: the L1 and L2 chains of an OP Stack
+// chain, the L1 contracts of its fault proofs and its honest actors. A profile resolves the params of the gate files
+// that name them, so the same monitors are validated, tested and deployed on mainnet and Sepolia without edits. The
+// settings of the fault proofs of a profile, such as the clock of its games, are substituted into the fixtures, which
+// are run on every profile of a test matrix.
+//
+// The built-in profiles leave the honest actors unset, since they depend on the operator. They are set by the
+// networks section of fpm.yaml, which also adds profiles for other chains, and resolving a param the profile leaves
+// unset fails.
+package networks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// Default is the profile used when none is selected.
+const Default = "base-mainnet"
+
+// Test is a profile for tests, of a local chain whose fault proofs differ from those of the built-in networks, which
+// all have a 3.5 day clock and respect game type 0: its games have a 40 second clock, DelayedWETH a 12 second delay,
+// and the portal respects game type 254. It names no contract, so nothing can be deployed on it, and it is not listed
+// by Names. The gate tests and the tests of the native monitors run the fixtures on it besides the networks of their
+// matrix, and so does a matrix of all networks.
+const Test = "test"
+
+// Profile is a network the monitors run on. The fields of the addresses and of the L2 chain are named after the
+// params of the gate files they resolve.
+type Profile struct {
+	// L1ChainID is the chain of the L1 contracts the monitors read, the chain they are validated and deployed on.
+	L1ChainID uint64 `yaml:"l1ChainId" json:"l1ChainId"`
+	// L2ChainID is the OP Stack chain whose output roots the games dispute.
+	L2ChainID uint64 `yaml:"l2ChainId" json:"l2ChainId"`
+
+	OptimismPortalProxy     eth.Address `yaml:"optimismPortalProxy" json:"optimismPortalProxy"`
+	DisputeGameFactoryProxy eth.Address `yaml:"disputeGameFactoryProxy" json:"disputeGameFactoryProxy"`
+	Multicall3              eth.Address `yaml:"multicall3" json:"multicall3"`
+
+	// HonestChallenger and HonestProposer are the accounts of the honest op-challenger and op-proposer.
+	HonestChallenger eth.Address `yaml:"honestChallenger" json:"honestChallenger"`
+	HonestProposer   eth.Address `yaml:"honestProposer" json:"honestProposer"`
+	// CBChallenger is the challenger the child detection monitor expects to counter invalid proposals.
+	CBChallenger eth.Address `yaml:"cbChallenger" json:"cbChallenger"`
+
+	// MaxClockDuration is the maxClockDuration of the games, in seconds.
+	MaxClockDuration uint64 `yaml:"maxClockDuration" json:"maxClockDuration"`
+	// WithdrawalDelay is the delay of DelayedWETH between the unlock and the withdrawal of a bond, in seconds.
+	WithdrawalDelay uint64 `yaml:"withdrawalDelay" json:"withdrawalDelay"`
+	// RespectedGameType is the game type respected by the OptimismPortal, 0 when unset. It is a pointer so that an
+	// override can set it back to 0.
+	RespectedGameType *uint32 `yaml:"respectedGameType" json:"respectedGameType"`
+}
+
+// GameType returns a game type to set as the RespectedGameType of a profile.
+func GameType(t uint32) *uint32 {
+	return &t
+}
+
+// GameType returns the respected game type of the profile.
+func (p Profile) GameType() uint32 {
+	if p.RespectedGameType == nil {
+		return 0
+	}
+	return *p.RespectedGameType
+}
+
+// multicall3 is deployed at the same address on every chain.
+var multicall3 = eth.MustAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+var builtin = map[string]Profile{
+	"base-mainnet": {
+		L1ChainID:               1,
+		L2ChainID:               8453,
+		OptimismPortalProxy:     eth.MustAddress("0x49048044D57e1C92A77f79988d21Fa8fAF74E97e"),
+		DisputeGameFactoryProxy: eth.MustAddress("0x43edB88C4B80fDD2AdFF2412A7BebF9dF42cB40e"),
+		Multicall3:              multicall3,
+		MaxClockDuration:        302400,
+		WithdrawalDelay:         302400,
+	},
+	"base-sepolia": {
+		L1ChainID:               11155111,
+		L2ChainID:               84532,
+		OptimismPortalProxy:     eth.MustAddress("0x49f53e41452C74589E85cA1677426Ba426459e85"),
+		DisputeGameFactoryProxy: eth.MustAddress("0xd6E6dBf4F7EA0ac412fD8b65ED297e64BB7a06E1"),
+		Multicall3:              multicall3,
+		MaxClockDuration:        302400,
+		WithdrawalDelay:         604800,
+	},
+	"op-mainnet": {
+		L1ChainID:               1,
+		L2ChainID:               10,
+		OptimismPortalProxy:     eth.MustAddress("0xbEb5Fc579115071764c7423A4f12eDde41f106Ed"),
+		DisputeGameFactoryProxy: eth.MustAddress("0xe5965Ab5962eDc7477C8520243A95517CD252fA9"),
+		Multicall3:              multicall3,
+		MaxClockDuration:        302400,
+		WithdrawalDelay:         302400,
+	},
+}
+
+var test = Profile{
+	L1ChainID:         900,
+	L2ChainID:         901,
+	MaxClockDuration:  40,
+	WithdrawalDelay:   12,
+	RespectedGameType: GameType(254),
+}
+
+// Names returns the names of the built-in profiles.
+func Names() []string {
+	names := make([]string, 0, len(builtin))
+	for name := range builtin {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the built-in profile name, or the Test profile.
+func Lookup(name string) (Profile, error) {
+	if name == Test {
+		return test, nil
+	}
+	p, ok := builtin[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown network %q, expected one of %v", name, Names())
+	}
+	return p, nil
+}
+
+// Merge returns p with the fields set in override, the ones that are not zero or, for the respected game type, not
+// nil, replacing its own.
+func (p Profile) Merge(override Profile) Profile {
+	for _, f := range []struct{ dst, src *uint64 }{
+		{&p.L1ChainID, &override.L1ChainID},
+		{&p.L2ChainID, &override.L2ChainID},
+		{&p.MaxClockDuration, &override.MaxClockDuration},
+		{&p.WithdrawalDelay, &override.WithdrawalDelay},
+	} {
+		if *f.src != 0 {
+			*f.dst = *f.src
+		}
+	}
+	if override.RespectedGameType != nil {
+		p.RespectedGameType = GameType(*override.RespectedGameType)
+	}
+	for _, f := range []struct{ dst, src *eth.Address }{
+		{&p.OptimismPortalProxy, &override.OptimismPortalProxy},
+		{&p.DisputeGameFactoryProxy, &override.DisputeGameFactoryProxy},
+		{&p.Multicall3, &override.Multicall3},
+		{&p.HonestChallenger, &override.HonestChallenger},
+		{&p.HonestProposer, &override.HonestProposer},
+		{&p.CBChallenger, &override.CBChallenger},
+	} {
+		if *f.src != (eth.Address{}) {
+			*f.dst = *f.src
+		}
+	}
+	return p
+}
+
+// paramNames are the params of the gate files a profile is meant to set.
+var paramNames = map[string]bool{
+	"l2ChainId":               true,
+	"optimismPortalProxy":     true,
+	"disputeGameFactoryProxy": true,
+	"multicall3":              true,
+	"honestChallenger":        true,
+	"honestProposer":          true,
+	"cbChallenger":            true,
+}
+
+// Params returns the params of the gate files set by the profile, by name.
+func (p Profile) Params() map[string]any {
+	params := make(map[string]any)
+	if p.L2ChainID != 0 {
+		params["l2ChainId"] = p.L2ChainID
+	}
+	for name, a := range map[string]eth.Address{
+		"optimismPortalProxy":     p.OptimismPortalProxy,
+		"disputeGameFactoryProxy": p.DisputeGameFactoryProxy,
+		"multicall3":              p.Multicall3,
+		"honestChallenger":        p.HonestChallenger,
+		"honestProposer":          p.HonestProposer,
+		"cbChallenger":            p.CBChallenger,
+	} {
+		if a != (eth.Address{}) {
+			params[name] = a.String()
+		}
+	}
+	return params
+}
+
+// Vars returns the values of the profile substituted into the fixtures, by the name of their field in fpm.yaml: the
+// params of the profile, its L1 chain and the settings of its fault proofs that are set. The respected game type is
+// always set, since 0 is a game type.
+func (p Profile) Vars() map[string]any {
+	vars := p.Params()
+	vars["l1ChainId"] = p.L1ChainID
+	vars["respectedGameType"] = uint64(p.GameType())
+	if p.MaxClockDuration != 0 {
+		vars["maxClockDuration"] = p.MaxClockDuration
+	}
+	if p.WithdrawalDelay != 0 {
+		vars["withdrawalDelay"] = p.WithdrawalDelay
+	}
+	return vars
+}
+
+// Resolve returns params completed with the values of the profile for the params in declared it does not set.
+// params itself is not modified, and is returned as is when the profile adds nothing. A declared param that is not
+// set, and that the profile is meant to set but leaves unset, such as an honest actor, fails rather than reaching the
+// monitor without it. The other params, such as disputeGame, are left to the caller.
+func (p Profile) Resolve(declared []string, params map[string]any) (map[string]any, error) {
+	defaults := p.Params()
+	var resolved map[string]any
+	var unset []string
+	for _, name := range declared {
+		if _, set := params[name]; set {
+			continue
+		}
+		v, ok := defaults[name]
+		if !ok {
+			if paramNames[name] {
+				unset = append(unset, name)
+			}
+			continue
+		}
+		if resolved == nil {
+			resolved = make(map[string]any, len(params)+1)
+			for k, v := range params {
+				resolved[k] = v
+			}
+		}
+		resolved[name] = v
+	}
+	if len(unset) > 0 {
+		return nil, fmt.Errorf("params %s are set neither by the params nor by the network profile", strings.Join(unset, ", "))
+	}
+	if resolved == nil {
+		return params, nil
+	}
+	return resolved, nil
+}
@@ -0,0 +1,110 @@
+package networks_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/monitors"
+	"github.com/base-org/fault-proof-monitors/networks"
+)
+
+func TestResolve(t *testing.T) {
+	p, err := networks.Lookup("base-mainnet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = p.Merge(networks.Profile{HonestChallenger: eth.MustAddress("0x00000000000000000000000000000000000000A2")})
+
+	params := map[string]any{"l2ChainId": 10}
+	resolved, err := p.Resolve([]string{"disputeGameFactoryProxy", "l2ChainId", "honestChallenger", "disputeGame"}, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"disputeGameFactoryProxy": "0x43edb88c4b80fdd2adff2412a7bebf9df42cb40e",
+		"l2ChainId":               10,
+		"honestChallenger":        "0x00000000000000000000000000000000000000a2",
+	}
+	if !reflect.DeepEqual(resolved, want) {
+		t.Errorf("expected %v, got %v", want, resolved)
+	}
+	if len(params) != 1 {
+		t.Errorf("expected the params to be left as is, got %v", params)
+	}
+	if got, err := p.Resolve([]string{"disputeGame"}, params); err != nil || !reflect.DeepEqual(got, params) {
+		t.Errorf("expected nothing to be resolved, got %v (%v)", got, err)
+	}
+	// the built-in profile leaves the honest proposer unset
+	if _, err := p.Resolve([]string{"honestProposer", "disputeGame"}, params); err == nil || !strings.Contains(err.Error(), "honestProposer") {
+		t.Errorf("expected the unset honest proposer to fail, got %v", err)
+	}
+	if _, err := p.Resolve([]string{"honestProposer"}, map[string]any{"honestProposer": "0x00"}); err != nil {
+		t.Errorf("expected a param set by the params to resolve, got %v", err)
+	}
+	if _, err := networks.Lookup("unknown"); err == nil {
+		t.Error("expected an unknown network to be rejected")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	p := networks.Profile{L1ChainID: 1, MaxClockDuration: 302400, RespectedGameType: networks.GameType(1)}
+	if got := p.Merge(networks.Profile{MaxClockDuration: 40}); got.GameType() != 1 || got.MaxClockDuration != 40 || got.L1ChainID != 1 {
+		t.Errorf("expected only the clock to be overridden, got %+v", got)
+	}
+	// 0 is a game type, which an override can set
+	got := p.Merge(networks.Profile{RespectedGameType: networks.GameType(0)})
+	if got.GameType() != 0 || got.RespectedGameType == nil {
+		t.Errorf("expected the respected game type to be overridden with 0, got %v", got.RespectedGameType)
+	}
+	if p.GameType() != 1 {
+		t.Error("expected the merged profile to be left as is")
+	}
+}
+
+// TestTestProfile checks that the test profile varies the settings of the fault proofs the fixtures depend on.
+func TestTestProfile(t *testing.T) {
+	test, err := networks.Lookup(networks.Test)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range networks.Names() {
+		if name == networks.Test {
+			t.Errorf("expected the test profile not to be listed")
+		}
+		p, _ := networks.Lookup(name)
+		if p.MaxClockDuration == test.MaxClockDuration || p.WithdrawalDelay == test.WithdrawalDelay || p.GameType() == test.GameType() {
+			t.Errorf("%s: expected the clock, delay and game type to differ from the test profile", name)
+		}
+	}
+}
+
+// TestBuiltinProfiles checks that every built-in profile configures the native monitors of the single instance gate
+// files on its own.
+func TestBuiltinProfiles(t *testing.T) {
+	for _, name := range networks.Names() {
+		p, _ := networks.Lookup(name)
+		if p.L1ChainID == 0 || p.L2ChainID == 0 {
+			t.Errorf("%s: expected both chains to be set", name)
+		}
+		for _, gate := range []string{"duplicate_dispute_game", "fault_proof_detection_parent"} {
+			declared, err := monitors.Params(gate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			params, err := p.Resolve(declared, nil)
+			if err != nil {
+				t.Errorf("%s: %s: %v", name, gate, err)
+				continue
+			}
+			if err := monitors.CheckParams(gate, params); err != nil {
+				t.Errorf("%s: %s: %v", name, gate, err)
+			}
+			if _, err := monitor.New(gate, params); err != nil {
+				t.Errorf("%s: %s: %v", name, gate, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Package outputroot computes and verifies L2 output roots, the root claims proposed in dispute games. It is the
+// native counterpart of the computation in `fault_proof_detection_parent.gate`.
+package outputroot
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// MessagePasserAddress is the L2ToL1MessagePasser predeploy whose storage root is committed to in the output root.
+var MessagePasserAddress = eth.MustAddress("0x4200000000000000000000000000000000000016")
+
+// V0 is the output root version implemented by OutputV0.
+var V0 = eth.Hash{}
+
+// OutputV0 holds the L2 commitments hashed into a version 0 output root.
+type OutputV0 struct {
+	StateRoot                eth.Hash
+	MessagePasserStorageRoot eth.Hash
+	BlockHash                eth.Hash
+}
+
+// Marshal returns the preimage of the output root: version ++ stateRoot ++ messagePasserStorageRoot ++ blockHash.
+func (o OutputV0) Marshal() []byte {
+	out := make([]byte, 0, 4*len(eth.Hash{}))
+	out = append(out, V0[:]...)
+	out = append(out, o.StateRoot[:]...)
+	out = append(out, o.MessagePasserStorageRoot[:]...)
+	return append(out, o.BlockHash[:]...)
+}
+
+// Root returns the output root, the Keccak-256 hash of the marshaled output.
+func (o OutputV0) Root() eth.Hash {
+	return eth.Keccak256(o.Marshal())
+}
+
+// FromHeaderAndProof builds the output for an L2 block from its header and an `eth_getProof` result for the
+// message passer at that block. The header hash and the account proof are both verified, so the returned output
+// only depends on the block hash being correct.
+func FromHeaderAndProof(header *eth.Header, proof *eth.AccountProof) (OutputV0, error) {
+	if err := header.Verify(); err != nil {
+		return OutputV0{}, err
+	}
+	if proof.Address != MessagePasserAddress {
+		return OutputV0{}, fmt.Errorf("proof is for %s, not the message passer %s", proof.Address, MessagePasserAddress)
+	}
+	if err := proof.Verify(header.StateRoot); err != nil {
+		return OutputV0{}, err
+	}
+	return OutputV0{
+		StateRoot:                header.StateRoot,
+		MessagePasserStorageRoot: proof.StorageHash,
+		BlockHash:                header.Hash,
+	}, nil
+}
+
+// Compute returns the output root for the raw JSON results of `eth_getBlockByNumber` and `eth_getProof`.
+func Compute(headerJSON []byte, proofJSON []byte) (eth.Hash, error) {
+	var header eth.Header
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return eth.Hash{}, fmt.Errorf("decoding block header: %w", err)
+	}
+	var proof eth.AccountProof
+	if err := json.Unmarshal(proofJSON, &proof); err != nil {
+		return eth.Hash{}, fmt.Errorf("decoding account proof: %w", err)
+	}
+	output, err := FromHeaderAndProof(&header, &proof)
+	if err != nil {
+		return eth.Hash{}, err
+	}
+	return output.Root(), nil
+}
@@ -0,0 +1,107 @@
+package outputroot
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// expectedFixtureRoot is the output root of the block and message passer proof in testdata. They are synthetic: the
+// header hash and the account proof verify, but the block is not from a real chain. They exercise the verification,
+// and TestRecorded checks real blocks.
+var expectedFixtureRoot = eth.MustHash("0x88fdedc50993402d6fd6e83126eccd8475be8b8039fc24ba4e0f9a0ec0af23c3")
+
+func readFixtures(t *testing.T) (*eth.Header, *eth.AccountProof) {
+	t.Helper()
+	var header eth.Header
+	var proof eth.AccountProof
+	for path, dst := range map[string]any{"testdata/block.json": &header, "testdata/proof.json": &proof} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Error reading %s: %v", path, err)
+		}
+		if err := json.Unmarshal(data, dst); err != nil {
+			t.Fatalf("Error decoding %s: %v", path, err)
+		}
+	}
+	return &header, &proof
+}
+
+func TestOutputV0Root(t *testing.T) {
+	output := OutputV0{
+		StateRoot:                eth.MustHash("0x01"),
+		MessagePasserStorageRoot: eth.MustHash("0x02"),
+		BlockHash:                eth.MustHash("0x03"),
+	}
+
+	// the preimage is the version followed by the three commitments, matching the parent gate monitor
+	preimage := output.Marshal()
+	if len(preimage) != 128 {
+		t.Fatalf("expected a 128 byte preimage, got %d bytes", len(preimage))
+	}
+	expected := "0x" + strings.Repeat("0", 64) +
+		strings.Repeat("0", 63) + "1" + strings.Repeat("0", 63) + "2" + strings.Repeat("0", 63) + "3"
+	if got := eth.EncodeHex(preimage); got != expected {
+		t.Errorf("expected preimage %s, got %s", expected, got)
+	}
+	if output.Root() != eth.Keccak256(preimage) {
+		t.Errorf("output root is not the hash of its preimage")
+	}
+}
+
+func TestCompute(t *testing.T) {
+	header, err := os.ReadFile("testdata/block.json")
+	if err != nil {
+		t.Fatalf("Error reading block fixture: %v", err)
+	}
+	proof, err := os.ReadFile("testdata/proof.json")
+	if err != nil {
+		t.Fatalf("Error reading proof fixture: %v", err)
+	}
+
+	root, err := Compute(header, proof)
+	if err != nil {
+		t.Fatalf("Error computing output root: %v", err)
+	}
+	if root != expectedFixtureRoot {
+		t.Errorf("expected output root %s, got %s", expectedFixtureRoot, root)
+	}
+}
+
+func TestFromHeaderAndProofTamperedStorageHash(t *testing.T) {
+	// A storage hash that does not match the proven account must be rejected
+	header, proof := readFixtures(t)
+	proof.StorageHash = eth.MustHash("0x01")
+	if _, err := FromHeaderAndProof(header, proof); err == nil {
+		t.Errorf("expected tampered storage hash to fail verification")
+	}
+}
+
+func TestFromHeaderAndProofTamperedStateRoot(t *testing.T) {
+	// A state root that does not match the block hash must be rejected
+	header, proof := readFixtures(t)
+	header.StateRoot = eth.MustHash("0x01")
+	if _, err := FromHeaderAndProof(header, proof); err == nil {
+		t.Errorf("expected tampered state root to fail verification")
+	}
+}
+
+func TestFromHeaderAndProofWrongAccount(t *testing.T) {
+	// Only proofs for the message passer commit to the output root
+	header, proof := readFixtures(t)
+	proof.Address = eth.MustAddress("0x4200000000000000000000000000000000000015")
+	if _, err := FromHeaderAndProof(header, proof); err == nil {
+		t.Errorf("expected proof for the wrong account to be rejected")
+	}
+}
+
+func TestFromHeaderAndProofMissingNode(t *testing.T) {
+	header, proof := readFixtures(t)
+	proof.AccountProof = proof.AccountProof[:1]
+	if _, err := FromHeaderAndProof(header, proof); err == nil {
+		t.Errorf("expected truncated proof to fail verification")
+	}
+}
@@ -0,0 +1,242 @@
+// Package replay evaluates monitors block by block over a past block range, usually one recorded in an archive, and
+// collects the violations into a timeline per game. Because the monitors are configured from the same params as
+// their Hexagate deployments, a threshold can be changed and the range replayed to see how the alerts would have
+// differed.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/monitors"
+	"github.com/base-org/fault-proof-monitors/rpc"
+	"github.com/base-org/fault-proof-monitors/trace"
+)
+
+// Deployment is a monitor configured as it would be deployed in Hexagate.
+type Deployment struct {
+	Name   string         `json:"name"`
+	Params map[string]any `json:"params"`
+}
+
+// Validator runs a gate file over mocked sources.
+type Validator interface {
+	// Validate runs the gate file name with params and mocks, returning the descriptions of the failed invariants.
+	Validate(ctx context.Context, name string, params, mocks map[string]any) ([]string, error)
+}
+
+// HexagateValidator runs the gate files through the Hexagate validate API.
+type HexagateValidator struct {
+	Client  *hexagate.Client
+	ChainID uint64
+}
+
+// Validate implements Validator.
+func (v HexagateValidator) Validate(ctx context.Context, name string, params, mocks map[string]any) ([]string, error) {
+	gate, err := monitors.Gate(name)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := v.Client.Validate(ctx, hexagate.ValidateRequest{
+		Gate:    gate,
+		ChainID: v.ChainID,
+		Params:  params,
+		Mocks:   mocks,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Exceptions) > 0 {
+		return nil, fmt.Errorf("gate %s raised exceptions: %v", name, resp.Exceptions)
+	}
+	failed := make([]string, len(resp.Failed))
+	for i, f := range resp.Failed {
+		failed[i] = hexagate.FailureDescription(f)
+	}
+	return failed, nil
+}
+
+// MocksValidator evaluates the native monitors over the mocks, as a stand-in for the validate API. It checks that
+// the sources a validate request would carry can be loaded, without calling the API.
+type MocksValidator struct{}
+
+// Validate implements Validator.
+func (MocksValidator) Validate(_ context.Context, name string, params, mocks map[string]any) ([]string, error) {
+	m, err := monitor.New(name, params)
+	if err != nil {
+		return nil, err
+	}
+	violations, err := monitor.EvaluateMocks(m, monitor.BlockContext{}, mocks)
+	if err != nil {
+		return nil, err
+	}
+	failed := make([]string, len(violations))
+	for i, v := range violations {
+		failed[i] = v.Description
+	}
+	return failed, nil
+}
+
+// Config configures a replay.
+type Config struct {
+	// Chain serves the blocks being replayed, such as an archive client.
+	Chain monitor.Chain
+	// Tracer traces each block to fill in the calls the monitors read. Without it, monitors see no calls.
+	Tracer trace.Caller
+	// History looks up past events and calls. If nil, logs are looked up in Chain from FromBlock, and past calls
+	// are the ones traced during the replay, so the range should start when the games were created.
+	History monitor.History
+	// L2 serves the L2 chains whose outputs are proposed, keyed by chain ID.
+	L2 map[uint64]monitor.Chain
+
+	FromBlock uint64
+	ToBlock   uint64
+	Monitors  []Deployment
+	// Validator, if set, evaluates the gate files over the sources the native monitors load instead of evaluating
+	// the native monitors.
+	Validator Validator
+}
+
+// Run replays the block range. Monitors that fail to evaluate at a block are recorded in the timeline rather than
+// stopping the replay, since an archive may not hold every request a changed monitor makes.
+func Run(ctx context.Context, cfg Config) (*Timeline, error) {
+	if cfg.ToBlock < cfg.FromBlock {
+		return nil, fmt.Errorf("invalid block range %d-%d", cfg.FromBlock, cfg.ToBlock)
+	}
+	ms := make([]monitor.Monitor, len(cfg.Monitors))
+	for i, d := range cfg.Monitors {
+		m, err := monitor.New(d.Name, d.Params)
+		if err != nil {
+			return nil, err
+		}
+		ms[i] = m
+	}
+	var traced *tracedHistory
+	if cfg.History == nil {
+		traced = &tracedHistory{logs: monitor.LogHistory{Chain: cfg.Chain, FromBlock: cfg.FromBlock}, from: cfg.FromBlock}
+		cfg.History = traced
+	}
+
+	tl := newTimeline(cfg.FromBlock, cfg.ToBlock)
+	for number := cfg.FromBlock; number <= cfg.ToBlock; number++ {
+		bc, err := monitor.NewBlockContext(ctx, cfg.Chain, rpc.NumberAt(number))
+		if err != nil {
+			return nil, err
+		}
+		bc.History = cfg.History
+		bc.L2 = cfg.L2
+		if cfg.Tracer != nil {
+			block, err := trace.Fetch(ctx, cfg.Tracer, number)
+			if err != nil {
+				return nil, fmt.Errorf("tracing block %d: %w", number, err)
+			}
+			bc.Calls = block.MonitorCalls()
+		}
+		if traced != nil {
+			traced.calls = append(traced.calls, bc.Calls...)
+		}
+
+		for i, m := range ms {
+			violations, err := evaluate(ctx, cfg, cfg.Monitors[i], m, bc)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				tl.fail(m, bc, err)
+				continue
+			}
+			for _, v := range violations {
+				tl.add(v)
+			}
+		}
+	}
+	return tl.finish(), nil
+}
+
+func evaluate(ctx context.Context, cfg Config, d Deployment, m monitor.Monitor, bc monitor.BlockContext) ([]monitor.Violation, error) {
+	if cfg.Validator == nil {
+		return m.Evaluate(ctx, bc)
+	}
+	mocks, err := monitor.Sources(ctx, m, bc)
+	if err != nil {
+		return nil, err
+	}
+	failed, err := cfg.Validator.Validate(ctx, d.Name, d.Params, mocks)
+	if err != nil {
+		return nil, err
+	}
+	violations := make([]monitor.Violation, len(failed))
+	for i, description := range failed {
+		violations[i] = monitor.Violation{
+			Monitor:     m.Name(),
+			Description: description,
+			Contract:    m.Contract(),
+			BlockNumber: bc.Number,
+			BlockHash:   bc.Hash,
+		}
+	}
+	return violations, nil
+}
+
+// tracedHistory looks up past logs in the chain and past calls in the blocks traced so far.
+type tracedHistory struct {
+	logs  monitor.LogHistory
+	from  uint64
+	calls []monitor.Call
+}
+
+func (h *tracedHistory) Logs(ctx context.Context, address eth.Address, topic eth.Hash, toBlock uint64) ([]eth.Log, error) {
+	return h.logs.Logs(ctx, address, topic, toBlock)
+}
+
+func (h *tracedHistory) Calls(_ context.Context, address eth.Address, selector []byte, toBlock uint64) ([]monitor.Call, error) {
+	if toBlock < h.from {
+		return nil, monitor.ErrCallHistoryUnavailable
+	}
+	var calls []monitor.Call
+	for _, c := range h.calls {
+		if c.BlockNumber <= toBlock && c.To == address && len(c.Input) >= len(selector) && string(c.Input[:len(selector)]) == string(selector) {
+			calls = append(calls, c)
+		}
+	}
+	return calls, nil
+}
+
+// ApplyOverride parses a param override of the form `monitor.param=value` and applies it to every deployment of
+// the monitor. The value is decoded as JSON if possible, and used as a string otherwise, so both
+// `unresolvable_dispute_game.extraTimeInSeconds=7200` and `challenged_proposal.honestProposer=0x...` work.
+func ApplyOverride(deployments []Deployment, override string) error {
+	target, value, ok := strings.Cut(override, "=")
+	name, param, ok2 := strings.Cut(target, ".")
+	if !ok || !ok2 || name == "" || param == "" {
+		return fmt.Errorf("invalid override %q, expected monitor.param=value", override)
+	}
+	var decoded any
+	dec := json.NewDecoder(strings.NewReader(value))
+	dec.UseNumber()
+	if err := dec.Decode(&decoded); err != nil || dec.More() {
+		decoded = value
+	}
+	found := false
+	for i := range deployments {
+		if deployments[i].Name != name {
+			continue
+		}
+		params := make(map[string]any, len(deployments[i].Params)+1)
+		for k, v := range deployments[i].Params {
+			params[k] = v
+		}
+		params[param] = decoded
+		deployments[i].Params = params
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("no deployment of monitor %s to override", name)
+	}
+	return nil
+}
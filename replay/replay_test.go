@@ -0,0 +1,149 @@
+package replay_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/follower/chaintest"
+	"github.com/base-org/fault-proof-monitors/game"
+	"github.com/base-org/fault-proof-monitors/replay"
+)
+
+var (
+	disputeGame = eth.MustAddress("0x00000000000000000000000000000000000000BB")
+	brokenGame  = eth.MustAddress("0x00000000000000000000000000000000000000CC")
+)
+
+// newChain serves a game created at genesis with a 20 second clock, so that it is resolvable from block 10, which
+// is resolved at block 20.
+func newChain() *chaintest.Chain {
+	g := game.NewGame(game.Config{MaxClockDuration: 20}, 1000)
+	chain := chaintest.NewChain(1000)
+	chain.Extend(30)
+	chain.HandleCall(chaintest.GameCalls(disputeGame, g, 20))
+	return chain
+}
+
+func deployments(grace int) []replay.Deployment {
+	return []replay.Deployment{{
+		Name:   "unresolvable_dispute_game",
+		Params: map[string]any{"disputeGame": disputeGame.String(), "extraTimeInSeconds": grace},
+	}}
+}
+
+func TestRun(t *testing.T) {
+	chain := newChain()
+	cases := []struct {
+		overrides   []string
+		first, last uint64
+	}{
+		{first: 11, last: 19},
+		// backtesting a longer grace period delays the alert
+		{overrides: []string{"unresolvable_dispute_game.extraTimeInSeconds=10"}, first: 16, last: 19},
+	}
+	for _, c := range cases {
+		ds := deployments(0)
+		for _, o := range c.overrides {
+			if err := replay.ApplyOverride(ds, o); err != nil {
+				t.Fatalf("Error applying override: %v", err)
+			}
+		}
+		tl, err := replay.Run(context.Background(), replay.Config{Chain: chain, FromBlock: 1, ToBlock: 25, Monitors: ds})
+		if err != nil {
+			t.Fatalf("Error replaying: %v", err)
+		}
+		if len(tl.Games) != 1 || tl.Games[0].Contract != disputeGame || len(tl.Games[0].Alerts) != 1 {
+			t.Fatalf("expected a single alert for the game, got %+v", tl.Games)
+		}
+		alert := tl.Games[0].Alerts[0]
+		if alert.FirstBlock != c.first || alert.LastBlock != c.last || alert.Violations != int(c.last-c.first+1) {
+			t.Errorf("overrides %v: expected alert over blocks %d-%d, got %+v", c.overrides, c.first, c.last, alert)
+		}
+	}
+}
+
+func TestRunRecordsFailures(t *testing.T) {
+	ds := append(deployments(0), replay.Deployment{
+		Name:   "unresolvable_dispute_game",
+		Params: map[string]any{"disputeGame": brokenGame.String(), "extraTimeInSeconds": 0},
+	})
+	tl, err := replay.Run(context.Background(), replay.Config{Chain: newChain(), FromBlock: 1, ToBlock: 12, Monitors: ds})
+	if err != nil {
+		t.Fatalf("Error replaying: %v", err)
+	}
+	if len(tl.Failures) != 12 || tl.Failures[0].Contract != brokenGame || tl.Failures[0].BlockNumber != 1 {
+		t.Errorf("expected the broken game to fail at every block, got %+v", tl.Failures)
+	}
+	if tl.Alerts() != 1 {
+		t.Errorf("expected the other game to keep alerting, got %d alerts", tl.Alerts())
+	}
+
+	var out strings.Builder
+	if err := tl.WriteText(&out); err != nil {
+		t.Fatalf("Error writing timeline: %v", err)
+	}
+	if !strings.Contains(out.String(), "blocks 11-12") || !strings.Contains(out.String(), "12 evaluations failed") {
+		t.Errorf("unexpected timeline:\n%s", out.String())
+	}
+}
+
+// recordingValidator records the mocks of every validate request, round tripped through JSON as the API would
+// receive them.
+type recordingValidator struct {
+	replay.MocksValidator
+	mocks []map[string]any
+}
+
+func (v *recordingValidator) Validate(ctx context.Context, name string, params, mocks map[string]any) ([]string, error) {
+	data, err := json.Marshal(mocks)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var decoded map[string]any
+	if err := dec.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	v.mocks = append(v.mocks, decoded)
+	return v.MocksValidator.Validate(ctx, name, params, decoded)
+}
+
+func TestRunValidate(t *testing.T) {
+	v := &recordingValidator{}
+	tl, err := replay.Run(context.Background(), replay.Config{Chain: newChain(), FromBlock: 1, ToBlock: 25, Monitors: deployments(0), Validator: v})
+	if err != nil {
+		t.Fatalf("Error replaying: %v", err)
+	}
+	if len(v.mocks) != 25 {
+		t.Fatalf("expected a validate request per block, got %d", len(v.mocks))
+	}
+	// every source is mocked, including after the game is resolved, when the native monitor stops early
+	for _, name := range []string{"gameDuration", "resolvedAt", "claimCount", "claimData", "currentTimestamp"} {
+		if _, ok := v.mocks[24][name]; !ok {
+			t.Errorf("source %s was not mocked", name)
+		}
+	}
+	if tl.Alerts() != 1 || tl.Games[0].Alerts[0].FirstBlock != 11 || tl.Games[0].Alerts[0].LastBlock != 19 {
+		t.Errorf("expected the same alert as the native monitor, got %+v", tl.Games)
+	}
+}
+
+func TestApplyOverride(t *testing.T) {
+	ds := deployments(0)
+	if err := replay.ApplyOverride(ds, "unresolvable_dispute_game.disputeGame=0x00000000000000000000000000000000000000cc"); err != nil {
+		t.Fatalf("Error applying override: %v", err)
+	}
+	if ds[0].Params["disputeGame"] != "0x00000000000000000000000000000000000000cc" {
+		t.Errorf("expected string override, got %v", ds[0].Params["disputeGame"])
+	}
+	for _, o := range []string{"extraTimeInSeconds=10", "challenged_proposal.disputeGame=0x", "unresolvable_dispute_game."} {
+		if err := replay.ApplyOverride(ds, o); err == nil {
+			t.Errorf("expected override %q to fail", o)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package replay
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/monitor"
+)
+
+// Timeline holds the alerts raised over a replayed block range, grouped by the contract each monitor was deployed
+// for, which is the dispute game for every per-game monitor.
+type Timeline struct {
+	FromBlock uint64    `json:"fromBlock"`
+	ToBlock   uint64    `json:"toBlock"`
+	Games     []Game    `json:"games"`
+	Failures  []Failure `json:"failures,omitempty"`
+
+	open map[alertKey]*Alert
+}
+
+// Game is the timeline of a single contract.
+type Game struct {
+	Contract eth.Address `json:"contract"`
+	Alerts   []*Alert    `json:"alerts"`
+}
+
+// Alert is a run of consecutive blocks over which an invariant failed. A new alert starts whenever the invariant
+// fails again after holding, as Hexagate would notify again.
+type Alert struct {
+	Monitor     string `json:"monitor"`
+	Description string `json:"description"`
+	FirstBlock  uint64 `json:"firstBlock"`
+	LastBlock   uint64 `json:"lastBlock"`
+	// Violations counts the violations over the run, which exceeds its length when an invariant fails several
+	// times in a block.
+	Violations int `json:"violations"`
+}
+
+// Failure is a monitor that could not be evaluated at a block.
+type Failure struct {
+	Monitor     string      `json:"monitor"`
+	Contract    eth.Address `json:"contract"`
+	BlockNumber uint64      `json:"blockNumber"`
+	Error       string      `json:"error"`
+}
+
+type alertKey struct {
+	contract    eth.Address
+	monitor     string
+	description string
+}
+
+func newTimeline(from, to uint64) *Timeline {
+	return &Timeline{FromBlock: from, ToBlock: to, open: make(map[alertKey]*Alert)}
+}
+
+func (tl *Timeline) add(v monitor.Violation) {
+	key := alertKey{contract: v.Contract, monitor: v.Monitor, description: v.Description}
+	if a, ok := tl.open[key]; ok && (a.LastBlock == v.BlockNumber || a.LastBlock+1 == v.BlockNumber) {
+		a.LastBlock = v.BlockNumber
+		a.Violations++
+		return
+	}
+	a := &Alert{Monitor: v.Monitor, Description: v.Description, FirstBlock: v.BlockNumber, LastBlock: v.BlockNumber, Violations: 1}
+	tl.open[key] = a
+	g := tl.game(v.Contract)
+	g.Alerts = append(g.Alerts, a)
+}
+
+func (tl *Timeline) fail(m monitor.Monitor, bc monitor.BlockContext, err error) {
+	tl.Failures = append(tl.Failures, Failure{Monitor: m.Name(), Contract: m.Contract(), BlockNumber: bc.Number, Error: err.Error()})
+}
+
+func (tl *Timeline) game(contract eth.Address) *Game {
+	for i := range tl.Games {
+		if tl.Games[i].Contract == contract {
+			return &tl.Games[i]
+		}
+	}
+	tl.Games = append(tl.Games, Game{Contract: contract})
+	return &tl.Games[len(tl.Games)-1]
+}
+
+func (tl *Timeline) finish() *Timeline {
+	sort.Slice(tl.Games, func(i, j int) bool {
+		return tl.Games[i].Alerts[0].FirstBlock < tl.Games[j].Alerts[0].FirstBlock
+	})
+	return tl
+}
+
+// Alerts returns the number of alerts across every game.
+func (tl *Timeline) Alerts() int {
+	n := 0
+	for _, g := range tl.Games {
+		n += len(g.Alerts)
+	}
+	return n
+}
+
+// WriteText writes the timeline in a human readable form.
+func (tl *Timeline) WriteText(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "blocks %d-%d: %d alerts across %d contracts\n", tl.FromBlock, tl.ToBlock, tl.Alerts(), len(tl.Games)); err != nil {
+		return err
+	}
+	for _, g := range tl.Games {
+		if _, err := fmt.Fprintf(w, "\n%s\n", g.Contract); err != nil {
+			return err
+		}
+		for _, a := range g.Alerts {
+			blocks := fmt.Sprintf("block %d", a.FirstBlock)
+			if a.LastBlock != a.FirstBlock {
+				blocks = fmt.Sprintf("blocks %d-%d", a.FirstBlock, a.LastBlock)
+			}
+			if _, err := fmt.Fprintf(w, "  %-24s %s: %s\n", blocks, a.Monitor, a.Description); err != nil {
+				return err
+			}
+		}
+	}
+	if len(tl.Failures) > 0 {
+		if _, err := fmt.Fprintf(w, "\n%d evaluations failed:\n", len(tl.Failures)); err != nil {
+			return err
+		}
+		for _, f := range tl.Failures {
+			if _, err := fmt.Fprintf(w, "  block %d %s on %s: %s\n", f.BlockNumber, f.Monitor, f.Contract, f.Error); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// BlockNumber selects a block either by number or, for the negative values below, by tag.
+type BlockNumber int64
+
+const (
+	SafeBlockNumber      BlockNumber = -4
+	FinalizedBlockNumber BlockNumber = -3
+	LatestBlockNumber    BlockNumber = -2
+	PendingBlockNumber   BlockNumber = -1
+	EarliestBlockNumber  BlockNumber = 0
+)
+
+var blockTags = map[BlockNumber]string{
+	SafeBlockNumber:      "safe",
+	FinalizedBlockNumber: "finalized",
+	LatestBlockNumber:    "latest",
+	PendingBlockNumber:   "pending",
+}
+
+// NumberAt returns the block number selecting block n.
+func NumberAt(n uint64) BlockNumber {
+	return BlockNumber(n)
+}
+
+// IsTag reports whether the block number is one of the named tags rather than an explicit height.
+func (n BlockNumber) IsTag() bool {
+	return n < 0
+}
+
+func (n BlockNumber) String() string {
+	if tag, ok := blockTags[n]; ok {
+		return tag
+	}
+	return strconv.FormatInt(int64(n), 10)
+}
+
+// MarshalText encodes the block number as a tag or a hex quantity.
+func (n BlockNumber) MarshalText() ([]byte, error) {
+	if tag, ok := blockTags[n]; ok {
+		return []byte(tag), nil
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("invalid block number %d", int64(n))
+	}
+	return eth.Uint64(n).MarshalText()
+}
+
+// UnmarshalText decodes a tag or a hex quantity.
+func (n *BlockNumber) UnmarshalText(text []byte) error {
+	for num, tag := range blockTags {
+		if string(text) == tag {
+			*n = num
+			return nil
+		}
+	}
+	if string(text) == "earliest" {
+		*n = EarliestBlockNumber
+		return nil
+	}
+	var q eth.Uint64
+	if err := q.UnmarshalText(text); err != nil {
+		return err
+	}
+	if int64(q) < 0 {
+		return fmt.Errorf("block number %s out of range", text)
+	}
+	*n = BlockNumber(q)
+	return nil
+}
@@ -0,0 +1,198 @@
+// Package rpc is a minimal Ethereum JSON-RPC client covering the methods the native monitors need. It only
+// depends on the standard library, so it can be pointed at a node or at the fake server in rpctest.
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeout bounds requests made with a context that has no deadline.
+const DefaultTimeout = 30 * time.Second
+
+// ErrNotFound is returned when the node answers a lookup with null, e.g. for a block it does not have yet.
+var ErrNotFound = errors.New("not found")
+
+// Error is a JSON-RPC error object returned by the node.
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("json-rpc error %d: %s", e.Code, e.Message)
+}
+
+// Request is a JSON-RPC 2.0 request object.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// Response is a JSON-RPC 2.0 response object.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// BatchElem is a single call in a batch request. Result must be a pointer to decode into, and Error is set if
+// that call failed.
+type BatchElem struct {
+	Method string
+	Params []any
+	Result any
+	Error  error
+}
+
+// Client sends JSON-RPC requests over HTTP.
+type Client struct {
+	url        string
+	httpClient *http.Client
+	timeout    time.Duration
+	nextID     atomic.Uint64
+}
+
+// NewClient returns a client for the JSON-RPC endpoint at url.
+func NewClient(url string) *Client {
+	return &Client{
+		url:        url,
+		httpClient: &http.Client{},
+		timeout:    DefaultTimeout,
+	}
+}
+
+// SetTimeout changes the timeout applied to requests made with a context that has no deadline.
+func (c *Client) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// SetHTTPClient replaces the HTTP client used to send requests, e.g. to serve them from a recorded archive.
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// Call sends a single request and decodes its result into result, which may be nil to discard it.
+func (c *Client) Call(ctx context.Context, result any, method string, params ...any) error {
+	req, err := c.newRequest(method, params)
+	if err != nil {
+		return err
+	}
+	var resp Response
+	if err := c.send(ctx, req, &resp); err != nil {
+		return err
+	}
+	return decodeResult(&resp, result)
+}
+
+// BatchCall sends all elements in a single batch request. Transport failures are returned directly, while
+// failures of individual calls are recorded in each element's Error.
+func (c *Client) BatchCall(ctx context.Context, batch []BatchElem) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	reqs := make([]*Request, len(batch))
+	byID := make(map[string]int, len(batch))
+	for i, elem := range batch {
+		req, err := c.newRequest(elem.Method, elem.Params)
+		if err != nil {
+			return err
+		}
+		reqs[i] = req
+		byID[string(req.ID)] = i
+	}
+
+	var resps []Response
+	if err := c.send(ctx, reqs, &resps); err != nil {
+		return err
+	}
+	seen := make(map[int]bool, len(resps))
+	for i := range resps {
+		idx, ok := byID[string(resps[i].ID)]
+		if !ok {
+			return fmt.Errorf("batch response with unknown id %s", resps[i].ID)
+		}
+		seen[idx] = true
+		batch[idx].Error = decodeResult(&resps[i], batch[idx].Result)
+	}
+	for i := range batch {
+		if !seen[i] {
+			batch[i].Error = fmt.Errorf("no response for %s in batch", batch[i].Method)
+		}
+	}
+	return nil
+}
+
+func (c *Client) newRequest(method string, params []any) (*Request, error) {
+	if params == nil {
+		params = []any{}
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("encoding params for %s: %w", method, err)
+	}
+	id := c.nextID.Add(1)
+	return &Request{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage(fmt.Sprintf("%d", id)),
+		Method:  method,
+		Params:  encoded,
+	}, nil
+}
+
+func (c *Client) send(ctx context.Context, body any, result any) error {
+	if _, ok := ctx.Deadline(); !ok && c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("json-rpc request failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+	return json.Unmarshal(respBody, result)
+}
+
+func decodeResult(resp *Response, result any) error {
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if result == nil {
+		return nil
+	}
+	if len(resp.Result) == 0 || string(resp.Result) == "null" {
+		return ErrNotFound
+	}
+	return json.Unmarshal(resp.Result, result)
+}
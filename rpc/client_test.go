@@ -0,0 +1,311 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/rpc"
+	"github.com/base-org/fault-proof-monitors/rpc/rpctest"
+)
+
+func TestBlockNumber(t *testing.T) {
+	server := rpctest.NewServer()
+	defer server.Close()
+	server.HandleResult("eth_blockNumber", "0x1406f40")
+
+	number, err := rpc.NewClient(server.URL).BlockNumber(context.Background())
+	if err != nil {
+		t.Fatalf("Error fetching block number: %v", err)
+	}
+	if number != 21000000 {
+		t.Errorf("expected block 21000000, got %d", number)
+	}
+}
+
+func TestBlockNumberEncoding(t *testing.T) {
+	cases := map[rpc.BlockNumber]string{
+		rpc.LatestBlockNumber:    `"latest"`,
+		rpc.SafeBlockNumber:      `"safe"`,
+		rpc.FinalizedBlockNumber: `"finalized"`,
+		rpc.PendingBlockNumber:   `"pending"`,
+		rpc.EarliestBlockNumber:  `"0x0"`,
+		rpc.NumberAt(21000000):   `"0x1406f40"`,
+	}
+	for number, want := range cases {
+		encoded, err := json.Marshal(number)
+		if err != nil {
+			t.Fatalf("Error encoding %s: %v", number, err)
+		}
+		if string(encoded) != want {
+			t.Errorf("expected %s, got %s", want, encoded)
+		}
+		var decoded rpc.BlockNumber
+		if err := json.Unmarshal(encoded, &decoded); err != nil || decoded != number {
+			t.Errorf("round trip of %s gave %s (%v)", want, decoded, err)
+		}
+	}
+	if _, err := json.Marshal(rpc.BlockNumber(-10)); err == nil {
+		t.Errorf("expected unknown tag to fail encoding")
+	}
+}
+
+func TestHeaderByNumber(t *testing.T) {
+	// the block served is the L2 block used by the output root fixtures
+	block, err := os.ReadFile("../outputroot/testdata/block.json")
+	if err != nil {
+		t.Fatalf("Error reading block fixture: %v", err)
+	}
+
+	server := rpctest.NewServer()
+	defer server.Close()
+	server.Handle("eth_getBlockByNumber", func(params json.RawMessage) (any, error) {
+		var args []any
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		if len(args) != 2 || args[0] != "finalized" || args[1] != false {
+			return nil, &rpc.Error{Code: -32602, Message: "unexpected params " + string(params)}
+		}
+		return json.RawMessage(block), nil
+	})
+
+	header, err := rpc.NewClient(server.URL).HeaderByNumber(context.Background(), rpc.FinalizedBlockNumber)
+	if err != nil {
+		t.Fatalf("Error fetching header: %v", err)
+	}
+	if err := header.Verify(); err != nil {
+		t.Errorf("Error verifying fetched header: %v", err)
+	}
+}
+
+func TestBlockNotFound(t *testing.T) {
+	server := rpctest.NewServer()
+	defer server.Close()
+	server.HandleResult("eth_getBlockByNumber", nil)
+
+	_, err := rpc.NewClient(server.URL).BlockByNumber(context.Background(), rpc.NumberAt(1), false)
+	if !errors.Is(err, rpc.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCallContract(t *testing.T) {
+	game := eth.MustAddress("0x00000000000000000000000000000000000000BB")
+	server := rpctest.NewServer()
+	defer server.Close()
+	server.Handle("eth_call", func(params json.RawMessage) (any, error) {
+		var args []json.RawMessage
+		if err := json.Unmarshal(params, &args); err != nil {
+			return nil, err
+		}
+		var msg rpc.CallMsg
+		if err := json.Unmarshal(args[0], &msg); err != nil {
+			return nil, err
+		}
+		if msg.To != game {
+			return nil, errors.New("unexpected target " + msg.To.String())
+		}
+		if string(args[1]) != `"0x64"` {
+			return nil, errors.New("unexpected block " + string(args[1]))
+		}
+		return eth.Bytes(eth.BytesToHash(msg.Data).Bytes()), nil
+	})
+
+	output, err := rpc.NewClient(server.URL).CallContract(context.Background(), rpc.CallMsg{To: game, Data: eth.Bytes{0x20}}, rpc.NumberAt(100))
+	if err != nil {
+		t.Fatalf("Error calling contract: %v", err)
+	}
+	if eth.BytesToHash(output) != eth.MustHash("0x20") {
+		t.Errorf("unexpected output %s", eth.EncodeHex(output))
+	}
+}
+
+func TestCallRevert(t *testing.T) {
+	server := rpctest.NewServer()
+	defer server.Close()
+	server.Handle("eth_call", func(json.RawMessage) (any, error) {
+		return nil, &rpc.Error{Code: 3, Message: "execution reverted", Data: json.RawMessage(`"0x8b4d5f9b"`)}
+	})
+
+	_, err := rpc.NewClient(server.URL).CallContract(context.Background(), rpc.CallMsg{}, rpc.LatestBlockNumber)
+	var rpcErr *rpc.Error
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("expected a JSON-RPC error, got %v", err)
+	}
+	if rpcErr.Code != 3 || string(rpcErr.Data) != `"0x8b4d5f9b"` {
+		t.Errorf("unexpected error %v with data %s", rpcErr, rpcErr.Data)
+	}
+}
+
+func TestBatchCallContract(t *testing.T) {
+	// A call without data reverts, which must only fail that element of the batch
+	server := rpctest.NewServer()
+	defer server.Close()
+	server.Handle("eth_call", func(params json.RawMessage) (any, error) {
+		var raw []json.RawMessage
+		if err := json.Unmarshal(params, &raw); err != nil {
+			return nil, err
+		}
+		var msg rpc.CallMsg
+		if err := json.Unmarshal(raw[0], &msg); err != nil {
+			return nil, err
+		}
+		if len(msg.Data) == 0 {
+			return nil, &rpc.Error{Code: 3, Message: "execution reverted"}
+		}
+		return msg.Data, nil
+	})
+
+	msgs := []rpc.CallMsg{{Data: eth.Bytes{1}}, {}, {Data: eth.Bytes{3}}}
+	outputs, errs, err := rpc.NewClient(server.URL).BatchCallContract(context.Background(), msgs, rpc.LatestBlockNumber)
+	if err != nil {
+		t.Fatalf("Error sending batch: %v", err)
+	}
+	if server.Batches() != 1 || server.Calls("eth_call") != 3 {
+		t.Errorf("expected one batch of three calls, got %d batches and %d calls", server.Batches(), server.Calls("eth_call"))
+	}
+	if errs[0] != nil || errs[2] != nil || errs[1] == nil {
+		t.Errorf("unexpected errors %v", errs)
+	}
+	if outputs[0][0] != 1 || outputs[2][0] != 3 {
+		t.Errorf("unexpected outputs %v", outputs)
+	}
+}
+
+func TestLogs(t *testing.T) {
+	factory := eth.MustAddress("0x43edB88C4B80fDD2AdFF2412A7BebF9dF42cB40e")
+	created := eth.Keccak256([]byte("DisputeGameCreated(address,uint32,bytes32)"))
+	server := rpctest.NewServer()
+	defer server.Close()
+	server.Handle("eth_getLogs", func(params json.RawMessage) (any, error) {
+		want := `[{"address":["` + strings.ToLower(factory.String()) + `"],"fromBlock":"0x64","toBlock":"latest","topics":["` + created.String() + `",null]}]`
+		if string(params) != want {
+			return nil, errors.New("unexpected filter " + string(params))
+		}
+		return []eth.Log{{Address: factory, Topics: []eth.Hash{created}, BlockNumber: 101, LogIndex: 2}}, nil
+	})
+
+	logs, err := rpc.NewClient(server.URL).Logs(context.Background(), rpc.FilterQuery{
+		FromBlock: rpc.NumberAt(100),
+		ToBlock:   rpc.LatestBlockNumber,
+		Addresses: []eth.Address{factory},
+		Topics:    [][]eth.Hash{{created}, nil},
+	})
+	if err != nil {
+		t.Fatalf("Error fetching logs: %v", err)
+	}
+	if len(logs) != 1 || logs[0].Topics[0] != created || logs[0].BlockNumber != 101 || logs[0].LogIndex != 2 {
+		t.Errorf("unexpected logs %+v", logs)
+	}
+}
+
+func TestFilterQueryRoundTrip(t *testing.T) {
+	factory := eth.MustAddress("0x43edB88C4B80fDD2AdFF2412A7BebF9dF42cB40e")
+	created := eth.Keccak256([]byte("DisputeGameCreated(address,uint32,bytes32)"))
+	query := rpc.FilterQuery{
+		FromBlock: rpc.NumberAt(100),
+		ToBlock:   rpc.LatestBlockNumber,
+		Addresses: []eth.Address{factory},
+		Topics:    [][]eth.Hash{{created}, nil, {created, created}},
+	}
+	encoded, err := json.Marshal(query)
+	if err != nil {
+		t.Fatalf("Error encoding filter: %v", err)
+	}
+	var decoded rpc.FilterQuery
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Error decoding filter: %v", err)
+	}
+	reencoded, _ := json.Marshal(decoded)
+	if string(reencoded) != string(encoded) {
+		t.Errorf("expected %s, got %s", encoded, reencoded)
+	}
+
+	// nodes also accept a single address
+	if err := json.Unmarshal([]byte(`{"blockHash":"`+created.String()+`","address":"`+factory.String()+`"}`), &decoded); err != nil {
+		t.Fatalf("Error decoding filter: %v", err)
+	}
+	if decoded.BlockHash == nil || *decoded.BlockHash != created || len(decoded.Addresses) != 1 || decoded.Addresses[0] != factory {
+		t.Errorf("unexpected filter %+v", decoded)
+	}
+}
+
+func TestProof(t *testing.T) {
+	// the proof served is the message passer proof used by the output root fixtures
+	proof, err := os.ReadFile("../outputroot/testdata/proof.json")
+	if err != nil {
+		t.Fatalf("Error reading proof fixture: %v", err)
+	}
+	block, err := os.ReadFile("../outputroot/testdata/block.json")
+	if err != nil {
+		t.Fatalf("Error reading block fixture: %v", err)
+	}
+	var header eth.Header
+	if err := json.Unmarshal(block, &header); err != nil {
+		t.Fatalf("Error decoding block fixture: %v", err)
+	}
+
+	server := rpctest.NewServer()
+	defer server.Close()
+	server.HandleResult("eth_getProof", json.RawMessage(proof))
+
+	account := eth.MustAddress("0x4200000000000000000000000000000000000016")
+	result, err := rpc.NewClient(server.URL).Proof(context.Background(), account, nil, rpc.NumberAt(uint64(header.Number)))
+	if err != nil {
+		t.Fatalf("Error fetching proof: %v", err)
+	}
+	if err := result.Verify(header.StateRoot); err != nil {
+		t.Errorf("Error verifying fetched proof: %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 1 || !strings.Contains(string(requests[0].Params), `,[],`) {
+		t.Errorf("expected an empty list of storage keys, got %s", requests[0].Params)
+	}
+}
+
+func TestUnknownMethod(t *testing.T) {
+	server := rpctest.NewServer()
+	defer server.Close()
+
+	err := rpc.NewClient(server.URL).Call(context.Background(), nil, "debug_traceBlockByNumber")
+	var rpcErr *rpc.Error
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32601 {
+		t.Errorf("expected method not found error, got %v", err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	server := rpctest.NewServer()
+	defer server.Close()
+	release := make(chan struct{})
+	defer close(release)
+	server.Handle("eth_blockNumber", func(json.RawMessage) (any, error) {
+		<-release
+		return "0x1", nil
+	})
+
+	client := rpc.NewClient(server.URL)
+	client.SetTimeout(50 * time.Millisecond)
+	start := time.Now()
+	if _, err := client.BlockNumber(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+
+	// an explicit deadline on the context takes precedence over the client timeout
+	client.SetTimeout(time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.BlockNumber(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("requests took %s to time out", elapsed)
+	}
+}
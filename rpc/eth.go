@@ -0,0 +1,191 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/base-org/fault-proof-monitors/eth"
+)
+
+// Block is a block returned by `eth_getBlockByNumber`. Transactions holds either hashes or full transaction
+// objects depending on how the block was requested, and is left undecoded.
+type Block struct {
+	eth.Header
+	Transactions []json.RawMessage `json:"transactions"`
+}
+
+// CallMsg is the transaction object for `eth_call`.
+type CallMsg struct {
+	From *eth.Address `json:"from,omitempty"`
+	To   eth.Address  `json:"to"`
+	Data eth.Bytes    `json:"data"`
+}
+
+// FilterQuery is the filter object for `eth_getLogs`. Each position in Topics matches any of the listed
+// topics, and an empty position matches every topic. BlockHash, when set, replaces the block range.
+type FilterQuery struct {
+	BlockHash *eth.Hash
+	FromBlock BlockNumber
+	ToBlock   BlockNumber
+	Addresses []eth.Address
+	Topics    [][]eth.Hash
+}
+
+// MarshalJSON encodes the filter in the shape nodes expect.
+func (q FilterQuery) MarshalJSON() ([]byte, error) {
+	filter := map[string]any{}
+	if q.BlockHash != nil {
+		filter["blockHash"] = q.BlockHash
+	} else {
+		filter["fromBlock"] = q.FromBlock
+		filter["toBlock"] = q.ToBlock
+	}
+	if len(q.Addresses) > 0 {
+		filter["address"] = q.Addresses
+	}
+	if len(q.Topics) > 0 {
+		topics := make([]any, len(q.Topics))
+		for i, position := range q.Topics {
+			switch len(position) {
+			case 0:
+				topics[i] = nil
+			case 1:
+				topics[i] = position[0]
+			default:
+				topics[i] = position
+			}
+		}
+		filter["topics"] = topics
+	}
+	return json.Marshal(filter)
+}
+
+// UnmarshalJSON decodes a filter as sent to nodes, accepting a single address or topic in place of a list.
+func (q *FilterQuery) UnmarshalJSON(data []byte) error {
+	var filter struct {
+		BlockHash *eth.Hash         `json:"blockHash"`
+		FromBlock *BlockNumber      `json:"fromBlock"`
+		ToBlock   *BlockNumber      `json:"toBlock"`
+		Address   json.RawMessage   `json:"address"`
+		Topics    []json.RawMessage `json:"topics"`
+	}
+	if err := json.Unmarshal(data, &filter); err != nil {
+		return err
+	}
+	*q = FilterQuery{BlockHash: filter.BlockHash, FromBlock: LatestBlockNumber, ToBlock: LatestBlockNumber}
+	if filter.FromBlock != nil {
+		q.FromBlock = *filter.FromBlock
+	}
+	if filter.ToBlock != nil {
+		q.ToBlock = *filter.ToBlock
+	}
+	if err := unmarshalOneOrMany(filter.Address, &q.Addresses); err != nil {
+		return fmt.Errorf("invalid address filter: %w", err)
+	}
+	q.Topics = make([][]eth.Hash, len(filter.Topics))
+	for i, position := range filter.Topics {
+		if err := unmarshalOneOrMany(position, &q.Topics[i]); err != nil {
+			return fmt.Errorf("invalid topic filter: %w", err)
+		}
+	}
+	if len(q.Topics) == 0 {
+		q.Topics = nil
+	}
+	return nil
+}
+
+// unmarshalOneOrMany decodes null, a single value or a list of values into the slice pointed to by dst.
+func unmarshalOneOrMany[T any](data json.RawMessage, dst *[]T) error {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return nil
+	}
+	if trimmed[0] == '[' {
+		return json.Unmarshal(trimmed, dst)
+	}
+	var one T
+	if err := json.Unmarshal(trimmed, &one); err != nil {
+		return err
+	}
+	*dst = []T{one}
+	return nil
+}
+
+// BlockNumber returns the number of the most recent block.
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	var result eth.Uint64
+	if err := c.Call(ctx, &result, "eth_blockNumber"); err != nil {
+		return 0, err
+	}
+	return uint64(result), nil
+}
+
+// BlockByNumber returns the block at number, with full transaction objects when fullTx is set.
+func (c *Client) BlockByNumber(ctx context.Context, number BlockNumber, fullTx bool) (*Block, error) {
+	var result Block
+	if err := c.Call(ctx, &result, "eth_getBlockByNumber", number, fullTx); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// HeaderByNumber returns the header of the block at number.
+func (c *Client) HeaderByNumber(ctx context.Context, number BlockNumber) (*eth.Header, error) {
+	block, err := c.BlockByNumber(ctx, number, false)
+	if err != nil {
+		return nil, err
+	}
+	return &block.Header, nil
+}
+
+// CallContract executes msg against the state at number without creating a transaction.
+func (c *Client) CallContract(ctx context.Context, msg CallMsg, number BlockNumber) ([]byte, error) {
+	var result eth.Bytes
+	if err := c.Call(ctx, &result, "eth_call", msg, number); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BatchCallContract executes every message against the state at number in a single batch request. The
+// returned errors line up with msgs and are nil for calls that succeeded.
+func (c *Client) BatchCallContract(ctx context.Context, msgs []CallMsg, number BlockNumber) ([][]byte, []error, error) {
+	results := make([]eth.Bytes, len(msgs))
+	batch := make([]BatchElem, len(msgs))
+	for i, msg := range msgs {
+		batch[i] = BatchElem{Method: "eth_call", Params: []any{msg, number}, Result: &results[i]}
+	}
+	if err := c.BatchCall(ctx, batch); err != nil {
+		return nil, nil, err
+	}
+	outputs := make([][]byte, len(msgs))
+	errs := make([]error, len(msgs))
+	for i := range batch {
+		outputs[i] = results[i]
+		errs[i] = batch[i].Error
+	}
+	return outputs, errs, nil
+}
+
+// Logs returns the logs matching query.
+func (c *Client) Logs(ctx context.Context, query FilterQuery) ([]eth.Log, error) {
+	var result []eth.Log
+	if err := c.Call(ctx, &result, "eth_getLogs", query); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Proof returns the Merkle proof of account and the given storage slots at number.
+func (c *Client) Proof(ctx context.Context, account eth.Address, keys []eth.Hash, number BlockNumber) (*eth.AccountProof, error) {
+	if keys == nil {
+		keys = []eth.Hash{}
+	}
+	var result eth.AccountProof
+	if err := c.Call(ctx, &result, "eth_getProof", account, keys, number); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,139 @@
+// Package rpctest provides a fake JSON-RPC server for testing code that talks to an Ethereum node.
+package rpctest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+// HandlerFunc answers a single JSON-RPC call. Returning an *rpc.Error sends it to the client as is, any other
+// error is reported as an internal error.
+type HandlerFunc func(params json.RawMessage) (any, error)
+
+// Server is an HTTP JSON-RPC server that dispatches calls to registered handlers and records every request.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	handlers map[string]HandlerFunc
+	requests []rpc.Request
+	batches  int
+}
+
+// NewServer starts a server with no handlers. Callers must Close it.
+func NewServer() *Server {
+	s := &Server{handlers: make(map[string]HandlerFunc)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Handle registers fn for method, replacing any previous handler.
+func (s *Server) Handle(method string, fn HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method] = fn
+}
+
+// HandleResult registers a handler that always answers method with result.
+func (s *Server) HandleResult(method string, result any) {
+	s.Handle(method, func(json.RawMessage) (any, error) {
+		return result, nil
+	})
+}
+
+// Requests returns every call received so far, with batches flattened in order.
+func (s *Server) Requests() []rpc.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]rpc.Request(nil), s.requests...)
+}
+
+// Calls returns the number of calls received for method.
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, req := range s.requests {
+		if req.Method == method {
+			count++
+		}
+	}
+	return count
+}
+
+// Batches returns the number of batch requests received.
+func (s *Server) Batches() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batches
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var resp any
+	if len(body) > 0 && body[0] == '[' {
+		var reqs []rpc.Request
+		if err := json.Unmarshal(body, &reqs); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.batches++
+		s.mu.Unlock()
+		resps := make([]rpc.Response, len(reqs))
+		for i, req := range reqs {
+			resps[i] = s.dispatch(req)
+		}
+		resp = resps
+	} else {
+		var req rpc.Request
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		resp = s.dispatch(req)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) dispatch(req rpc.Request) rpc.Response {
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	fn, ok := s.handlers[req.Method]
+	s.mu.Unlock()
+
+	resp := rpc.Response{JSONRPC: "2.0", ID: req.ID}
+	if !ok {
+		resp.Error = &rpc.Error{Code: -32601, Message: "the method " + req.Method + " does not exist/is not available"}
+		return resp
+	}
+	result, err := fn(req.Params)
+	if err != nil {
+		var rpcErr *rpc.Error
+		if !errors.As(err, &rpcErr) {
+			rpcErr = &rpc.Error{Code: -32603, Message: err.Error()}
+		}
+		resp.Error = rpcErr
+		return resp
+	}
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		resp.Error = &rpc.Error{Code: -32603, Message: err.Error()}
+		return resp
+	}
+	resp.Result = encoded
+	return resp
+}
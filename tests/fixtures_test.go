@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/fixtures"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/networks"
+)
+
+// TestFixtures runs every shared fixture against its gate file, on each network of the matrix of the config and on the
+// test profile, whose clock and respected game type differ from those of the built-in networks, with the values of
+// the profile substituted. The same fixtures are evaluated by the native monitors in the monitor package, which keeps
+// the two implementations in agreement.
+func TestFixtures(t *testing.T) {
+	all, err := fixtures.All()
+	if err != nil {
+		t.Fatalf("Error loading fixtures: %v", err)
+	}
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("Error loading config: %v", err)
+	}
+
+	matrix := cfg.MatrixNetworks()
+	if !slices.Contains(matrix, networks.Test) {
+		matrix = append(matrix, networks.Test)
+	}
+	for _, network := range matrix {
+		profile, err := cfg.Lookup(network)
+		if err != nil {
+			t.Fatalf("Error loading network %s: %v", network, err)
+		}
+		for _, f := range all {
+			testFixture(t, cfg, network, profile, f)
+		}
+	}
+}
+
+func testFixture(t *testing.T, cfg config.Config, network string, profile networks.Profile, f fixtures.Fixture) {
+	f, err := f.Substitute(profile.Vars())
+	if err != nil {
+		t.Fatalf("Error substituting %s/%s: %v", f.Monitor, f.Name, err)
+	}
+	t.Run(network+"/"+f.Monitor+"/"+f.Name, func(t *testing.T) {
+		file := f.Monitor + ".gate"
+
+		// read in the gate file
+		data, err := ReadGateFile(file)
+		if err != nil {
+			t.Fatalf("Error reading file %s: %v", file, err)
+		}
+
+		// call the validate request endpoint and parse the results
+		failed, exceptions, trace, err := HandleValidateRequestOn(cfg, profile, data, f.Params, f.Mocks)
+		if err != nil {
+			t.Fatalf("Error handling validate request for %s: %v", file, err)
+		}
+
+		// check if the validate request threw any exceptions
+		if len(exceptions) > 0 {
+			fmt.Println(trace)
+			t.Errorf("Exceptions for %s: %v", file, exceptions)
+		}
+
+		if f.ExpectAlert && len(failed) == 0 {
+			fmt.Println(trace)
+			t.Errorf("Monitor did not fire an alert for %s when it was supposed to: %s", file, f.Description)
+		}
+		if !f.ExpectAlert && len(failed) > 0 {
+			fmt.Println(trace)
+			t.Errorf("Monitor fired an alert for %s when it was not supposed to: %s", file, f.Description)
+		}
+
+		// check the descriptions of the alerts when the fixture lists them
+		alerts := make([]string, len(failed))
+		for i, alert := range failed {
+			alerts[i] = hexagate.FailureDescription(alert)
+		}
+		if f.Alerts != nil && !slices.Equal(alerts, f.Alerts) {
+			fmt.Println(trace)
+			t.Errorf("Monitor fired alerts %q for %s, expected %q: %s", alerts, file, f.Alerts, f.Description)
+		}
+	})
+}
@@ -1,35 +1,16 @@
 package tests
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 
-	"github.com/joho/godotenv"
+	"github.com/base-org/fault-proof-monitors/config"
+	"github.com/base-org/fault-proof-monitors/hexagate"
+	"github.com/base-org/fault-proof-monitors/networks"
 )
 
-const (
-	VALIDATE_ENDPOINT = "https://api.hexagate.com/api/v1/invariants/validate"
-)
-
-type ValidateRequest struct {
-	Gate    string         `json:"gate"`
-	ChainId int            `json:"chain_id"`
-	Params  map[string]any `json:"params"`
-	Mocks   map[string]any `json:"mocks"`
-	Trace   bool           `json:"trace"`
-}
-
-type ValidateResponse struct {
-	Count      int   `json:"count"`
-	Failed     []any `json:"failed"`
-	Exceptions []any `json:"exceptions"`
-	Trace      any   `json:"trace"`
-}
-
 func ReadGateFile(filename string) (string, error) {
 	file, err := os.Open(fmt.Sprintf("../monitors/%s", filename))
 	if err != nil {
@@ -44,59 +25,37 @@ func ReadGateFile(filename string) (string, error) {
 	return string(data[:]), nil
 }
 
+// HandleValidateRequest runs a gate file through the validate endpoint, with the API key, API root and network
+// resolved by the config package, so the tests run from any directory. The params are sent as they are.
 func HandleValidateRequest(gatefile string, params map[string]any, mocks map[string]any) ([]any, []any, any, error) {
-	requestData := ValidateRequest{
-		Gate:    gatefile,
-		ChainId: 1,
-		Params:  params,
-		Mocks:   mocks,
-		Trace:   true,
-	}
-
-	// marshal data into expected JSON format
-	data := new(bytes.Buffer)
-	enc := json.NewEncoder(data)
-	enc.SetEscapeHTML(false)
-	err := enc.Encode(requestData)
+	cfg, err := config.Load("")
 	if err != nil {
 		return []any{}, []any{}, nil, err
 	}
-
-	// retrieve API key from .env file and call the API endpoint
-	err = godotenv.Load("../.env")
+	profile, err := cfg.Profile()
 	if err != nil {
 		return []any{}, []any{}, nil, err
 	}
+	return HandleValidateRequestOn(cfg, profile, gatefile, params, mocks)
+}
 
-	// create the POST request
-	req, err := http.NewRequest("POST", VALIDATE_ENDPOINT, data)
-	if err != nil {
-		return []any{}, []any{}, nil, err
-	}
-
-	// set the appliable headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Hexagate-Api-Key", os.Getenv("HEXAGATE_API_KEY"))
-
-	// send the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// HandleValidateRequestOn runs a gate file through the validate endpoint on the L1 chain of profile. The params are
+// sent as they are.
+func HandleValidateRequestOn(cfg config.Config, profile networks.Profile, gatefile string, params map[string]any, mocks map[string]any) ([]any, []any, any, error) {
+	client, err := cfg.Client()
 	if err != nil {
 		return []any{}, []any{}, nil, err
 	}
-	defer resp.Body.Close()
 
-	// parse and decode the response
-	var response ValidateResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
+	response, err := client.Validate(context.Background(), hexagate.ValidateRequest{
+		Gate:    gatefile,
+		ChainID: profile.L1ChainID,
+		Params:  params,
+		Mocks:   mocks,
+		Trace:   true,
+	})
 	if err != nil {
 		return []any{}, []any{}, nil, err
 	}
-
-	// access the response data
-	failed := response.Failed
-	exceptions := response.Exceptions
-	trace := response.Trace
-
-	return failed, exceptions, trace, nil
+	return response.Failed, response.Exceptions, response.Trace, nil
 }
@@ -0,0 +1,208 @@
+// Package trace extracts the calls made in a block from `debug_traceBlockByNumber` with geth's callTracer. The
+// flattened calls back the native monitors' versions of the gate language's Calls and FilterAddressesInTrace,
+// which see internal calls and not only top-level transactions.
+package trace
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/rpc"
+)
+
+// Frame is a call frame as reported by the callTracer.
+type Frame struct {
+	Type         string      `json:"type"`
+	From         eth.Address `json:"from"`
+	To           eth.Address `json:"to"`
+	Value        *eth.Big    `json:"value,omitempty"`
+	Gas          eth.Uint64  `json:"gas"`
+	GasUsed      eth.Uint64  `json:"gasUsed"`
+	Input        eth.Bytes   `json:"input"`
+	Output       eth.Bytes   `json:"output,omitempty"`
+	Error        string      `json:"error,omitempty"`
+	RevertReason string      `json:"revertReason,omitempty"`
+	Calls        []Frame     `json:"calls,omitempty"`
+}
+
+// Transaction is the trace of a single transaction in the block. Nodes that fail to trace a transaction report
+// an error instead of a result.
+type Transaction struct {
+	TxHash eth.Hash `json:"txHash"`
+	Result *Frame   `json:"result,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// Caller sends a JSON-RPC request. It is implemented by *rpc.Client.
+type Caller interface {
+	Call(ctx context.Context, result any, method string, params ...any) error
+}
+
+// tracerConfig selects the callTracer. Logs are not needed since events are read with `eth_getLogs`.
+var tracerConfig = map[string]any{"tracer": "callTracer"}
+
+// FetchBlock traces every transaction in the block.
+func FetchBlock(ctx context.Context, c Caller, number rpc.BlockNumber) ([]Transaction, error) {
+	var txs []Transaction
+	if err := c.Call(ctx, &txs, "debug_traceBlockByNumber", number, tracerConfig); err != nil {
+		return nil, fmt.Errorf("tracing block %s: %w", number, err)
+	}
+	for i, tx := range txs {
+		if tx.Error != "" || tx.Result == nil {
+			return nil, fmt.Errorf("tracing transaction %d of block %s: %s", i, number, tx.Error)
+		}
+	}
+	return txs, nil
+}
+
+// Call is a single call frame of a block, flattened from its trace.
+type Call struct {
+	BlockNumber uint64
+	TxHash      eth.Hash
+	TxIndex     int
+	// Sender is the account that signed the transaction the call was made in.
+	Sender eth.Address
+	// Type is the call type reported by the tracer, e.g. CALL, DELEGATECALL or CREATE.
+	Type  string
+	From  eth.Address
+	To    eth.Address
+	Input eth.Bytes
+	Value *big.Int
+	// Depth is zero for the top-level call of the transaction.
+	Depth int
+	// Reverted is set if the call or any of its callers failed, so none of its effects persisted.
+	Reverted bool
+}
+
+// DecodedCall is a call with its arguments decoded.
+type DecodedCall struct {
+	Call
+	Args []any
+}
+
+// Block is the flattened trace of a block.
+type Block struct {
+	Number uint64
+	// Calls are in execution order: each transaction's frames are walked depth first.
+	Calls []Call
+}
+
+// Flatten walks the call frames of every transaction in the block.
+func Flatten(number uint64, txs []Transaction) *Block {
+	b := &Block{Number: number}
+	for i, tx := range txs {
+		if tx.Result == nil {
+			continue
+		}
+		b.flatten(Call{BlockNumber: number, TxHash: tx.TxHash, TxIndex: i, Sender: tx.Result.From}, tx.Result, 0, false)
+	}
+	return b
+}
+
+func (b *Block) flatten(base Call, f *Frame, depth int, reverted bool) {
+	reverted = reverted || f.Error != ""
+	c := base
+	c.Type = f.Type
+	c.From = f.From
+	c.To = f.To
+	c.Input = f.Input
+	c.Value = new(big.Int)
+	if f.Value != nil {
+		c.Value.Set(f.Value.Int())
+	}
+	c.Depth = depth
+	c.Reverted = reverted
+	b.Calls = append(b.Calls, c)
+	for i := range f.Calls {
+		b.flatten(base, &f.Calls[i], depth+1, reverted)
+	}
+}
+
+// Fetch traces and flattens the block.
+func Fetch(ctx context.Context, c Caller, number uint64) (*Block, error) {
+	txs, err := FetchBlock(ctx, c, rpc.NumberAt(number))
+	if err != nil {
+		return nil, err
+	}
+	return Flatten(number, txs), nil
+}
+
+// Decode mirrors the gate language's Calls, returning the successful calls to method on contract with their
+// decoded arguments. Delegate calls are skipped so a call through a proxy is only counted once, against the proxy.
+func (b *Block) Decode(contract eth.Address, method abi.Method) ([]DecodedCall, error) {
+	var decoded []DecodedCall
+	selector := method.Selector()
+	for _, c := range b.Calls {
+		if c.Reverted || c.Type == "DELEGATECALL" || c.To != contract || len(c.Input) < 4 || !bytes.Equal(c.Input[:4], selector) {
+			continue
+		}
+		args, err := method.UnpackInputs(c.Input)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s call in transaction %s: %w", method.Name, c.TxHash, err)
+		}
+		decoded = append(decoded, DecodedCall{Call: c, Args: args})
+	}
+	return decoded, nil
+}
+
+// Addresses returns every address that made or received a call in the block, in order of first appearance.
+func (b *Block) Addresses() []eth.Address {
+	seen := make(map[eth.Address]bool)
+	var addresses []eth.Address
+	for _, c := range b.Calls {
+		for _, a := range []eth.Address{c.From, c.To} {
+			if !seen[a] {
+				seen[a] = true
+				addresses = append(addresses, a)
+			}
+		}
+	}
+	return addresses
+}
+
+// FilterAddresses mirrors the gate language's FilterAddressesInTrace, returning the given addresses that appear in
+// the block's trace.
+func (b *Block) FilterAddresses(addresses ...eth.Address) []eth.Address {
+	seen := make(map[eth.Address]bool)
+	for _, c := range b.Calls {
+		seen[c.From] = true
+		seen[c.To] = true
+	}
+	found := []eth.Address{}
+	for _, a := range addresses {
+		if seen[a] {
+			found = append(found, a)
+		}
+	}
+	return found
+}
+
+// MonitorCalls returns the successful calls in the form native monitors read from BlockContext.Calls. Delegate
+// calls are left out as in Decode.
+func (b *Block) MonitorCalls() []monitor.Call {
+	var calls []monitor.Call
+	for _, c := range b.Calls {
+		if c.Reverted || c.Type == "DELEGATECALL" {
+			continue
+		}
+		calls = append(calls, monitor.Call{BlockNumber: c.BlockNumber, From: c.From, To: c.To, Input: c.Input, Value: c.Value})
+	}
+	return calls
+}
+
+// Prepare returns a hook for follower.Config.Prepare that fills in the calls of each block from its trace.
+func Prepare(c Caller) func(ctx context.Context, bc *monitor.BlockContext) error {
+	return func(ctx context.Context, bc *monitor.BlockContext) error {
+		b, err := Fetch(ctx, c, bc.Number)
+		if err != nil {
+			return err
+		}
+		bc.Calls = b.MonitorCalls()
+		return nil
+	}
+}
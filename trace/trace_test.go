@@ -0,0 +1,174 @@
+package trace_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/base-org/fault-proof-monitors/abi"
+	"github.com/base-org/fault-proof-monitors/eth"
+	"github.com/base-org/fault-proof-monitors/monitor"
+	"github.com/base-org/fault-proof-monitors/rpc"
+	"github.com/base-org/fault-proof-monitors/rpc/rpctest"
+	"github.com/base-org/fault-proof-monitors/trace"
+)
+
+var (
+	honest     = eth.MustAddress("0x642229f238fb9dE03374Be34B0eD8D9De80752c5")
+	challenger = eth.MustAddress("0x8Ca1E12404d16373Aef756179B185F27b2994F3a")
+	game       = eth.MustAddress("0x0E6E9b8B1eC7bbf5D6FbB6A6CF0a2F3e9A5cE9d4")
+	weth       = eth.MustAddress("0x84d9A9E5e7F9fDA1BBF0E5E4F11F3aF5Fe4E07dC")
+	wethImpl   = eth.MustAddress("0x5e40B9231B86984b5150507046e354dbFbeD3d9e")
+	factory    = eth.MustAddress("0x43edB88C4B80fDD2AdFF2412A7BebF9dF42cB40e")
+)
+
+// loadBlock reads the recorded callTracer output of a block with four transactions: a claimCredit that withdraws
+// from DelayedWETH through its proxy, a dispute game creation, a reverted attack and a plain transfer.
+func loadBlock(t *testing.T) []trace.Transaction {
+	t.Helper()
+	data, err := os.ReadFile("testdata/block.json")
+	if err != nil {
+		t.Fatalf("Error reading trace fixture: %v", err)
+	}
+	var txs []trace.Transaction
+	if err := json.Unmarshal(data, &txs); err != nil {
+		t.Fatalf("Error decoding trace fixture: %v", err)
+	}
+	return txs
+}
+
+func TestFlatten(t *testing.T) {
+	b := trace.Flatten(21000000, loadBlock(t))
+	if len(b.Calls) != 13 {
+		t.Fatalf("expected 13 calls, got %d", len(b.Calls))
+	}
+
+	// the ETH sent back to the game by the DelayedWETH implementation is four frames deep in the first transaction
+	c := b.Calls[3]
+	if c.TxIndex != 0 || c.Depth != 3 || c.Sender != honest || c.From != weth || c.To != game || c.BlockNumber != 21000000 {
+		t.Errorf("unexpected call %+v", c)
+	}
+	if c.Value.Cmp(big.NewInt(109e15)) != 0 {
+		t.Errorf("expected a value of 0.109 ETH, got %s", c.Value)
+	}
+
+	// every frame of the reverted attack is marked reverted, including the deposit that succeeded within it
+	for _, c := range b.Calls {
+		if reverted := c.TxIndex == 2; c.Reverted != reverted {
+			t.Errorf("call %d of transaction %d has reverted %t", c.Depth, c.TxIndex, c.Reverted)
+		}
+		if c.TxIndex == 2 && c.Sender != challenger {
+			t.Errorf("expected sender %s, got %s", challenger, c.Sender)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	b := trace.Flatten(21000000, loadBlock(t))
+
+	// the withdrawal is made through the proxy, so the delegate call to the implementation is not counted again
+	withdraw := abi.MustParseMethod("withdraw(address _guy, uint256 _wad)")
+	calls, err := b.Decode(weth, withdraw)
+	if err != nil {
+		t.Fatalf("Error decoding withdraw calls: %v", err)
+	}
+	if len(calls) != 1 || calls[0].From != game || calls[0].Args[0] != honest || calls[0].Args[1].(*big.Int).Cmp(big.NewInt(109e15)) != 0 {
+		t.Errorf("unexpected withdraw calls %+v", calls)
+	}
+	if calls, _ := b.Decode(wethImpl, withdraw); len(calls) != 0 {
+		t.Errorf("expected no calls to the implementation, got %+v", calls)
+	}
+
+	// only the deposit of the successful game creation counts, not the one in the reverted attack
+	deposits, err := b.Decode(weth, abi.MustParseMethod("deposit() payable"))
+	if err != nil {
+		t.Fatalf("Error decoding deposit calls: %v", err)
+	}
+	if len(deposits) != 1 || deposits[0].TxIndex != 1 || deposits[0].Sender != challenger {
+		t.Errorf("unexpected deposit calls %+v", deposits)
+	}
+
+	created, err := b.Decode(factory, abi.Create)
+	if err != nil {
+		t.Fatalf("Error decoding create calls: %v", err)
+	}
+	if len(created) != 1 || created[0].Args[0].(*big.Int).Sign() != 0 || created[0].Depth != 0 {
+		t.Errorf("unexpected create calls %+v", created)
+	}
+}
+
+func TestFilterAddresses(t *testing.T) {
+	b := trace.Flatten(21000000, loadBlock(t))
+	absent := eth.MustAddress("0x00000000000000000000000000000000000000AA")
+	found := b.FilterAddresses(game, absent, wethImpl)
+	if len(found) != 2 || found[0] != game || found[1] != wethImpl {
+		t.Errorf("unexpected addresses %v", found)
+	}
+	if found := b.FilterAddresses(absent); found == nil || len(found) != 0 {
+		t.Errorf("expected an empty list, got %v", found)
+	}
+
+	addresses := b.Addresses()
+	if len(addresses) != 8 || addresses[0] != honest || addresses[1] != game {
+		t.Errorf("unexpected addresses %v", addresses)
+	}
+}
+
+func TestFetch(t *testing.T) {
+	data, err := os.ReadFile("testdata/block.json")
+	if err != nil {
+		t.Fatalf("Error reading trace fixture: %v", err)
+	}
+	server := rpctest.NewServer()
+	defer server.Close()
+	server.HandleResult("debug_traceBlockByNumber", json.RawMessage(data))
+
+	b, err := trace.Fetch(context.Background(), rpc.NewClient(server.URL), 21000000)
+	if err != nil {
+		t.Fatalf("Error fetching trace: %v", err)
+	}
+	if len(b.Calls) != 13 {
+		t.Errorf("expected 13 calls, got %d", len(b.Calls))
+	}
+	if params := string(server.Requests()[0].Params); params != `["0x1406f40",{"tracer":"callTracer"}]` {
+		t.Errorf("unexpected params %s", params)
+	}
+}
+
+func TestFetchTransactionError(t *testing.T) {
+	server := rpctest.NewServer()
+	defer server.Close()
+	server.HandleResult("debug_traceBlockByNumber", json.RawMessage(`[{"txHash":"0x01","error":"execution timeout"}]`))
+
+	_, err := trace.Fetch(context.Background(), rpc.NewClient(server.URL), 1)
+	if err == nil || !strings.Contains(err.Error(), "execution timeout") {
+		t.Errorf("expected the tracing error to be reported, got %v", err)
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	data, err := os.ReadFile("testdata/block.json")
+	if err != nil {
+		t.Fatalf("Error reading trace fixture: %v", err)
+	}
+	server := rpctest.NewServer()
+	defer server.Close()
+	server.HandleResult("debug_traceBlockByNumber", json.RawMessage(data))
+
+	bc := monitor.BlockContext{Number: 21000000}
+	if err := trace.Prepare(rpc.NewClient(server.URL))(context.Background(), &bc); err != nil {
+		t.Fatalf("Error preparing block: %v", err)
+	}
+	// the two delegate calls and the two frames of the reverted attack are left out
+	if len(bc.Calls) != 9 {
+		t.Errorf("expected 9 calls, got %d", len(bc.Calls))
+	}
+	for _, c := range bc.Calls {
+		if c.To == wethImpl {
+			t.Errorf("unexpected delegate call %+v", c)
+		}
+	}
+}